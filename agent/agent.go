@@ -0,0 +1,259 @@
+// Package agent runs the tool-use loop between a language model and an MCP
+// server: the conversation and the server's tools are sent to the model, the
+// tool calls it returns are executed through the client and their results
+// appended, and this repeats until the model answers without calling a tool.
+//
+//	a := agent.New(c, myModel, agent.WithMaxSteps(5))
+//	messages, err := a.Run(ctx, []agent.Message{{Role: agent.RoleUser, Content: "What's the weather?"}})
+//
+// The model is anything implementing LLM, typically a thin wrapper around a
+// vendor SDK using the adapters of the client package.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// Roles of the messages of a conversation.
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// DefaultMaxSteps is the number of model turns Run allows unless WithMaxSteps
+// is given.
+const DefaultMaxSteps = 10
+
+// ErrMaxSteps is returned by Run when the model still calls tools after the
+// last allowed step.
+var ErrMaxSteps = errors.New("agent: step limit reached")
+
+// Message is a message of a conversation. Assistant messages may carry tool
+// calls; tool messages answer the call with ID ToolCallID.
+type Message struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content,omitempty"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+// ToolCall is a call of a tool requested by the model. Arguments is a JSON object.
+type ToolCall struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// Tool is a tool offered to the model. InputSchema is the JSON Schema of its
+// arguments.
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// LLM is a language model able to call tools.
+type LLM interface {
+	// Generate returns the next assistant message of the conversation.
+	Generate(ctx context.Context, messages []Message, tools []Tool) (Message, error)
+}
+
+// StreamingLLM is an LLM able to stream the text of its answer. Run uses it
+// when WithStreaming is given.
+type StreamingLLM interface {
+	LLM
+	// GenerateStream is Generate, calling onDelta with each piece of text as
+	// it is produced.
+	GenerateStream(ctx context.Context, messages []Message, tools []Tool, onDelta func(delta string)) (Message, error)
+}
+
+// ApprovalFunc decides whether a tool call may run. A denied call is answered
+// with a tool message telling the model so; an error stops Run.
+type ApprovalFunc func(ctx context.Context, call ToolCall) (bool, error)
+
+// Option configures an Agent.
+type Option func(*Agent)
+
+// WithMaxSteps sets the number of model turns Run allows.
+func WithMaxSteps(n int) Option {
+	return func(a *Agent) {
+		a.maxSteps = n
+	}
+}
+
+// WithApproval asks approve before running each tool call.
+func WithApproval(approve ApprovalFunc) Option {
+	return func(a *Agent) {
+		a.approve = approve
+	}
+}
+
+// WithStreaming streams the text of the model's answers to onDelta when the
+// model implements StreamingLLM.
+func WithStreaming(onDelta func(delta string)) Option {
+	return func(a *Agent) {
+		a.onDelta = onDelta
+	}
+}
+
+// WithMessageHook calls onMessage with each message Run appends to the
+// conversation.
+func WithMessageHook(onMessage func(Message)) Option {
+	return func(a *Agent) {
+		a.onMessage = onMessage
+	}
+}
+
+// WithToolCallConcurrency runs up to n tool calls of a step at a time.
+func WithToolCallConcurrency(n int) Option {
+	return func(a *Agent) {
+		a.concurrency = n
+	}
+}
+
+// Agent runs conversations between a model and the tools of an MCP server.
+type Agent struct {
+	client      *client.HTTPClient
+	llm         LLM
+	maxSteps    int
+	approve     ApprovalFunc
+	onDelta     func(delta string)
+	onMessage   func(Message)
+	concurrency int
+}
+
+// New returns an agent offering the tools of c to llm.
+func New(c *client.HTTPClient, llm LLM, opts ...Option) *Agent {
+	a := &Agent{
+		client:      c,
+		llm:         llm,
+		maxSteps:    DefaultMaxSteps,
+		concurrency: 1,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Run continues the conversation until the model answers without calling a
+// tool, and returns the conversation including the messages it added. When the
+// step limit is reached it returns the conversation so far and ErrMaxSteps.
+func (a *Agent) Run(ctx context.Context, messages []Message) ([]Message, error) {
+	messages = slices.Clone(messages)
+	tools, err := a.tools(ctx)
+	if err != nil {
+		return messages, err
+	}
+
+	for step := 1; step <= a.maxSteps; step++ {
+		reply, err := a.generate(ctx, messages, tools)
+		if err != nil {
+			return messages, fmt.Errorf("failed to generate step %d: %w", step, err)
+		}
+		if reply.Role == "" {
+			reply.Role = RoleAssistant
+		}
+		messages = a.append(messages, reply)
+		if len(reply.ToolCalls) == 0 {
+			return messages, nil
+		}
+
+		results, err := a.execute(ctx, reply.ToolCalls)
+		if err != nil {
+			return messages, err
+		}
+		messages = a.append(messages, results...)
+	}
+	return messages, ErrMaxSteps
+}
+
+func (a *Agent) generate(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	if streaming, ok := a.llm.(StreamingLLM); ok && a.onDelta != nil {
+		return streaming.GenerateStream(ctx, messages, tools, a.onDelta)
+	}
+	return a.llm.Generate(ctx, messages, tools)
+}
+
+func (a *Agent) append(messages []Message, added ...Message) []Message {
+	if a.onMessage != nil {
+		for _, message := range added {
+			a.onMessage(message)
+		}
+	}
+	return append(messages, added...)
+}
+
+// tools lists the tools of the server, following pagination.
+func (a *Agent) tools(ctx context.Context) ([]Tool, error) {
+	var (
+		tools  []Tool
+		cursor mcp.Cursor
+	)
+	for {
+		result, err := a.client.ListTools(ctx, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		for _, tool := range result.Tools {
+			schema := map[string]any{}
+			if len(tool.InputSchema) > 0 {
+				if err := json.Unmarshal(tool.InputSchema, &schema); err != nil {
+					return nil, fmt.Errorf("failed to parse input schema of tool %s: %w", tool.Name, err)
+				}
+			}
+			tools = append(tools, Tool{Name: tool.Name, Description: tool.Description, InputSchema: schema})
+		}
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = result.NextCursor
+	}
+}
+
+// execute runs the approved tool calls and returns the tool messages
+// answering every call, in order.
+func (a *Agent) execute(ctx context.Context, calls []ToolCall) ([]Message, error) {
+	results := make([]Message, len(calls))
+	var (
+		approved []client.OpenaiToolCall
+		indexes  []int
+	)
+	for i, call := range calls {
+		results[i] = Message{Role: RoleTool, ToolCallID: call.ID}
+		if a.approve != nil {
+			ok, err := a.approve(ctx, call)
+			if err != nil {
+				return nil, fmt.Errorf("failed to approve tool call %s: %w", call.ID, err)
+			}
+			if !ok {
+				results[i].Content = "Error: the user denied this tool call"
+				continue
+			}
+		}
+		approved = append(approved, client.OpenaiToolCall{
+			ID:       call.ID,
+			Type:     "function",
+			Function: client.OpenaiFunctionCall{Name: call.Name, Arguments: string(call.Arguments)},
+		})
+		indexes = append(indexes, i)
+	}
+
+	toolMessages, err := a.client.ExecuteOpenaiToolCalls(ctx, approved, client.WithToolCallConcurrency(a.concurrency))
+	if err != nil {
+		return nil, err
+	}
+	for j, toolMessage := range toolMessages {
+		results[indexes[j]].Content = toolMessage.Content
+	}
+	return results, nil
+}
@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+// scriptedLLM answers with the messages of script in turn, recording what it
+// was given.
+type scriptedLLM struct {
+	script []Message
+	seen   [][]Message
+	tools  []Tool
+}
+
+func (l *scriptedLLM) Generate(ctx context.Context, messages []Message, tools []Tool) (Message, error) {
+	l.seen = append(l.seen, messages)
+	l.tools = tools
+	if len(l.seen) > len(l.script) {
+		return l.script[len(l.script)-1], nil
+	}
+	return l.script[len(l.seen)-1], nil
+}
+
+// streamingLLM streams the words of its answers.
+type streamingLLM struct {
+	scriptedLLM
+}
+
+func (l *streamingLLM) GenerateStream(ctx context.Context, messages []Message, tools []Tool, onDelta func(string)) (Message, error) {
+	reply, err := l.Generate(ctx, messages, tools)
+	for _, word := range strings.SplitAfter(reply.Content, " ") {
+		onDelta(word)
+	}
+	return reply, err
+}
+
+func newTestClient(t *testing.T) *client.HTTPClient {
+	t.Helper()
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{
+		Name:        "weather",
+		Description: "Current weather of a city",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}}}`),
+	}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("sunny in " + arguments["city"].(string)), nil
+	})
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	c, err := client.NewHTTPClient(&client.Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func weatherCall(id string) Message {
+	return Message{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: id, Name: "weather", Arguments: json.RawMessage(`{"city":"Paris"}`)}}}
+}
+
+func TestRun(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	question := []Message{{Role: RoleUser, Content: "Weather in Paris?"}}
+
+	t.Run("Loop", func(t *testing.T) {
+		llm := &scriptedLLM{script: []Message{
+			weatherCall("call_1"),
+			{Role: RoleAssistant, Content: "It is sunny."},
+		}}
+		var hooked []Message
+		messages, err := New(c, llm, WithMessageHook(func(m Message) { hooked = append(hooked, m) })).Run(ctx, question)
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		want := []Message{
+			question[0],
+			weatherCall("call_1"),
+			{Role: RoleTool, ToolCallID: "call_1", Content: "sunny in Paris"},
+			{Role: RoleAssistant, Content: "It is sunny."},
+		}
+		if !reflect.DeepEqual(messages, want) {
+			t.Errorf("Expected %+v, got %+v", want, messages)
+		}
+		if !reflect.DeepEqual(hooked, want[1:]) {
+			t.Errorf("Expected hook to see %+v, got %+v", want[1:], hooked)
+		}
+		if len(llm.tools) != 1 || llm.tools[0].Name != "weather" || llm.tools[0].InputSchema["type"] != "object" {
+			t.Errorf("Expected the weather tool, got %+v", llm.tools)
+		}
+		if len(llm.seen[1]) != 3 {
+			t.Errorf("Expected the tool result to be sent back, got %+v", llm.seen[1])
+		}
+	})
+
+	t.Run("Approval", func(t *testing.T) {
+		llm := &scriptedLLM{script: []Message{weatherCall("call_1"), {Content: "Sorry."}}}
+		var asked []string
+		approve := func(ctx context.Context, call ToolCall) (bool, error) {
+			asked = append(asked, call.Name)
+			return false, nil
+		}
+		messages, err := New(c, llm, WithApproval(approve)).Run(ctx, question)
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if !reflect.DeepEqual(asked, []string{"weather"}) {
+			t.Errorf("Expected approval to be asked for weather, got %v", asked)
+		}
+		if got := messages[2].Content; got != "Error: the user denied this tool call" {
+			t.Errorf("Expected a denial, got %q", got)
+		}
+		if got := messages[3].Role; got != RoleAssistant {
+			t.Errorf("Expected the role to default to %s, got %q", RoleAssistant, got)
+		}
+	})
+
+	t.Run("MaxSteps", func(t *testing.T) {
+		llm := &scriptedLLM{script: []Message{weatherCall("call_1")}}
+		messages, err := New(c, llm, WithMaxSteps(2)).Run(ctx, question)
+		if !errors.Is(err, ErrMaxSteps) {
+			t.Fatalf("Expected ErrMaxSteps, got %v", err)
+		}
+		if len(messages) != 5 {
+			t.Errorf("Expected 5 messages, got %d", len(messages))
+		}
+	})
+
+	t.Run("Streaming", func(t *testing.T) {
+		llm := &streamingLLM{scriptedLLM{script: []Message{{Content: "It is sunny."}}}}
+		var streamed strings.Builder
+		if _, err := New(c, llm, WithStreaming(func(delta string) { streamed.WriteString(delta) })).Run(ctx, question); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if streamed.String() != "It is sunny." {
+			t.Errorf("Expected the answer to be streamed, got %q", streamed.String())
+		}
+	})
+}
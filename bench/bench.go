@@ -0,0 +1,233 @@
+// Package bench load-tests MCP servers, for capacity planning.
+//
+// Run sends a request, such as a tool call, from concurrent workers sharing
+// one or more sessions, and reports the latency distribution, the errors and
+// the reconnects of the listening streams:
+//
+//	report, err := bench.Run(ctx, connect, bench.CallTool("search", args), bench.Options{
+//		Concurrency: 20,
+//		Duration:    time.Minute,
+//	})
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/client/transport"
+)
+
+// DefaultRequests is the number of requests sent when neither Requests nor
+// Duration bounds a run.
+const DefaultRequests = 100
+
+// ErrToolError is the error of a tool call whose result reports an error.
+var ErrToolError = errors.New("tool reported an error")
+
+// Request sends one request of a load test with c.
+type Request func(ctx context.Context, c *client.HTTPClient) error
+
+// CallTool returns a Request calling a tool with arguments. Results flagged
+// as errors count as errors.
+func CallTool(name string, arguments map[string]any) Request {
+	return func(ctx context.Context, c *client.HTTPClient) error {
+		result, err := c.CallTool(ctx, name, arguments)
+		if err != nil {
+			return err
+		}
+		if result.IsError {
+			return ErrToolError
+		}
+		return nil
+	}
+}
+
+// ReadResource returns a Request reading a resource.
+func ReadResource(uri string) Request {
+	return func(ctx context.Context, c *client.HTTPClient) error {
+		_, err := c.ReadResource(ctx, uri)
+		return err
+	}
+}
+
+// Connect creates the client of a session. It must pass opts on to the
+// client, as Run counts reconnects through them.
+type Connect func(ctx context.Context, opts ...client.HTTPClientOption) (*client.HTTPClient, error)
+
+// Options configures Run.
+type Options struct {
+	// Concurrency is the number of workers sending requests at once, 1 by default
+	Concurrency int
+	// Sessions is the number of clients the workers are spread over, each
+	// with its own session, 1 by default and at most Concurrency
+	Sessions int
+	// Requests is the number of requests to send in total
+	Requests int
+	// Duration bounds the time spent sending requests. With Requests, the run
+	// ends on whichever is reached first
+	Duration time.Duration
+}
+
+// Report is the outcome of a run. Durations are in nanoseconds in JSON.
+type Report struct {
+	Requests int `json:"requests"`
+	Errors   int `json:"errors"`
+	// ErrorCounts counts the errors by message
+	ErrorCounts map[string]int `json:"errorCounts,omitempty"`
+	// Reconnects counts the times a session reconnected its listening stream
+	// or was re-established
+	Reconnects int           `json:"reconnects"`
+	Elapsed    time.Duration `json:"elapsed"`
+	Min        time.Duration `json:"min"`
+	Mean       time.Duration `json:"mean"`
+	P50        time.Duration `json:"p50"`
+	P90        time.Duration `json:"p90"`
+	P99        time.Duration `json:"p99"`
+	Max        time.Duration `json:"max"`
+
+	// latencies holds the latency of every request, sorted
+	latencies []time.Duration
+}
+
+// ErrorRate returns the share of requests that failed, between 0 and 1.
+func (r *Report) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// Throughput returns the requests sent per second.
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// Percentile returns the latency under which p percent of the requests
+// completed, by the nearest-rank method.
+func (r *Report) Percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(r.latencies))))
+	return r.latencies[min(max(rank, 1), len(r.latencies))-1]
+}
+
+// worker is the share of a run of one worker.
+type worker struct {
+	latencies []time.Duration
+	errors    map[string]int
+}
+
+// Run connects the sessions, then sends request from each worker until the
+// options' bounds are reached or ctx is done, and reports how it went.
+// Requests cut short by the end of the run are not counted.
+func Run(ctx context.Context, connect Connect, request Request, options Options) (*Report, error) {
+	concurrency := max(options.Concurrency, 1)
+	limit := options.Requests
+	if limit <= 0 && options.Duration <= 0 {
+		limit = DefaultRequests
+	}
+
+	var reconnects atomic.Int64
+	countReconnects := client.WithConnectionStateHandler(func(state transport.ConnectionState, err error) {
+		if state == transport.ConnectionReconnecting {
+			reconnects.Add(1)
+		}
+	})
+	clients := make([]*client.HTTPClient, 0, min(max(options.Sessions, 1), concurrency))
+	defer func() {
+		for _, c := range clients {
+			c.Close()
+		}
+	}()
+	for i := range cap(clients) {
+		c, err := connect(ctx, countReconnects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect session %d: %w", i, err)
+		}
+		clients = append(clients, c)
+	}
+
+	runCtx := ctx
+	if options.Duration > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, options.Duration)
+		defer cancel()
+	}
+	var sent atomic.Int64
+	next := func() bool {
+		if runCtx.Err() != nil {
+			return false
+		}
+		return limit <= 0 || sent.Add(1) <= int64(limit)
+	}
+
+	start := time.Now()
+	workers := make([]*worker, concurrency)
+	var wg sync.WaitGroup
+	for i := range workers {
+		w := &worker{errors: map[string]int{}}
+		workers[i] = w
+		c := clients[i%len(clients)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for next() {
+				requestStart := time.Now()
+				err := request(runCtx, c)
+				latency := time.Since(requestStart)
+				if err != nil && runCtx.Err() != nil {
+					return
+				}
+				w.latencies = append(w.latencies, latency)
+				if err != nil {
+					w.errors[err.Error()]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	report := &Report{Elapsed: time.Since(start), Reconnects: int(reconnects.Load())}
+	for _, w := range workers {
+		report.latencies = append(report.latencies, w.latencies...)
+		for message, n := range w.errors {
+			if report.ErrorCounts == nil {
+				report.ErrorCounts = map[string]int{}
+			}
+			report.ErrorCounts[message] += n
+			report.Errors += n
+		}
+	}
+	report.summarize()
+	return report, nil
+}
+
+// summarize computes the latency statistics of the report.
+func (r *Report) summarize() {
+	slices.Sort(r.latencies)
+	r.Requests = len(r.latencies)
+	if r.Requests == 0 {
+		return
+	}
+	var total time.Duration
+	for _, latency := range r.latencies {
+		total += latency
+	}
+	r.Min = r.latencies[0]
+	r.Max = r.latencies[len(r.latencies)-1]
+	r.Mean = total / time.Duration(r.Requests)
+	r.P50 = r.Percentile(50)
+	r.P90 = r.Percentile(90)
+	r.P99 = r.Percentile(99)
+}
@@ -0,0 +1,110 @@
+package bench
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+// connectTo returns a Connect creating clients of srv.
+func connectTo(srv *mcptest.Server, extra ...client.HTTPClientOption) Connect {
+	return func(ctx context.Context, opts ...client.HTTPClientOption) (*client.HTTPClient, error) {
+		return client.NewHTTPClient(&client.Options{BaseURL: srv.URL}, append(opts, extra...)...)
+	}
+}
+
+func TestRun(t *testing.T) {
+	srv := mcptest.NewServer()
+	var calls atomic.Int64
+	srv.AddTool(mcp.Tool{Name: "flaky"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText("ok")
+		result.IsError = calls.Add(1)%4 == 0
+		return result, nil
+	})
+	srv.AddResource(mcp.Resource{URI: "file:///a.txt", Name: "a"}, mcp.TextResourceContents{URI: "file:///a.txt", Text: "a"})
+	srv.Start()
+	defer srv.Close()
+
+	t.Run("Requests", func(t *testing.T) {
+		report, err := Run(context.Background(), connectTo(srv), CallTool("flaky", nil), Options{Concurrency: 4, Requests: 40})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if report.Requests != 40 {
+			t.Errorf("Expected 40 requests, got %d", report.Requests)
+		}
+		if report.Errors != 10 || report.ErrorCounts[ErrToolError.Error()] != 10 {
+			t.Errorf("Expected 10 tool errors, got %d: %v", report.Errors, report.ErrorCounts)
+		}
+		if rate := report.ErrorRate(); rate != 0.25 {
+			t.Errorf("Expected an error rate of 0.25, got %v", rate)
+		}
+		if report.Min <= 0 || report.Min > report.P50 || report.P50 > report.P90 || report.P90 > report.P99 || report.P99 > report.Max {
+			t.Errorf("Expected ordered latencies, got %+v", report)
+		}
+		if report.Throughput() <= 0 {
+			t.Errorf("Expected a throughput, got %v", report.Throughput())
+		}
+	})
+
+	t.Run("Duration", func(t *testing.T) {
+		report, err := Run(context.Background(), connectTo(srv), ReadResource("file:///a.txt"), Options{Concurrency: 2, Duration: 50 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if report.Requests == 0 || report.Errors != 0 {
+			t.Errorf("Expected requests without errors, got %d requests and %v", report.Requests, report.ErrorCounts)
+		}
+		if report.Elapsed < 50*time.Millisecond || report.Elapsed > time.Second {
+			t.Errorf("Expected the run to last about 50ms, got %s", report.Elapsed)
+		}
+	})
+
+	t.Run("Reconnects", func(t *testing.T) {
+		var sent atomic.Int64
+		expiring := func(ctx context.Context, c *client.HTTPClient) error {
+			if sent.Add(1) == 5 {
+				srv.ExpireSession()
+			}
+			_, err := c.ReadResource(ctx, "file:///a.txt")
+			return err
+		}
+		report, err := Run(context.Background(), connectTo(srv, client.WithSessionRecovery()), expiring, Options{Requests: 10})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if report.Reconnects != 1 || report.Errors != 0 {
+			t.Errorf("Expected 1 reconnect and no errors, got %d and %v", report.Reconnects, report.ErrorCounts)
+		}
+	})
+
+	t.Run("Connection failure", func(t *testing.T) {
+		failing := func(ctx context.Context, opts ...client.HTTPClientOption) (*client.HTTPClient, error) {
+			return client.NewHTTPClient(&client.Options{BaseURL: "http://127.0.0.1:1"}, opts...)
+		}
+		if _, err := Run(context.Background(), failing, ReadResource("file:///a.txt"), Options{}); err == nil {
+			t.Error("Expected an error when a session cannot connect")
+		}
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	report := &Report{}
+	for i := 1; i <= 100; i++ {
+		report.latencies = append(report.latencies, time.Duration(i)*time.Millisecond)
+	}
+	report.summarize()
+	for p, want := range map[float64]time.Duration{0: time.Millisecond, 50: 50 * time.Millisecond, 99: 99 * time.Millisecond, 100: 100 * time.Millisecond} {
+		if got := report.Percentile(p); got != want {
+			t.Errorf("Expected percentile %v to be %s, got %s", p, want, got)
+		}
+	}
+	if report.Mean != 50500*time.Microsecond {
+		t.Errorf("Expected a mean of 50.5ms, got %s", report.Mean)
+	}
+}
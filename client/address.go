@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+)
+
+// New creates a client for the server at address, whose scheme picks the
+// transport, and initializes the session within ctx:
+//
+//	client.New(ctx, "https://example.com/mcp", nil)
+//	client.New(ctx, "stdio:./server --flag", nil)
+//	client.New(ctx, "ws://localhost:8080/mcp", nil) // once a "ws" transport is registered
+//
+// http and https URLs and stdio command lines use the transports of this
+// module, configured by options as NewHTTPClient and NewStdioClient do.
+// Other schemes use the factory registered with transport.Register, and
+// only the client-side options apply. options may be nil; its BaseURL is
+// replaced by address.
+func New(ctx context.Context, address string, options *Options, opts ...HTTPClientOption) (*HTTPClient, error) {
+	var o Options
+	if options != nil {
+		o = *options
+	}
+	switch transport.Scheme(address) {
+	case "http", "https":
+		o.BaseURL = address
+		return NewHTTPClientContext(ctx, &o, opts...)
+	case transport.StdioScheme:
+		command, args, err := transport.ParseStdioAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		return newStdioClient(ctx, command, args, nil, &o, opts...)
+	}
+	transportImpl, err := transport.New(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transport: %w", err)
+	}
+	return newClient(ctx, transportImpl, &o, opts...)
+}
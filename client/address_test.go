@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, nil)
+	srv.Start()
+	defer srv.Close()
+
+	t.Run("HTTP", func(t *testing.T) {
+		c, err := New(ctx, srv.URL, nil)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		defer c.Close()
+		if _, err := c.ListTools(ctx, ""); err != nil {
+			t.Errorf("ListTools failed: %v", err)
+		}
+	})
+
+	t.Run("Stdio", func(t *testing.T) {
+		t.Setenv("MCPGOPHER_TEST_STDIO_SERVER", "1")
+		t.Setenv("MCPGOPHER_TEST_TOKEN", "from-address")
+		c, err := New(ctx, "stdio:"+os.Args[0]+" -test.run=^TestStdioServerProcess$", nil)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		defer c.Close()
+		result, err := c.CallTool(ctx, "env", nil)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "from-address" {
+			t.Errorf("Expected the stdio server to answer, got %+v", result.Content)
+		}
+	})
+
+	t.Run("Registered scheme", func(t *testing.T) {
+		transport.Register("mcptest", func(address string) (transport.Interface, error) {
+			return transport.NewStreamableHTTP("http://" + strings.TrimPrefix(address, "mcptest://"))
+		})
+		c, err := New(ctx, "mcptest://"+strings.TrimPrefix(srv.URL, "http://"), nil)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		defer c.Close()
+		if _, err := c.ListTools(ctx, ""); err != nil {
+			t.Errorf("ListTools failed: %v", err)
+		}
+	})
+
+	t.Run("Unsupported scheme", func(t *testing.T) {
+		if _, err := New(ctx, "ws://localhost/mcp", nil); !errors.Is(err, transport.ErrUnsupportedScheme) {
+			t.Errorf("Expected ErrUnsupportedScheme, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// AnthropicTool is a tool definition in the shape the Anthropic Messages API
+// expects in its "tools" parameter.
+type AnthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+// AnthropicToolUse is a "tool_use" content block of an assistant message,
+// asking for a tool to be called.
+type AnthropicToolUse struct {
+	Type  string          `json:"type"`
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// AnthropicToolResult is the "tool_result" content block answering a
+// tool_use, to be sent back in a user message.
+type AnthropicToolResult struct {
+	Type      string                  `json:"type"`
+	ToolUseID string                  `json:"tool_use_id"`
+	Content   []AnthropicContentBlock `json:"content,omitempty"`
+	IsError   bool                    `json:"is_error,omitempty"`
+}
+
+// AnthropicContentBlock is a text or image block of a tool result.
+type AnthropicContentBlock struct {
+	Type   string                `json:"type"`
+	Text   string                `json:"text,omitempty"`
+	Source *AnthropicImageSource `json:"source,omitempty"`
+}
+
+// AnthropicImageSource holds base64-encoded image data.
+type AnthropicImageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+// AnthropicTools returns the tools of the server as Anthropic tool definitions.
+func (c *HTTPClient) AnthropicTools(ctx context.Context) ([]AnthropicTool, error) {
+	tools, err := allTools(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	anthropicTools := make([]AnthropicTool, 0, len(tools))
+	for _, tool := range tools {
+		schema := map[string]interface{}{}
+		if len(tool.InputSchema) > 0 {
+			if err := json.Unmarshal(tool.InputSchema, &schema); err != nil {
+				return nil, fmt.Errorf("failed to parse input schema of tool %s: %w", tool.Name, err)
+			}
+		}
+		anthropicTools = append(anthropicTools, AnthropicTool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: normalizeSchema(schema),
+		})
+	}
+	return anthropicTools, nil
+}
+
+// CallAnthropicToolUse calls the tool a tool_use block asks for and returns
+// the tool_result block answering it. A tool reporting an error yields a
+// result with IsError set; an error is returned only when the call fails.
+func (c *HTTPClient) CallAnthropicToolUse(ctx context.Context, toolUse AnthropicToolUse) (*AnthropicToolResult, error) {
+	var arguments map[string]any
+	if len(toolUse.Input) > 0 {
+		if err := json.Unmarshal(toolUse.Input, &arguments); err != nil {
+			return nil, fmt.Errorf("failed to parse input of tool_use %s: %w", toolUse.ID, err)
+		}
+	}
+	result, err := c.CallTool(ctx, toolUse.Name, arguments)
+	if err != nil {
+		return nil, err
+	}
+	return NewAnthropicToolResult(toolUse.ID, result), nil
+}
+
+// NewAnthropicToolResult wraps the result of a tools/call as the tool_result
+// block answering the tool_use with the given ID. Text and images are kept;
+// text resources become text and other content is rendered as JSON text.
+func NewAnthropicToolResult(toolUseID string, result *mcp.CallToolResult) *AnthropicToolResult {
+	toolResult := &AnthropicToolResult{
+		Type:      "tool_result",
+		ToolUseID: toolUseID,
+		IsError:   result.IsError,
+	}
+	for _, content := range result.Content {
+		var block AnthropicContentBlock
+		switch content := content.(type) {
+		case mcp.TextContent:
+			block = AnthropicContentBlock{Type: "text", Text: content.Text}
+		case mcp.ImageContent:
+			block = AnthropicContentBlock{Type: "image", Source: &AnthropicImageSource{
+				Type:      "base64",
+				MediaType: content.MimeType,
+				Data:      content.Data,
+			}}
+		case mcp.EmbeddedResource:
+			if text, ok := content.Resource.(mcp.TextResourceContents); ok {
+				block = AnthropicContentBlock{Type: "text", Text: text.Text}
+				break
+			}
+			data, _ := json.Marshal(content)
+			block = AnthropicContentBlock{Type: "text", Text: string(data)}
+		default:
+			data, _ := json.Marshal(content)
+			block = AnthropicContentBlock{Type: "text", Text: string(data)}
+		}
+		toolResult.Content = append(toolResult.Content, block)
+	}
+	return toolResult
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestAnthropicTools(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{
+		Name:        "tag",
+		Description: "Tag an item",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"tags":{"type":"array"}},"required":["tags"]}`),
+	}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		tags, _ := json.Marshal(arguments["tags"])
+		return mcp.NewToolResultText(string(tags)), nil
+	})
+	srv.AddTool(mcp.Tool{Name: "fail"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText("boom")
+		result.IsError = true
+		return result, nil
+	})
+	srv.Start()
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	t.Run("Definitions", func(t *testing.T) {
+		tools, err := client.AnthropicTools(ctx)
+		if err != nil {
+			t.Fatalf("AnthropicTools failed: %v", err)
+		}
+		var tag *AnthropicTool
+		for i := range tools {
+			if tools[i].Name == "tag" {
+				tag = &tools[i]
+			}
+		}
+		if tag == nil {
+			t.Fatalf("Expected tool tag, got %+v", tools)
+		}
+		data, err := json.Marshal(tag)
+		if err != nil {
+			t.Fatalf("Failed to marshal tool: %v", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Failed to unmarshal tool: %v", err)
+		}
+		want := map[string]any{
+			"name":        "tag",
+			"description": "Tag an item",
+			"input_schema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				},
+				"required": []any{"tags"},
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("ToolUse", func(t *testing.T) {
+		result, err := client.CallAnthropicToolUse(ctx, AnthropicToolUse{
+			Type:  "tool_use",
+			ID:    "toolu_01",
+			Name:  "tag",
+			Input: json.RawMessage(`{"tags":["a","b"]}`),
+		})
+		if err != nil {
+			t.Fatalf("CallAnthropicToolUse failed: %v", err)
+		}
+		want := &AnthropicToolResult{
+			Type:      "tool_result",
+			ToolUseID: "toolu_01",
+			Content:   []AnthropicContentBlock{{Type: "text", Text: `["a","b"]`}},
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Errorf("Expected %+v, got %+v", want, result)
+		}
+	})
+
+	t.Run("ToolError", func(t *testing.T) {
+		result, err := client.CallAnthropicToolUse(ctx, AnthropicToolUse{Type: "tool_use", ID: "toolu_02", Name: "fail"})
+		if err != nil {
+			t.Fatalf("CallAnthropicToolUse failed: %v", err)
+		}
+		if !result.IsError || len(result.Content) != 1 || result.Content[0].Text != "boom" {
+			t.Errorf("Expected an error result with text boom, got %+v", result)
+		}
+	})
+}
+
+func TestNewAnthropicToolResult(t *testing.T) {
+	result := NewAnthropicToolResult("toolu_03", &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.ImageContent{Type: "image", Data: "aGVsbG8=", MimeType: "image/png"},
+			mcp.EmbeddedResource{Type: "resource", Resource: mcp.TextResourceContents{URI: "file:///a.txt", Text: "contents"}},
+		},
+	})
+	want := []AnthropicContentBlock{
+		{Type: "image", Source: &AnthropicImageSource{Type: "base64", MediaType: "image/png", Data: "aGVsbG8="}},
+		{Type: "text", Text: "contents"},
+	}
+	if !reflect.DeepEqual(result.Content, want) {
+		t.Errorf("Expected %+v, got %+v", want, result.Content)
+	}
+}
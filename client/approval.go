@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// CallApprovalFunc approves a call to a tool that may modify its
+// environment, typically by asking the user, or returns an error refusing
+// it. Use tool.IsDestructive to tell destructive tools from additive ones.
+type CallApprovalFunc func(ctx context.Context, tool mcp.Tool, arguments map[string]any) error
+
+// WithCallApproval makes CallTool ask approve before calling tools that are
+// not annotated as read-only, as the spec recommends keeping a human in the
+// loop for those. A tool called before being listed is looked up with
+// tools/list; one the server does not list is not read-only. A refused call
+// is not sent and CallTool returns an error wrapping the one of approve.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/tools#security-considerations
+func WithCallApproval(approve CallApprovalFunc) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.callApproval = approve
+	}
+}
+
+// approveCall asks for the approval of a call to the named tool, if needed.
+func (c *HTTPClient) approveCall(ctx context.Context, name string, arguments map[string]any) error {
+	if c.callApproval == nil {
+		return nil
+	}
+	tool, ok, err := c.lookupTool(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		tool = mcp.Tool{Name: name}
+	}
+	if tool.IsReadOnly() {
+		return nil
+	}
+	if err := c.callApproval(ctx, tool, arguments); err != nil {
+		return fmt.Errorf("call to tool %s not approved: %w", name, err)
+	}
+	return nil
+}
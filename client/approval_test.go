@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestCallApproval(t *testing.T) {
+	srv := startTestServer()
+	readOnly := true
+	handler := func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	srv.AddTool(mcp.Tool{Name: "read", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: &readOnly}}, handler)
+	srv.AddTool(mcp.Tool{Name: "delete"}, handler)
+	defer srv.Close()
+
+	errRefused := errors.New("refused by user")
+	var asked []string
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithCallApproval(func(ctx context.Context, tool mcp.Tool, arguments map[string]any) error {
+		asked = append(asked, tool.Name)
+		if arguments["confirm"] != true {
+			return errRefused
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	if _, err := client.CallTool(ctx, "read", nil); err != nil {
+		t.Errorf("Expected the read-only tool to be called, got %v", err)
+	}
+	if len(asked) != 0 {
+		t.Errorf("Expected no approval for the read-only tool, got %v", asked)
+	}
+
+	sent := len(srv.Requests())
+	if _, err := client.CallTool(ctx, "delete", nil); !errors.Is(err, errRefused) {
+		t.Errorf("Expected the refusal, got %v", err)
+	}
+	if got := len(srv.Requests()); got != sent {
+		t.Errorf("Expected the refused call not to be sent, got %d requests", got-sent)
+	}
+
+	if _, err := client.CallTool(ctx, "delete", map[string]any{"confirm": true}); err != nil {
+		t.Errorf("Expected the approved call to succeed, got %v", err)
+	}
+	if len(asked) != 2 || asked[0] != "delete" {
+		t.Errorf("Expected approval to be asked twice for delete, got %v", asked)
+	}
+}
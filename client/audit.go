@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// Outcomes of audited tool calls.
+const (
+	// AuditOutcomeSuccess is a call that returned a result
+	AuditOutcomeSuccess = "success"
+	// AuditOutcomeToolError is a call whose result reports an error
+	AuditOutcomeToolError = "tool_error"
+	// AuditOutcomeError is a call that failed or was refused by the client
+	AuditOutcomeError = "error"
+)
+
+// redactedValue replaces the redacted arguments of audit records.
+const redactedValue = "[REDACTED]"
+
+// DefaultRedactedKeys are the argument names redacted from audit records by
+// default. Arguments are redacted when their name contains one of the keys,
+// ignoring case, at any depth.
+var DefaultRedactedKeys = []string{"password", "secret", "token", "apikey", "api_key", "authorization", "credential"}
+
+// AuditRecord describes a tools/call.
+type AuditRecord struct {
+	Time      time.Time      `json:"time"`
+	SessionID string         `json:"sessionId,omitempty"`
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Outcome   string         `json:"outcome"`
+	Error     string         `json:"error,omitempty"`
+	Duration  time.Duration  `json:"durationNs"`
+}
+
+// AuditSink receives a record for every tool call. Records are delivered
+// synchronously, in the goroutine calling the tool, and possibly
+// concurrently.
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+// WithAuditSink sends an AuditRecord to sink for every tool call, including
+// the calls refused by the client. Arguments whose name contains one of
+// redactKeys, or DefaultRedactedKeys if none is given, are redacted. Errors
+// of the sink are ignored.
+func WithAuditSink(sink AuditSink, redactKeys ...string) HTTPClientOption {
+	if len(redactKeys) == 0 {
+		redactKeys = DefaultRedactedKeys
+	}
+	return func(c *HTTPClient) {
+		c.auditSink = sink
+		c.auditRedactKeys = redactKeys
+	}
+}
+
+// audit records a tool call that started at start.
+func (c *HTTPClient) audit(ctx context.Context, name string, arguments map[string]any, start time.Time, result *mcp.CallToolResult, err error) {
+	if c.auditSink == nil {
+		return
+	}
+	record := AuditRecord{
+		Time:      start,
+		SessionID: c.GetSessionID(),
+		Tool:      name,
+		Arguments: redactArguments(arguments, c.auditRedactKeys),
+		Outcome:   AuditOutcomeSuccess,
+		Duration:  time.Since(start),
+	}
+	switch {
+	case err != nil:
+		record.Outcome = AuditOutcomeError
+		record.Error = err.Error()
+	case result != nil && result.IsError:
+		record.Outcome = AuditOutcomeToolError
+	}
+	_ = c.auditSink.Record(ctx, record)
+}
+
+// redactArguments returns a copy of arguments with the values of sensitive
+// keys replaced.
+func redactArguments(arguments map[string]any, keys []string) map[string]any {
+	if arguments == nil {
+		return nil
+	}
+	return redactValue(arguments, keys).(map[string]any)
+}
+
+func redactValue(value any, keys []string) any {
+	switch value := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(value))
+		for name, v := range value {
+			if sensitiveKey(name, keys) {
+				redacted[name] = redactedValue
+			} else {
+				redacted[name] = redactValue(v, keys)
+			}
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(value))
+		for i, v := range value {
+			redacted[i] = redactValue(v, keys)
+		}
+		return redacted
+	}
+	return value
+}
+
+func sensitiveKey(name string, keys []string) bool {
+	name = strings.ToLower(name)
+	for _, key := range keys {
+		if strings.Contains(name, strings.ToLower(key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// JSONLAuditSink writes audit records as JSON lines.
+type JSONLAuditSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewJSONLAuditSink returns a sink writing records to w, one JSON object per
+// line.
+func NewJSONLAuditSink(w io.Writer) *JSONLAuditSink {
+	return &JSONLAuditSink{w: w}
+}
+
+// OpenAuditLog returns a sink appending records to the JSON lines file at
+// path, created if needed. Close it when done.
+func OpenAuditLog(path string) (*JSONLAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &JSONLAuditSink{w: f, closer: f}, nil
+}
+
+// Record implements AuditSink.
+func (s *JSONLAuditSink) Record(ctx context.Context, record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file opened by OpenAuditLog.
+func (s *JSONLAuditSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
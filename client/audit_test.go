@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestAuditSink(t *testing.T) {
+	srv := startTestServer()
+	srv.AddTool(mcp.Tool{Name: "login"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv.AddTool(mcp.Tool{Name: "fail"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText("boom")
+		result.IsError = true
+		return result, nil
+	})
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("OpenAuditLog failed: %v", err)
+	}
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL},
+		WithAuditSink(sink),
+		WithToolFilter(AllowDenyTools(nil, []string{"denied"})))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	_, _ = client.CallTool(ctx, "login", map[string]any{"user": "ann", "auth": map[string]any{"Password": "hunter2"}})
+	_, _ = client.CallTool(ctx, "fail", nil)
+	_, _ = client.CallTool(ctx, "denied", nil)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer f.Close()
+	var records []AuditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Failed to decode record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+
+	login := records[0]
+	if login.Tool != "login" || login.Outcome != AuditOutcomeSuccess || login.SessionID != client.GetSessionID() || login.Time.IsZero() {
+		t.Errorf("Unexpected record: %+v", login)
+	}
+	if login.Arguments["user"] != "ann" || login.Arguments["auth"].(map[string]any)["Password"] != "[REDACTED]" {
+		t.Errorf("Expected the password to be redacted, got %v", login.Arguments)
+	}
+	if records[1].Outcome != AuditOutcomeToolError {
+		t.Errorf("Expected a tool error, got %+v", records[1])
+	}
+	if records[2].Outcome != AuditOutcomeError || records[2].Error == "" {
+		t.Errorf("Expected the refused call to be recorded, got %+v", records[2])
+	}
+}
+
+type failingSink struct{}
+
+func (failingSink) Record(ctx context.Context, record AuditRecord) error {
+	return errors.New("disk full")
+}
+
+func TestAuditSinkError(t *testing.T) {
+	srv := startTestServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithAuditSink(failingSink{}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CallTool(context.Background(), "echo", nil); err != nil {
+		t.Errorf("Expected sink errors to be ignored, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// listKey identifies a cached page of a list method.
+type listKey struct {
+	method mcp.MCPMethod
+	cursor mcp.Cursor
+}
+
+// listCache holds the raw results of list requests until the server says the list changed.
+type listCache struct {
+	mu    sync.Mutex
+	pages map[listKey][]byte
+	// generation is bumped on every invalidation so that a response fetched
+	// before it is not stored afterwards
+	generation map[mcp.MCPMethod]uint64
+}
+
+// listChanges maps each list_changed notification to the list methods it invalidates.
+var listChanges = map[mcp.MCPMethod][]mcp.MCPMethod{
+	mcp.MethodNotificationToolsListChanged:     {mcp.MethodToolsList},
+	mcp.MethodNotificationPromptsListChanged:   {mcp.MethodPromptsList},
+	mcp.MethodNotificationResourcesListChanged: {mcp.MethodResourcesList, mcp.MethodResourcesTemplatesList},
+}
+
+// WithListCaching enables or disables caching of tools/list, prompts/list,
+// resources/list and resources/templates/list results. Caching is enabled by
+// default and only applies to lists the server declared listChanged for, since
+// their notifications are what invalidates the cache.
+func WithListCaching(enabled bool) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.skipListCache = !enabled
+	}
+}
+
+// Refresh drops every cached list, so that the next List call asks the server.
+// Use it with servers that change their lists without notifying.
+func (c *HTTPClient) Refresh() {
+	for _, methods := range listChanges {
+		c.listCache.invalidate(methods...)
+	}
+	c.toolIndex.reset()
+}
+
+// watchListChanges invalidates the cached lists when the server notifies a change.
+func (c *HTTPClient) watchListChanges() {
+	for notification, methods := range listChanges {
+		c.Subscribe(string(notification), func(mcp.Notification) {
+			c.listCache.invalidate(methods...)
+		})
+	}
+	c.Subscribe(string(mcp.MethodNotificationToolsListChanged), func(mcp.Notification) {
+		c.toolIndex.reset()
+	})
+}
+
+// invalidate drops the cached pages of methods.
+func (lc *listCache) invalidate(methods ...mcp.MCPMethod) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if lc.generation == nil {
+		lc.generation = map[mcp.MCPMethod]uint64{}
+	}
+	for _, method := range methods {
+		lc.generation[method]++
+		for key := range lc.pages {
+			if key.method == method {
+				delete(lc.pages, key)
+			}
+		}
+	}
+}
+
+// list sends a paginated list request, answering from the cache when possible.
+func (c *HTTPClient) list(ctx context.Context, method mcp.MCPMethod, cursor mcp.Cursor) ([]byte, error) {
+	if !c.listCacheable(method) {
+		return c.Request(ctx, string(method), mcp.PaginatedRequest{Cursor: cursor})
+	}
+
+	key := listKey{method: method, cursor: cursor}
+	lc := &c.listCache
+	lc.mu.Lock()
+	if raw, ok := lc.pages[key]; ok {
+		lc.mu.Unlock()
+		return raw, nil
+	}
+	generation := lc.generation[method]
+	lc.mu.Unlock()
+
+	raw, err := c.Request(ctx, string(method), mcp.PaginatedRequest{Cursor: cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if lc.generation[method] == generation {
+		if lc.pages == nil {
+			lc.pages = map[listKey][]byte{}
+		}
+		lc.pages[key] = raw
+	}
+	return raw, nil
+}
+
+// listCacheable reports whether the results of method may be cached, that is
+// whether the server notifies when the list changes.
+func (c *HTTPClient) listCacheable(method mcp.MCPMethod) bool {
+	if c.skipListCache {
+		return false
+	}
+	capabilities := c.ServerCapabilities()
+	switch method {
+	case mcp.MethodToolsList:
+		return capabilities.Tools != nil && capabilities.Tools.ListChanged
+	case mcp.MethodPromptsList:
+		return capabilities.Prompts != nil && capabilities.Prompts.ListChanged
+	case mcp.MethodResourcesList, mcp.MethodResourcesTemplatesList:
+		return capabilities.Resources != nil && capabilities.Resources.ListChanged
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestListCaching(t *testing.T) {
+	srv := startTestServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, nil)
+	srv.AddPrompt(mcp.Prompt{Name: "greet"}, nil)
+	srv.SetCapabilities(mcp.ServerCapabilities{
+		Tools:   &mcp.ToolsCapabilities{ListChanged: true},
+		Prompts: &mcp.PromptsCapabilities{},
+	})
+	srv.SetBehavior("ping", mcptest.Behavior{Notifications: []mcp.JSONRPCNotification{
+		{JSONRPC: mcp.JSONRPC_VERSION, Method: "notifications/tools/list_changed"},
+	}})
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	count := func(method string) int {
+		n := 0
+		for _, request := range srv.Requests() {
+			if request.Method == method {
+				n++
+			}
+		}
+		return n
+	}
+	listTools := func() {
+		t.Helper()
+		result, err := client.ListTools(ctx, "")
+		if err != nil {
+			t.Fatalf("ListTools failed: %v", err)
+		}
+		if len(result.Tools) != 1 || result.Tools[0].Name != "echo" {
+			t.Errorf("Unexpected tools: %+v", result.Tools)
+		}
+	}
+
+	listTools()
+	listTools()
+	if got := count("tools/list"); got != 1 {
+		t.Errorf("Expected the second list to be cached, got %d requests", got)
+	}
+
+	// The ping response carries a tools list_changed notification
+	if _, err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	listTools()
+	if got := count("tools/list"); got != 2 {
+		t.Errorf("Expected list_changed to invalidate the cache, got %d requests", got)
+	}
+
+	client.Refresh()
+	listTools()
+	if got := count("tools/list"); got != 3 {
+		t.Errorf("Expected Refresh to invalidate the cache, got %d requests", got)
+	}
+
+	t.Run("without listChanged", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if _, err := client.ListPrompts(ctx, ""); err != nil {
+				t.Fatalf("ListPrompts failed: %v", err)
+			}
+		}
+		if got := count("prompts/list"); got != 2 {
+			t.Errorf("Expected prompts not to be cached, got %d requests", got)
+		}
+	})
+}
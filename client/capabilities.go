@@ -0,0 +1,123 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// ErrCapabilityNotSupported is matched by errors.Is for every
+// *CapabilityNotSupportedError.
+var ErrCapabilityNotSupported = errors.New("capability not supported by server")
+
+// CapabilityNotSupportedError is returned, without contacting the server, by
+// client methods whose capability the server did not declare during initialization.
+type CapabilityNotSupportedError struct {
+	// Method is the MCP method that was not sent
+	Method string
+	// Capability is the missing server capability, e.g. "resources.subscribe"
+	Capability string
+}
+
+func (e *CapabilityNotSupportedError) Error() string {
+	return fmt.Sprintf("%s: server does not support %s", e.Method, e.Capability)
+}
+
+func (e *CapabilityNotSupportedError) Is(target error) bool {
+	return target == ErrCapabilityNotSupported
+}
+
+// WithCapabilityChecks enables or disables failing fast on methods the server
+// did not declare support for. Checks are enabled by default; disable them for
+// servers that under-declare their capabilities.
+func WithCapabilityChecks(enabled bool) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.skipCapabilityChecks = !enabled
+	}
+}
+
+// WithExperimental declares a non-standard capability under "experimental".
+// Options.Capabilities entries under the same name are replaced.
+func WithExperimental(name string, value map[string]any) HTTPClientOption {
+	return func(c *HTTPClient) {
+		if c.experimental == nil {
+			c.experimental = map[string]any{}
+		}
+		c.experimental[name] = value
+	}
+}
+
+// clientCapabilities builds the capabilities sent in the initialize request:
+// Options.Capabilities, overlaid with the capabilities of the handlers
+// registered through WithRoots, WithSampling and WithExperimental.
+func (c *HTTPClient) clientCapabilities() map[string]any {
+	capabilities := map[string]any{}
+	if c.config != nil && c.config.Options != nil {
+		for name, value := range c.config.Options.Capabilities {
+			capabilities[name] = value
+		}
+	}
+
+	if c.roots != nil {
+		capabilities["roots"] = mcp.RootsCapabilities{ListChanged: c.roots.listChanged}
+	}
+	if c.samplingHandler != nil {
+		if _, ok := capabilities["sampling"]; !ok {
+			capabilities["sampling"] = mcp.SamplingCapabilities{}
+		}
+	}
+	if len(c.experimental) > 0 {
+		experimental := map[string]any{}
+		if existing, ok := capabilities["experimental"].(map[string]any); ok {
+			for name, value := range existing {
+				experimental[name] = value
+			}
+		}
+		for name, value := range c.experimental {
+			experimental[name] = value
+		}
+		capabilities["experimental"] = experimental
+	}
+	return capabilities
+}
+
+// requiredCapability returns the server capability method depends on, and
+// whether the server declared it. Methods with no requirement are always supported.
+func requiredCapability(method mcp.MCPMethod, capabilities mcp.ServerCapabilities) (string, bool) {
+	switch method {
+	case mcp.MethodToolsList, mcp.MethodToolsCall:
+		return "tools", capabilities.Tools != nil
+	case mcp.MethodPromptsList, mcp.MethodPromptsGet:
+		return "prompts", capabilities.Prompts != nil
+	case mcp.MethodResourcesList, mcp.MethodResourcesTemplatesList, mcp.MethodResourcesRead:
+		return "resources", capabilities.Resources != nil
+	case mcp.MethodResourcesSubscribe, mcp.MethodResourcesUnsubscribe:
+		return "resources.subscribe", capabilities.Resources != nil && capabilities.Resources.Subscribe
+	case mcp.MethodLoggingSetLevel:
+		return "logging", capabilities.Logging != nil
+	case mcp.MethodCompleteList:
+		return "completions", capabilities.Completions != nil
+	}
+	return "", true
+}
+
+// checkCapability fails with a *CapabilityNotSupportedError if the server did
+// not declare the capability method needs. Before initialization nothing is known,
+// so every method is allowed.
+func (c *HTTPClient) checkCapability(method mcp.MCPMethod) error {
+	if c.skipCapabilityChecks {
+		return nil
+	}
+	c.initMu.RLock()
+	initResult := c.initResult
+	c.initMu.RUnlock()
+	if initResult == nil {
+		return nil
+	}
+
+	if capability, ok := requiredCapability(method, initResult.Capabilities); !ok {
+		return &CapabilityNotSupportedError{Method: string(method), Capability: capability}
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestCapabilityChecks(t *testing.T) {
+	srv := startTestServer()
+	srv.AddResource(mcp.Resource{URI: "file:///a.txt", Name: "a"}, mcp.TextResourceContents{URI: "file:///a.txt", Text: "a"})
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	sent := len(srv.Requests())
+
+	tests := map[string]func() error{
+		"prompts": func() error {
+			_, err := client.ListPrompts(ctx, "")
+			return err
+		},
+		"tools": func() error {
+			_, err := client.CallTool(ctx, "echo", nil)
+			return err
+		},
+		"resources.subscribe": func() error {
+			return client.SubscribeResource(ctx, "file:///a.txt")
+		},
+		"completions": func() error {
+			_, err := client.Complete(ctx, mcp.NewResourceReference("file:///{name}"), "name", "a")
+			return err
+		},
+	}
+	for capability, call := range tests {
+		t.Run(capability, func(t *testing.T) {
+			err := call()
+			if !errors.Is(err, ErrCapabilityNotSupported) {
+				t.Fatalf("Expected ErrCapabilityNotSupported, got %v", err)
+			}
+			var capErr *CapabilityNotSupportedError
+			if !errors.As(err, &capErr) || capErr.Capability != capability {
+				t.Errorf("Expected missing capability %q, got %v", capability, err)
+			}
+		})
+	}
+	if got := len(srv.Requests()); got != sent {
+		t.Errorf("Expected no requests to be sent, got %d", got-sent)
+	}
+
+	// Declared capabilities go through
+	result, err := client.ReadResource(ctx, "file:///a.txt")
+	if err != nil {
+		t.Fatalf("ReadResource failed: %v", err)
+	}
+	if text, ok := result.Contents[0].(mcp.TextResourceContents); !ok || text.Text != "a" {
+		t.Errorf("Unexpected contents: %+v", result.Contents)
+	}
+}
+
+func TestCapabilityChecksDisabled(t *testing.T) {
+	srv := startTestServer()
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithCapabilityChecks(false))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	// mcptest answers prompts/list even without any prompts registered
+	if _, err := client.ListPrompts(context.Background(), ""); err != nil {
+		t.Errorf("Expected the request to be sent, got %v", err)
+	}
+}
+
+func TestClientCapabilities(t *testing.T) {
+	srv := startTestServer()
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{
+		BaseURL: srv.URL,
+		Capabilities: map[string]interface{}{
+			"experimental": map[string]any{"a": map[string]any{}},
+			"custom":       true,
+		},
+	},
+		WithRoots(true),
+		WithSampling(func(ctx context.Context, request *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			return nil, nil
+		}),
+		WithExperimental("b", map[string]any{"v": 1}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	var params struct {
+		Capabilities map[string]any `json:"capabilities"`
+	}
+	if err := json.Unmarshal(srv.Requests()[0].Params, &params); err != nil {
+		t.Fatalf("Failed to decode initialize params: %v", err)
+	}
+	want := map[string]any{
+		"roots":        map[string]any{"listChanged": true},
+		"sampling":     map[string]any{},
+		"experimental": map[string]any{"a": map[string]any{}, "b": map[string]any{"v": float64(1)}},
+		"custom":       true,
+	}
+	if !reflect.DeepEqual(params.Capabilities, want) {
+		t.Errorf("Expected capabilities %v, got %v", want, params.Capabilities)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		srv := startTestServer()
+		defer srv.Close()
+		client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer client.Close()
+
+		var params struct {
+			Capabilities map[string]any `json:"capabilities"`
+		}
+		if err := json.Unmarshal(srv.Requests()[0].Params, &params); err != nil {
+			t.Fatalf("Failed to decode initialize params: %v", err)
+		}
+		if len(params.Capabilities) != 0 {
+			t.Errorf("Expected no capabilities, got %v", params.Capabilities)
+		}
+	})
+}
+
+func TestServerRequests(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.HandleMethod("work", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		if err := mcptest.SendRequest(ctx, mcp.NewIntRequestId(1), "roots/list", nil); err != nil {
+			return nil, err
+		}
+		err := mcptest.SendRequest(ctx, mcp.NewIntRequestId(2), "sampling/createMessage", map[string]any{
+			"messages":  []any{map[string]any{"role": "user", "content": map[string]any{"type": "text", "text": "hi"}}},
+			"maxTokens": 10,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{}, nil
+	})
+	srv.SetBehavior("work", mcptest.Behavior{SSE: true})
+	srv.Start()
+	defer srv.Close()
+
+	root := mcp.Root{URI: "file:///project", Name: "project"}
+	var sampled *mcp.CreateMessageRequest
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL},
+		WithRoots(true, root),
+		WithSampling(func(ctx context.Context, request *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			sampled = request
+			return &mcp.CreateMessageResult{
+				SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("hello")},
+				Model:           "test",
+			}, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if _, err := client.Request(ctx, "work", nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	answers := map[mcp.RequestId]mcptest.Request{}
+	for _, request := range srv.Requests() {
+		if request.IsResponse() {
+			answers[request.ID] = request
+		}
+	}
+
+	var roots mcp.ListRootsResult
+	if err := json.Unmarshal(answers[mcp.NewIntRequestId(1)].Result, &roots); err != nil {
+		t.Fatalf("Failed to decode roots: %v", err)
+	}
+	if len(roots.Roots) != 1 || roots.Roots[0] != root {
+		t.Errorf("Expected roots [%v], got %v", root, roots.Roots)
+	}
+
+	if sampled == nil || sampled.Params.MaxTokens != 10 || len(sampled.Params.Messages) != 1 {
+		t.Fatalf("Expected the sampling request to be decoded, got %+v", sampled)
+	}
+	var message mcp.CreateMessageResult
+	if err := json.Unmarshal(answers[mcp.NewIntRequestId(2)].Result, &message); err != nil {
+		t.Fatalf("Failed to decode sampling result: %v", err)
+	}
+	if text, ok := message.Content.(mcp.TextContent); !ok || text.Text != "hello" || message.Model != "test" {
+		t.Errorf("Unexpected sampling result: %+v", message)
+	}
+
+	t.Run("SetRoots", func(t *testing.T) {
+		if err := client.SetRoots(ctx, nil); err != nil {
+			t.Fatalf("SetRoots failed: %v", err)
+		}
+		requests := srv.Requests()
+		if last := requests[len(requests)-1]; last.Method != string(mcp.MethodNotificationRootsListChanged) {
+			t.Errorf("Expected a roots list_changed notification, got %q", last.Method)
+		}
+		if got := client.Roots(); len(got) != 0 {
+			t.Errorf("Expected no roots, got %v", got)
+		}
+
+		if err := client.SetRoots(ctx, []mcp.Root{{URI: "file:///project/src/../"}}); err != nil {
+			t.Fatalf("SetRoots failed: %v", err)
+		}
+		if got := client.Roots(); len(got) != 1 || got[0].URI != "file:///project" {
+			t.Errorf("Expected the normalized root, got %v", got)
+		}
+		if err := client.SetRoots(ctx, []mcp.Root{{URI: "https://example.com"}}); !errors.Is(err, mcp.ErrInvalidRoot) {
+			t.Errorf("Expected ErrInvalidRoot, got %v", err)
+		}
+		if got := client.Roots(); len(got) != 1 {
+			t.Errorf("Expected the roots to be left unchanged, got %v", got)
+		}
+	})
+
+	t.Run("WithRoots", func(t *testing.T) {
+		client, err := NewHTTPClient(&Options{BaseURL: srv.URL, LazyInit: true}, WithRoots(false, mcp.Root{URI: "file:///project/src/../"}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer client.Close()
+		if got := client.Roots(); len(got) != 1 || got[0].URI != "file:///project" {
+			t.Errorf("Expected the normalized root, got %v", got)
+		}
+
+		if _, err := NewHTTPClient(&Options{BaseURL: srv.URL, LazyInit: true}, WithRoots(false, mcp.Root{URI: "https://example.com"})); !errors.Is(err, mcp.ErrInvalidRoot) {
+			t.Errorf("Expected ErrInvalidRoot, got %v", err)
+		}
+	})
+}
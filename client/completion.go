@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// CompleteOption configures Complete.
+type CompleteOption func(params *mcp.CompleteRequestParams)
+
+// WithCompletionContext sends the values of the arguments already filled in,
+// by name, so that the server can complete depending on them. Servers
+// predating the 2025-06-18 revision ignore them.
+func WithCompletionContext(arguments map[string]string) CompleteOption {
+	return func(params *mcp.CompleteRequestParams) {
+		params.Context = &mcp.CompletionContext{Arguments: arguments}
+	}
+}
+
+// Complete asks the server for values completing the argument of a prompt or
+// resource template. ref is an mcp.PromptReference or mcp.ResourceReference,
+// see mcp.NewPromptReference and mcp.NewResourceReference; value is what was
+// typed so far.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/utilities/completion
+func (c *HTTPClient) Complete(ctx context.Context, ref mcp.Reference, argument, value string, opts ...CompleteOption) (*mcp.CompleteResult, error) {
+	if err := c.checkCapability(mcp.MethodCompleteList); err != nil {
+		return nil, err
+	}
+	params := mcp.CompleteRequestParams{
+		Ref:      ref,
+		Argument: mcp.CompleteArgument{Name: argument, Value: value},
+	}
+	for _, opt := range opts {
+		opt(&params)
+	}
+	raw, err := c.Request(ctx, string(mcp.MethodCompleteList), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.CompleteResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal completion: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestComplete(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.HandleMethod(string(mcp.MethodCompleteList), func(ctx context.Context, request *mcptest.Request) (any, error) {
+		var params struct {
+			Ref      mcp.PromptReference `json:"ref"`
+			Argument struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"argument"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.Ref.Type != "ref/prompt" || params.Ref.Name != "greet" || params.Argument.Name != "language" {
+			return nil, mcp.NewError(mcp.ErrorInvalidParams, "unexpected params")
+		}
+		var result mcp.CompleteResult
+		for _, language := range []string{"go", "gleam", "python"} {
+			if strings.HasPrefix(language, params.Argument.Value) {
+				result.Completion.Values = append(result.Completion.Values, language)
+			}
+		}
+		result.Completion.Total = len(result.Completion.Values)
+		return result, nil
+	})
+	srv.Start()
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.Complete(context.Background(), mcp.NewPromptReference("greet"), "language", "g")
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if want := []string{"go", "gleam"}; !reflect.DeepEqual(result.Completion.Values, want) || result.Completion.Total != 2 {
+		t.Errorf("Expected %q, got %+v", want, result.Completion)
+	}
+}
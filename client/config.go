@@ -0,0 +1,146 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ServerConfig describes how to reach a server in an mcpServers config file.
+// Command is set for stdio servers, URL for Streamable HTTP servers.
+type ServerConfig struct {
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// ServersConfig is the "mcpServers" config file format used by Claude Desktop
+// and other hosts, mapping server names to their ServerConfig.
+type ServersConfig struct {
+	MCPServers map[string]ServerConfig `json:"mcpServers"`
+}
+
+// LoadConfig reads an mcpServers config file. See ParseConfig.
+func LoadConfig(path string) (*ServersConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig parses an mcpServers config. $VAR and ${VAR} references to
+// environment variables in commands, arguments, env values, URLs and headers
+// are expanded, so that secrets can be kept out of the file. A reference to an
+// unset variable is an error.
+func ParseConfig(data []byte) (*ServersConfig, error) {
+	var config ServersConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	for name, server := range config.MCPServers {
+		expanded, err := server.expand()
+		if err != nil {
+			return nil, fmt.Errorf("server %s: %w", name, err)
+		}
+		if (expanded.Command == "") == (expanded.URL == "") {
+			return nil, fmt.Errorf("server %s: exactly one of command and url must be set", name)
+		}
+		config.MCPServers[name] = expanded
+	}
+	return &config, nil
+}
+
+// expand returns a copy of the config with environment variables expanded.
+func (c ServerConfig) expand() (ServerConfig, error) {
+	var missing []string
+	expand := func(s string) string {
+		return os.Expand(s, func(name string) string {
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+			return value
+		})
+	}
+	expandMap := func(m map[string]string) map[string]string {
+		if m == nil {
+			return nil
+		}
+		expanded := make(map[string]string, len(m))
+		for key, value := range m {
+			expanded[key] = expand(value)
+		}
+		return expanded
+	}
+
+	expanded := ServerConfig{
+		Command: expand(c.Command),
+		Env:     expandMap(c.Env),
+		URL:     expand(c.URL),
+		Headers: expandMap(c.Headers),
+	}
+	for _, arg := range c.Args {
+		expanded.Args = append(expanded.Args, expand(arg))
+	}
+	if len(missing) > 0 {
+		return ServerConfig{}, fmt.Errorf("undefined environment variables: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// Connect creates a client for the server, starting it first for stdio servers.
+// options may be nil; its BaseURL and Headers are replaced by the config's.
+func (c ServerConfig) Connect(options *Options, opts ...HTTPClientOption) (*HTTPClient, error) {
+	var o Options
+	if options != nil {
+		o = *options
+	}
+	if c.Command != "" {
+		env := make([]string, 0, len(c.Env))
+		for key, value := range c.Env {
+			env = append(env, key+"="+value)
+		}
+		sort.Strings(env)
+		return NewStdioClient(c.Command, c.Args, env, &o, opts...)
+	}
+	o.BaseURL = c.URL
+	o.Headers = c.Headers
+	return NewHTTPClient(&o, opts...)
+}
+
+// NewManagerFromConfig connects to every server of config and returns a
+// Manager holding them under their config names. If any server fails, the
+// ones already connected are closed and the errors are returned.
+func NewManagerFromConfig(config *ServersConfig, options *Options, opts ...HTTPClientOption) (*Manager, error) {
+	names := make([]string, 0, len(config.MCPServers))
+	for name := range config.MCPServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manager := NewManager()
+	var errs []error
+	for _, name := range names {
+		client, err := config.MCPServers[name].Connect(options, opts...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("server %s: %w", name, err))
+			continue
+		}
+		if err := manager.Add(name, client); err != nil {
+			client.Close()
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		manager.Close()
+		return nil, errors.Join(errs...)
+	}
+	return manager, nil
+}
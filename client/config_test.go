@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+// TestStdioServerProcess is not a real test: it serves a stdio MCP server
+// when the test binary is started as a subprocess by the tests below.
+func TestStdioServerProcess(t *testing.T) {
+	if os.Getenv("MCPGOPHER_TEST_STDIO_SERVER") != "1" {
+		t.Skip("helper process")
+	}
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "env"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(os.Getenv("MCPGOPHER_TEST_TOKEN")), nil
+	})
+	_ = srv.ServeStdio(context.Background(), os.Stdin, os.Stdout)
+	os.Exit(0)
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Setenv("MCPGOPHER_TEST_TOKEN", "secret")
+	config, err := ParseConfig([]byte(`{"mcpServers": {
+		"local": {"command": "server", "args": ["--token=$MCPGOPHER_TEST_TOKEN"], "env": {"TOKEN": "${MCPGOPHER_TEST_TOKEN}"}},
+		"remote": {"url": "https://example.com/mcp", "headers": {"Authorization": "Bearer ${MCPGOPHER_TEST_TOKEN}"}}
+	}}`))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	local := config.MCPServers["local"]
+	if local.Args[0] != "--token=secret" || local.Env["TOKEN"] != "secret" {
+		t.Errorf("Expected variables to be expanded, got %+v", local)
+	}
+	if remote := config.MCPServers["remote"]; remote.Headers["Authorization"] != "Bearer secret" {
+		t.Errorf("Expected variables to be expanded, got %+v", remote)
+	}
+
+	invalid := map[string]string{
+		"undefined variable": `{"mcpServers": {"a": {"url": "${MCPGOPHER_TEST_UNDEFINED}"}}}`,
+		"no transport":       `{"mcpServers": {"a": {}}}`,
+		"two transports":     `{"mcpServers": {"a": {"command": "x", "url": "http://x"}}}`,
+		"malformed":          `{"mcpServers": []}`,
+	}
+	for name, data := range invalid {
+		if _, err := ParseConfig([]byte(data)); err == nil {
+			t.Errorf("Expected %s to fail", name)
+		}
+	}
+}
+
+func TestNewManagerFromConfig(t *testing.T) {
+	srv := startTestServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, nil)
+	defer srv.Close()
+
+	t.Setenv("MCPGOPHER_TEST_URL", srv.URL)
+	data, _ := json.Marshal(map[string]any{"mcpServers": map[string]any{
+		"http": map[string]any{"url": "$MCPGOPHER_TEST_URL"},
+		"stdio": map[string]any{
+			"command": os.Args[0],
+			"args":    []string{"-test.run=^TestStdioServerProcess$"},
+			"env":     map[string]string{"MCPGOPHER_TEST_STDIO_SERVER": "1", "MCPGOPHER_TEST_TOKEN": "from-env"},
+		},
+	}})
+	config, err := ParseConfig(data)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	manager, err := NewManagerFromConfig(config, nil)
+	if err != nil {
+		t.Fatalf("NewManagerFromConfig failed: %v", err)
+	}
+	defer manager.Close()
+
+	ctx := context.Background()
+	tools, err := manager.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "http.echo" || names[1] != "stdio.env" {
+		t.Errorf("Unexpected tools: %v", names)
+	}
+
+	result, err := manager.CallTool(ctx, "stdio.env", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "from-env" {
+		t.Errorf("Expected the server to see its env, got %+v", result.Content)
+	}
+
+	t.Run("failure", func(t *testing.T) {
+		config.MCPServers["broken"] = ServerConfig{Command: "/nonexistent/mcp-server"}
+		if _, err := NewManagerFromConfig(config, nil); err == nil {
+			t.Errorf("Expected a server that cannot start to fail")
+		}
+	})
+}
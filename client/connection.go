@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// connection tracks the connection state of a client and reports its changes.
+type connection struct {
+	state        atomic.Int32
+	onChange     transport.ConnectionStateHandler
+	onDisconnect func(err error)
+	onReconnect  func()
+	// recoverSessions re-initializes the sessions the server forgets
+	recoverSessions bool
+}
+
+// WithConnectionStateHandler sets the function called on every change of the
+// connection state: the listening stream being lost and reopened, the session
+// expiring and being re-established, or reconnecting being given up. It runs
+// on the goroutine that observed the change and should not block.
+func WithConnectionStateHandler(handler transport.ConnectionStateHandler) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.connection.onChange = handler
+	}
+}
+
+// WithConnectionCallbacks sets the functions called when the connection to
+// the server is lost, with the cause, and when it is re-established. Either
+// may be nil.
+func WithConnectionCallbacks(onDisconnect func(err error), onReconnect func()) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.connection.onDisconnect = onDisconnect
+		c.connection.onReconnect = onReconnect
+	}
+}
+
+// WithSessionRecovery makes the client initialize a new session when the
+// server answers that it forgot the current one, as a restarted server does,
+// then send the failed request again. Without it the request fails with
+// transport.ErrSessionTerminated.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/transports#session-management
+func WithSessionRecovery() HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.connection.recoverSessions = true
+	}
+}
+
+// ConnectionState returns the state of the connection to the server. Unlike
+// State, it changes while the session lasts, as the server stops and starts
+// answering.
+func (c *HTTPClient) ConnectionState() transport.ConnectionState {
+	return transport.ConnectionState(c.connection.state.Load())
+}
+
+// setConnectionState records a new connection state and reports it if it
+// changed.
+func (c *HTTPClient) setConnectionState(state transport.ConnectionState, err error) {
+	previous := transport.ConnectionState(c.connection.state.Swap(int32(state)))
+	if previous == state {
+		return
+	}
+	if c.connection.onChange != nil {
+		c.connection.onChange(state, err)
+	}
+	switch {
+	case state == transport.ConnectionLost && previous == transport.ConnectionConnected:
+		if c.connection.onDisconnect != nil {
+			c.connection.onDisconnect(err)
+		}
+	case state == transport.ConnectionConnected && previous != transport.ConnectionConnecting:
+		if c.connection.onReconnect != nil {
+			c.connection.onReconnect()
+		}
+		if c.offlineQueue != nil {
+			c.offlineQueue.reachable()
+		}
+	}
+}
+
+// watchConnection follows the connection state of transports reporting it.
+func (c *HTTPClient) watchConnection() {
+	if t, ok := c.transport.(interface {
+		SetConnectionStateHandler(transport.ConnectionStateHandler)
+	}); ok {
+		t.SetConnectionStateHandler(c.setConnectionState)
+	}
+}
+
+// reestablishSession initializes a new session after the server forgot the
+// previous one, unless another request did already.
+func (c *HTTPClient) reestablishSession(ctx context.Context) error {
+	c.lifecycleMu.Lock()
+	defer c.lifecycleMu.Unlock()
+	// The transport forgets the terminated session ID
+	if t, ok := c.transport.(interface{ GetSessionId() string }); ok && t.GetSessionId() != "" {
+		return nil
+	}
+	c.setConnectionState(transport.ConnectionReconnecting, nil)
+	if err := c.initialize(ctx); err != nil {
+		err = fmt.Errorf("failed to re-establish session: %w", err)
+		c.setConnectionState(transport.ConnectionFailed, err)
+		return err
+	}
+	c.setConnectionState(transport.ConnectionConnected, nil)
+	return nil
+}
+
+// recoversSession reports whether a request failed because the server forgot
+// the session, and is to be sent again in a new one.
+func (c *HTTPClient) recoversSession(method string, err error) bool {
+	return c.connection.recoverSessions && c.State() == StateReady &&
+		errors.Is(err, transport.ErrSessionTerminated) && method != string(mcp.MethodInitialize)
+}
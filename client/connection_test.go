@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestConnectionState(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo", Description: "echo"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv.Start()
+	defer srv.Close()
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var states []transport.ConnectionState
+	var disconnects []error
+	reconnects := 0
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL},
+		WithConnectionStateHandler(func(state transport.ConnectionState, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			states = append(states, state)
+		}),
+		WithConnectionCallbacks(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			disconnects = append(disconnects, err)
+		}, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			reconnects++
+		}),
+		WithSessionRecovery(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	t.Run("Connected", func(t *testing.T) {
+		if got := client.ConnectionState(); got != transport.ConnectionConnected {
+			t.Errorf("Expected %s, got %s", transport.ConnectionConnected, got)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if reconnects != 0 || len(disconnects) != 0 {
+			t.Errorf("Expected no callbacks on the first connection, got %d reconnects and %v", reconnects, disconnects)
+		}
+	})
+
+	t.Run("SessionReestablished", func(t *testing.T) {
+		session := srv.SessionID()
+		srv.ExpireSession()
+		if _, err := client.ListTools(ctx, ""); err != nil {
+			t.Fatalf("Expected the request to succeed in a new session, got %v", err)
+		}
+		if srv.SessionID() == "" || srv.SessionID() == session {
+			t.Errorf("Expected a new session, got %q", srv.SessionID())
+		}
+		if got := client.ConnectionState(); got != transport.ConnectionConnected {
+			t.Errorf("Expected %s, got %s", transport.ConnectionConnected, got)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		want := []transport.ConnectionState{
+			transport.ConnectionConnected,
+			transport.ConnectionLost,
+			transport.ConnectionReconnecting,
+			transport.ConnectionConnected,
+		}
+		if len(states) != len(want) {
+			t.Fatalf("Expected states %v, got %v", want, states)
+		}
+		for i := range want {
+			if states[i] != want[i] {
+				t.Fatalf("Expected states %v, got %v", want, states)
+			}
+		}
+		if len(disconnects) != 1 || !errors.Is(disconnects[0], transport.ErrSessionTerminated) {
+			t.Errorf("Expected one disconnect caused by the terminated session, got %v", disconnects)
+		}
+		if reconnects != 1 {
+			t.Errorf("Expected one reconnect, got %d", reconnects)
+		}
+	})
+
+	t.Run("WithoutRecovery", func(t *testing.T) {
+		other, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer other.Close()
+		srv.ExpireSession()
+		if _, err := other.Ping(ctx); !errors.Is(err, transport.ErrSessionTerminated) {
+			t.Errorf("Expected ErrSessionTerminated, got %v", err)
+		}
+		if got := other.ConnectionState(); got != transport.ConnectionLost {
+			t.Errorf("Expected %s, got %s", transport.ConnectionLost, got)
+		}
+	})
+
+	t.Run("GaveUp", func(t *testing.T) {
+		srv.ExpireSession()
+		srv.SetBehavior("initialize", mcptest.Behavior{Error: mcp.NewError(mcp.ErrorInternalError, "down")})
+		defer srv.SetBehavior("initialize", mcptest.Behavior{})
+		_, err := client.ListTools(ctx, "")
+		if err == nil {
+			t.Fatal("Expected the request to fail")
+		}
+		if got := client.ConnectionState(); got != transport.ConnectionFailed {
+			t.Errorf("Expected %s, got %s", transport.ConnectionFailed, got)
+		}
+	})
+}
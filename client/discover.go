@@ -0,0 +1,258 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// endpointPaths are the paths Discover probes for the MCP endpoint of an
+// origin given without a path.
+var endpointPaths = []string{"/mcp", "/"}
+
+// ProtectedResourceMetadata is the OAuth protected resource metadata (RFC
+// 9728) of an MCP server, naming the authorization servers issuing its tokens.
+type ProtectedResourceMetadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers"`
+	ScopesSupported        []string `json:"scopes_supported,omitempty"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported,omitempty"`
+	ResourceDocumentation  string   `json:"resource_documentation,omitempty"`
+}
+
+// AuthorizationServerMetadata is the OAuth authorization server metadata (RFC
+// 8414) of the issuer of tokens for an MCP server.
+type AuthorizationServerMetadata struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	RegistrationEndpoint          string   `json:"registration_endpoint,omitempty"`
+	ScopesSupported               []string `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported        []string `json:"response_types_supported,omitempty"`
+	GrantTypesSupported           []string `json:"grant_types_supported,omitempty"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// Discovery is what Discover found out about an MCP server.
+type Discovery struct {
+	// Options connect to the MCP endpoint
+	Options *Options
+	// Resource is the protected resource metadata of the server, nil if it
+	// publishes none
+	Resource *ProtectedResourceMetadata
+	// AuthorizationServer is the metadata of the authorization server of the
+	// server, nil if it does not require authorization
+	AuthorizationServer *AuthorizationServerMetadata
+}
+
+// DiscoverOption configures Discover.
+type DiscoverOption func(*discoverConfig)
+
+type discoverConfig struct {
+	httpClient *http.Client
+}
+
+// WithDiscoveryHTTPClient makes Discover send its requests with client,
+// http.DefaultClient by default. The returned Options connect with it too.
+func WithDiscoveryHTTPClient(client *http.Client) DiscoverOption {
+	return func(c *discoverConfig) {
+		c.httpClient = client
+	}
+}
+
+// Discover probes origin, such as "example.com" or a full endpoint URL, for
+// its MCP endpoint and the discovery documents of the authorization spec. An
+// origin without a path is probed at /mcp, then at its root. The endpoint
+// answering 401 or publishing protected resource metadata requires
+// authorization; its authorization server metadata is then fetched too, and
+// both are set in the returned Options.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/authorization
+func Discover(ctx context.Context, origin string, opts ...DiscoverOption) (*Discovery, error) {
+	config := &discoverConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	httpClient := config.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if !strings.Contains(origin, "://") {
+		origin = "https://" + origin
+	}
+	base, err := url.Parse(origin)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid origin %q", origin)
+	}
+
+	paths := []string{base.Path}
+	if base.Path == "" || base.Path == "/" {
+		paths = endpointPaths
+	}
+	var endpoint *url.URL
+	var resp *http.Response
+	for _, p := range paths {
+		u := *base
+		u.Path = p
+		resp, err = probeEndpoint(ctx, httpClient, u.String())
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusNotFound {
+			endpoint = &u
+			break
+		}
+	}
+	if endpoint == nil {
+		return nil, fmt.Errorf("no MCP endpoint found at %s", origin)
+	}
+
+	d := &Discovery{}
+	unauthorized := resp.StatusCode == http.StatusUnauthorized
+
+	// The challenge may point to the metadata, otherwise it is at the
+	// well-known URL of the endpoint, or of its origin
+	metadataURLs := []string{wellKnownURL(endpoint, "oauth-protected-resource"), wellKnownURL(rootURL(endpoint), "oauth-protected-resource")}
+	if u := challengeParam(resp.Header.Get("WWW-Authenticate"), "resource_metadata"); u != "" {
+		metadataURLs = []string{u}
+	}
+	for _, u := range metadataURLs {
+		var metadata ProtectedResourceMetadata
+		found, err := getJSON(ctx, httpClient, u, &metadata)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			d.Resource = &metadata
+			break
+		}
+	}
+
+	var issuers []string
+	if d.Resource != nil {
+		issuers = d.Resource.AuthorizationServers
+	} else if unauthorized {
+		// Servers without resource metadata are their own authorization server
+		issuers = []string{rootURL(endpoint).String()}
+	}
+	for _, issuer := range issuers {
+		metadata, err := fetchAuthorizationServerMetadata(ctx, httpClient, issuer)
+		if err != nil {
+			return nil, err
+		}
+		if metadata != nil {
+			d.AuthorizationServer = metadata
+			break
+		}
+	}
+	if d.AuthorizationServer == nil && d.Resource == nil && unauthorized {
+		// Default endpoints of servers without any metadata
+		root := strings.TrimSuffix(rootURL(endpoint).String(), "/")
+		d.AuthorizationServer = &AuthorizationServerMetadata{
+			Issuer:                root,
+			AuthorizationEndpoint: root + "/authorize",
+			TokenEndpoint:         root + "/token",
+			RegistrationEndpoint:  root + "/register",
+		}
+	}
+	d.Options = &Options{
+		BaseURL:             endpoint.String(),
+		HTTPClient:          config.httpClient,
+		ProtectedResource:   d.Resource,
+		AuthorizationServer: d.AuthorizationServer,
+	}
+	return d, nil
+}
+
+// probeEndpoint sends the GET opening an SSE stream to a possible endpoint,
+// which answers anything but 404, even without a session.
+func probeEndpoint(ctx context.Context, httpClient *http.Client, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe %s: %w", endpoint, err)
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// fetchAuthorizationServerMetadata returns the RFC 8414 metadata of issuer,
+// falling back to its OpenID Connect discovery document, or nil if it
+// publishes neither.
+func fetchAuthorizationServerMetadata(ctx context.Context, httpClient *http.Client, issuer string) (*AuthorizationServerMetadata, error) {
+	u, err := url.Parse(issuer)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid authorization server %q", issuer)
+	}
+	for _, suffix := range []string{"oauth-authorization-server", "openid-configuration"} {
+		var metadata AuthorizationServerMetadata
+		found, err := getJSON(ctx, httpClient, wellKnownURL(u, suffix), &metadata)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return &metadata, nil
+		}
+	}
+	return nil, nil
+}
+
+// wellKnownURL returns the well-known URL of a metadata document about u,
+// inserting the suffix between its host and path (RFC 8615).
+func wellKnownURL(u *url.URL, suffix string) string {
+	return u.Scheme + "://" + u.Host + "/.well-known/" + suffix + strings.TrimSuffix(u.Path, "/")
+}
+
+// rootURL returns the origin of u.
+func rootURL(u *url.URL) *url.URL {
+	return &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}
+}
+
+// getJSON decodes the JSON document at u, fetched with httpClient, into v. It
+// reports false if there is no such document.
+func getJSON(ctx context.Context, httpClient *http.Client, u string, v any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", u, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", u, err)
+	}
+	return true, nil
+}
+
+// challengeParam returns a parameter of a WWW-Authenticate challenge.
+func challengeParam(challenge, name string) string {
+	for _, param := range strings.Split(challenge, ",") {
+		param = strings.TrimSpace(param)
+		if i := strings.IndexByte(param, ' '); i >= 0 && !strings.Contains(param[:i], "=") {
+			// Skip the scheme before the first parameter
+			param = strings.TrimSpace(param[i+1:])
+		}
+		key, value, ok := strings.Cut(param, "=")
+		if ok && strings.EqualFold(key, name) {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
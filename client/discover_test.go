@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Authorization", func(t *testing.T) {
+		mux := http.NewServeMux()
+		ts := httptest.NewServer(mux)
+		t.Cleanup(ts.Close)
+		mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("WWW-Authenticate", `Bearer resource_metadata="`+ts.URL+`/meta"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		mux.HandleFunc("/meta", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"resource":"` + ts.URL + `/mcp","authorization_servers":["` + ts.URL + `/tenant"]}`))
+		})
+		mux.HandleFunc("/.well-known/oauth-authorization-server/tenant", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"issuer":"` + ts.URL + `/tenant","token_endpoint":"` + ts.URL + `/tenant/token"}`))
+		})
+
+		d, err := Discover(ctx, ts.URL)
+		if err != nil {
+			t.Fatalf("Discover failed: %v", err)
+		}
+		if d.Options.BaseURL != ts.URL+"/mcp" {
+			t.Errorf("Expected endpoint %s/mcp, got %s", ts.URL, d.Options.BaseURL)
+		}
+		if d.Resource == nil || d.Resource.Resource != ts.URL+"/mcp" {
+			t.Errorf("Expected the protected resource metadata, got %+v", d.Resource)
+		}
+		if d.AuthorizationServer == nil || d.AuthorizationServer.TokenEndpoint != ts.URL+"/tenant/token" {
+			t.Errorf("Expected the authorization server metadata, got %+v", d.AuthorizationServer)
+		}
+		if d.Options.ProtectedResource != d.Resource || d.Options.AuthorizationServer != d.AuthorizationServer {
+			t.Errorf("Expected the metadata in the options, got %+v %+v", d.Options.ProtectedResource, d.Options.AuthorizationServer)
+		}
+	})
+
+	t.Run("Root", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/{$}", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Missing session ID", http.StatusBadRequest)
+		})
+		ts := httptest.NewServer(mux)
+		t.Cleanup(ts.Close)
+
+		d, err := Discover(ctx, ts.URL)
+		if err != nil {
+			t.Fatalf("Discover failed: %v", err)
+		}
+		if d.Options.BaseURL != ts.URL+"/" {
+			t.Errorf("Expected endpoint %s/, got %s", ts.URL, d.Options.BaseURL)
+		}
+		if d.Resource != nil || d.AuthorizationServer != nil {
+			t.Errorf("Expected no authorization, got %+v %+v", d.Resource, d.AuthorizationServer)
+		}
+		if d.Options.HTTPClient != nil {
+			t.Errorf("Expected no HTTP client in the options, got %+v", d.Options.HTTPClient)
+		}
+	})
+
+	t.Run("DefaultEndpoints", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/sse" {
+				http.NotFound(w, r)
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		t.Cleanup(ts.Close)
+
+		d, err := Discover(ctx, ts.URL+"/sse")
+		if err != nil {
+			t.Fatalf("Discover failed: %v", err)
+		}
+		if d.AuthorizationServer == nil || d.AuthorizationServer.AuthorizationEndpoint != ts.URL+"/authorize" {
+			t.Errorf("Expected the default endpoints, got %+v", d.AuthorizationServer)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		ts := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(ts.Close)
+
+		if _, err := Discover(ctx, ts.URL); err == nil {
+			t.Error("Expected an error without an endpoint")
+		}
+	})
+
+	t.Run("HTTPClient", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Tenant") == "" {
+				http.NotFound(w, r)
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		t.Cleanup(ts.Close)
+		httpClient := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("X-Tenant", "acme")
+			return http.DefaultTransport.RoundTrip(r)
+		})}
+
+		d, err := Discover(ctx, ts.URL, WithDiscoveryHTTPClient(httpClient))
+		if err != nil {
+			t.Fatalf("Discover failed: %v", err)
+		}
+		if d.Options.HTTPClient != httpClient {
+			t.Errorf("Expected the HTTP client in the options, got %+v", d.Options.HTTPClient)
+		}
+		if d.AuthorizationServer == nil || d.AuthorizationServer.TokenEndpoint != ts.URL+"/token" {
+			t.Errorf("Expected the default authorization endpoints, got %+v", d.AuthorizationServer)
+		}
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp/spec"
+)
+
+// ErrNoServerURL is returned by FromEnv when MCP_SERVER_URL is not set.
+var ErrNoServerURL = errors.New("MCP_SERVER_URL is not set")
+
+// FromEnv returns the Options configured by environment variables, for
+// deployments and scripts that configure the client without flags:
+//
+//	MCP_SERVER_URL        server address, as given to New (required)
+//	MCP_AUTH_TOKEN        bearer token sent in the Authorization header
+//	MCP_HEADERS           additional headers, as a JSON object
+//	MCP_TIMEOUT           request timeout, in seconds or as a duration such as "30s"
+//	MCP_CONNECT_TIMEOUT   connection timeout, as a duration
+//	MCP_PROTOCOL_VERSION  protocol version to request
+//	MCP_LISTEN            open an SSE stream for notifications, as a boolean
+//	MCP_DEBUG             enable debug logging, as a boolean
+//
+// Connect with them by passing BaseURL to New:
+//
+//	o, err := client.FromEnv()
+//	...
+//	c, err := client.New(ctx, o.BaseURL, o)
+//
+// Empty variables are ignored; invalid ones are an error naming them.
+func FromEnv() (*Options, error) {
+	o := &Options{BaseURL: os.Getenv("MCP_SERVER_URL")}
+	if o.BaseURL == "" {
+		return nil, ErrNoServerURL
+	}
+
+	if value := os.Getenv("MCP_HEADERS"); value != "" {
+		if err := json.Unmarshal([]byte(value), &o.Headers); err != nil {
+			return nil, fmt.Errorf("invalid MCP_HEADERS: %w", err)
+		}
+	}
+	if token := os.Getenv("MCP_AUTH_TOKEN"); token != "" {
+		if o.Headers == nil {
+			o.Headers = map[string]string{}
+		}
+		o.Headers["Authorization"] = "Bearer " + token
+	}
+
+	if value := os.Getenv("MCP_TIMEOUT"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			timeout, durationErr := time.ParseDuration(value)
+			if durationErr != nil {
+				return nil, fmt.Errorf("invalid MCP_TIMEOUT: %q is neither seconds nor a duration", value)
+			}
+			seconds = int(math.Ceil(timeout.Seconds()))
+		}
+		if seconds <= 0 {
+			return nil, fmt.Errorf("invalid MCP_TIMEOUT: %q is not positive", value)
+		}
+		o.Timeout = seconds
+	}
+	if value := os.Getenv("MCP_CONNECT_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MCP_CONNECT_TIMEOUT: %w", err)
+		}
+		o.ConnectTimeout = timeout
+	}
+
+	if version := os.Getenv("MCP_PROTOCOL_VERSION"); version != "" {
+		if !slices.Contains(spec.Revisions, version) {
+			return nil, fmt.Errorf("invalid MCP_PROTOCOL_VERSION: %q is not one of %s", version, strings.Join(spec.Revisions, ", "))
+		}
+		o.ProtocolVersion = version
+	}
+
+	for name, field := range map[string]*bool{"MCP_LISTEN": &o.Listen, "MCP_DEBUG": &o.Debug} {
+		if value := os.Getenv(name); value != "" {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			*field = enabled
+		}
+	}
+	return o, nil
+}
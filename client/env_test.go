@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		t.Setenv("MCP_SERVER_URL", "")
+		if _, err := FromEnv(); !errors.Is(err, ErrNoServerURL) {
+			t.Errorf("Expected ErrNoServerURL, got %v", err)
+		}
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		t.Setenv("MCP_SERVER_URL", "https://example.com/mcp")
+		t.Setenv("MCP_AUTH_TOKEN", "secret")
+		t.Setenv("MCP_HEADERS", `{"X-Tenant":"acme"}`)
+		t.Setenv("MCP_TIMEOUT", "1500ms")
+		t.Setenv("MCP_CONNECT_TIMEOUT", "5s")
+		t.Setenv("MCP_PROTOCOL_VERSION", "2024-11-05")
+		t.Setenv("MCP_LISTEN", "true")
+		t.Setenv("MCP_DEBUG", "")
+
+		o, err := FromEnv()
+		if err != nil {
+			t.Fatalf("FromEnv failed: %v", err)
+		}
+		if o.BaseURL != "https://example.com/mcp" {
+			t.Errorf("Expected the server URL, got %q", o.BaseURL)
+		}
+		if o.Headers["Authorization"] != "Bearer secret" || o.Headers["X-Tenant"] != "acme" {
+			t.Errorf("Expected the token and the headers, got %v", o.Headers)
+		}
+		if o.Timeout != 2 || o.ConnectTimeout != 5*time.Second {
+			t.Errorf("Expected timeouts of 2s and 5s, got %d and %s", o.Timeout, o.ConnectTimeout)
+		}
+		if o.ProtocolVersion != "2024-11-05" || !o.Listen || o.Debug {
+			t.Errorf("Expected the protocol version and listening without debug, got %+v", o)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for name, value := range map[string]string{
+			"MCP_HEADERS":          "X-Tenant: acme",
+			"MCP_TIMEOUT":          "soon",
+			"MCP_CONNECT_TIMEOUT":  "5",
+			"MCP_PROTOCOL_VERSION": "2020-01-01",
+			"MCP_DEBUG":            "maybe",
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Setenv("MCP_SERVER_URL", "https://example.com/mcp")
+				t.Setenv(name, value)
+				if _, err := FromEnv(); err == nil {
+					t.Errorf("Expected an error for %s=%s", name, value)
+				}
+			})
+		}
+	})
+
+	t.Run("Connect", func(t *testing.T) {
+		srv := mcptest.NewServer()
+		srv.Start()
+		defer srv.Close()
+		t.Setenv("MCP_SERVER_URL", srv.URL)
+		t.Setenv("MCP_AUTH_TOKEN", "secret")
+
+		o, err := FromEnv()
+		if err != nil {
+			t.Fatalf("FromEnv failed: %v", err)
+		}
+		c, err := New(context.Background(), o.BaseURL, o)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		defer c.Close()
+		if auth := srv.Requests()[0].Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Expected the token to be sent, got %q", auth)
+		}
+	})
+}
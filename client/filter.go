@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// ErrToolNotAllowed is matched by errors.Is for every *ToolNotAllowedError.
+var ErrToolNotAllowed = errors.New("tool not allowed")
+
+// ToolNotAllowedError is returned by CallTool, without contacting the server,
+// for tools rejected by the filters set with WithToolFilter.
+type ToolNotAllowedError struct {
+	Tool string
+}
+
+func (e *ToolNotAllowedError) Error() string {
+	return fmt.Sprintf("tool %s is not allowed", e.Tool)
+}
+
+func (e *ToolNotAllowedError) Is(target error) bool {
+	return target == ErrToolNotAllowed
+}
+
+// ToolFilter reports whether a tool may be listed and called.
+type ToolFilter func(tool mcp.Tool) bool
+
+// AllowDenyTools returns a ToolFilter allowing the tools whose name matches a
+// pattern of allow, or any tool if allow is empty, unless it matches a
+// pattern of deny. Patterns use the path.Match syntax, such as "github_*".
+func AllowDenyTools(allow, deny []string) ToolFilter {
+	return func(tool mcp.Tool) bool {
+		return (len(allow) == 0 || matchToolName(allow, tool.Name)) && !matchToolName(deny, tool.Name)
+	}
+}
+
+// matchToolName reports whether name matches one of patterns.
+func matchToolName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WithToolFilter hides the tools rejected by filter from ListTools and the
+// LLM adapters, and makes CallTool refuse them with a *ToolNotAllowedError.
+// Tools must pass every filter set. A tool called before being listed is
+// looked up with tools/list; one the server does not list is filtered by
+// name only.
+func WithToolFilter(filter ToolFilter) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.toolFilters = append(c.toolFilters, filter)
+	}
+}
+
+// toolAllowed reports whether tool passes the filters.
+func (c *HTTPClient) toolAllowed(tool mcp.Tool) bool {
+	for _, filter := range c.toolFilters {
+		if !filter(tool) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterTools returns the tools passing the filters.
+func (c *HTTPClient) filterTools(tools []mcp.Tool) []mcp.Tool {
+	if len(c.toolFilters) == 0 {
+		return tools
+	}
+	allowed := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if c.toolAllowed(tool) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// checkToolAllowed returns a *ToolNotAllowedError if the named tool does not
+// pass the filters.
+func (c *HTTPClient) checkToolAllowed(ctx context.Context, name string) error {
+	if len(c.toolFilters) == 0 {
+		return nil
+	}
+	tool, ok, err := c.lookupTool(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		tool = mcp.Tool{Name: name}
+	}
+	if !c.toolAllowed(tool) {
+		return &ToolNotAllowedError{Tool: name}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestToolFilter(t *testing.T) {
+	srv := startTestServer()
+	for _, name := range []string{"fs_read", "fs_write", "search"} {
+		srv.AddTool(mcp.Tool{Name: name, Description: name}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+	}
+	defer srv.Close()
+
+	noSearch := func(tool mcp.Tool) bool { return tool.Name != "search" }
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL},
+		WithToolFilter(AllowDenyTools([]string{"fs_*", "search"}, []string{"*_write"})),
+		WithToolFilter(noSearch))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	t.Run("ListTools", func(t *testing.T) {
+		result, err := client.ListTools(ctx, "")
+		if err != nil {
+			t.Fatalf("ListTools failed: %v", err)
+		}
+		if len(result.Tools) != 1 || result.Tools[0].Name != "fs_read" {
+			t.Errorf("Expected only fs_read, got %+v", result.Tools)
+		}
+		tools, err := client.AnthropicTools(ctx)
+		if err != nil {
+			t.Fatalf("AnthropicTools failed: %v", err)
+		}
+		if len(tools) != 1 || tools[0].Name != "fs_read" {
+			t.Errorf("Expected only fs_read, got %+v", tools)
+		}
+	})
+
+	t.Run("OpenaiTools", func(t *testing.T) {
+		tools, err := client.OpenaiTools()
+		if err != nil {
+			t.Fatalf("OpenaiTools failed: %v", err)
+		}
+		if len(tools) != 1 || tools[0].Name != "fs_read" {
+			t.Errorf("Expected only fs_read, got %+v", tools)
+		}
+	})
+
+	t.Run("CallTool", func(t *testing.T) {
+		if _, err := client.CallTool(ctx, "fs_read", nil); err != nil {
+			t.Errorf("Expected fs_read to be allowed, got %v", err)
+		}
+		for _, name := range []string{"fs_write", "search", "unlisted"} {
+			sent := len(srv.Requests())
+			_, err := client.CallTool(ctx, name, nil)
+			var notAllowed *ToolNotAllowedError
+			if !errors.As(err, &notAllowed) || !errors.Is(err, ErrToolNotAllowed) || notAllowed.Tool != name {
+				t.Errorf("Expected ToolNotAllowedError for %s, got %v", name, err)
+			}
+			for _, request := range srv.Requests()[sent:] {
+				if request.Method == "tools/call" {
+					t.Errorf("Expected no tools/call for %s", name)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// GeminiFunctionDeclaration is a tool definition in the shape the Gemini API
+// expects in the functionDeclarations of a tool.
+type GeminiFunctionDeclaration struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// GeminiFunctionCall is a functionCall part of a model response.
+type GeminiFunctionCall struct {
+	ID   string         `json:"id,omitempty"`
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// GeminiFunctionResponse is the functionResponse part answering a function
+// call. Response holds "output" on success and "error" when the tool failed.
+type GeminiFunctionResponse struct {
+	ID       string         `json:"id,omitempty"`
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+// geminiSchemaKeys are the JSON Schema keywords Gemini accepts; others are
+// rejected by the API and are dropped.
+var geminiSchemaKeys = []string{
+	"type", "format", "title", "description", "nullable", "enum",
+	"items", "minItems", "maxItems", "properties", "required",
+	"minProperties", "maxProperties", "minLength", "maxLength", "pattern",
+	"minimum", "maximum", "anyOf", "propertyOrdering",
+}
+
+// geminiFormats are the formats Gemini accepts for each type.
+var geminiFormats = map[string][]string{
+	"string":  {"enum", "date-time"},
+	"number":  {"float", "double"},
+	"integer": {"int32", "int64"},
+}
+
+// GeminiTools returns the tools of the server as Gemini function declarations.
+func (c *HTTPClient) GeminiTools(ctx context.Context) ([]GeminiFunctionDeclaration, error) {
+	tools, err := allTools(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	declarations := make([]GeminiFunctionDeclaration, 0, len(tools))
+	for _, tool := range tools {
+		declaration := GeminiFunctionDeclaration{Name: tool.Name, Description: tool.Description}
+		if len(tool.InputSchema) > 0 {
+			schema := map[string]any{}
+			if err := json.Unmarshal(tool.InputSchema, &schema); err != nil {
+				return nil, fmt.Errorf("failed to parse input schema of tool %s: %w", tool.Name, err)
+			}
+			// A function without parameters must not declare an empty object
+			if properties, _ := schema["properties"].(map[string]any); len(properties) > 0 {
+				declaration.Parameters = geminiSchema(schema)
+			}
+		}
+		declarations = append(declarations, declaration)
+	}
+	return declarations, nil
+}
+
+// geminiSchema converts a JSON Schema to the subset Gemini accepts.
+func geminiSchema(schema map[string]any) map[string]any {
+	result := map[string]any{}
+	for _, key := range geminiSchemaKeys {
+		if value, ok := schema[key]; ok {
+			result[key] = value
+		}
+	}
+
+	// Gemini has a single type plus a nullable flag
+	if types, ok := schema["type"].([]any); ok {
+		delete(result, "type")
+		for _, t := range types {
+			if t == "null" {
+				result["nullable"] = true
+			} else if _, set := result["type"]; !set {
+				result["type"] = t
+			}
+		}
+	}
+	schemaType, _ := result["type"].(string)
+
+	if value, ok := schema["const"].(string); ok {
+		result["enum"] = []any{value}
+	}
+	if enum, ok := result["enum"].([]any); ok {
+		for _, value := range enum {
+			if _, ok := value.(string); !ok {
+				delete(result, "enum")
+				break
+			}
+		}
+	}
+	if format, ok := result["format"].(string); ok && !slices.Contains(geminiFormats[schemaType], format) {
+		delete(result, "format")
+	}
+
+	if properties, ok := result["properties"].(map[string]any); ok {
+		converted := make(map[string]any, len(properties))
+		for name, property := range properties {
+			if propertyMap, ok := property.(map[string]any); ok {
+				converted[name] = geminiSchema(propertyMap)
+			}
+		}
+		result["properties"] = converted
+
+		// Gemini rejects required properties it does not know about
+		if required, ok := result["required"].([]any); ok {
+			var known []any
+			for _, name := range required {
+				if name, ok := name.(string); ok && converted[name] != nil {
+					known = append(known, name)
+				}
+			}
+			result["required"] = known
+		}
+	}
+	if items, ok := result["items"].(map[string]any); ok {
+		result["items"] = geminiSchema(items)
+	} else if schemaType == "array" {
+		result["items"] = map[string]any{"type": "string"}
+	}
+	if anyOf, ok := result["anyOf"].([]any); ok {
+		converted := make([]any, 0, len(anyOf))
+		for _, alternative := range anyOf {
+			if alternativeMap, ok := alternative.(map[string]any); ok {
+				converted = append(converted, geminiSchema(alternativeMap))
+			}
+		}
+		result["anyOf"] = converted
+	}
+	return result
+}
+
+// CallGeminiFunctionCall calls the tool a function call asks for and returns
+// the function response answering it. A tool reporting an error yields a
+// response holding "error"; an error is returned only when the call fails.
+func (c *HTTPClient) CallGeminiFunctionCall(ctx context.Context, call GeminiFunctionCall) (*GeminiFunctionResponse, error) {
+	result, err := c.CallTool(ctx, call.Name, call.Args)
+	if err != nil {
+		return nil, err
+	}
+	return NewGeminiFunctionResponse(call, result), nil
+}
+
+// NewGeminiFunctionResponse wraps the result of a tools/call as the function
+// response answering call. Text content is joined by newlines and other
+// content is rendered as JSON text.
+func NewGeminiFunctionResponse(call GeminiFunctionCall, result *mcp.CallToolResult) *GeminiFunctionResponse {
+	key := "output"
+	if result.IsError {
+		key = "error"
+	}
+	return &GeminiFunctionResponse{
+		ID:       call.ID,
+		Name:     call.Name,
+		Response: map[string]any{key: joinContent(result.Content)},
+	}
+}
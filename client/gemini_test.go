@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestGeminiTools(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{
+		Name:        "search",
+		Description: "Search items",
+		InputSchema: json.RawMessage(`{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"query": {"type": "string", "format": "uri", "default": "x"},
+				"limit": {"type": ["integer", "null"], "format": "int32"},
+				"sort": {"const": "asc"},
+				"tags": {"type": "array"}
+			},
+			"required": ["query", "missing"]
+		}`),
+	}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("found " + arguments["query"].(string)), nil
+	})
+	srv.AddTool(mcp.Tool{Name: "now"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText("clock stopped")
+		result.IsError = true
+		return result, nil
+	})
+	srv.Start()
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	t.Run("Declarations", func(t *testing.T) {
+		declarations, err := client.GeminiTools(ctx)
+		if err != nil {
+			t.Fatalf("GeminiTools failed: %v", err)
+		}
+		byName := map[string]GeminiFunctionDeclaration{}
+		for _, declaration := range declarations {
+			byName[declaration.Name] = declaration
+		}
+
+		if now := byName["now"]; now.Parameters != nil {
+			t.Errorf("Expected no parameters for now, got %v", now.Parameters)
+		}
+		want := map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{"type": "string"},
+				"limit": map[string]any{"type": "integer", "format": "int32", "nullable": true},
+				"sort":  map[string]any{"enum": []any{"asc"}},
+				"tags":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+			"required": []any{"query"},
+		}
+		if got := byName["search"].Parameters; !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("FunctionCall", func(t *testing.T) {
+		response, err := client.CallGeminiFunctionCall(ctx, GeminiFunctionCall{
+			ID:   "call-1",
+			Name: "search",
+			Args: map[string]any{"query": "gophers"},
+		})
+		if err != nil {
+			t.Fatalf("CallGeminiFunctionCall failed: %v", err)
+		}
+		want := &GeminiFunctionResponse{ID: "call-1", Name: "search", Response: map[string]any{"output": "found gophers"}}
+		if !reflect.DeepEqual(response, want) {
+			t.Errorf("Expected %+v, got %+v", want, response)
+		}
+	})
+
+	t.Run("ToolError", func(t *testing.T) {
+		response, err := client.CallGeminiFunctionCall(ctx, GeminiFunctionCall{Name: "now"})
+		if err != nil {
+			t.Fatalf("CallGeminiFunctionCall failed: %v", err)
+		}
+		if got := response.Response["error"]; got != "clock stopped" {
+			t.Errorf("Expected error %q, got %v", "clock stopped", response.Response)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// Hooks are optional callbacks invoked at each stage of the client's JSON-RPC
+// traffic. They receive the raw envelopes and timing information, which makes
+// them suitable for audit trails, metrics and debugging.
+// Hooks are called synchronously and must not block.
+type Hooks struct {
+	// OnRequest is called right before a request is handed to the transport.
+	OnRequest func(ctx context.Context, request *transport.JSONRPCRequest)
+
+	// OnResponse is called when a response envelope is received, including
+	// envelopes carrying a JSON-RPC error.
+	OnResponse func(ctx context.Context, request *transport.JSONRPCRequest, response *transport.JSONRPCResponse, elapsed time.Duration)
+
+	// OnNotification is called for every notification received from the server.
+	OnNotification func(notification *transport.JSONRPCNotification, receivedAt time.Time)
+
+	// OnError is called when a request fails, either in the transport or
+	// because the server answered with a JSON-RPC error.
+	OnError func(ctx context.Context, request *transport.JSONRPCRequest, err error, elapsed time.Duration)
+
+	// OnServerPing is called when the server pings the client. The ping is
+	// answered automatically.
+	OnServerPing func(id mcp.RequestId, receivedAt time.Time)
+}
+
+func (h *Hooks) request(ctx context.Context, request *transport.JSONRPCRequest) {
+	if h != nil && h.OnRequest != nil {
+		h.OnRequest(ctx, request)
+	}
+}
+
+func (h *Hooks) response(ctx context.Context, request *transport.JSONRPCRequest, response *transport.JSONRPCResponse, elapsed time.Duration) {
+	if h != nil && h.OnResponse != nil {
+		h.OnResponse(ctx, request, response, elapsed)
+	}
+}
+
+func (h *Hooks) notification(notification *transport.JSONRPCNotification, receivedAt time.Time) {
+	if h != nil && h.OnNotification != nil {
+		h.OnNotification(notification, receivedAt)
+	}
+}
+
+func (h *Hooks) error(ctx context.Context, request *transport.JSONRPCRequest, err error, elapsed time.Duration) {
+	if h != nil && h.OnError != nil {
+		h.OnError(ctx, request, err, elapsed)
+	}
+}
+
+func (h *Hooks) serverPing(id mcp.RequestId, receivedAt time.Time) {
+	if h != nil && h.OnServerPing != nil {
+		h.OnServerPing(id, receivedAt)
+	}
+}
@@ -4,23 +4,110 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcp/jsonschema"
 )
 
 // HTTPClient implements the Interface for MCP client over HTTP transport.
 // It implements the Model Context Protocol (MCP) client-side functionality.
+// NewStdioClient creates one that talks to a server subprocess over stdio instead.
 // See: http://spec.modelcontextprotocol.io/2025-03-26/
 type HTTPClient struct {
 	transport transport.Interface
 	config    *Config
 
-	notificationHandler func(method string, params map[string]interface{})
+	// requestIDs generates the IDs of outgoing requests, unique within the session
+	requestIDs transport.RequestIDGenerator
+
+	// progressHandlers maps in-flight progress tokens to their ProgressFunc
+	progressHandlers  sync.Map
+	lastProgressToken atomic.Int64
+
+	// initResult is the server's answer to initialize
+	initResult *mcp.InitializeResult
+	initMu     sync.RWMutex
+
+	keepAlive *keepAlive
+
+	skipCapabilityChecks bool
+
+	// roots, samplingHandler and experimental back the client capabilities
+	roots           *roots
+	samplingHandler SamplingHandler
+	experimental    map[string]any
+
+	notifications notificationRouter
+	// unsubscribeHandler removes the handler set by SetNotificationHandler
+	unsubscribeHandler func()
+
+	notificationBuffer int
+	overflowPolicy     OverflowPolicy
+
+	listCache     listCache
+	skipListCache bool
+
+	// toolIndex holds the listed tools whose schemas validate arguments and results
+	toolIndex            toolIndex
+	argumentValidator    jsonschema.Validator
+	outputValidator      jsonschema.Validator
+	outputValidationMode OutputValidationMode
+
+	// strict validates every message against the protocol schema
+	strict bool
+
+	// toolFilters restrict the tools listed and called
+	toolFilters []ToolFilter
+	// callApproval approves the calls to tools that are not read-only
+	callApproval CallApprovalFunc
+	// policy decides whether tool calls are sent
+	policy *Policy
+
+	auditSink       AuditSink
+	auditRedactKeys []string
+
+	// requestSlots bounds the requests in flight, if set
+	requestSlots chan struct{}
+
+	// offlineQueue holds the requests issued while the server is unreachable, if set
+	offlineQueue *offlineQueue
+
+	// connection is the state of the connection to the server
+	connection connection
+
+	// state is the State of the client; lifecycleMu serializes the
+	// initializations and started tells whether the transport was started
+	state       atomic.Int32
+	lifecycleMu sync.Mutex
+	started     bool
+}
+
+var _ Interface = (*HTTPClient)(nil)
+
+// HTTPClientOption configures optional HTTPClient behavior.
+type HTTPClientOption func(*HTTPClient)
+
+// defaultInitTimeout bounds the initialization done by the constructors
+// without a context.
+const defaultInitTimeout = 10 * time.Second
+
+// NewHTTPClient creates a new HTTP client and initializes the session, unless
+// Options.LazyInit is set, waiting up to 10 seconds for the server. Use
+// NewHTTPClientContext to control the wait.
+func NewHTTPClient(options *Options, opts ...HTTPClientOption) (*HTTPClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultInitTimeout)
+	defer cancel()
+	return NewHTTPClientContext(ctx, options, opts...)
 }
 
-// NewHTTPClient creates a new HTTP client
-func NewHTTPClient(options *Options) (*HTTPClient, error) {
+// NewHTTPClientContext creates a new HTTP client and initializes the session
+// within ctx, unless Options.LazyInit is set.
+func NewHTTPClientContext(ctx context.Context, options *Options, opts ...HTTPClientOption) (*HTTPClient, error) {
 	if options == nil {
 		options = &Options{}
 	}
@@ -33,14 +120,56 @@ func NewHTTPClient(options *Options) (*HTTPClient, error) {
 	// Create transport options
 	transportOpts := []transport.StreamableHTTPCOption{}
 
+	if options.HTTPClient != nil {
+		transportOpts = append(transportOpts, transport.WithHTTPClient(options.HTTPClient))
+	}
+
 	// Add headers if provided
 	if len(options.Headers) > 0 {
 		transportOpts = append(transportOpts, transport.WithHTTPHeaders(options.Headers))
 	}
 
-	// Add timeout if provided
+	// Add timeouts if provided
 	if options.Timeout > 0 {
-		transportOpts = append(transportOpts, transport.WithHTTPTimeout(time.Duration(options.Timeout)*time.Second))
+		transportOpts = append(transportOpts, transport.WithRequestTimeout(time.Duration(options.Timeout)*time.Second))
+	}
+	if options.ConnectTimeout > 0 {
+		transportOpts = append(transportOpts, transport.WithConnectTimeout(options.ConnectTimeout))
+	}
+	if options.ResponseHeaderTimeout > 0 {
+		transportOpts = append(transportOpts, transport.WithResponseHeaderTimeout(options.ResponseHeaderTimeout))
+	}
+	if options.IdleStreamTimeout > 0 {
+		transportOpts = append(transportOpts, transport.WithIdleStreamTimeout(options.IdleStreamTimeout))
+	}
+	if options.MaxResponseSize > 0 {
+		transportOpts = append(transportOpts, transport.WithMaxResponseSize(options.MaxResponseSize))
+	}
+	if options.CompressionThreshold > 0 {
+		transportOpts = append(transportOpts, transport.WithRequestCompression(options.CompressionThreshold))
+	}
+
+	if options.OnUnauthorized != nil {
+		transportOpts = append(transportOpts, transport.WithOnUnauthorized(options.OnUnauthorized))
+	}
+
+	if options.Listen {
+		transportOpts = append(transportOpts, transport.WithListening())
+	}
+
+	// Report server pings, which the transport answers, to the hooks
+	transportOpts = append(transportOpts, transport.WithPingHandler(func(id mcp.RequestId) {
+		options.Hooks.serverPing(id, time.Now())
+	}))
+
+	// Report the errors of the transport to the logger
+	if options.Logger != nil {
+		transportOpts = append(transportOpts, transport.WithLogger(slog.New(slog.NewTextHandler(options.Logger, nil))))
+	}
+
+	// Dump the wire traffic to the logger in debug mode
+	if options.Debug && options.Logger != nil {
+		transportOpts = append(transportOpts, transport.WithWireDump(options.Logger))
 	}
 
 	// Create transport
@@ -49,65 +178,226 @@ func NewHTTPClient(options *Options) (*HTTPClient, error) {
 		return nil, fmt.Errorf("failed to create transport: %w", err)
 	}
 
+	return newClient(ctx, transportImpl, options, opts...)
+}
+
+// newClient wires a client to a created transport, then starts the transport
+// and initializes the session within ctx, unless initialization is lazy.
+func newClient(ctx context.Context, transportImpl transport.Interface, options *Options, opts ...HTTPClientOption) (*HTTPClient, error) {
 	client := &HTTPClient{
-		transport: transportImpl,
-		config:    &Config{Options: options},
+		transport:  transportImpl,
+		config:     &Config{Options: options},
+		requestIDs: transport.CounterRequestIDs(),
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	if client.roots != nil && client.roots.err != nil {
+		return nil, fmt.Errorf("invalid roots: %w", client.roots.err)
 	}
 
 	// Configure notification handler
 	transportImpl.SetNotificationHandler(func(notification transport.JSONRPCNotification) {
-		if client.notificationHandler != nil {
-			client.notificationHandler(notification.Method, notification.Params.AdditionalFields)
+		options.Hooks.notification(&notification, time.Now())
+		if err := client.checkNotification(DirectionIncoming, notification); err != nil {
+			if options.Logger != nil {
+				fmt.Fprintf(options.Logger, "dropped notification: %v\n", err)
+			}
+			return
 		}
+		client.dispatchProgress(notification)
+		client.notifications.dispatch(mcp.Notification{
+			Method: notification.Method,
+			Params: notification.Params.AdditionalFields,
+		})
 	})
 
-	// Immediately initialize the transport (connect to server)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// Drop cached lists when the server says they changed
+	client.watchListChanges()
 
-	// Determine protocol version, clientInfo, and capabilities
-	protocolVersion := "2025-03-26"
-	if client.config != nil && client.config.Options != nil && client.config.Options.ProtocolVersion != "" {
-		protocolVersion = client.config.Options.ProtocolVersion
-	}
-	clientInfo := map[string]interface{}{
-		"name":    "mcpgopher",
-		"version": Version,
-	}
-	capabilities := map[string]interface{}{}
+	// Report the connection state of the transport
+	client.watchConnection()
+
+	// Answer the requests the server sends to the client
+	transportImpl.SetRequestHandler(client.strictRequestHandler(client.handleServerRequest))
 
-	if err := transportImpl.Initialize(ctx, protocolVersion, clientInfo, capabilities); err != nil {
+	if options.LazyInit {
+		return client, nil
+	}
+	if err := client.Initialize(ctx); err != nil {
+		transportImpl.Close()
 		return nil, fmt.Errorf("failed to initialize MCP client: %w", err)
 	}
-
 	return client, nil
 }
 
-// Initialize initializes the client with the server using the transport's Initialize method.
+// Initialize initializes the client with the server by sending the initialize
+// request, then notifications/initialized, making the client ready.
+// The transport stores the session ID returned by the server.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/lifecycle#initialization
 func (c *HTTPClient) Initialize(ctx context.Context) error {
-	protocolVersion := "2025-03-26"
+	c.lifecycleMu.Lock()
+	defer c.lifecycleMu.Unlock()
+	return c.initializeLocked(ctx)
+}
+
+// ensureInitialized initializes the session of a lazily initialized client
+// on its first request.
+func (c *HTTPClient) ensureInitialized(ctx context.Context) error {
+	if c.State() != StateCreated || !c.config.Options.LazyInit {
+		return nil
+	}
+	c.lifecycleMu.Lock()
+	defer c.lifecycleMu.Unlock()
+	if c.State() != StateCreated {
+		return nil
+	}
+	return c.initializeLocked(ctx)
+}
+
+// initializeLocked starts the transport if needed and initializes the session.
+func (c *HTTPClient) initializeLocked(ctx context.Context) error {
+	if !c.started && c.State() != StateClosed {
+		if err := c.transport.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start transport: %w", err)
+		}
+		c.started = true
+	}
+	c.setState(StateCreated, StateInitializing)
+	if err := c.initialize(ctx); err != nil {
+		c.setState(StateInitializing, StateCreated)
+		return err
+	}
+	if c.setState(StateInitializing, StateReady) {
+		c.startKeepAlive()
+	}
+	c.setConnectionState(transport.ConnectionConnected, nil)
+	return nil
+}
+
+// initialize sends the initialize request, then notifications/initialized.
+func (c *HTTPClient) initialize(ctx context.Context) error {
+	request := transport.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      c.nextRequestID(),
+		Method:  "initialize",
+		Params:  c.initializeParams(),
+	}
+
+	response, err := c.send(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("failed to initialize: %w", response.Error)
+	}
+
+	var result mcp.InitializeResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		return fmt.Errorf("failed to decode initialize result: %w", err)
+	}
+	c.initMu.Lock()
+	c.initResult = &result
+	c.initMu.Unlock()
+	c.Refresh()
+
+	notification := transport.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Method:  string(mcp.MethodNotificationInitialized),
+	}
+	if err := c.notify(ctx, notification); err != nil {
+		return fmt.Errorf("failed to send initialized notification: %w", err)
+	}
+	return nil
+}
+
+// initializeResult returns the result of the last successful Initialize.
+func (c *HTTPClient) initializeResult() mcp.InitializeResult {
+	c.initMu.RLock()
+	defer c.initMu.RUnlock()
+	if c.initResult == nil {
+		return mcp.InitializeResult{}
+	}
+	return *c.initResult
+}
+
+// ServerInfo returns the name and version the server reported during initialization.
+func (c *HTTPClient) ServerInfo() mcp.Implementation {
+	return c.initializeResult().ServerInfo
+}
+
+// ServerCapabilities returns the capabilities the server declared during initialization.
+func (c *HTTPClient) ServerCapabilities() mcp.ServerCapabilities {
+	return c.initializeResult().Capabilities
+}
+
+// Instructions returns the server's usage instructions, intended for the
+// LLM's context. It is empty if the server sent none.
+func (c *HTTPClient) Instructions() string {
+	return c.initializeResult().Instructions
+}
+
+// protocolVersion returns the protocol version the client requests.
+func (c *HTTPClient) protocolVersion() string {
 	if c.config != nil && c.config.Options != nil && c.config.Options.ProtocolVersion != "" {
-		protocolVersion = c.config.Options.ProtocolVersion
+		return c.config.Options.ProtocolVersion
 	}
+	return "2025-03-26"
+}
+
+// initializeParams builds the params of the initialize request from the client configuration.
+func (c *HTTPClient) initializeParams() map[string]interface{} {
 	clientInfo := map[string]interface{}{
 		"name":    "mcpgopher",
 		"version": Version,
 	}
-	capabilities := map[string]interface{}{}
-	return c.transport.Initialize(ctx, protocolVersion, clientInfo, capabilities)
+	return map[string]interface{}{
+		"protocolVersion": c.protocolVersion(),
+		"clientInfo":      clientInfo,
+		"capabilities":    c.clientCapabilities(),
+	}
 }
 
 // Close closes the client connection and ends the session with the server.
 // See: http://spec.modelcontextprotocol.io/2025-03-26/base-protocol#shutdown
 func (c *HTTPClient) Close() error {
+	if State(c.state.Swap(int32(StateClosed))) == StateClosed {
+		return nil
+	}
+	c.stopKeepAlive()
+	if c.offlineQueue != nil {
+		c.offlineQueue.close()
+	}
 	return c.transport.Close()
 }
 
-// SetNotificationHandler sets a handler for server notifications.
-// Notifications are one-way messages from the server that don't expect a response.
-// See: http://spec.modelcontextprotocol.io/2025-03-26/base-protocol#notifications
+// SetNotificationHandler sets a handler for all server notifications,
+// replacing the one set by a previous call. A nil handler removes it.
+//
+// Deprecated: Use Subscribe, which supports several handlers.
 func (c *HTTPClient) SetNotificationHandler(handler func(method string, params map[string]interface{})) {
-	c.notificationHandler = handler
+	if c.unsubscribeHandler != nil {
+		c.unsubscribeHandler()
+		c.unsubscribeHandler = nil
+	}
+	if handler == nil {
+		return
+	}
+	c.unsubscribeHandler = c.Subscribe("*", func(notification mcp.Notification) {
+		handler(notification.Method, notification.Params)
+	})
+}
+
+// handleServerRequest answers the requests the server sends to the client
+// with the handlers registered through the client options.
+func (c *HTTPClient) handleServerRequest(ctx context.Context, request transport.JSONRPCRequest) (any, error) {
+	switch {
+	case request.Method == string(mcp.MethodRootsList) && c.roots != nil:
+		return c.listRoots(), nil
+	case request.Method == string(mcp.MethodSamplingCreateMessage) && c.samplingHandler != nil:
+		return c.createMessage(ctx, request)
+	}
+	return nil, mcp.NewError(mcp.ErrorMethodNotFound, fmt.Sprintf("method not found: %s", request.Method))
 }
 
 // Request makes a request to the server with custom parameters.
@@ -115,46 +405,142 @@ func (c *HTTPClient) SetNotificationHandler(handler func(method string, params m
 // See: http://spec.modelcontextprotocol.io/2025-03-26/base-protocol#requests-and-responses
 func (c *HTTPClient) Request(ctx context.Context, method string, params interface{}) ([]byte, error) {
 	// Ensure initialize always sends required params
-	if method == "initialize" {
-		if params == nil {
-			protocolVersion := "2025-03-26"
-			if c.config != nil && c.config.Options != nil && c.config.Options.ProtocolVersion != "" {
-				protocolVersion = c.config.Options.ProtocolVersion
-			}
-			clientInfo := map[string]interface{}{
-				"name":    "mcpgopher",
-				"version": Version,
-			}
-			params = map[string]interface{}{
-				"protocolVersion": protocolVersion,
-				"clientInfo":      clientInfo,
-				"capabilities":    map[string]interface{}{},
-			}
-		}
+	if method == "initialize" && params == nil {
+		params = c.initializeParams()
 	}
 
 	// Create the JSONRPC request
 	request := transport.JSONRPCRequest{
 		JSONRPC: "2.0",
-		ID:      fmt.Sprintf("%d", time.Now().UnixNano()),
+		ID:      c.nextRequestID(),
 		Method:  method,
 		Params:  params,
 	}
 
 	// Send request using the transport interface
-	response, err := c.transport.SendRequest(ctx, request)
+	response, err := c.send(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
 	// Check for error
 	if response.Error != nil {
-		return nil, fmt.Errorf("error %d: %s", response.Error.Code, response.Error.Message)
+		return nil, response.Error
 	}
 
 	return response.Result, nil
 }
 
+// send hands a request to the transport, invoking the configured hooks around it.
+// JSON-RPC error responses are returned as a response and reported to OnError.
+// With WithOfflineQueue, a request issued while the server is unreachable
+// waits for it to answer again.
+func (c *HTTPClient) send(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	if c.queuesOffline(request.Method) {
+		return c.offlineQueue.do(ctx, request)
+	}
+	return c.sendRecovering(ctx, request)
+}
+
+// sendRecovering sends a request. With WithSessionRecovery, a request failing
+// because the server forgot the session is sent again in a new one.
+func (c *HTTPClient) sendRecovering(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	response, err := c.sendOnce(ctx, request)
+	if c.recoversSession(request.Method, err) {
+		if err := c.reestablishSession(ctx); err != nil {
+			return nil, err
+		}
+		return c.sendOnce(ctx, request)
+	}
+	return response, err
+}
+
+// sendOnce sends a request through the transport once.
+func (c *HTTPClient) sendOnce(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	if request.Method != string(mcp.MethodInitialize) {
+		if err := c.ensureInitialized(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.checkState(request.Method); err != nil {
+		return nil, err
+	}
+	if err := c.checkRequest(request); err != nil {
+		return nil, err
+	}
+	release, err := c.acquireRequestSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	hooks := c.hooks()
+	hooks.request(ctx, &request)
+
+	start := time.Now()
+	response, err := c.transport.SendRequest(ctx, request)
+	elapsed := time.Since(start)
+	if err != nil {
+		hooks.error(ctx, &request, err, elapsed)
+		if ctx.Err() != nil && request.Method != string(mcp.MethodInitialize) {
+			go c.cancelRequest(request.ID, context.Cause(ctx))
+		}
+		return nil, err
+	}
+
+	hooks.response(ctx, &request, response, elapsed)
+	if err := c.checkResponse(request.Method, response); err != nil {
+		hooks.error(ctx, &request, err, elapsed)
+		return nil, err
+	}
+	if response.Error != nil {
+		hooks.error(ctx, &request, response.Error, elapsed)
+	}
+	return response, nil
+}
+
+// cancelTimeout bounds the delivery of a notifications/cancelled.
+const cancelTimeout = 5 * time.Second
+
+// cancelRequest tells the server that the request with the given ID was
+// abandoned, so it can stop working on it. Delivery is best effort.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/utilities/cancellation
+func (c *HTTPClient) cancelRequest(id mcp.RequestId, cause error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+	defer cancel()
+
+	notification := transport.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Method:  string(mcp.MethodNotificationCancelled),
+	}
+	notification.Params.AdditionalFields = map[string]interface{}{
+		"requestId": id,
+		"reason":    cause.Error(),
+	}
+	_ = c.notify(ctx, notification)
+}
+
+// WithRequestIDs sets how the IDs of outgoing requests are generated,
+// transport.CounterRequestIDs by default.
+func WithRequestIDs(generate transport.RequestIDGenerator) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.requestIDs = generate
+	}
+}
+
+// nextRequestID returns a new ID for an outgoing request.
+func (c *HTTPClient) nextRequestID() mcp.RequestId {
+	return c.requestIDs()
+}
+
+// hooks returns the configured hooks, which may be nil.
+func (c *HTTPClient) hooks() *Hooks {
+	if c.config == nil || c.config.Options == nil {
+		return nil
+	}
+	return c.config.Options.Hooks
+}
+
 // GetSessionID returns the current session ID
 func (c *HTTPClient) GetSessionID() string {
 	if t, ok := c.transport.(*transport.StreamableHTTP); ok {
@@ -164,21 +550,35 @@ func (c *HTTPClient) GetSessionID() string {
 }
 
 // Ping sends a ping request to the server and waits for a response.
-// It returns an error if the ping fails.
+// It returns the measured round-trip time, or an error if the ping fails.
 // See: http://spec.modelcontextprotocol.io/2025-03-26/base-protocol#ping
-func (c *HTTPClient) Ping(ctx context.Context) error {
-	return c.transport.Ping(ctx)
+func (c *HTTPClient) Ping(ctx context.Context) (time.Duration, error) {
+	request := transport.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      c.nextRequestID(),
+		Method:  "ping",
+	}
+	start := time.Now()
+	response, err := c.send(ctx, request)
+	latency := time.Since(start)
+	if err != nil {
+		return 0, fmt.Errorf("ping failed: %w", err)
+	}
+	if response.Error != nil {
+		return 0, fmt.Errorf("ping failed: %w", response.Error)
+	}
+	return latency, nil
 }
 
 // RawRequest sends a request and returns the full JSON-RPC envelope as bytes.
 func (c *HTTPClient) RawRequest(ctx context.Context, method string, params interface{}) ([]byte, error) {
 	request := transport.JSONRPCRequest{
 		JSONRPC: "2.0",
-		ID:      fmt.Sprintf("%d", time.Now().UnixNano()),
+		ID:      c.nextRequestID(),
 		Method:  method,
 		Params:  params,
 	}
-	response, err := c.transport.SendRequest(ctx, request)
+	response, err := c.send(ctx, request)
 	if err != nil {
 		return nil, err
 	}
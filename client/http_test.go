@@ -2,13 +2,31 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
 )
 
+// startTestServer starts an in-process MCP server for client tests.
+func startTestServer() *mcptest.Server {
+	srv := mcptest.NewServer()
+	srv.Start()
+	return srv
+}
+
 func TestHTTPClient(t *testing.T) {
+	srv := startTestServer()
+	defer srv.Close()
+
 	// Create client with custom protocol version
 	client, err := NewHTTPClient(&Options{
-		BaseURL:         "http://localhost:62770",
+		BaseURL:         srv.URL,
 		Debug:           true,
 		ProtocolVersion: "2025-03-26",
 	})
@@ -28,8 +46,11 @@ func TestHTTPClient(t *testing.T) {
 
 func TestProtocolVersionSentCorrectly(t *testing.T) {
 	// Create client with no explicit protocol version
+	srv := startTestServer()
+	defer srv.Close()
+
 	client, err := NewHTTPClient(&Options{
-		BaseURL: "http://localhost:62770",
+		BaseURL: srv.URL,
 		Debug:   true,
 	})
 	if err != nil {
@@ -43,12 +64,31 @@ func TestProtocolVersionSentCorrectly(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Initialize failed: %v", err)
 	}
+
+	// Check the default protocol version was sent
+	for _, request := range srv.Requests() {
+		if request.Method != "initialize" {
+			continue
+		}
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			t.Fatalf("Failed to decode initialize params: %v", err)
+		}
+		if params.ProtocolVersion != "2025-03-26" {
+			t.Errorf("Expected protocol version 2025-03-26, got %q", params.ProtocolVersion)
+		}
+	}
 }
 
 func TestPing(t *testing.T) {
 	// Create client
+	srv := startTestServer()
+	defer srv.Close()
+
 	client, err := NewHTTPClient(&Options{
-		BaseURL: "http://localhost:62770",
+		BaseURL: srv.URL,
 		Debug:   true,
 	})
 	if err != nil {
@@ -64,8 +104,184 @@ func TestPing(t *testing.T) {
 	}
 
 	// Test Ping
-	err = client.Ping(ctx)
+	latency, err := client.Ping(ctx)
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if latency <= 0 {
+		t.Errorf("Expected a positive latency, got %s", latency)
+	}
+
+	// Strict servers reject unexpected params, so ping must not send any
+	requests := srv.Requests()
+	if last := requests[len(requests)-1]; last.Method != "ping" || len(last.Params) != 0 {
+		t.Errorf("Expected a ping without params, got %s %s", last.Method, last.Params)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	server := startTestServer()
+	defer server.Close()
+
+	var mu sync.Mutex
+	var requests, responses []string
+	var errs []error
+	client, err := NewHTTPClient(&Options{
+		BaseURL: server.URL,
+		Hooks: &Hooks{
+			OnRequest: func(ctx context.Context, request *transport.JSONRPCRequest) {
+				mu.Lock()
+				defer mu.Unlock()
+				requests = append(requests, request.Method)
+			},
+			OnResponse: func(ctx context.Context, request *transport.JSONRPCRequest, response *transport.JSONRPCResponse, elapsed time.Duration) {
+				mu.Lock()
+				defer mu.Unlock()
+				responses = append(responses, request.Method)
+				if elapsed <= 0 {
+					t.Errorf("expected positive elapsed time for %s", request.Method)
+				}
+			},
+			OnError: func(ctx context.Context, request *transport.JSONRPCRequest, err error, elapsed time.Duration) {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, err)
+			},
+		},
+	})
 	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if _, err := client.Ping(ctx); err != nil {
 		t.Fatalf("Ping failed: %v", err)
 	}
+	if _, err := client.Request(ctx, "fail", nil); err == nil {
+		t.Fatalf("Expected error for failing method")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []string{"initialize", "ping", "fail"}
+	if fmt.Sprint(requests) != fmt.Sprint(expected) {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+	if fmt.Sprint(responses) != fmt.Sprint(expected) {
+		t.Errorf("Expected responses %v, got %v", expected, responses)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %d", len(errs))
+	}
+}
+
+func TestRequestIDs(t *testing.T) {
+	server := startTestServer()
+	defer server.Close()
+
+	var mu sync.Mutex
+	var ids []mcp.RequestId
+	var n int
+	client, err := NewHTTPClient(&Options{
+		BaseURL: server.URL,
+		Hooks: &Hooks{
+			OnRequest: func(ctx context.Context, request *transport.JSONRPCRequest) {
+				mu.Lock()
+				defer mu.Unlock()
+				ids = append(ids, request.ID)
+			},
+		},
+	}, WithRequestIDs(func() mcp.RequestId {
+		mu.Lock()
+		defer mu.Unlock()
+		n++
+		return mcp.NewStringRequestId(fmt.Sprintf("req-%d", n))
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []mcp.RequestId{mcp.NewStringRequestId("req-1"), mcp.NewStringRequestId("req-2")}
+	if fmt.Sprint(ids) != fmt.Sprint(expected) || ids[0] != expected[0] {
+		t.Errorf("Expected IDs %v, got %v", expected, ids)
+	}
+}
+
+func TestCancellationNotification(t *testing.T) {
+	server := startTestServer()
+	server.SetBehavior("slow", mcptest.Behavior{Delay: 5 * time.Second})
+	defer server.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.Request(ctx, "slow", nil); err == nil {
+		t.Fatalf("Expected the request to time out")
+	}
+
+	// The notification is sent in the background
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		var slowID mcp.RequestId
+		for _, request := range server.Requests() {
+			if request.Method == "slow" {
+				slowID = request.ID
+			}
+			if request.Method != "notifications/cancelled" {
+				continue
+			}
+			var params struct {
+				RequestID mcp.RequestId `json:"requestId"`
+				Reason    string        `json:"reason"`
+			}
+			if err := json.Unmarshal(request.Params, &params); err != nil {
+				t.Fatalf("Invalid params: %v", err)
+			}
+			if params.RequestID != slowID {
+				t.Errorf("Expected requestId %v, got %v", slowID, params.RequestID)
+			}
+			if params.Reason != context.DeadlineExceeded.Error() {
+				t.Errorf("Unexpected reason %q", params.Reason)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected a notifications/cancelled to be sent")
+}
+
+func TestServerInfo(t *testing.T) {
+	srv := startTestServer()
+	srv.SetInstructions("Use the echo tool to repeat text.")
+	srv.AddTool(mcp.Tool{Name: "echo"}, nil)
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if info := client.ServerInfo(); info.Name != "mcptest" || info.Version == "" {
+		t.Errorf("Unexpected server info: %+v", info)
+	}
+	capabilities := client.ServerCapabilities()
+	if capabilities.Tools == nil || capabilities.Prompts != nil {
+		t.Errorf("Unexpected capabilities: %+v", capabilities)
+	}
+	if got := client.Instructions(); got != "Use the echo tool to repeat text." {
+		t.Errorf("Unexpected instructions: %q", got)
+	}
 }
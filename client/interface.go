@@ -3,26 +3,59 @@ package client
 import (
 	"context"
 	"io"
+	"net/http"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
 )
 
-// Interface for MCP client
+// Interface is the MCP client API, implemented by HTTPClient over Streamable
+// HTTP and stdio, so that consumers can program against it and substitute
+// fakes in tests.
 type Interface interface {
 	// Initialize initializes the client with the server
 	Initialize(ctx context.Context) error
-	
+
 	// Close closes the client connection
 	Close() error
-	
+
+	// Ping checks that the server is responsive and returns the round-trip time
+	Ping(ctx context.Context) (time.Duration, error)
+
+	// ServerInfo returns the name and version the server reported during initialization
+	ServerInfo() mcp.Implementation
+
+	// ServerCapabilities returns the capabilities the server declared during initialization
+	ServerCapabilities() mcp.ServerCapabilities
+
+	// ListTools returns a page of the tools offered by the server
+	ListTools(ctx context.Context, cursor mcp.Cursor) (*mcp.ListToolsResult, error)
+
+	// CallTool invokes a tool on the server
+	CallTool(ctx context.Context, name string, arguments map[string]any) (*mcp.CallToolResult, error)
+
+	// ListResources returns a page of the resources offered by the server
+	ListResources(ctx context.Context, cursor mcp.Cursor) (*mcp.ListResourcesResult, error)
+
+	// ReadResource returns the contents of a resource
+	ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error)
+
+	// ListPrompts returns a page of the prompts offered by the server
+	ListPrompts(ctx context.Context, cursor mcp.Cursor) (*mcp.ListPromptsResult, error)
+
+	// GetPrompt returns a prompt rendered with the given arguments
+	GetPrompt(ctx context.Context, name string, arguments map[string]string) (*mcp.GetPromptResult, error)
+
 	// Request makes a request to the server with custom parameters
 	Request(ctx context.Context, method string, params interface{}) ([]byte, error)
-	
-	// SendInput sends input to the server
-	SendInput(ctx context.Context, input string) error
-	
+
 	// GetSessionID returns the current session ID
 	GetSessionID() string
-	
-	// SetNotificationHandler sets a handler for server notifications
+
+	// Subscribe registers a handler for the server notifications matching a method pattern
+	Subscribe(pattern string, handler NotificationHandler) (unsubscribe func())
+
+	// SetNotificationHandler sets a handler for every server notification
 	SetNotificationHandler(handler func(method string, params map[string]interface{}))
 }
 
@@ -30,30 +63,78 @@ type Interface interface {
 type Options struct {
 	// BaseURL is the server URL to connect to
 	BaseURL string
-	
+
+	// HTTPClient sends the requests, a client of the transport's own if nil;
+	// ConnectTimeout and ResponseHeaderTimeout do not apply to it
+	HTTPClient *http.Client
+
 	// Headers are additional HTTP headers to include in requests
 	Headers map[string]string
-	
-	// Timeout is the request timeout
+
+	// Timeout is the deadline of each request in seconds, including
+	// responses streamed with SSE
 	Timeout int
-	
+
+	// ConnectTimeout limits how long connecting to the server takes
+	ConnectTimeout time.Duration
+
+	// ResponseHeaderTimeout limits how long the server takes to start
+	// answering a request
+	ResponseHeaderTimeout time.Duration
+
+	// IdleStreamTimeout ends SSE streams on which nothing is received for
+	// that long
+	IdleStreamTimeout time.Duration
+
+	// MaxResponseSize limits the size of responses and SSE events in bytes,
+	// transport.DefaultMaxResponseSize if zero
+	MaxResponseSize int64
+
+	// CompressionThreshold gzips the requests of at least that many bytes
+	// when positive; the server must accept gzip-encoded requests
+	CompressionThreshold int
+
+	// OnUnauthorized is called when the server answers 401 Unauthorized, with
+	// its WWW-Authenticate header, to get a new bearer token; the request is
+	// then retried once with it
+	OnUnauthorized func(ctx context.Context, wwwAuthenticate string) (newToken string, err error)
+
+	// ProtectedResource and AuthorizationServer are the authorization
+	// metadata of the server, as found by Discover, for OnUnauthorized to
+	// get tokens from; they are not used by the client itself
+	ProtectedResource   *ProtectedResourceMetadata
+	AuthorizationServer *AuthorizationServerMetadata
+
+	// LazyInit defers starting the transport and initializing the session to
+	// the first request or to an explicit Initialize, instead of the
+	// constructor
+	LazyInit bool
+
+	// Listen opens an SSE stream after initialization so that the server can
+	// send notifications and requests while no request is in flight
+	Listen bool
+
 	// Debug enables debug logging
 	Debug bool
-	
+
 	// Logger provides a custom logger
 	Logger io.Writer
-	
+
 	// ProtocolVersion specifies the MCP protocol version to use
 	// If not provided, defaults to "2025-03-26"
 	ProtocolVersion string
-	
+
 	// Capabilities defines the client capabilities to advertise to the server
-	// If not provided, default capabilities will be used
+	// Capabilities of handlers registered with WithRoots, WithSampling and
+	// WithExperimental are added on top
 	Capabilities map[string]interface{}
+
+	// Hooks are invoked at each stage of a request and for every notification
+	Hooks *Hooks
 }
 
 // Config represents client configuration
 type Config struct {
 	// Options contains user-provided configuration
 	Options *Options
-}
\ No newline at end of file
+}
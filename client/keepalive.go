@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultUnhealthyThreshold is the number of consecutive failed keepalive
+// pings after which the server is considered unhealthy.
+const DefaultUnhealthyThreshold = 3
+
+// keepAlive pings the server in the background and tracks its health.
+type keepAlive struct {
+	interval    time.Duration
+	threshold   int
+	onUnhealthy func(failures int, err error)
+	onRecovered func()
+
+	mu        sync.Mutex
+	failures  int
+	unhealthy bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// WithKeepAlive makes the client ping the server every interval for as long
+// as it is open. See WithHealthCallbacks to be told when the server stops answering.
+func WithKeepAlive(interval time.Duration) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.keepAliveConfig().interval = interval
+	}
+}
+
+// WithUnhealthyThreshold sets how many consecutive keepalive pings must fail
+// before the server is considered unhealthy. Defaults to DefaultUnhealthyThreshold.
+func WithUnhealthyThreshold(failures int) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.keepAliveConfig().threshold = failures
+	}
+}
+
+// WithHealthCallbacks sets the functions called when keepalive pings start
+// failing and when the server answers again. Either may be nil.
+// The callbacks run on the keepalive goroutine.
+func WithHealthCallbacks(onUnhealthy func(failures int, err error), onRecovered func()) HTTPClientOption {
+	return func(c *HTTPClient) {
+		ka := c.keepAliveConfig()
+		ka.onUnhealthy = onUnhealthy
+		ka.onRecovered = onRecovered
+	}
+}
+
+// keepAliveConfig returns the keepalive settings, creating them if needed.
+func (c *HTTPClient) keepAliveConfig() *keepAlive {
+	if c.keepAlive == nil {
+		c.keepAlive = &keepAlive{threshold: DefaultUnhealthyThreshold}
+	}
+	return c.keepAlive
+}
+
+// Healthy reports whether the server is answering keepalive pings.
+// It is always true when keepalive is disabled.
+func (c *HTTPClient) Healthy() bool {
+	if c.keepAlive == nil {
+		return true
+	}
+	c.keepAlive.mu.Lock()
+	defer c.keepAlive.mu.Unlock()
+	return !c.keepAlive.unhealthy
+}
+
+// startKeepAlive launches the keepalive goroutine if an interval is configured.
+func (c *HTTPClient) startKeepAlive() {
+	ka := c.keepAlive
+	if ka == nil || ka.interval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ka.cancel = cancel
+	ka.done = make(chan struct{})
+
+	go func() {
+		defer close(ka.done)
+		ticker := time.NewTicker(ka.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			pingCtx, cancel := context.WithTimeout(ctx, ka.interval)
+			_, err := c.Ping(pingCtx)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+			ka.record(err)
+		}
+	}()
+}
+
+// stopKeepAlive stops the keepalive goroutine and waits for it to exit.
+func (c *HTTPClient) stopKeepAlive() {
+	ka := c.keepAlive
+	if ka == nil || ka.cancel == nil {
+		return
+	}
+	ka.cancel()
+	<-ka.done
+}
+
+// record updates the health state with the outcome of a ping and fires the
+// callbacks on transitions.
+func (ka *keepAlive) record(err error) {
+	ka.mu.Lock()
+	if err == nil {
+		recovered := ka.unhealthy
+		ka.failures = 0
+		ka.unhealthy = false
+		ka.mu.Unlock()
+		if recovered && ka.onRecovered != nil {
+			ka.onRecovered()
+		}
+		return
+	}
+
+	ka.failures++
+	failures := ka.failures
+	becameUnhealthy := !ka.unhealthy && failures >= ka.threshold
+	if becameUnhealthy {
+		ka.unhealthy = true
+	}
+	ka.mu.Unlock()
+	if becameUnhealthy && ka.onUnhealthy != nil {
+		ka.onUnhealthy(failures, err)
+	}
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestKeepAlive(t *testing.T) {
+	srv := startTestServer()
+	defer srv.Close()
+
+	unhealthy := make(chan int, 1)
+	recovered := make(chan struct{}, 1)
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL},
+		WithKeepAlive(20*time.Millisecond),
+		WithUnhealthyThreshold(2),
+		WithHealthCallbacks(
+			func(failures int, err error) { unhealthy <- failures },
+			func() { recovered <- struct{}{} },
+		),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if !client.Healthy() {
+		t.Errorf("Expected a new client to be healthy")
+	}
+
+	srv.SetBehavior("ping", mcptest.Behavior{Error: mcp.NewError(mcp.ErrorInternalError, "down")})
+	select {
+	case failures := <-unhealthy:
+		if failures != 2 {
+			t.Errorf("Expected to turn unhealthy after 2 failures, got %d", failures)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected OnUnhealthy to be called")
+	}
+	if client.Healthy() {
+		t.Errorf("Expected the client to report unhealthy")
+	}
+
+	srv.SetBehavior("ping", mcptest.Behavior{})
+	select {
+	case <-recovered:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected OnRecovered to be called")
+	}
+	if !client.Healthy() {
+		t.Errorf("Expected the client to report healthy again")
+	}
+
+	// Close stops the pings
+	client.Close()
+	count := len(srv.Requests())
+	time.Sleep(60 * time.Millisecond)
+	if got := len(srv.Requests()); got != count {
+		t.Errorf("Expected no pings after Close, got %d more requests", got-count)
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+var (
+	// ErrNotInitialized is returned for requests sent before the session is
+	// initialized, other than pings.
+	ErrNotInitialized = errors.New("client not initialized")
+	// ErrClosed is returned for requests sent after Close.
+	ErrClosed = errors.New("client closed")
+)
+
+// State is the lifecycle state of a client.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/lifecycle
+type State int32
+
+const (
+	// StateCreated is a client that has not initialized a session yet
+	StateCreated State = iota
+	// StateInitializing is a client waiting for the answer to initialize
+	StateInitializing
+	// StateReady is a client with an initialized session
+	StateReady
+	// StateClosed is a client that was closed
+	StateClosed
+)
+
+func (s State) String() string {
+	switch s {
+	case StateCreated:
+		return "created"
+	case StateInitializing:
+		return "initializing"
+	case StateReady:
+		return "ready"
+	case StateClosed:
+		return "closed"
+	}
+	return fmt.Sprintf("State(%d)", int32(s))
+}
+
+// State returns the lifecycle state of the client. Initializing a ready
+// client again keeps it ready.
+func (c *HTTPClient) State() State {
+	return State(c.state.Load())
+}
+
+// setState moves the client from state from to state to, and reports whether
+// it was in state from.
+func (c *HTTPClient) setState(from, to State) bool {
+	return c.state.CompareAndSwap(int32(from), int32(to))
+}
+
+// checkState returns an error if method may not be sent in the current state.
+// Only initialize and pings may be sent before the session is initialized.
+func (c *HTTPClient) checkState(method string) error {
+	switch c.State() {
+	case StateClosed:
+		return fmt.Errorf("%s: %w", method, ErrClosed)
+	case StateReady:
+		return nil
+	}
+	if method == string(mcp.MethodInitialize) || method == string(mcp.MethodPing) {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", method, ErrNotInitialized)
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLifecycle(t *testing.T) {
+	srv := startTestServer()
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("Initialized", func(t *testing.T) {
+		if client.State() != StateReady {
+			t.Errorf("Expected ready, got %s", client.State())
+		}
+		var methods []string
+		for _, request := range srv.Requests() {
+			methods = append(methods, request.Method)
+		}
+		if len(methods) < 2 || methods[0] != "initialize" || methods[1] != "notifications/initialized" {
+			t.Errorf("Expected initialize then notifications/initialized, got %v", methods)
+		}
+		if err := client.Initialize(ctx); err != nil || client.State() != StateReady {
+			t.Errorf("Expected re-initializing to keep the client ready, got %v, %s", err, client.State())
+		}
+	})
+
+	t.Run("NotInitialized", func(t *testing.T) {
+		created := &HTTPClient{}
+		if created.State() != StateCreated {
+			t.Errorf("Expected created, got %s", created.State())
+		}
+		if err := created.checkState("tools/list"); !errors.Is(err, ErrNotInitialized) {
+			t.Errorf("Expected ErrNotInitialized, got %v", err)
+		}
+		if err := created.checkState("ping"); err != nil {
+			t.Errorf("Expected pings to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if client.State() != StateClosed {
+			t.Errorf("Expected closed, got %s", client.State())
+		}
+		sent := len(srv.Requests())
+		if _, err := client.Request(ctx, "tools/list", nil); !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v", err)
+		}
+		if err := client.Initialize(ctx); !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v", err)
+		}
+		if got := len(srv.Requests()); got != sent {
+			t.Errorf("Expected no requests after Close, got %d", got-sent)
+		}
+		if err := client.Close(); err != nil {
+			t.Errorf("Expected closing again to succeed, got %v", err)
+		}
+	})
+}
+
+func TestLazyInit(t *testing.T) {
+	srv := startTestServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	t.Run("FirstRequest", func(t *testing.T) {
+		client, err := NewHTTPClientContext(ctx, &Options{BaseURL: srv.URL, LazyInit: true})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer client.Close()
+		if client.State() != StateCreated || len(srv.Requests()) != 0 {
+			t.Fatalf("Expected no request before the first call, got %s and %d requests", client.State(), len(srv.Requests()))
+		}
+		if _, err := client.Ping(ctx); err != nil {
+			t.Fatalf("Ping failed: %v", err)
+		}
+		if client.State() != StateReady || srv.Requests()[0].Method != "initialize" {
+			t.Errorf("Expected the first call to initialize, got %s and %+v", client.State(), srv.Requests())
+		}
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		client, err := NewHTTPClientContext(ctx, &Options{BaseURL: "http://127.0.0.1:1", LazyInit: true})
+		if err != nil {
+			t.Fatalf("Expected construction to succeed without a server, got %v", err)
+		}
+		defer client.Close()
+		if err := client.Initialize(ctx); err == nil || client.State() != StateCreated {
+			t.Errorf("Expected initialization to fail and the client to stay created, got %v, %s", err, client.State())
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := NewHTTPClientContext(canceled, &Options{BaseURL: srv.URL}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the context to end the initialization, got %v", err)
+		}
+	})
+}
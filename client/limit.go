@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithMaxConcurrentRequests limits the requests in flight to n, so that
+// fanning out many tool calls does not open as many HTTP requests and SSE
+// streams against the server. Further requests wait for one to complete, or
+// for their context to end. Zero or less means no limit.
+func WithMaxConcurrentRequests(n int) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.requestSlots = nil
+		if n > 0 {
+			c.requestSlots = make(chan struct{}, n)
+		}
+	}
+}
+
+// acquireRequestSlot waits until a request may be sent, and returns the
+// function to call once it completes.
+func (c *HTTPClient) acquireRequestSlot(ctx context.Context) (release func(), err error) {
+	if c.requestSlots == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.requestSlots <- struct{}{}:
+		return func() { <-c.requestSlots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to wait for a request slot: %w", context.Cause(ctx))
+	}
+}
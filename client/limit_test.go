@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := startTestServer()
+	srv.AddTool(mcp.Tool{Name: "slow"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return mcp.NewToolResultText("ok"), nil
+	})
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithMaxConcurrentRequests(2))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	t.Run("Limit", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.CallTool(ctx, "slow", nil); err != nil {
+					t.Errorf("CallTool failed: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+		if got := peak.Load(); got != 2 {
+			t.Errorf("Expected at most 2 calls in flight, got %d", got)
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		release1, _ := client.acquireRequestSlot(ctx)
+		release2, _ := client.acquireRequestSlot(ctx)
+		defer release1()
+		defer release2()
+
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		if _, err := client.CallTool(ctx, "slow", nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the deadline to end the wait, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// ToolNameSeparator separates the server name from the tool name in the
+// namespaced tool names used by Manager, as in "github.create_issue".
+const ToolNameSeparator = "."
+
+// ServerNotificationHandler receives a notification sent by one of the servers of a Manager.
+type ServerNotificationHandler func(server string, notification mcp.Notification)
+
+// managedServer is a client held by a Manager.
+type managedServer struct {
+	client      *HTTPClient
+	unsubscribe func()
+}
+
+// managerSubscription is a handler registered on a Manager for a method pattern.
+type managerSubscription struct {
+	pattern string
+	handler ServerNotificationHandler
+}
+
+// Manager holds connections to several MCP servers, identified by name. It
+// exposes their tools under namespaced "server.tool" names, routes tool calls
+// to the right server and merges their notifications.
+type Manager struct {
+	mu            sync.RWMutex
+	servers       map[string]*managedServer
+	subscriptions []*managerSubscription
+}
+
+// NewManager creates an empty Manager.
+func NewManager() *Manager {
+	return &Manager{servers: map[string]*managedServer{}}
+}
+
+// Add registers an initialized client under name. The Manager takes
+// ownership of the client and closes it on Remove or Close.
+// Names must be unique and must not contain ToolNameSeparator.
+func (m *Manager) Add(name string, client *HTTPClient) error {
+	if name == "" || strings.Contains(name, ToolNameSeparator) {
+		return fmt.Errorf("invalid server name %q", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.servers[name]; ok {
+		return fmt.Errorf("server %q already exists", name)
+	}
+	m.servers[name] = &managedServer{
+		client: client,
+		unsubscribe: client.Subscribe("*", func(notification mcp.Notification) {
+			m.dispatch(name, notification)
+		}),
+	}
+	return nil
+}
+
+// Remove closes the client registered under name and forgets it.
+func (m *Manager) Remove(name string) error {
+	m.mu.Lock()
+	server, ok := m.servers[name]
+	delete(m.servers, name)
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown server %q", name)
+	}
+
+	server.unsubscribe()
+	return server.client.Close()
+}
+
+// Client returns the client registered under name.
+func (m *Manager) Client(name string) (*HTTPClient, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	server, ok := m.servers[name]
+	if !ok {
+		return nil, false
+	}
+	return server.client, true
+}
+
+// Servers returns the names of the registered servers, sorted.
+func (m *Manager) Servers() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.servers))
+	for name := range m.servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListTools returns the tools of every server, renamed to "server.tool".
+// Servers that fail to answer are skipped and reported in the returned error,
+// alongside the tools of the others.
+func (m *Manager) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	var (
+		tools []mcp.Tool
+		errs  []error
+	)
+	for _, name := range m.Servers() {
+		client, ok := m.Client(name)
+		if !ok {
+			continue
+		}
+		serverTools, err := allTools(ctx, client)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("server %s: %w", name, err))
+			continue
+		}
+		for _, tool := range serverTools {
+			tool.Name = name + ToolNameSeparator + tool.Name
+			tools = append(tools, tool)
+		}
+	}
+	return tools, errors.Join(errs...)
+}
+
+// CallTool calls a tool by its namespaced "server.tool" name.
+func (m *Manager) CallTool(ctx context.Context, name string, arguments map[string]any) (*mcp.CallToolResult, error) {
+	server, tool, ok := strings.Cut(name, ToolNameSeparator)
+	if !ok {
+		return nil, fmt.Errorf("tool name %q is not namespaced with a server", name)
+	}
+	client, ok := m.Client(server)
+	if !ok {
+		return nil, fmt.Errorf("unknown server %q for tool %q", server, name)
+	}
+	return client.CallTool(ctx, tool, arguments)
+}
+
+// Subscribe registers handler for the notifications of every server, present
+// and future, whose method matches pattern. Patterns are those of
+// HTTPClient.Subscribe. It returns a function that removes the handler.
+func (m *Manager) Subscribe(pattern string, handler ServerNotificationHandler) (unsubscribe func()) {
+	sub := &managerSubscription{pattern: pattern, handler: handler}
+	m.mu.Lock()
+	m.subscriptions = append(m.subscriptions, sub)
+	m.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			for i, s := range m.subscriptions {
+				if s == sub {
+					m.subscriptions = append(m.subscriptions[:i:i], m.subscriptions[i+1:]...)
+					return
+				}
+			}
+		})
+	}
+}
+
+// dispatch passes a notification from server to every matching subscription.
+func (m *Manager) dispatch(server string, notification mcp.Notification) {
+	m.mu.RLock()
+	subscriptions := m.subscriptions
+	m.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		if matchMethod(sub.pattern, notification.Method) {
+			sub.handler(server, notification)
+		}
+	}
+}
+
+// Close closes every server and empties the Manager.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	servers := m.servers
+	m.servers = map[string]*managedServer{}
+	m.mu.Unlock()
+
+	var errs []error
+	for name, server := range servers {
+		server.unsubscribe()
+		if err := server.client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("server %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// allTools lists the tools of client, following pagination.
+func allTools(ctx context.Context, client *HTTPClient) ([]mcp.Tool, error) {
+	var (
+		tools  []mcp.Tool
+		cursor mcp.Cursor
+	)
+	for {
+		result, err := client.ListTools(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = result.NextCursor
+	}
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestManager(t *testing.T) {
+	newServer := func(name string) *mcptest.Server {
+		srv := mcptest.NewServer()
+		srv.AddTool(mcp.Tool{Name: "whoami"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(name), nil
+		})
+		srv.SetBehavior("ping", mcptest.Behavior{Notifications: []mcp.JSONRPCNotification{
+			{JSONRPC: mcp.JSONRPC_VERSION, Method: "notifications/tools/list_changed"},
+		}})
+		srv.Start()
+		return srv
+	}
+
+	manager := NewManager()
+	defer manager.Close()
+	for _, name := range []string{"alpha", "beta"} {
+		srv := newServer(name)
+		defer srv.Close()
+		client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if err := manager.Add(name, client); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	tools, err := manager.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "alpha.whoami" || names[1] != "beta.whoami" {
+		t.Errorf("Unexpected namespaced tools: %v", names)
+	}
+
+	result, err := manager.CallTool(ctx, "beta.whoami", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "beta" {
+		t.Errorf("Expected the call to be routed to beta, got %+v", result.Content)
+	}
+
+	t.Run("notifications", func(t *testing.T) {
+		var from []string
+		unsubscribe := manager.Subscribe("notifications/tools/*", func(server string, notification mcp.Notification) {
+			from = append(from, server)
+		})
+		defer unsubscribe()
+
+		for _, name := range manager.Servers() {
+			client, _ := manager.Client(name)
+			if _, err := client.Ping(ctx); err != nil {
+				t.Fatalf("Ping failed: %v", err)
+			}
+		}
+		if len(from) != 2 || from[0] != "alpha" || from[1] != "beta" {
+			t.Errorf("Expected a notification from each server, got %v", from)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, name := range []string{"whoami", "gamma.whoami"} {
+			if _, err := manager.CallTool(ctx, name, nil); err == nil {
+				t.Errorf("Expected CallTool(%q) to fail", name)
+			}
+		}
+		client, _ := manager.Client("alpha")
+		for _, name := range []string{"alpha", "", "a.b"} {
+			if err := manager.Add(name, client); err == nil {
+				t.Errorf("Expected Add(%q) to fail", name)
+			}
+		}
+	})
+
+	if err := manager.Remove("alpha"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if servers := manager.Servers(); len(servers) != 1 || servers[0] != "beta" {
+		t.Errorf("Unexpected servers after Remove: %v", servers)
+	}
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// NotificationHandler receives a server notification.
+// Use DecodeNotification to get the typed form of known notifications.
+type NotificationHandler func(notification mcp.Notification)
+
+// subscription is a handler registered for a method pattern.
+type subscription struct {
+	pattern string
+	handler NotificationHandler
+}
+
+// notificationRouter fans notifications out to the subscriptions matching their method.
+type notificationRouter struct {
+	mu            sync.RWMutex
+	subscriptions []*subscription
+}
+
+// Subscribe registers handler for the notifications whose method matches
+// pattern, and returns a function that removes it.
+//
+// A pattern is either an exact method, "*" for every notification, or a prefix
+// ending in "*" such as "notifications/resources/*". A notification is passed to
+// every matching handler, in the order they subscribed. Handlers run on the
+// goroutine reading the server's responses and should not block.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/base-protocol#notifications
+func (c *HTTPClient) Subscribe(pattern string, handler NotificationHandler) (unsubscribe func()) {
+	sub := &subscription{pattern: pattern, handler: handler}
+	r := &c.notifications
+	r.mu.Lock()
+	r.subscriptions = append(r.subscriptions, sub)
+	r.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			for i, s := range r.subscriptions {
+				if s == sub {
+					r.subscriptions = append(r.subscriptions[:i:i], r.subscriptions[i+1:]...)
+					return
+				}
+			}
+		})
+	}
+}
+
+// dispatch passes notification to every matching subscription.
+func (r *notificationRouter) dispatch(notification mcp.Notification) {
+	r.mu.RLock()
+	subscriptions := r.subscriptions
+	r.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		if matchMethod(sub.pattern, notification.Method) {
+			sub.handler(notification)
+		}
+	}
+}
+
+// matchMethod reports whether method matches a subscription pattern.
+func matchMethod(pattern, method string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(method, prefix)
+	}
+	return pattern == method
+}
+
+// DecodeNotification decodes a notification into its typed form:
+// *mcp.ProgressNotification, *mcp.LoggingMessageNotification,
+// *mcp.CancelledNotification, *mcp.ResourceUpdatedNotification,
+// *mcp.ResourceListChangedNotification, *mcp.ToolListChangedNotification or
+// *mcp.PromptListChangedNotification. Other methods are returned as a *mcp.Notification.
+func DecodeNotification(notification mcp.Notification) (any, error) {
+	var typed any
+	switch mcp.MCPMethod(notification.Method) {
+	case mcp.MethodNotificationProgress:
+		typed = &mcp.ProgressNotification{}
+	case mcp.MethodNotificationLoggingMessage:
+		typed = &mcp.LoggingMessageNotification{}
+	case mcp.MethodNotificationCancelled:
+		typed = &mcp.CancelledNotification{}
+	case mcp.MethodNotificationResourceUpdated:
+		typed = &mcp.ResourceUpdatedNotification{}
+	case mcp.MethodNotificationResourcesListChanged:
+		typed = &mcp.ResourceListChangedNotification{}
+	case mcp.MethodNotificationToolsListChanged:
+		typed = &mcp.ToolListChangedNotification{}
+	case mcp.MethodNotificationPromptsListChanged:
+		typed = &mcp.PromptListChangedNotification{}
+	default:
+		return &notification, nil
+	}
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	if err := json.Unmarshal(data, typed); err != nil {
+		return nil, fmt.Errorf("failed to decode %s notification: %w", notification.Method, err)
+	}
+	return typed, nil
+}
+
+// OverflowPolicy decides what a notification channel does when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// DropOldest discards the oldest buffered notification to make room
+	DropOldest OverflowPolicy = iota
+	// Block waits for the consumer. It stalls every response and notification
+	// from the server until the channel is read, so consumers must keep up.
+	Block
+)
+
+// DefaultNotificationBuffer is the buffer size of channels returned by Notifications.
+const DefaultNotificationBuffer = 64
+
+// WithNotificationBuffer sets the buffer size and overflow policy of the
+// channels returned by Notifications.
+func WithNotificationBuffer(size int, policy OverflowPolicy) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.notificationBuffer = size
+		c.overflowPolicy = policy
+	}
+}
+
+// Notifications returns a channel receiving the server notifications whose
+// method matches one of the patterns, as accepted by Subscribe, or every
+// notification if none is given. The channel is closed when ctx is done.
+// See WithNotificationBuffer for the buffer size and what happens when it fills up.
+func (c *HTTPClient) Notifications(ctx context.Context, patterns ...string) <-chan mcp.JSONRPCNotification {
+	size := c.notificationBuffer
+	if size <= 0 {
+		size = DefaultNotificationBuffer
+	}
+	ch := make(chan mcp.JSONRPCNotification, size)
+	policy := c.overflowPolicy
+
+	var (
+		mu     sync.Mutex
+		closed bool
+	)
+	unsubscribe := c.Subscribe("*", func(notification mcp.Notification) {
+		if !matchAny(patterns, notification.Method) {
+			return
+		}
+		message := mcp.JSONRPCNotification{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			Method:  notification.Method,
+			Params:  notification.Params,
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		if policy == Block {
+			select {
+			case ch <- message:
+			case <-ctx.Done():
+			}
+			return
+		}
+		for {
+			select {
+			case ch <- message:
+				return
+			default:
+			}
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	})
+
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+		mu.Lock()
+		closed = true
+		close(ch)
+		mu.Unlock()
+	}()
+	return ch
+}
+
+// matchAny reports whether method matches one of patterns, or whether there are none.
+func matchAny(patterns []string, method string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matchMethod(pattern, method) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestSubscribe(t *testing.T) {
+	srv := startTestServer()
+	srv.SetBehavior("ping", mcptest.Behavior{Notifications: []mcp.JSONRPCNotification{
+		{JSONRPC: mcp.JSONRPC_VERSION, Method: "notifications/resources/updated", Params: map[string]any{"uri": "file:///a.txt"}},
+		{JSONRPC: mcp.JSONRPC_VERSION, Method: "notifications/tools/list_changed"},
+	}})
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	var exact, prefix, all []string
+	client.Subscribe("notifications/tools/list_changed", func(n mcp.Notification) { exact = append(exact, n.Method) })
+	client.Subscribe("notifications/resources/*", func(n mcp.Notification) { prefix = append(prefix, n.Method) })
+	unsubscribe := client.Subscribe("*", func(n mcp.Notification) { all = append(all, n.Method) })
+
+	ctx := context.Background()
+	if _, err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if len(exact) != 1 || exact[0] != "notifications/tools/list_changed" {
+		t.Errorf("Unexpected exact matches: %v", exact)
+	}
+	if len(prefix) != 1 || prefix[0] != "notifications/resources/updated" {
+		t.Errorf("Unexpected prefix matches: %v", prefix)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected the wildcard to match both notifications, got %v", all)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	if _, err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if len(all) != 2 || len(exact) != 2 || len(prefix) != 2 {
+		t.Errorf("Expected only the remaining subscriptions to be called, got %v %v %v", all, exact, prefix)
+	}
+}
+
+func TestDecodeNotification(t *testing.T) {
+	decoded, err := DecodeNotification(mcp.Notification{
+		Method: "notifications/progress",
+		Params: map[string]any{"progressToken": "t", "progress": 1.0, "total": 2.0, "message": "half"},
+	})
+	if err != nil {
+		t.Fatalf("DecodeNotification failed: %v", err)
+	}
+	progress, ok := decoded.(*mcp.ProgressNotification)
+	if !ok {
+		t.Fatalf("Expected a *mcp.ProgressNotification, got %T", decoded)
+	}
+	if progress.Params.ProgressToken != "t" || progress.Params.Total != 2 || progress.Params.Message != "half" {
+		t.Errorf("Unexpected params: %+v", progress.Params)
+	}
+
+	decoded, err = DecodeNotification(mcp.Notification{Method: "notifications/resources/updated", Params: map[string]any{"uri": "file:///a"}})
+	if err != nil {
+		t.Fatalf("DecodeNotification failed: %v", err)
+	}
+	if updated, ok := decoded.(*mcp.ResourceUpdatedNotification); !ok || updated.Params.URI != "file:///a" {
+		t.Errorf("Unexpected decoding: %#v", decoded)
+	}
+
+	decoded, err = DecodeNotification(mcp.Notification{Method: "custom/event"})
+	if err != nil {
+		t.Fatalf("DecodeNotification failed: %v", err)
+	}
+	if n, ok := decoded.(*mcp.Notification); !ok || n.Method != "custom/event" {
+		t.Errorf("Expected unknown methods to stay untyped, got %#v", decoded)
+	}
+
+	if _, err := DecodeNotification(mcp.Notification{Method: "notifications/progress", Params: map[string]any{"progress": "x"}}); err == nil {
+		t.Errorf("Expected invalid params to fail")
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	srv := startTestServer()
+	srv.SetBehavior("ping", mcptest.Behavior{Notifications: []mcp.JSONRPCNotification{
+		{JSONRPC: mcp.JSONRPC_VERSION, Method: "notifications/resources/updated", Params: map[string]any{"uri": "file:///a"}},
+		{JSONRPC: mcp.JSONRPC_VERSION, Method: "notifications/tools/list_changed"},
+		{JSONRPC: mcp.JSONRPC_VERSION, Method: "notifications/resources/updated", Params: map[string]any{"uri": "file:///b"}},
+	}})
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithNotificationBuffer(1, DropOldest))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resources := client.Notifications(ctx, "notifications/resources/*")
+	if _, err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+
+	// The buffer holds one notification, so only the newest match is kept
+	select {
+	case notification := <-resources:
+		params, _ := notification.Params.(map[string]any)
+		if notification.Method != "notifications/resources/updated" || params["uri"] != "file:///b" {
+			t.Errorf("Expected the newest resource notification, got %+v", notification)
+		}
+	default:
+		t.Fatalf("Expected a buffered notification")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-resources:
+		if ok {
+			t.Errorf("Expected no further notifications")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the channel to close when the context ends")
+	}
+}
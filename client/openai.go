@@ -3,6 +3,11 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/contriboss/mcpgopher/mcp"
 )
 
 type OpenaiTool struct {
@@ -33,17 +38,36 @@ func (c *HTTPClient) OpenaiTools() ([]OpenaiTool, error) {
 	for _, toolRaw := range toolsRaw {
 		tool := OpenaiTool{}
 		toolMap := toolRaw.(map[string]interface{})
+		if !c.openaiToolAllowed(toolMap) {
+			continue
+		}
 		normalizedTool := mcpToVendor(toolMap)
 		function := normalizedTool["function"].(map[string]interface{})
 		parameters := function["parameters"].(map[string]interface{})
 		tool.Name = toolMap["name"].(string)
-		tool.Description = toolMap["description"].(string)
+		tool.Description, _ = toolMap["description"].(string)
 		tool.Parameters = parameters
 		tools = append(tools, tool)
 	}
 	return tools, nil
 }
 
+// openaiToolAllowed reports whether a listed tool passes the filters.
+func (c *HTTPClient) openaiToolAllowed(toolMap map[string]interface{}) bool {
+	if len(c.toolFilters) == 0 {
+		return true
+	}
+	data, err := json.Marshal(toolMap)
+	if err != nil {
+		return false
+	}
+	var tool mcp.Tool
+	if err := json.Unmarshal(data, &tool); err != nil {
+		return false
+	}
+	return c.toolAllowed(tool)
+}
+
 // mcpToVendor converts MCP format to vendor format
 func mcpToVendor(toolMap map[string]interface{}) map[string]interface{} {
 	return map[string]interface{}{
@@ -94,3 +118,98 @@ func normalizeSchema(schema map[string]interface{}) map[string]interface{} {
 
 	return result
 }
+
+// OpenaiToolCall is an entry of the tool_calls of an OpenAI assistant message.
+type OpenaiToolCall struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Function OpenaiFunctionCall `json:"function"`
+}
+
+// OpenaiFunctionCall names the function of a tool call; Arguments is a JSON
+// object encoded as a string.
+type OpenaiFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ToolMessage is a role "tool" message answering a tool call, ready to be
+// appended to the conversation.
+type ToolMessage struct {
+	Role       string `json:"role"`
+	ToolCallID string `json:"tool_call_id"`
+	Content    string `json:"content"`
+}
+
+// ToolCallOption configures ExecuteOpenaiToolCalls and CallTools.
+type ToolCallOption func(*toolCallConfig)
+
+type toolCallConfig struct {
+	concurrency int
+}
+
+// WithToolCallConcurrency runs up to n tool calls at a time. By default,
+// ExecuteOpenaiToolCalls runs them one after the other.
+func WithToolCallConcurrency(n int) ToolCallOption {
+	return func(c *toolCallConfig) {
+		c.concurrency = n
+	}
+}
+
+// ExecuteOpenaiToolCalls calls the tools requested by the tool_calls of an
+// OpenAI response and returns the tool messages answering them, in the order
+// of toolCalls. Malformed arguments and tools reporting an error are answered
+// with a message describing the problem, so the model can recover; an error is
+// returned only when a call fails.
+func (c *HTTPClient) ExecuteOpenaiToolCalls(ctx context.Context, toolCalls []OpenaiToolCall, opts ...ToolCallOption) ([]ToolMessage, error) {
+	config := toolCallConfig{concurrency: 1}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	messages := make([]ToolMessage, len(toolCalls))
+	errs := make([]error, len(toolCalls))
+	runConcurrently(len(toolCalls), config.concurrency, func(i int) {
+		messages[i], errs[i] = c.executeOpenaiToolCall(ctx, toolCalls[i])
+	})
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (c *HTTPClient) executeOpenaiToolCall(ctx context.Context, toolCall OpenaiToolCall) (ToolMessage, error) {
+	message := ToolMessage{Role: "tool", ToolCallID: toolCall.ID}
+
+	var arguments map[string]any
+	if toolCall.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &arguments); err != nil {
+			message.Content = fmt.Sprintf("Error: invalid arguments for %s: %v", toolCall.Function.Name, err)
+			return message, nil
+		}
+	}
+	result, err := c.CallTool(ctx, toolCall.Function.Name, arguments)
+	if err != nil {
+		return message, fmt.Errorf("failed to execute tool call %s: %w", toolCall.ID, err)
+	}
+	message.Content = joinContent(result.Content)
+	if result.IsError {
+		message.Content = "Error: " + message.Content
+	}
+	return message, nil
+}
+
+// joinContent renders tool result content as text: text content is joined by
+// newlines and other content is rendered as JSON.
+func joinContent(content []mcp.Content) string {
+	texts := make([]string, 0, len(content))
+	for _, c := range content {
+		if text, ok := c.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+			continue
+		}
+		data, _ := json.Marshal(c)
+		texts = append(texts, string(data))
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestExecuteOpenaiToolCalls(t *testing.T) {
+	var running, peak atomic.Int32
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "upper"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		text, _ := arguments["text"].(string)
+		return mcp.NewToolResultText("<" + text + ">"), nil
+	})
+	srv.AddTool(mcp.Tool{Name: "fail"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText("boom")
+		result.IsError = true
+		return result, nil
+	})
+	srv.Start()
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	call := func(id, name, arguments string) OpenaiToolCall {
+		return OpenaiToolCall{ID: id, Type: "function", Function: OpenaiFunctionCall{Name: name, Arguments: arguments}}
+	}
+
+	t.Run("Tools without description", func(t *testing.T) {
+		tools, err := client.OpenaiTools()
+		if err != nil {
+			t.Fatalf("OpenaiTools failed: %v", err)
+		}
+		if len(tools) != 2 || tools[0].Name != "upper" || tools[0].Description != "" {
+			t.Errorf("Unexpected tools: %+v", tools)
+		}
+	})
+
+	t.Run("Messages", func(t *testing.T) {
+		messages, err := client.ExecuteOpenaiToolCalls(ctx, []OpenaiToolCall{
+			call("call_1", "upper", `{"text":"a"}`),
+			call("call_2", "fail", `{}`),
+			call("call_3", "upper", `{"text":`),
+		})
+		if err != nil {
+			t.Fatalf("ExecuteOpenaiToolCalls failed: %v", err)
+		}
+		want := []ToolMessage{
+			{Role: "tool", ToolCallID: "call_1", Content: "<a>"},
+			{Role: "tool", ToolCallID: "call_2", Content: "Error: boom"},
+			{Role: "tool", ToolCallID: "call_3", Content: "Error: invalid arguments for upper: unexpected end of JSON input"},
+		}
+		if !reflect.DeepEqual(messages, want) {
+			t.Errorf("Expected %+v, got %+v", want, messages)
+		}
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		peak.Store(0)
+		toolCalls := []OpenaiToolCall{
+			call("call_1", "upper", `{"text":"a"}`),
+			call("call_2", "upper", `{"text":"b"}`),
+			call("call_3", "upper", `{"text":"c"}`),
+			call("call_4", "upper", `{"text":"d"}`),
+		}
+		messages, err := client.ExecuteOpenaiToolCalls(ctx, toolCalls, WithToolCallConcurrency(2))
+		if err != nil {
+			t.Fatalf("ExecuteOpenaiToolCalls failed: %v", err)
+		}
+		for i, message := range messages {
+			if message.ToolCallID != toolCalls[i].ID {
+				t.Errorf("Expected message %d to answer %s, got %s", i, toolCalls[i].ID, message.ToolCallID)
+			}
+		}
+		if got := peak.Load(); got != 2 {
+			t.Errorf("Expected 2 concurrent calls, got %d", got)
+		}
+	})
+
+	t.Run("CallFailure", func(t *testing.T) {
+		_, err := client.ExecuteOpenaiToolCalls(ctx, []OpenaiToolCall{call("call_1", "missing", `{}`)})
+		if err == nil {
+			t.Fatal("Expected an error calling an unknown tool")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// DefaultToolCallConcurrency is the number of calls CallTools runs at a time
+// by default.
+const DefaultToolCallConcurrency = 4
+
+// ToolCall is a call of a tool by CallTools.
+type ToolCall struct {
+	Name      string
+	Arguments map[string]any
+}
+
+// ToolCallResult is the outcome of a ToolCall: its result, or the error of
+// the call.
+type ToolCallResult struct {
+	Result *mcp.CallToolResult
+	Err    error
+}
+
+// CallTools calls several tools concurrently, up to
+// DefaultToolCallConcurrency at a time unless set with
+// WithToolCallConcurrency, and returns their outcomes in the order of calls.
+// All calls are made even if some fail; the failures are then also returned
+// joined in the error. Tools reporting an error are not failures.
+func (c *HTTPClient) CallTools(ctx context.Context, calls []ToolCall, opts ...ToolCallOption) ([]ToolCallResult, error) {
+	config := toolCallConfig{concurrency: DefaultToolCallConcurrency}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	results := make([]ToolCallResult, len(calls))
+	runConcurrently(len(calls), config.concurrency, func(i int) {
+		results[i].Result, results[i].Err = c.CallTool(ctx, calls[i].Name, calls[i].Arguments)
+	})
+
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("call %d to tool %s: %w", i, calls[i].Name, result.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// runConcurrently calls fn for each index below count, running up to
+// concurrency calls at a time, and waits for them to return.
+func runConcurrently(count, concurrency int, fn func(i int)) {
+	concurrency = max(1, min(concurrency, count))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := range count {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
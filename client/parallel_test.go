@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestCallTools(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := startTestServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return mcp.NewToolResultText(arguments["text"].(string)), nil
+	})
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithToolFilter(AllowDenyTools(nil, []string{"denied"})))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	calls := []ToolCall{
+		{Name: "echo", Arguments: map[string]any{"text": "a"}},
+		{Name: "denied"},
+		{Name: "echo", Arguments: map[string]any{"text": "b"}},
+		{Name: "echo", Arguments: map[string]any{"text": "c"}},
+		{Name: "echo", Arguments: map[string]any{"text": "d"}},
+	}
+	results, err := client.CallTools(ctx, calls, WithToolCallConcurrency(2))
+	if !errors.Is(err, ErrToolNotAllowed) {
+		t.Errorf("Expected the failure of the denied call, got %v", err)
+	}
+	if len(results) != len(calls) {
+		t.Fatalf("Expected %d results, got %d", len(calls), len(results))
+	}
+	for i, expected := range []string{"a", "", "b", "c", "d"} {
+		if expected == "" {
+			if results[i].Err == nil {
+				t.Errorf("Expected an error for call %d", i)
+			}
+			continue
+		}
+		if results[i].Err != nil || results[i].Result.Content[0].(mcp.TextContent).Text != expected {
+			t.Errorf("Expected %q for call %d, got %+v", expected, i, results[i])
+		}
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("Expected at most 2 calls in flight, got %d", got)
+	}
+}
@@ -0,0 +1,311 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// PolicyDecision is what a Policy decides for a tool call.
+type PolicyDecision int
+
+const (
+	// PolicyAsk asks the Ask function of the policy whether to send the call
+	PolicyAsk PolicyDecision = iota
+	// PolicyAllow sends the call
+	PolicyAllow
+	// PolicyDeny refuses the call without sending it
+	PolicyDeny
+)
+
+func (d PolicyDecision) String() string {
+	switch d {
+	case PolicyAsk:
+		return "ask"
+	case PolicyAllow:
+		return "allow"
+	case PolicyDeny:
+		return "deny"
+	}
+	return fmt.Sprintf("PolicyDecision(%d)", int(d))
+}
+
+// ErrPolicyDenied is matched by errors.Is for every *PolicyDeniedError.
+var ErrPolicyDenied = errors.New("denied by policy")
+
+// PolicyDeniedError is returned by CallTool, without contacting the server,
+// for calls the policy set with WithPolicy denies.
+type PolicyDeniedError struct {
+	Tool string
+	// Rule is the name of the rule that denied the call or asked for it, if
+	// the decision was not the default one
+	Rule string
+	// Asked is set when the call was refused by the Ask function, now or in a
+	// remembered answer
+	Asked bool
+}
+
+func (e *PolicyDeniedError) Error() string {
+	msg := fmt.Sprintf("call to tool %s denied by policy", e.Tool)
+	if e.Rule != "" {
+		msg += " rule " + e.Rule
+	}
+	if e.Asked {
+		msg += " after asking"
+	}
+	return msg
+}
+
+func (e *PolicyDeniedError) Is(target error) bool {
+	return target == ErrPolicyDenied
+}
+
+// ArgumentMatcher reports whether the value of an argument matches; value is
+// nil for arguments the call does not pass.
+type ArgumentMatcher func(value any) bool
+
+// ArgumentEquals matches arguments equal to want once both are encoded as
+// JSON, so that 1 matches 1.0.
+func ArgumentEquals(want any) ArgumentMatcher {
+	wantJSON, err := json.Marshal(want)
+	return func(value any) bool {
+		got, gotErr := json.Marshal(value)
+		return err == nil && gotErr == nil && string(got) == string(wantJSON)
+	}
+}
+
+// ArgumentMatches matches string arguments matching a pattern of the
+// path.Match syntax, such as "/tmp/*".
+func ArgumentMatches(pattern string) ArgumentMatcher {
+	return func(value any) bool {
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		matched, _ := path.Match(pattern, s)
+		return matched
+	}
+}
+
+// ArgumentRegexp matches string arguments in which re finds a match.
+func ArgumentRegexp(re *regexp.Regexp) ArgumentMatcher {
+	return func(value any) bool {
+		s, ok := value.(string)
+		return ok && re.MatchString(s)
+	}
+}
+
+// PolicyRule decides the calls it matches. A rule matches a call when every
+// condition set matches.
+type PolicyRule struct {
+	// Name identifies the rule in errors and remembered answers
+	Name string
+
+	// Tools are patterns of the tool names matched, in the path.Match
+	// syntax, such as "github_*"; any tool matches if empty
+	Tools []string
+
+	// ReadOnly, Destructive, Idempotent and OpenWorld match the annotation
+	// hints of the tool, with the defaults of the spec for tools that do
+	// not set them: not read-only, destructive, not idempotent and open
+	// world
+	ReadOnly    *bool
+	Destructive *bool
+	Idempotent  *bool
+	OpenWorld   *bool
+
+	// Arguments match the arguments of the call by name
+	Arguments map[string]ArgumentMatcher
+
+	// Decision is the decision for the calls matched
+	Decision PolicyDecision
+}
+
+// matches reports whether the rule matches a call of tool with arguments.
+func (r *PolicyRule) matches(tool mcp.Tool, arguments map[string]any) bool {
+	if len(r.Tools) > 0 && !matchToolName(r.Tools, tool.Name) {
+		return false
+	}
+	annotations := tool.Annotations
+	if annotations == nil {
+		annotations = &mcp.ToolAnnotations{}
+	}
+	for _, hint := range []struct {
+		want *bool
+		got  bool
+	}{
+		{r.ReadOnly, tool.IsReadOnly()},
+		{r.Destructive, tool.IsDestructive()},
+		{r.Idempotent, annotations.IdempotentHint != nil && *annotations.IdempotentHint},
+		{r.OpenWorld, annotations.OpenWorldHint == nil || *annotations.OpenWorldHint},
+	} {
+		if hint.want != nil && *hint.want != hint.got {
+			return false
+		}
+	}
+	for name, match := range r.Arguments {
+		if !match(arguments[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// PolicyAskFunc asks whether to send a call the policy asks for, typically
+// the user. It returns whether the call is allowed and whether to remember
+// the answer for the next calls of the tool that rule asks for in the
+// session. rule is nil when the call is asked for by the default decision.
+type PolicyAskFunc func(ctx context.Context, tool mcp.Tool, arguments map[string]any, rule *PolicyRule) (allow, remember bool, err error)
+
+// ConsentKey identifies a remembered answer: the one given for calls of
+// Tool asked for by the rule named Rule, "" for the default decision, in
+// the session SessionID, "" for transports without sessions.
+type ConsentKey struct {
+	SessionID string
+	Tool      string
+	Rule      string
+}
+
+// ConsentStore remembers the answers of the Ask function of a policy.
+type ConsentStore interface {
+	// Load returns the answer remembered for key, if any.
+	Load(ctx context.Context, key ConsentKey) (allow, ok bool, err error)
+	// Save remembers the answer for key.
+	Save(ctx context.Context, key ConsentKey, allow bool) error
+}
+
+// MemoryConsentStore is a ConsentStore keeping the answers in memory.
+type MemoryConsentStore struct {
+	mu      sync.Mutex
+	answers map[ConsentKey]bool
+}
+
+// NewMemoryConsentStore creates an empty MemoryConsentStore.
+func NewMemoryConsentStore() *MemoryConsentStore {
+	return &MemoryConsentStore{answers: map[ConsentKey]bool{}}
+}
+
+// Load implements ConsentStore.
+func (s *MemoryConsentStore) Load(ctx context.Context, key ConsentKey) (bool, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	allow, ok := s.answers[key]
+	return allow, ok, nil
+}
+
+// Save implements ConsentStore.
+func (s *MemoryConsentStore) Save(ctx context.Context, key ConsentKey, allow bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.answers[key] = allow
+	return nil
+}
+
+// Policy decides whether to send tool calls, as shared guardrails for hosts.
+type Policy struct {
+	// Rules are evaluated in order, the first one matching a call deciding it
+	Rules []PolicyRule
+
+	// Default decides the calls no rule matches, PolicyAsk if zero
+	Default PolicyDecision
+
+	// Ask decides the calls the policy asks for; they are denied if nil
+	Ask PolicyAskFunc
+
+	// Store remembers the answers of Ask when it asks to; answers are not
+	// remembered if nil
+	Store ConsentStore
+}
+
+// Evaluate returns the decision of the policy for a call of tool with
+// arguments, and the rule deciding it, nil for the default decision.
+func (p *Policy) Evaluate(tool mcp.Tool, arguments map[string]any) (PolicyDecision, *PolicyRule) {
+	for i := range p.Rules {
+		if p.Rules[i].matches(tool, arguments) {
+			return p.Rules[i].Decision, &p.Rules[i]
+		}
+	}
+	return p.Default, nil
+}
+
+// WithPolicy makes CallTool evaluate policy before each call, after the tool
+// filters and argument validation. Denied calls are not sent and CallTool
+// returns a *PolicyDeniedError. A tool called before being listed is looked
+// up with tools/list; one the server does not list is matched by name only.
+func WithPolicy(policy *Policy) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.policy = policy
+	}
+}
+
+// checkPolicy returns a *PolicyDeniedError if the policy denies the call.
+func (c *HTTPClient) checkPolicy(ctx context.Context, name string, arguments map[string]any) error {
+	if c.policy == nil {
+		return nil
+	}
+	tool, ok, err := c.lookupTool(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		tool = mcp.Tool{Name: name}
+	}
+
+	decision, rule := c.policy.Evaluate(tool, arguments)
+	denied := &PolicyDeniedError{Tool: name}
+	if rule != nil {
+		denied.Rule = rule.Name
+	}
+	switch decision {
+	case PolicyAllow:
+		return nil
+	case PolicyAsk:
+		denied.Asked = true
+		allow, err := c.askPolicy(ctx, tool, arguments, rule)
+		if err != nil {
+			return err
+		}
+		if allow {
+			return nil
+		}
+	}
+	return denied
+}
+
+// askPolicy asks the Ask function of the policy about a call, unless the
+// answer is remembered.
+func (c *HTTPClient) askPolicy(ctx context.Context, tool mcp.Tool, arguments map[string]any, rule *PolicyRule) (bool, error) {
+	if c.policy.Ask == nil {
+		return false, nil
+	}
+	key := ConsentKey{SessionID: c.GetSessionID(), Tool: tool.Name}
+	if rule != nil {
+		key.Rule = rule.Name
+	}
+	if c.policy.Store != nil {
+		allow, ok, err := c.policy.Store.Load(ctx, key)
+		if err != nil {
+			return false, fmt.Errorf("failed to load consent: %w", err)
+		}
+		if ok {
+			return allow, nil
+		}
+	}
+
+	allow, remember, err := c.policy.Ask(ctx, tool, arguments, rule)
+	if err != nil {
+		return false, fmt.Errorf("failed to ask for consent to call tool %s: %w", tool.Name, err)
+	}
+	if remember && c.policy.Store != nil {
+		if err := c.policy.Store.Save(ctx, key, allow); err != nil {
+			return false, fmt.Errorf("failed to save consent: %w", err)
+		}
+	}
+	return allow, nil
+}
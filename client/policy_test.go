@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	readOnly, closedWorld := true, false
+	policy := &Policy{
+		Rules: []PolicyRule{
+			{Name: "no-secrets", Arguments: map[string]ArgumentMatcher{"path": ArgumentRegexp(regexp.MustCompile(`\.env$`))}, Decision: PolicyDeny},
+			{Name: "reads", ReadOnly: &readOnly, Decision: PolicyAllow},
+			{Name: "tmp", Tools: []string{"fs_*"}, Arguments: map[string]ArgumentMatcher{"path": ArgumentMatches("/tmp/*")}, Decision: PolicyAllow},
+			{Name: "local", OpenWorld: &closedWorld, Arguments: map[string]ArgumentMatcher{"force": ArgumentEquals(false)}, Decision: PolicyAllow},
+		},
+		Default: PolicyDeny,
+	}
+	read := mcp.Tool{Name: "fs_read", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: &readOnly}}
+	write := mcp.Tool{Name: "fs_write"}
+	local := mcp.Tool{Name: "reset", Annotations: &mcp.ToolAnnotations{OpenWorldHint: &closedWorld}}
+
+	for _, tt := range []struct {
+		name      string
+		tool      mcp.Tool
+		arguments map[string]any
+		decision  PolicyDecision
+		rule      string
+	}{
+		{"Argument matcher", read, map[string]any{"path": "/app/.env"}, PolicyDeny, "no-secrets"},
+		{"Annotation hint", read, map[string]any{"path": "/app/main.go"}, PolicyAllow, "reads"},
+		{"Tool pattern", write, map[string]any{"path": "/tmp/out"}, PolicyAllow, "tmp"},
+		{"Missing argument", local, nil, PolicyDeny, ""},
+		{"Equal argument", local, map[string]any{"force": false}, PolicyAllow, "local"},
+		{"Default", write, map[string]any{"path": "/etc/passwd"}, PolicyDeny, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, rule := policy.Evaluate(tt.tool, tt.arguments)
+			name := ""
+			if rule != nil {
+				name = rule.Name
+			}
+			if decision != tt.decision || name != tt.rule {
+				t.Errorf("Expected %s by %q, got %s by %q", tt.decision, tt.rule, decision, name)
+			}
+		})
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	srv := startTestServer()
+	handler := func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	srv.AddTool(mcp.Tool{Name: "search"}, handler)
+	srv.AddTool(mcp.Tool{Name: "deploy"}, handler)
+	srv.AddTool(mcp.Tool{Name: "drop_table"}, handler)
+	defer srv.Close()
+
+	var asked []string
+	answer, remember := false, false
+	policy := &Policy{
+		Rules: []PolicyRule{
+			{Name: "search", Tools: []string{"search"}, Decision: PolicyAllow},
+			{Name: "drop", Tools: []string{"drop_*"}, Decision: PolicyDeny},
+		},
+		Ask: func(ctx context.Context, tool mcp.Tool, arguments map[string]any, rule *PolicyRule) (bool, bool, error) {
+			asked = append(asked, tool.Name)
+			return answer, remember, nil
+		},
+		Store: NewMemoryConsentStore(),
+	}
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithPolicy(policy))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	t.Run("Allow", func(t *testing.T) {
+		if _, err := client.CallTool(ctx, "search", nil); err != nil {
+			t.Errorf("Expected the allowed call to succeed, got %v", err)
+		}
+	})
+
+	t.Run("Deny", func(t *testing.T) {
+		sent := len(srv.Requests())
+		_, err := client.CallTool(ctx, "drop_table", nil)
+		var denied *PolicyDeniedError
+		if !errors.As(err, &denied) || !errors.Is(err, ErrPolicyDenied) || denied.Rule != "drop" || denied.Asked {
+			t.Errorf("Expected a denial by rule drop, got %v", err)
+		}
+		if got := len(srv.Requests()); got != sent {
+			t.Errorf("Expected the denied call not to be sent, got %d requests", got-sent)
+		}
+	})
+
+	t.Run("Ask", func(t *testing.T) {
+		asked = nil
+		var denied *PolicyDeniedError
+		if _, err := client.CallTool(ctx, "deploy", nil); !errors.As(err, &denied) || !denied.Asked {
+			t.Errorf("Expected the refused call to be denied after asking, got %v", err)
+		}
+		answer, remember = true, true
+		for range 2 {
+			if _, err := client.CallTool(ctx, "deploy", nil); err != nil {
+				t.Errorf("Expected the approved call to succeed, got %v", err)
+			}
+		}
+		if len(asked) != 2 {
+			t.Errorf("Expected to be asked twice, the answer being remembered, got %v", asked)
+		}
+		allow, ok, _ := policy.Store.Load(ctx, ConsentKey{SessionID: client.GetSessionID(), Tool: "deploy"})
+		if !ok || !allow {
+			t.Errorf("Expected the remembered answer to be stored for the session, got %v, %v", allow, ok)
+		}
+	})
+
+	t.Run("No Ask", func(t *testing.T) {
+		client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithPolicy(&Policy{}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer client.Close()
+		if _, err := client.CallTool(ctx, "search", nil); !errors.Is(err, ErrPolicyDenied) {
+			t.Errorf("Expected calls to be denied without an Ask function, got %v", err)
+		}
+	})
+}
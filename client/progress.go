@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// ProgressFunc receives progress updates for a request. Total is zero when
+// the server does not know it.
+type ProgressFunc func(progress, total float64, message string)
+
+// CallToolWithProgress invokes a tool like CallTool, passing every
+// notifications/progress the server sends for the call to onProgress.
+// onProgress is called from the notification goroutine and must not block.
+// Progress arriving after the call completes is dropped.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/utilities/progress
+func (c *HTTPClient) CallToolWithProgress(ctx context.Context, name string, arguments map[string]any, onProgress ProgressFunc) (*mcp.CallToolResult, error) {
+	token := fmt.Sprintf("progress-%d", c.lastProgressToken.Add(1))
+	c.progressHandlers.Store(token, onProgress)
+	defer c.progressHandlers.Delete(token)
+
+	return c.callTool(ctx, name, arguments, &mcp.Meta{ProgressToken: token})
+}
+
+// dispatchProgress routes a progress notification to the callback registered
+// for its token, if any.
+func (c *HTTPClient) dispatchProgress(notification transport.JSONRPCNotification) {
+	if notification.Method != string(mcp.MethodNotificationProgress) {
+		return
+	}
+	params := notification.Params.AdditionalFields
+	token, ok := params["progressToken"]
+	if !ok {
+		return
+	}
+	handler, ok := c.progressHandlers.Load(fmt.Sprint(token))
+	if !ok {
+		return
+	}
+
+	progress, _ := params["progress"].(float64)
+	total, _ := params["total"].(float64)
+	message := mcp.ExtractString(params, "message")
+	handler.(ProgressFunc)(progress, total, message)
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestCallToolWithProgress(t *testing.T) {
+	srv := startTestServer()
+	srv.AddTool(mcp.Tool{Name: "work"}, nil)
+	// Report progress against the token the client sent, plus a stray token
+	srv.HandleMethod("tools/call", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		var params struct {
+			Meta struct {
+				ProgressToken any `json:"progressToken"`
+			} `json:"_meta"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, err
+		}
+		token := params.Meta.ProgressToken
+		_ = mcptest.SendNotification(ctx, "notifications/progress", map[string]any{"progressToken": token, "progress": 1, "total": 2})
+		_ = mcptest.SendNotification(ctx, "notifications/progress", map[string]any{"progressToken": "other", "progress": 9})
+		_ = mcptest.SendNotification(ctx, "notifications/progress", map[string]any{"progressToken": token, "progress": 2, "total": 2, "message": "done"})
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv.SetBehavior("tools/call", mcptest.Behavior{SSE: true})
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	type update struct {
+		progress, total float64
+		message         string
+	}
+	var (
+		mu      sync.Mutex
+		updates []update
+	)
+	result, err := client.CallToolWithProgress(context.Background(), "work", nil, func(progress, total float64, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, update{progress, total, message})
+	})
+	if err != nil {
+		t.Fatalf("CallToolWithProgress failed: %v", err)
+	}
+	if len(result.Content) != 1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []update{{1, 2, ""}, {2, 2, "done"}}
+	if len(updates) != len(want) || updates[0] != want[0] || updates[1] != want[1] {
+		t.Errorf("Expected updates %+v, got %+v", want, updates)
+	}
+
+	// The token is released once the call completes
+	count := 0
+	client.progressHandlers.Range(func(key, value any) bool {
+		count++
+		return true
+	})
+	if count != 0 {
+		t.Errorf("Expected no registered progress handlers, got %d", count)
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// ListPrompts returns a page of the prompts offered by the server.
+// Pass the NextCursor of the previous page to continue, or "" to start.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/prompts#listing-prompts
+func (c *HTTPClient) ListPrompts(ctx context.Context, cursor mcp.Cursor) (*mcp.ListPromptsResult, error) {
+	if err := c.checkCapability(mcp.MethodPromptsList); err != nil {
+		return nil, err
+	}
+	raw, err := c.list(ctx, mcp.MethodPromptsList, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ListPromptsResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal prompts: %w", err)
+	}
+	return &result, nil
+}
+
+// GetPrompt returns the prompt with the given name, filled in with arguments.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/prompts#getting-a-prompt
+func (c *HTTPClient) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*mcp.GetPromptResult, error) {
+	if err := c.checkCapability(mcp.MethodPromptsGet); err != nil {
+		return nil, err
+	}
+	params := map[string]any{"name": name}
+	if arguments != nil {
+		params["arguments"] = arguments
+	}
+	raw, err := c.Request(ctx, string(mcp.MethodPromptsGet), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.GetPromptResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal prompt: %w", err)
+	}
+	return &result, nil
+}
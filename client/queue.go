@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+var (
+	// ErrOfflineQueueFull is returned for requests issued while the server is
+	// unreachable once the offline queue holds as many as it may.
+	ErrOfflineQueueFull = errors.New("offline queue full")
+	// ErrRequestExpired is returned for queued requests the server did not
+	// become reachable for within their time to live.
+	ErrRequestExpired = errors.New("queued request expired")
+)
+
+// offlineRetryInterval is how often the oldest queued request is sent again
+// while the server is unreachable.
+var offlineRetryInterval = time.Second
+
+// offlineQueue holds the requests issued while the server is unreachable and
+// replays them, in order, once it answers again.
+type offlineQueue struct {
+	size int
+	ttl  time.Duration
+	// send sends a request to the server
+	send func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error)
+
+	mu        sync.Mutex
+	entries   []*queuedRequest
+	replaying bool
+	closed    bool
+	// wake interrupts the wait between two attempts
+	wake chan struct{}
+}
+
+// queuedRequest is a request waiting in the offline queue.
+type queuedRequest struct {
+	ctx      context.Context
+	request  transport.JSONRPCRequest
+	deadline time.Time
+	done     chan queuedResult
+}
+
+// queuedResult is the outcome of a replayed request.
+type queuedResult struct {
+	response *transport.JSONRPCResponse
+	err      error
+}
+
+// WithOfflineQueue makes the requests issued while the server is unreachable
+// wait, up to size of them for up to ttl each, and replays them in order once
+// it answers again, instead of failing. The oldest request is sent again
+// every second until it gets through. Requests past ttl fail with
+// ErrRequestExpired and requests beyond size with ErrOfflineQueueFull.
+// Initialize and pings are never queued.
+//
+// A request whose connection broke after it was sent may be replayed though
+// the server received it, so only queue calls that are safe to repeat.
+func WithOfflineQueue(size int, ttl time.Duration) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.offlineQueue = &offlineQueue{size: size, ttl: ttl, send: c.sendRecovering, wake: make(chan struct{}, 1)}
+	}
+}
+
+// QueuedRequests returns the number of requests waiting for the server to be
+// reachable again.
+func (c *HTTPClient) QueuedRequests() int {
+	if c.offlineQueue == nil {
+		return 0
+	}
+	c.offlineQueue.mu.Lock()
+	defer c.offlineQueue.mu.Unlock()
+	return len(c.offlineQueue.entries)
+}
+
+// queuesOffline reports whether requests of method may wait in the offline
+// queue.
+func (c *HTTPClient) queuesOffline(method string) bool {
+	return c.offlineQueue != nil && method != string(mcp.MethodInitialize) && method != string(mcp.MethodPing)
+}
+
+// unreachable reports whether a request failed because the server could not
+// be reached, rather than because it answered with an error or timed out.
+func unreachable(ctx context.Context, err error) bool {
+	var urlErr *url.Error
+	return err != nil && ctx.Err() == nil && errors.As(err, &urlErr) && !urlErr.Timeout()
+}
+
+// do sends a request, queuing it if the server is unreachable or requests
+// are already waiting for it.
+func (q *offlineQueue) do(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	q.mu.Lock()
+	waiting := len(q.entries) > 0
+	q.mu.Unlock()
+	if !waiting {
+		response, err := q.send(ctx, request)
+		if !unreachable(ctx, err) {
+			return response, err
+		}
+	}
+
+	entry := &queuedRequest{ctx: ctx, request: request, deadline: time.Now().Add(q.ttl), done: make(chan queuedResult, 1)}
+	if err := q.push(entry); err != nil {
+		return nil, fmt.Errorf("%s: %w", request.Method, err)
+	}
+	timer := time.NewTimer(q.ttl)
+	defer timer.Stop()
+	select {
+	case result := <-entry.done:
+		return result.response, result.err
+	case <-timer.C:
+		return nil, fmt.Errorf("%s: %w", request.Method, ErrRequestExpired)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%s: %w", request.Method, context.Cause(ctx))
+	}
+}
+
+// push appends a request to the queue, starting the replay if needed.
+func (q *offlineQueue) push(entry *queuedRequest) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	if len(q.entries) >= q.size {
+		return ErrOfflineQueueFull
+	}
+	q.entries = append(q.entries, entry)
+	if !q.replaying {
+		q.replaying = true
+		go q.replay()
+	}
+	return nil
+}
+
+// replay sends the queued requests in order until the queue is empty,
+// waiting between attempts while the server is unreachable.
+func (q *offlineQueue) replay() {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			for _, entry := range q.entries {
+				entry.done <- queuedResult{err: fmt.Errorf("%s: %w", entry.request.Method, ErrClosed)}
+			}
+			q.entries = nil
+		}
+		if len(q.entries) == 0 {
+			q.replaying = false
+			q.mu.Unlock()
+			return
+		}
+		entry := q.entries[0]
+		q.mu.Unlock()
+
+		// Requests given up by their callers are dropped
+		ctx, cancel := context.WithDeadline(entry.ctx, entry.deadline)
+		var response *transport.JSONRPCResponse
+		err := ctx.Err()
+		if err == nil {
+			response, err = q.send(ctx, entry.request)
+		}
+		retry := unreachable(ctx, err)
+		cancel()
+		if retry {
+			select {
+			case <-time.After(offlineRetryInterval):
+			case <-q.wake:
+			}
+			continue
+		}
+
+		entry.done <- queuedResult{response: response, err: err}
+		q.mu.Lock()
+		q.entries = q.entries[1:]
+		q.mu.Unlock()
+	}
+}
+
+// reachable tells the queue the server answers again, to replay the queued
+// requests without waiting.
+func (q *offlineQueue) reachable() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// close fails the queued requests with ErrClosed.
+func (q *offlineQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.reachable()
+}
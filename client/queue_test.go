@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestOfflineQueue(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo", Description: "echo"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		text, _ := arguments["text"].(string)
+		return mcp.NewToolResultText(text), nil
+	})
+	srv.Start()
+	defer srv.Close()
+
+	// The proxy drops the connections while the server is down
+	var down atomic.Bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		srv.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	retryInterval := offlineRetryInterval
+	offlineRetryInterval = 10 * time.Millisecond
+	defer func() { offlineRetryInterval = retryInterval }()
+	ctx := context.Background()
+
+	newClient := func(t *testing.T, size int, ttl time.Duration) *HTTPClient {
+		t.Helper()
+		down.Store(false)
+		client, err := NewHTTPClient(&Options{BaseURL: proxy.URL}, WithOfflineQueue(size, ttl))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		down.Store(true)
+		return client
+	}
+	waitQueued := func(t *testing.T, client *HTTPClient, n int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for client.QueuedRequests() != n {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected %d queued requests, got %d", n, client.QueuedRequests())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	call := func(client *HTTPClient, text string) chan error {
+		errs := make(chan error, 1)
+		go func() {
+			result, err := client.CallTool(ctx, "echo", map[string]any{"text": text})
+			if err == nil && result.Content[0].(mcp.TextContent).Text != text {
+				err = errors.New("unexpected result")
+			}
+			errs <- err
+		}()
+		return errs
+	}
+
+	t.Run("Replay", func(t *testing.T) {
+		client := newClient(t, 10, time.Minute)
+		defer client.Close()
+		first := call(client, "first")
+		waitQueued(t, client, 1)
+		second := call(client, "second")
+		waitQueued(t, client, 2)
+
+		down.Store(false)
+		for _, errs := range []chan error{first, second} {
+			if err := <-errs; err != nil {
+				t.Errorf("Expected the queued call to succeed, got %v", err)
+			}
+		}
+		var calls []string
+		for _, request := range srv.Requests() {
+			if request.Method == "tools/call" {
+				calls = append(calls, string(request.Params))
+			}
+		}
+		if len(calls) < 2 || !strings.Contains(calls[len(calls)-2], "first") || !strings.Contains(calls[len(calls)-1], "second") {
+			t.Errorf("Expected the calls to be replayed in order, got %v", calls)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		client := newClient(t, 10, 50*time.Millisecond)
+		defer client.Close()
+		if err := <-call(client, "late"); !errors.Is(err, ErrRequestExpired) {
+			t.Errorf("Expected ErrRequestExpired, got %v", err)
+		}
+	})
+
+	t.Run("Full", func(t *testing.T) {
+		client := newClient(t, 1, time.Minute)
+		defer client.Close()
+		queued := call(client, "queued")
+		waitQueued(t, client, 1)
+		if err := <-call(client, "rejected"); !errors.Is(err, ErrOfflineQueueFull) {
+			t.Errorf("Expected ErrOfflineQueueFull, got %v", err)
+		}
+		client.Close()
+		if err := <-queued; !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed once the client is closed, got %v", err)
+		}
+	})
+
+	t.Run("Ping", func(t *testing.T) {
+		client := newClient(t, 10, time.Minute)
+		defer client.Close()
+		if _, err := client.Ping(ctx); err == nil {
+			t.Error("Expected pings to fail rather than wait")
+		}
+		if client.QueuedRequests() != 0 {
+			t.Errorf("Expected no queued request, got %d", client.QueuedRequests())
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// ListResources returns a page of the resources offered by the server.
+// Pass the NextCursor of the previous page to continue, or "" to start.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/resources#listing-resources
+func (c *HTTPClient) ListResources(ctx context.Context, cursor mcp.Cursor) (*mcp.ListResourcesResult, error) {
+	if err := c.checkCapability(mcp.MethodResourcesList); err != nil {
+		return nil, err
+	}
+	raw, err := c.list(ctx, mcp.MethodResourcesList, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ListResourcesResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resources: %w", err)
+	}
+	return &result, nil
+}
+
+// ListResourceTemplates returns a page of the resource templates offered by the server.
+// Templates with an invalid URI template are kept; see mcp.URITemplate.Valid.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/resources#resource-templates
+func (c *HTTPClient) ListResourceTemplates(ctx context.Context, cursor mcp.Cursor) (*mcp.ListResourceTemplatesResult, error) {
+	if err := c.checkCapability(mcp.MethodResourcesTemplatesList); err != nil {
+		return nil, err
+	}
+	raw, err := c.list(ctx, mcp.MethodResourcesTemplatesList, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	message := json.RawMessage(raw)
+	return mcp.ParseListResourceTemplatesResult(&message, true)
+}
+
+// ReadResource returns the contents of the resource at uri.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/resources#reading-resources
+func (c *HTTPClient) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	if err := c.checkCapability(mcp.MethodResourcesRead); err != nil {
+		return nil, err
+	}
+	raw, err := c.Request(ctx, string(mcp.MethodResourcesRead), map[string]any{"uri": uri})
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ReadResourceResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resource: %w", err)
+	}
+	return &result, nil
+}
+
+// ReadResourceTo writes the contents of the resource at uri to w and returns
+// the number of bytes written. Blobs are decoded from the response as they
+// are written, without holding the decoded resource in memory, and the
+// contents of responses split into several items are written in order.
+func (c *HTTPClient) ReadResourceTo(ctx context.Context, uri string, w io.Writer) (int64, error) {
+	if err := c.checkCapability(mcp.MethodResourcesRead); err != nil {
+		return 0, err
+	}
+	raw, err := c.Request(ctx, string(mcp.MethodResourcesRead), map[string]any{"uri": uri})
+	if err != nil {
+		return 0, err
+	}
+
+	// Blobs are kept raw to decode them while writing
+	var result struct {
+		Contents []struct {
+			Text *string         `json:"text"`
+			Blob json.RawMessage `json:"blob"`
+		} `json:"contents"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal resource: %w", err)
+	}
+	var written int64
+	for i, contents := range result.Contents {
+		var r io.Reader
+		switch {
+		case contents.Text != nil:
+			r = strings.NewReader(*contents.Text)
+		case len(contents.Blob) > 0:
+			r, err = blobReader(contents.Blob)
+			if err != nil {
+				return written, fmt.Errorf("contents %d: %w", i, err)
+			}
+		default:
+			return written, fmt.Errorf("contents %d: unsupported resource type", i)
+		}
+		n, err := io.Copy(w, r)
+		written += n
+		if err != nil {
+			return written, fmt.Errorf("failed to write contents %d: %w", i, err)
+		}
+	}
+	return written, nil
+}
+
+// blobReader returns a reader decoding a base64 JSON string.
+func blobReader(blob json.RawMessage) (io.Reader, error) {
+	var encoded io.Reader
+	if len(blob) >= 2 && blob[0] == '"' && blob[len(blob)-1] == '"' && bytes.IndexByte(blob, '\\') < 0 {
+		encoded = bytes.NewReader(blob[1 : len(blob)-1])
+	} else {
+		// Strings with escapes, such as \/, are unquoted first
+		var s string
+		if err := json.Unmarshal(blob, &s); err != nil {
+			return nil, fmt.Errorf("failed to decode blob: %w", err)
+		}
+		encoded = strings.NewReader(s)
+	}
+	return base64.NewDecoder(base64.StdEncoding, encoded), nil
+}
+
+// SubscribeResource asks the server to send notifications/resources/updated when the
+// resource at uri changes.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/resources#subscriptions
+func (c *HTTPClient) SubscribeResource(ctx context.Context, uri string) error {
+	if err := c.checkCapability(mcp.MethodResourcesSubscribe); err != nil {
+		return err
+	}
+	_, err := c.Request(ctx, string(mcp.MethodResourcesSubscribe), map[string]any{"uri": uri})
+	return err
+}
+
+// UnsubscribeResource cancels a subscription made with SubscribeResource.
+func (c *HTTPClient) UnsubscribeResource(ctx context.Context, uri string) error {
+	if err := c.checkCapability(mcp.MethodResourcesUnsubscribe); err != nil {
+		return err
+	}
+	_, err := c.Request(ctx, string(mcp.MethodResourcesUnsubscribe), map[string]any{"uri": uri})
+	return err
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestReadResourceTo(t *testing.T) {
+	srv := startTestServer()
+	defer srv.Close()
+	srv.AddResource(mcp.Resource{URI: "file:///big.bin"}, nil)
+	// A blob split in two items, the second with an escaped slash
+	srv.HandleMethod("resources/read", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		return json.RawMessage(`{"contents":[
+			{"uri":"file:///big.bin","blob":"aGVsbG8g"},
+			{"uri":"file:///big.bin","blob":"P\/8="},
+			{"uri":"file:///big.bin","text":"!"}
+		]}`), nil
+	})
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	n, err := client.ReadResourceTo(ctx, "file:///big.bin", &buf)
+	if err != nil {
+		t.Fatalf("ReadResourceTo failed: %v", err)
+	}
+	expected := "hello ?\xff!"
+	if buf.String() != expected || n != int64(len(expected)) {
+		t.Errorf("Expected %q, got %q (%d bytes)", expected, buf.String(), n)
+	}
+
+	srv.HandleMethod("resources/read", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		return json.RawMessage(`{"contents":[{"uri":"file:///big.bin","blob":"not base64!"}]}`), nil
+	})
+	if _, err := client.ReadResourceTo(ctx, "file:///big.bin", &bytes.Buffer{}); err == nil {
+		t.Error("Expected an error for an invalid blob")
+	}
+}
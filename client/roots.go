@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// roots holds the roots the client exposes to the server.
+type roots struct {
+	listChanged bool
+
+	mu    sync.RWMutex
+	roots []mcp.Root
+	// err is the error normalizing the initial roots, failing the constructor
+	err error
+}
+
+// WithRoots declares the roots capability and answers the server's
+// roots/list requests with the given roots. If listChanged is true, SetRoots
+// notifies the server whenever the roots change. The roots are normalized as
+// by SetRoots; if one is invalid, the client constructor fails with an error
+// wrapping mcp.ErrInvalidRoot.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/client/roots
+func WithRoots(listChanged bool, initial ...mcp.Root) HTTPClientOption {
+	return func(c *HTTPClient) {
+		normalized, err := normalizeRoots(initial)
+		c.roots = &roots{listChanged: listChanged, roots: normalized, err: err}
+	}
+}
+
+// Roots returns the roots currently exposed to the server.
+func (c *HTTPClient) Roots() []mcp.Root {
+	if c.roots == nil {
+		return nil
+	}
+	c.roots.mu.RLock()
+	defer c.roots.mu.RUnlock()
+	return append([]mcp.Root(nil), c.roots.roots...)
+}
+
+// SetRoots replaces the roots exposed to the server and, if the client was
+// created with WithRoots(true, ...), sends notifications/roots/list_changed.
+// The roots are normalized with Root.Normalize; if one is invalid, the roots
+// are left unchanged and the error wraps mcp.ErrInvalidRoot.
+func (c *HTTPClient) SetRoots(ctx context.Context, roots []mcp.Root) error {
+	if c.roots == nil {
+		return errors.New("roots are not enabled, use WithRoots")
+	}
+	normalized, err := normalizeRoots(roots)
+	if err != nil {
+		return err
+	}
+	c.roots.mu.Lock()
+	c.roots.roots = normalized
+	c.roots.mu.Unlock()
+
+	if !c.roots.listChanged {
+		return nil
+	}
+	notification := transport.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Method:  string(mcp.MethodNotificationRootsListChanged),
+	}
+	if err := c.notify(ctx, notification); err != nil {
+		return fmt.Errorf("failed to notify roots change: %w", err)
+	}
+	return nil
+}
+
+// normalizeRoots returns roots normalized with Root.Normalize, or the error
+// of the first invalid one.
+func normalizeRoots(roots []mcp.Root) ([]mcp.Root, error) {
+	normalized := make([]mcp.Root, len(roots))
+	for i, root := range roots {
+		var err error
+		if normalized[i], err = root.Normalize(); err != nil {
+			return nil, err
+		}
+	}
+	return normalized, nil
+}
+
+// listRoots answers a roots/list request.
+func (c *HTTPClient) listRoots() *mcp.ListRootsResult {
+	roots := c.Roots()
+	if roots == nil {
+		roots = []mcp.Root{}
+	}
+	return &mcp.ListRootsResult{Roots: roots}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// SamplingHandler answers a server's sampling/createMessage request, typically
+// by asking an LLM. Errors of type *mcp.Error are sent to the server as-is.
+type SamplingHandler func(ctx context.Context, request *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
+
+// WithSampling declares the sampling capability and answers the server's
+// sampling/createMessage requests with handler.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/client/sampling
+func WithSampling(handler SamplingHandler) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.samplingHandler = handler
+	}
+}
+
+// createMessage decodes a sampling/createMessage request and passes it to the sampling handler.
+func (c *HTTPClient) createMessage(ctx context.Context, request transport.JSONRPCRequest) (any, error) {
+	params, err := json.Marshal(request.Params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal params: %w", err)
+	}
+	message := mcp.CreateMessageRequest{Method: request.Method}
+	if err := json.Unmarshal(params, &message.Params); err != nil {
+		return nil, mcp.NewError(mcp.ErrorInvalidParams, fmt.Sprintf("invalid sampling params: %v", err))
+	}
+	return c.samplingHandler(ctx, &message)
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// Levels of the MCP logging levels that slog lacks, between the standard ones.
+const (
+	slogLevelNotice    = slog.LevelInfo + 2
+	slogLevelCritical  = slog.LevelError + 4
+	slogLevelAlert     = slog.LevelError + 8
+	slogLevelEmergency = slog.LevelError + 12
+)
+
+// SlogLevel maps an MCP logging level to a slog level. notice, critical,
+// alert and emergency, which slog lacks, fall between and above the
+// standard levels; unknown levels map to slog.LevelInfo.
+func SlogLevel(level mcp.LoggingLevel) slog.Level {
+	switch level {
+	case mcp.LoggingLevelDebug:
+		return slog.LevelDebug
+	case mcp.LoggingLevelNotice:
+		return slogLevelNotice
+	case mcp.LoggingLevelWarning:
+		return slog.LevelWarn
+	case mcp.LoggingLevelError:
+		return slog.LevelError
+	case mcp.LoggingLevelCritical:
+		return slogLevelCritical
+	case mcp.LoggingLevelAlert:
+		return slogLevelAlert
+	case mcp.LoggingLevelEmergency:
+		return slogLevelEmergency
+	}
+	return slog.LevelInfo
+}
+
+// LogToSlog records the log messages of the server to logger. The name of
+// the server's logger is recorded as the "logger" attribute. Data that is a
+// string becomes the message; an object becomes attributes, its "message" or
+// "msg" field, if a string, the message; other data is the "data" attribute.
+// Use SetLogLevel to choose which messages the server sends.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/utilities/logging
+func LogToSlog(logger *slog.Logger) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.Subscribe(string(mcp.MethodNotificationLoggingMessage), func(notification mcp.Notification) {
+			logNotification(logger, notification)
+		})
+		// The method of log messages in the specification
+		c.Subscribe("notifications/message", func(notification mcp.Notification) {
+			logNotification(logger, notification)
+		})
+	}
+}
+
+// logNotification records a log message notification to logger.
+func logNotification(logger *slog.Logger, notification mcp.Notification) {
+	level, _ := notification.Params["level"].(string)
+	slogLevel := SlogLevel(mcp.LoggingLevel(level))
+	ctx := context.Background()
+	if !logger.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	var attrs []slog.Attr
+	if name, ok := notification.Params["logger"].(string); ok && name != "" {
+		attrs = append(attrs, slog.String("logger", name))
+	}
+	var message string
+	switch data := notification.Params["data"].(type) {
+	case string:
+		message = data
+	case map[string]any:
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if text, ok := data[key].(string); ok && message == "" && (key == "message" || key == "msg") {
+				message = text
+				continue
+			}
+			attrs = append(attrs, slog.Any(key, data[key]))
+		}
+	case nil:
+	default:
+		attrs = append(attrs, slog.Any("data", data))
+	}
+	logger.LogAttrs(ctx, slogLevel, message, attrs...)
+}
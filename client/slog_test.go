@@ -0,0 +1,78 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestLogToSlog(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "work", Description: "work"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv.HandleMethod("tools/call", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		_ = mcptest.SendNotification(ctx, "notifications/logging/message", map[string]any{"level": "debug", "data": "filtered"})
+		_ = mcptest.SendNotification(ctx, "notifications/logging/message", map[string]any{"level": "warning", "logger": "db", "data": "slow query"})
+		_ = mcptest.SendNotification(ctx, "notifications/message", map[string]any{"level": "critical", "data": map[string]any{"message": "disk full", "free": 0}})
+		_ = mcptest.SendNotification(ctx, "notifications/message", map[string]any{"level": "notice", "data": []any{1, 2}})
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv.SetBehavior("tools/call", mcptest.Behavior{SSE: true})
+	srv.Start()
+	defer srv.Close()
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, LogToSlog(logger))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CallTool(context.Background(), "work", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Failed to decode record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d: %s", len(records), out.String())
+	}
+	if records[0]["level"] != "WARN" || records[0]["msg"] != "slow query" || records[0]["logger"] != "db" {
+		t.Errorf("Expected a warning from db, got %v", records[0])
+	}
+	if records[1]["level"] != "ERROR+4" || records[1]["msg"] != "disk full" || records[1]["free"] != float64(0) {
+		t.Errorf("Expected a critical record with attributes, got %v", records[1])
+	}
+	if records[2]["level"] != "INFO+2" || records[2]["data"] == nil {
+		t.Errorf("Expected a notice with data, got %v", records[2])
+	}
+}
+
+func TestSlogLevel(t *testing.T) {
+	levels := []mcp.LoggingLevel{
+		mcp.LoggingLevelDebug, mcp.LoggingLevelInfo, mcp.LoggingLevelNotice, mcp.LoggingLevelWarning,
+		mcp.LoggingLevelError, mcp.LoggingLevelCritical, mcp.LoggingLevelAlert, mcp.LoggingLevelEmergency,
+	}
+	for i := 1; i < len(levels); i++ {
+		if SlogLevel(levels[i]) <= SlogLevel(levels[i-1]) {
+			t.Errorf("Expected %s to be more severe than %s", levels[i], levels[i-1])
+		}
+	}
+	if SlogLevel("verbose") != slog.LevelInfo {
+		t.Errorf("Expected unknown levels to map to INFO, got %v", SlogLevel("verbose"))
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// NewStdioClient starts the server command with args as a subprocess and
+// creates a client talking to it over stdio. env holds extra "KEY=value"
+// environment variables for the server. Options.BaseURL, Headers, Listen and
+// the timeouts do not apply. Close stops the server.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/transports#stdio
+func NewStdioClient(command string, args []string, env []string, options *Options, opts ...HTTPClientOption) (*HTTPClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultInitTimeout)
+	defer cancel()
+	return newStdioClient(ctx, command, args, env, options, opts...)
+}
+
+// newStdioClient is NewStdioClient initializing the session within ctx.
+func newStdioClient(ctx context.Context, command string, args []string, env []string, options *Options, opts ...HTTPClientOption) (*HTTPClient, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	transportImpl := transport.NewStdio(command, args,
+		transport.WithStdioEnv(env),
+		// Report server pings, which the transport answers, to the hooks
+		transport.WithStdioPingHandler(func(id mcp.RequestId) {
+			options.Hooks.serverPing(id, time.Now())
+		}),
+	)
+	return newClient(ctx, transportImpl, options, opts...)
+}
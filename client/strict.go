@@ -0,0 +1,297 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcp/jsonschema"
+	"github.com/contriboss/mcpgopher/mcp/spec"
+)
+
+// ErrProtocolViolation is matched by errors.Is for every *ProtocolViolationError.
+var ErrProtocolViolation = errors.New("protocol violation")
+
+// Directions of the messages checked in strict mode.
+const (
+	// DirectionOutgoing is a message sent by the client
+	DirectionOutgoing = "outgoing"
+	// DirectionIncoming is a message received from the server
+	DirectionIncoming = "incoming"
+)
+
+// ProtocolViolationError reports, in strict mode, a message that does not
+// match the MCP schema of the negotiated protocol version. Err is usually a
+// *jsonschema.ValidationError listing the violations.
+type ProtocolViolationError struct {
+	// Direction is DirectionOutgoing or DirectionIncoming
+	Direction string
+	// Method of the request, response or notification
+	Method string
+	Err    error
+}
+
+func (e *ProtocolViolationError) Error() string {
+	return fmt.Sprintf("%s %s message violates the protocol: %v", e.Direction, e.Method, e.Err)
+}
+
+func (e *ProtocolViolationError) Is(target error) bool {
+	return target == ErrProtocolViolation
+}
+
+func (e *ProtocolViolationError) Unwrap() error {
+	return e.Err
+}
+
+// WithStrictMode validates every message exchanged with the server against
+// the embedded MCP schema of the negotiated protocol version: JSON-RPC
+// envelopes, and the params and results of the methods known to the client.
+// Invalid requests are not sent and invalid responses fail the call with a
+// *ProtocolViolationError. Invalid server requests are answered with an
+// invalid params error, and invalid notifications are dropped and reported to
+// Options.Logger. Servers negotiating a version without an embedded schema
+// fail initialization.
+func WithStrictMode() HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.strict = true
+	}
+}
+
+// Definitions of the params of client requests, by method.
+var requestParamsDefs = map[mcp.MCPMethod]string{
+	mcp.MethodInitialize:             "InitializeRequestParams",
+	mcp.MethodToolsList:              "PaginatedRequestParams",
+	mcp.MethodToolsCall:              "CallToolRequestParams",
+	mcp.MethodResourcesList:          "PaginatedRequestParams",
+	mcp.MethodResourcesTemplatesList: "PaginatedRequestParams",
+	mcp.MethodResourcesRead:          "ResourceRequestParams",
+	mcp.MethodResourcesSubscribe:     "ResourceRequestParams",
+	mcp.MethodResourcesUnsubscribe:   "ResourceRequestParams",
+	mcp.MethodPromptsList:            "PaginatedRequestParams",
+	mcp.MethodPromptsGet:             "GetPromptRequestParams",
+	mcp.MethodCompleteList:           "CompleteRequestParams",
+	mcp.MethodLoggingSetLevel:        "SetLevelRequestParams",
+	mcp.MethodSamplingCreateMessage:  "CreateMessageRequestParams",
+	mcp.MethodRootsList:              "PaginatedRequestParams",
+}
+
+// Definitions of results, by method. Other methods return a plain Result.
+var resultDefs = map[mcp.MCPMethod]string{
+	mcp.MethodInitialize:             "InitializeResult",
+	mcp.MethodToolsList:              "ListToolsResult",
+	mcp.MethodToolsCall:              "CallToolResult",
+	mcp.MethodResourcesList:          "ListResourcesResult",
+	mcp.MethodResourcesTemplatesList: "ListResourceTemplatesResult",
+	mcp.MethodResourcesRead:          "ReadResourceResult",
+	mcp.MethodPromptsList:            "ListPromptsResult",
+	mcp.MethodPromptsGet:             "GetPromptResult",
+	mcp.MethodCompleteList:           "CompleteResult",
+	mcp.MethodSamplingCreateMessage:  "CreateMessageResult",
+	mcp.MethodRootsList:              "ListRootsResult",
+}
+
+// Definitions of the params of notifications, by method. Other notifications
+// take plain NotificationParams.
+var notificationParamsDefs = map[mcp.MCPMethod]string{
+	mcp.MethodNotificationCancelled:      "CancelledNotificationParams",
+	mcp.MethodNotificationProgress:       "ProgressNotificationParams",
+	mcp.MethodNotificationLoggingMessage: "LoggingMessageNotificationParams",
+	// The method of log messages in the specification
+	"notifications/message":               "LoggingMessageNotificationParams",
+	mcp.MethodNotificationResourceUpdated: "ResourceUpdatedNotificationParams",
+}
+
+// protocolSchema validates messages against the definitions of the schema of
+// one protocol version.
+type protocolSchema struct {
+	// refs holds, by definition name, a schema referencing it
+	refs map[string]json.RawMessage
+}
+
+// loadedSchemas caches the parsed schemas by protocol version.
+var loadedSchemas sync.Map
+
+// loadProtocolSchema returns the embedded schema of a protocol version.
+func loadProtocolSchema(version string) (*protocolSchema, error) {
+	if schema, ok := loadedSchemas.Load(version); ok {
+		return schema.(*protocolSchema), nil
+	}
+	data, err := spec.Schema(version)
+	if err != nil {
+		return nil, err
+	}
+	// Definitions are under $defs, or definitions in the upstream schemas
+	var document struct {
+		Defs        map[string]json.RawMessage `json:"$defs"`
+		Definitions map[string]json.RawMessage `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse schema of protocol version %s: %w", version, err)
+	}
+	keyword, defs := "$defs", document.Defs
+	if len(defs) == 0 {
+		keyword, defs = "definitions", document.Definitions
+	}
+	encoded, err := json.Marshal(defs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema of protocol version %s: %w", version, err)
+	}
+	schema := &protocolSchema{refs: make(map[string]json.RawMessage, len(defs))}
+	for name := range defs {
+		schema.refs[name] = json.RawMessage(fmt.Sprintf(`{"$ref":"#/%s/%s",%q:%s}`, keyword, name, keyword, encoded))
+	}
+	loadedSchemas.Store(version, schema)
+	return schema, nil
+}
+
+// validate checks instance against the named definition.
+func (s *protocolSchema) validate(def string, instance any) error {
+	schema, ok := s.refs[def]
+	if !ok {
+		return fmt.Errorf("schema has no definition %s", def)
+	}
+	return jsonschema.Default.Validate(schema, instance)
+}
+
+// validateMessage checks a message against an envelope definition, then its
+// params or result, taken from field, against def. An absent field is
+// checked as an empty object. Violations are reported relative to the message.
+func (s *protocolSchema) validateMessage(envelope, field, def string, message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	if err := s.validate(envelope, json.RawMessage(data)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to decode message: %w", err)
+	}
+	value, ok := fields[field]
+	if !ok {
+		value = json.RawMessage(`{}`)
+	}
+	err = s.validate(def, value)
+	// Point the violations at the field within the message
+	var invalid *jsonschema.ValidationError
+	if errors.As(err, &invalid) {
+		for i := range invalid.Violations {
+			invalid.Violations[i].Path = "/" + field + invalid.Violations[i].Path
+		}
+	}
+	return err
+}
+
+// strictSchema returns the schema of the negotiated protocol version, or of
+// the requested one before initialization.
+func (c *HTTPClient) strictSchema() (*protocolSchema, error) {
+	version := c.initializeResult().ProtocolVersion
+	if version == "" {
+		version = c.protocolVersion()
+	}
+	return loadProtocolSchema(version)
+}
+
+// checkMessage validates a message in strict mode, wrapping violations in a
+// *ProtocolViolationError.
+func (c *HTTPClient) checkMessage(direction, method, envelope, field, def string, message any) error {
+	if !c.strict {
+		return nil
+	}
+	schema, err := c.strictSchema()
+	if err == nil {
+		err = schema.validateMessage(envelope, field, def, message)
+	}
+	if err != nil {
+		return &ProtocolViolationError{Direction: direction, Method: method, Err: err}
+	}
+	return nil
+}
+
+// checkRequest validates a request sent to the server in strict mode.
+func (c *HTTPClient) checkRequest(request transport.JSONRPCRequest) error {
+	def, ok := requestParamsDefs[mcp.MCPMethod(request.Method)]
+	if !ok {
+		def = "NotificationParams"
+	}
+	return c.checkMessage(DirectionOutgoing, request.Method, "JSONRPCRequest", "params", def, request)
+}
+
+// checkResponse validates the response to a request in strict mode. The
+// protocol version negotiated by initialize must have an embedded schema.
+func (c *HTTPClient) checkResponse(method string, response *transport.JSONRPCResponse) error {
+	if !c.strict {
+		return nil
+	}
+	if response.Error != nil {
+		return c.checkMessage(DirectionIncoming, method, "JSONRPCResponseOrError", "error", "Error", response)
+	}
+	def, ok := resultDefs[mcp.MCPMethod(method)]
+	if !ok {
+		def = "Result"
+	}
+	if err := c.checkMessage(DirectionIncoming, method, "JSONRPCResponseOrError", "result", def, response); err != nil {
+		return err
+	}
+	if method == string(mcp.MethodInitialize) {
+		var result mcp.InitializeResult
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			return &ProtocolViolationError{Direction: DirectionIncoming, Method: method, Err: err}
+		}
+		if _, err := loadProtocolSchema(result.ProtocolVersion); err != nil {
+			return &ProtocolViolationError{Direction: DirectionIncoming, Method: method, Err: err}
+		}
+	}
+	return nil
+}
+
+// checkNotification validates a notification in strict mode.
+func (c *HTTPClient) checkNotification(direction string, notification transport.JSONRPCNotification) error {
+	def, ok := notificationParamsDefs[mcp.MCPMethod(notification.Method)]
+	if !ok {
+		def = "NotificationParams"
+	}
+	return c.checkMessage(direction, notification.Method, "JSONRPCNotification", "params", def, notification)
+}
+
+// notify sends a notification to the server, validated in strict mode.
+func (c *HTTPClient) notify(ctx context.Context, notification transport.JSONRPCNotification) error {
+	if err := c.checkNotification(DirectionOutgoing, notification); err != nil {
+		return err
+	}
+	return c.transport.SendNotification(ctx, notification)
+}
+
+// strictRequestHandler validates the requests of the server and the results
+// of handler in strict mode.
+func (c *HTTPClient) strictRequestHandler(handler transport.RequestHandler) transport.RequestHandler {
+	return func(ctx context.Context, request transport.JSONRPCRequest) (any, error) {
+		if err := c.checkRequest(request); err != nil {
+			violation := err.(*ProtocolViolationError)
+			violation.Direction = DirectionIncoming
+			return nil, mcp.NewError(mcp.ErrorInvalidParams, violation.Error())
+		}
+		result, err := handler(ctx, request)
+		if err != nil || !c.strict {
+			return result, err
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode result: %w", err)
+		}
+		response := &transport.JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: request.ID, Result: data}
+		def, ok := resultDefs[mcp.MCPMethod(request.Method)]
+		if !ok {
+			def = "Result"
+		}
+		if err := c.checkMessage(DirectionOutgoing, request.Method, "JSONRPCResponse", "result", def, response); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
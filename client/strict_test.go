@@ -0,0 +1,139 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcp/jsonschema"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestStrictMode(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo", Description: "echo"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv.AddResource(mcp.Resource{URI: "file:///a.txt", Name: "a"}, mcp.TextResourceContents{URI: "file:///a.txt", Text: "a"})
+	srv.HandleMethod("tools/call", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		_ = mcptest.SendNotification(ctx, "notifications/message", map[string]any{"level": "loud", "data": "dropped"})
+		_ = mcptest.SendNotification(ctx, "notifications/message", map[string]any{"level": "info", "data": "kept"})
+		if strings.Contains(string(request.Params), "video") {
+			return map[string]any{"content": []any{map[string]any{"type": "video", "url": "https://example.com"}}}, nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv.SetBehavior("tools/call", mcptest.Behavior{SSE: true})
+	srv.Start()
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL, Logger: &logs}, WithStrictMode())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var received []any
+	client.Subscribe("notifications/message", func(notification mcp.Notification) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, notification.Params["data"])
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		if _, err := client.ListTools(ctx, ""); err != nil {
+			t.Errorf("ListTools failed: %v", err)
+		}
+		if _, err := client.ReadResource(ctx, "file:///a.txt"); err != nil {
+			t.Errorf("ReadResource failed: %v", err)
+		}
+		if _, err := client.CallTool(ctx, "echo", map[string]any{"text": "hi"}); err != nil {
+			t.Errorf("CallTool failed: %v", err)
+		}
+	})
+
+	t.Run("InvalidNotification", func(t *testing.T) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(received) != 1 || received[0] != "kept" {
+			t.Errorf("Expected only the valid notification, got %v", received)
+		}
+		if !strings.Contains(logs.String(), "dropped notification") || !strings.Contains(logs.String(), "/params/level") {
+			t.Errorf("Expected the dropped notification to be logged, got %q", logs.String())
+		}
+	})
+
+	t.Run("UnknownContentType", func(t *testing.T) {
+		_, err := client.CallTool(ctx, "echo", map[string]any{"text": "video"})
+		var violation *ProtocolViolationError
+		if !errors.As(err, &violation) || !errors.Is(err, ErrProtocolViolation) {
+			t.Fatalf("Expected a ProtocolViolationError, got %v", err)
+		}
+		if violation.Direction != DirectionIncoming || violation.Method != "tools/call" {
+			t.Errorf("Expected an incoming tools/call violation, got %s %s", violation.Direction, violation.Method)
+		}
+		var invalid *jsonschema.ValidationError
+		if !errors.As(err, &invalid) || !strings.Contains(err.Error(), "/content/0/type") {
+			t.Errorf("Expected the violation to point at the content type, got %v", err)
+		}
+	})
+
+	t.Run("MissingRequiredField", func(t *testing.T) {
+		sent := len(srv.Requests())
+		_, err := client.Request(ctx, "tools/call", map[string]any{"arguments": map[string]any{}})
+		var violation *ProtocolViolationError
+		if !errors.As(err, &violation) || violation.Direction != DirectionOutgoing {
+			t.Fatalf("Expected an outgoing ProtocolViolationError, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "name") {
+			t.Errorf("Expected the missing name to be reported, got %v", err)
+		}
+		if len(srv.Requests()) != sent {
+			t.Error("Expected the invalid request not to be sent")
+		}
+	})
+}
+
+func TestStrictModeVersion(t *testing.T) {
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		srv := mcptest.NewServer()
+		srv.HandleMethod("initialize", func(ctx context.Context, request *mcptest.Request) (any, error) {
+			return mcp.InitializeResult{
+				ProtocolVersion: "1999-01-01",
+				ServerInfo:      mcp.Implementation{Name: "old", Version: "1"},
+			}, nil
+		})
+		srv.Start()
+		defer srv.Close()
+
+		_, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithStrictMode())
+		if !errors.Is(err, ErrProtocolViolation) || !strings.Contains(err.Error(), "1999-01-01") {
+			t.Errorf("Expected a protocol violation for the version, got %v", err)
+		}
+	})
+
+	t.Run("AudioBeforeAudioExisted", func(t *testing.T) {
+		schema, err := loadProtocolSchema("2024-11-05")
+		if err != nil {
+			t.Fatalf("loadProtocolSchema failed: %v", err)
+		}
+		result := map[string]any{"content": []any{map[string]any{"type": "audio", "data": "AA==", "mimeType": "audio/wav"}}}
+		if err := schema.validate("CallToolResult", result); err == nil {
+			t.Error("Expected audio content to be rejected by 2024-11-05")
+		}
+		schema, err = loadProtocolSchema("2025-03-26")
+		if err != nil {
+			t.Fatalf("loadProtocolSchema failed: %v", err)
+		}
+		if err := schema.validate("CallToolResult", result); err != nil {
+			t.Errorf("Expected audio content to be accepted by 2025-03-26, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// PartialResultError is returned by CallTool when the call fails, typically
+// because the context was cancelled or timed out, after the server had already
+// streamed some content. Result holds the content received so far.
+type PartialResultError struct {
+	Result *mcp.CallToolResult
+	Err    error
+}
+
+func (e *PartialResultError) Error() string {
+	return fmt.Sprintf("partial result (%d content items): %v", len(e.Result.Content), e.Err)
+}
+
+func (e *PartialResultError) Unwrap() error {
+	return e.Err
+}
+
+// ListTools returns a page of the tools offered by the server.
+// Pass the NextCursor of the previous page to continue, or "" to start.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/tools#listing-tools
+func (c *HTTPClient) ListTools(ctx context.Context, cursor mcp.Cursor) (*mcp.ListToolsResult, error) {
+	if err := c.checkCapability(mcp.MethodToolsList); err != nil {
+		return nil, err
+	}
+	raw, err := c.list(ctx, mcp.MethodToolsList, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ListToolsResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tools: %w", err)
+	}
+	c.toolIndex.add(result.Tools)
+	result.Tools = c.filterTools(result.Tools)
+	return &result, nil
+}
+
+// CallTool invokes a tool on the server.
+//
+// Notifications carrying a "content" array that the server streams while the
+// call is in flight are accumulated. If the call then fails, the accumulated
+// content is returned in a *PartialResultError.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/tools#calling-tools
+func (c *HTTPClient) CallTool(ctx context.Context, name string, arguments map[string]any) (*mcp.CallToolResult, error) {
+	return c.callTool(ctx, name, arguments, nil)
+}
+
+// callTool sends a tools/call request, with meta as the request's _meta if
+// set, and audits it.
+func (c *HTTPClient) callTool(ctx context.Context, name string, arguments map[string]any, meta *mcp.Meta) (*mcp.CallToolResult, error) {
+	start := time.Now()
+	result, err := c.sendToolCall(ctx, name, arguments, meta)
+	c.audit(ctx, name, arguments, start, result, err)
+	return result, err
+}
+
+// sendToolCall checks and sends a tools/call request.
+func (c *HTTPClient) sendToolCall(ctx context.Context, name string, arguments map[string]any, meta *mcp.Meta) (*mcp.CallToolResult, error) {
+	if err := c.checkCapability(mcp.MethodToolsCall); err != nil {
+		return nil, err
+	}
+	if err := c.checkToolAllowed(ctx, name); err != nil {
+		return nil, err
+	}
+	if err := c.validateArguments(ctx, name, arguments); err != nil {
+		return nil, err
+	}
+	if err := c.checkPolicy(ctx, name, arguments); err != nil {
+		return nil, err
+	}
+	if err := c.approveCall(ctx, name, arguments); err != nil {
+		return nil, err
+	}
+
+	var (
+		mu      sync.Mutex
+		partial []mcp.Content
+	)
+	ctx = transport.WithRequestNotificationHandler(ctx, func(notification transport.JSONRPCNotification) {
+		content := partialContent(notification)
+		mu.Lock()
+		partial = append(partial, content...)
+		mu.Unlock()
+	})
+
+	params := map[string]any{"name": name}
+	if arguments != nil {
+		params["arguments"] = arguments
+	}
+	if meta != nil {
+		params["_meta"] = meta
+	}
+	raw, err := c.Request(ctx, string(mcp.MethodToolsCall), params)
+	if err != nil {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(partial) > 0 {
+			content := append([]mcp.Content(nil), partial...)
+			return nil, &PartialResultError{Result: &mcp.CallToolResult{Content: content}, Err: err}
+		}
+		return nil, err
+	}
+
+	message := json.RawMessage(raw)
+	result, err := mcp.ParseCallToolResult(&message)
+	if err != nil {
+		return nil, err
+	}
+	return c.validateOutput(ctx, name, result)
+}
+
+// partialContent extracts the content items carried by a streamed notification.
+// Items that fail to parse are skipped.
+func partialContent(notification transport.JSONRPCNotification) []mcp.Content {
+	items, ok := notification.Params.AdditionalFields["content"].([]any)
+	if !ok {
+		return nil
+	}
+	var content []mcp.Content
+	for _, item := range items {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if parsed, err := mcp.ParseContent(itemMap); err == nil {
+			content = append(content, parsed)
+		}
+	}
+	return content
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestCallTool(t *testing.T) {
+	srv := startTestServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(arguments["text"].(string)), nil
+	})
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	tools, err := client.ListTools(ctx, "")
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "echo" {
+		t.Errorf("Unexpected tools: %+v", tools.Tools)
+	}
+
+	result, err := client.CallTool(ctx, "echo", map[string]any{"text": "hello"})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if len(result.Content) != 1 || result.Content[0].(mcp.TextContent).Text != "hello" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestCallToolPartialResult(t *testing.T) {
+	srv := startTestServer()
+	// The tool never finishes on its own
+	srv.AddTool(mcp.Tool{Name: "slow"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	chunk := func(text string) mcp.JSONRPCNotification {
+		return mcp.JSONRPCNotification{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			Method:  "notifications/message",
+			Params:  map[string]any{"content": []any{map[string]any{"type": "text", "text": text}}},
+		}
+	}
+	srv.SetBehavior("tools/call", mcptest.Behavior{Notifications: []mcp.JSONRPCNotification{
+		chunk("first"),
+		{JSONRPC: mcp.JSONRPC_VERSION, Method: "notifications/progress"},
+		chunk("second"),
+	}})
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	_, err = client.CallTool(ctx, "slow", nil)
+	var partialErr *PartialResultError
+	if !errors.As(err, &partialErr) {
+		t.Fatalf("Expected a PartialResultError, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the cause to be the deadline, got %v", err)
+	}
+
+	content := partialErr.Result.Content
+	if len(content) != 2 || content[0].(mcp.TextContent).Text != "first" || content[1].(mcp.TextContent).Text != "second" {
+		t.Errorf("Unexpected partial content: %+v", content)
+	}
+
+	t.Run("no content", func(t *testing.T) {
+		srv.SetBehavior("tools/call", mcptest.Behavior{SSE: true})
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := client.CallTool(ctx, "slow", nil)
+		if err == nil || errors.As(err, &partialErr) {
+			t.Errorf("Expected a plain error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// UnauthorizedHandler is called when the server answers 401 Unauthorized,
+// with the WWW-Authenticate header of the response. It returns a new bearer
+// token, typically refreshed from the authorization server, or an error to
+// give up. It may be called concurrently by requests failing together.
+type UnauthorizedHandler func(ctx context.Context, wwwAuthenticate string) (newToken string, err error)
+
+// WithOnUnauthorized sets the handler asked for a new bearer token when the
+// server answers 401. The request is then retried once with the token, which
+// is also sent with all later requests in place of any Authorization header
+// set with WithHTTPHeaders.
+func WithOnUnauthorized(handler UnauthorizedHandler) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.onUnauthorized = handler
+	}
+}
+
+// doAuthorized sends the request built by newRequest, with the bearer token
+// obtained from the unauthorized handler, if any. On 401, it asks the handler
+// for a new token and sends a new request once more.
+func (c *StreamableHTTP) doAuthorized(ctx context.Context, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for retried := false; ; retried = true {
+		req, err := newRequest()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		if token, _ := c.token.Load().(string); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := c.do(req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized || c.onUnauthorized == nil || retried {
+			return resp, err
+		}
+		c.wireDump.response(resp, nil)
+		resp.Body.Close()
+
+		token, err := c.onUnauthorized(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh credentials: %w", err)
+		}
+		c.token.Store(token)
+	}
+}
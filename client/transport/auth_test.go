@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestStreamableHTTPOnUnauthorized(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":"1","result":{}}`)
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	request := JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewStringRequestId("1"), Method: "test"}
+
+	t.Run("Refresh", func(t *testing.T) {
+		requests.Store(0)
+		var challenges []string
+		trans, err := NewStreamableHTTP(srv.URL,
+			WithHTTPHeaders(map[string]string{"Authorization": "Bearer stale"}),
+			WithOnUnauthorized(func(ctx context.Context, wwwAuthenticate string) (string, error) {
+				challenges = append(challenges, wwwAuthenticate)
+				return "fresh", nil
+			}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+
+		for range 2 {
+			response, err := trans.SendRequest(ctx, request)
+			if err != nil || response.Error != nil {
+				t.Fatalf("Expected the retried request to succeed, got %v, %+v", err, response)
+			}
+		}
+		if len(challenges) != 1 || challenges[0] != `Bearer error="invalid_token"` {
+			t.Errorf("Expected the handler to get the challenge once, got %q", challenges)
+		}
+		if got := requests.Load(); got != 3 {
+			t.Errorf("Expected 3 requests, got %d", got)
+		}
+		if err := trans.SendNotification(ctx, JSONRPCNotification{JSONRPC: "2.0", Method: "n"}); err != nil {
+			t.Errorf("Expected the token to be reused by notifications, got %v", err)
+		}
+	})
+
+	t.Run("RetriedOnce", func(t *testing.T) {
+		requests.Store(0)
+		trans, err := NewStreamableHTTP(srv.URL, WithOnUnauthorized(func(ctx context.Context, wwwAuthenticate string) (string, error) {
+			return "still-wrong", nil
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+
+		_, err = trans.SendRequest(ctx, request)
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorUnauthorized {
+			t.Errorf("Expected an unauthorized error, got %v", err)
+		}
+		if got := requests.Load(); got != 2 {
+			t.Errorf("Expected 2 requests, got %d", got)
+		}
+	})
+
+	t.Run("HandlerError", func(t *testing.T) {
+		errDenied := errors.New("user denied consent")
+		trans, err := NewStreamableHTTP(srv.URL, WithOnUnauthorized(func(ctx context.Context, wwwAuthenticate string) (string, error) {
+			return "", errDenied
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+
+		if _, err := trans.SendRequest(ctx, request); !errors.Is(err, errDenied) {
+			t.Errorf("Expected the handler error, got %v", err)
+		}
+	})
+}
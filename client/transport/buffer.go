@@ -0,0 +1,133 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledBufferSize is the capacity beyond which buffers are dropped rather
+// than pooled, so that one large message does not keep its memory alive.
+const maxPooledBufferSize = 1 << 20
+
+var (
+	bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+	readerPool = sync.Pool{New: func() any { return bufio.NewReader(nil) }}
+	gzipPool   = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+)
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns a buffer to the pool.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// getReader returns a pooled buffered reader reading r.
+func getReader(r io.Reader) *bufio.Reader {
+	br := readerPool.Get().(*bufio.Reader)
+	br.Reset(r)
+	return br
+}
+
+// putReader returns a buffered reader to the pool.
+func putReader(br *bufio.Reader) {
+	br.Reset(nil)
+	readerPool.Put(br)
+}
+
+// messageBody is a marshaled message held in a pooled buffer. The buffer
+// goes back to the pool once the owner released the body and every request
+// sending it closed its reader, which the HTTP client does even on errors.
+type messageBody struct {
+	buf  *bytes.Buffer
+	refs atomic.Int32
+	// gzipped is the compressed body, made once for all the requests sending it
+	gzipped *messageBody
+}
+
+// marshalBody encodes message as JSON in a pooled buffer. The caller owns
+// the returned body and must release it.
+func marshalBody(message any) (*messageBody, error) {
+	buf := getBuffer()
+	if err := json.NewEncoder(buf).Encode(message); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	// Encode ends the message with a newline, which Marshal does not
+	buf.Truncate(buf.Len() - 1)
+	return newMessageBody(buf), nil
+}
+
+// newMessageBody returns a body owned by the caller holding buf.
+func newMessageBody(buf *bytes.Buffer) *messageBody {
+	body := &messageBody{buf: buf}
+	body.refs.Store(1)
+	return body
+}
+
+// Bytes returns the encoded message. It is valid until the body is released.
+func (b *messageBody) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// gzip returns the body compressed with gzip, owned by b.
+func (b *messageBody) gzip() (*messageBody, error) {
+	if b.gzipped != nil {
+		return b.gzipped, nil
+	}
+	buf := getBuffer()
+	zw := gzipPool.Get().(*gzip.Writer)
+	defer gzipPool.Put(zw)
+	zw.Reset(buf)
+	if _, err := zw.Write(b.buf.Bytes()); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	b.gzipped = newMessageBody(buf)
+	return b.gzipped, nil
+}
+
+// reader returns a reader of the body, which keeps it alive until closed.
+func (b *messageBody) reader() io.ReadCloser {
+	b.refs.Add(1)
+	return &bodyReader{Reader: bytes.NewReader(b.buf.Bytes()), body: b}
+}
+
+// release gives up a reference to the body, pooling its buffer after the last.
+func (b *messageBody) release() {
+	if b.refs.Add(-1) != 0 {
+		return
+	}
+	if b.gzipped != nil {
+		b.gzipped.release()
+	}
+	putBuffer(b.buf)
+}
+
+// bodyReader reads a messageBody as the body of a request.
+type bodyReader struct {
+	*bytes.Reader
+	body  *messageBody
+	close sync.Once
+}
+
+func (r *bodyReader) Close() error {
+	r.close.Do(r.body.release)
+	return nil
+}
@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestMessageBody(t *testing.T) {
+	message := map[string]any{"text": "<a & b>", "n": 1}
+	body, err := marshalBody(message)
+	if err != nil {
+		t.Fatalf("marshalBody failed: %v", err)
+	}
+	want, _ := json.Marshal(message)
+	if !bytes.Equal(body.Bytes(), want) {
+		t.Errorf("Expected %s, got %s", want, body.Bytes())
+	}
+
+	// Readers keep the body alive after its owner released it
+	reader := body.reader()
+	body.release()
+	got, _ := io.ReadAll(reader)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Expected the reader to read %s, got %s", want, got)
+	}
+	reader.Close()
+	reader.Close()
+	if refs := body.refs.Load(); refs != 0 {
+		t.Errorf("Expected no reference left, got %d", refs)
+	}
+}
+
+func TestReadSSELongLines(t *testing.T) {
+	long := strings.Repeat("x", 10000)
+	stream := "data: " + long + "\n\ndata: short\n\ndata: " + long + "y\n\n"
+	var got []string
+	c := &StreamableHTTP{}
+	_ = c.readSSE(context.Background(), io.NopCloser(strings.NewReader(stream)), nil, func(event, data string) {
+		got = append(got, data)
+	})
+	if len(got) != 3 || got[0] != long || got[1] != "short" || got[2] != long+"y" {
+		t.Errorf("Unexpected events: %d", len(got))
+	}
+}
+
+// benchmarkSendRequest sends tool calls with arguments of argumentSize bytes
+// to a server answering them from memory.
+func benchmarkSendRequest(b *testing.B, argumentSize int, options ...StreamableHTTPCOption) {
+	response := []byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"ok"}]}}`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(response)
+	}))
+	defer srv.Close()
+	trans, err := NewStreamableHTTP(srv.URL, options...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer trans.Close()
+
+	request := JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewIntRequestId(1),
+		Method:  "tools/call",
+		Params:  map[string]any{"name": "echo", "arguments": map[string]any{"text": strings.Repeat("x", argumentSize)}},
+	}
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := trans.SendRequest(ctx, request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSendRequest(b *testing.B) {
+	benchmarkSendRequest(b, 64*1024)
+}
+
+func BenchmarkSendRequestCompressed(b *testing.B) {
+	benchmarkSendRequest(b, 64*1024, WithRequestCompression(1024))
+}
+
+func BenchmarkReadSSE(b *testing.B) {
+	var stream bytes.Buffer
+	for i := range 1000 {
+		fmt.Fprintf(&stream, "id: %d\nevent: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\",\"params\":{\"progress\":%d}}\n\n", i, i)
+	}
+	data := stream.Bytes()
+	c := &StreamableHTTP{}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		events := 0
+		_ = c.readSSE(context.Background(), io.NopCloser(bytes.NewReader(data)), nil, func(event, data string) {
+			events++
+		})
+		if events != 1000 {
+			b.Fatalf("Expected 1000 events, got %d", events)
+		}
+	}
+}
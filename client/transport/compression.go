@@ -0,0 +1,106 @@
+package transport
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding lists the content codings the transport decodes.
+const acceptEncoding = "gzip, deflate"
+
+// WithRequestCompression gzips the bodies of the messages sent to the server
+// that are at least minSize bytes, such as large tool arguments. The server
+// must accept gzip-encoded requests, as the handler of the server package
+// does.
+func WithRequestCompression(minSize int) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.compressRequests = true
+		sc.compressMinSize = minSize
+	}
+}
+
+// newPost creates a POST request sending body, gzipped if request compression
+// is enabled and body is large enough. body must be held until the request
+// was sent.
+func (c *StreamableHTTP) newPost(ctx context.Context, body *messageBody) (*http.Request, error) {
+	encoding := ""
+	if c.compressRequests && len(body.Bytes()) >= c.compressMinSize {
+		gzipped, err := body.gzip()
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress request: %w", err)
+		}
+		body, encoding = gzipped, "gzip"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// GetBody is only called while the request is sent, when body is held
+	req.Body = body.reader()
+	req.ContentLength = int64(len(body.Bytes()))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return body.reader(), nil
+	}
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	return req, nil
+}
+
+// do sends a request accepting compressed responses, and returns the response
+// with its body decoded.
+func (c *StreamableHTTP) do(req *http.Request) (*http.Response, error) {
+	// Setting the header disables the transparent gzip decoding of
+	// http.Transport, which does not support deflate
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	switch encoding {
+	case "gzip", "x-gzip", "deflate":
+		resp.Body = &decompressor{body: resp.Body, encoding: encoding}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// decompressor decodes a gzip or deflate response body. The decoder is
+// created on the first read, so that waiting for the first event of an SSE
+// stream does not block.
+type decompressor struct {
+	body     io.ReadCloser
+	encoding string
+	r        io.Reader
+	err      error
+}
+
+func (d *decompressor) Read(p []byte) (int, error) {
+	if d.r == nil && d.err == nil {
+		if d.encoding == "deflate" {
+			d.r, d.err = zlib.NewReader(d.body)
+		} else {
+			d.r, d.err = gzip.NewReader(d.body)
+		}
+		if d.err != nil {
+			d.err = fmt.Errorf("failed to decode %s response: %w", d.encoding, d.err)
+		}
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return d.r.Read(p)
+}
+
+func (d *decompressor) Close() error {
+	return d.body.Close()
+}
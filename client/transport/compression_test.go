@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestStreamableHTTPCompression(t *testing.T) {
+	result := `{"jsonrpc":"2.0","id":"1","result":{"text":"` + strings.Repeat("x", 1000) + `"}}`
+	var received string
+	var receivedEncoding string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedEncoding = r.Header.Get("Content-Encoding")
+		var body io.Reader = r.Body
+		if receivedEncoding == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("Failed to decode request: %v", err)
+				return
+			}
+			body = zr
+		}
+		data, _ := io.ReadAll(body)
+		received = string(data)
+
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "deflate") {
+			t.Errorf("Expected gzip and deflate to be accepted, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		contentType := "application/json"
+		payload := result
+		if r.URL.Query().Has("sse") {
+			contentType = "text/event-stream"
+			payload = fmt.Sprintf("data: %s\n\n", result)
+		}
+		w.Header().Set("Content-Type", contentType)
+		var zw io.WriteCloser
+		switch encoding := r.URL.Query().Get("encoding"); encoding {
+		case "gzip":
+			zw = gzip.NewWriter(w)
+		case "deflate":
+			zw = zlib.NewWriter(w)
+		default:
+			io.WriteString(w, payload)
+			return
+		}
+		w.Header().Set("Content-Encoding", r.URL.Query().Get("encoding"))
+		io.WriteString(zw, payload)
+		zw.Close()
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	request := JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewStringRequestId("1"), Method: "big"}
+
+	for _, query := range []string{"encoding=gzip", "encoding=deflate", "encoding=gzip&sse", "encoding=identity"} {
+		t.Run(query, func(t *testing.T) {
+			trans, err := NewStreamableHTTP(srv.URL + "?" + query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer trans.Close()
+			response, err := trans.SendRequest(ctx, request)
+			if err != nil {
+				t.Fatalf("SendRequest failed: %v", err)
+			}
+			if !strings.Contains(string(response.Result), strings.Repeat("x", 1000)) {
+				t.Errorf("Expected the decoded result, got %s", response.Result)
+			}
+		})
+	}
+
+	t.Run("RequestCompression", func(t *testing.T) {
+		trans, err := NewStreamableHTTP(srv.URL, WithRequestCompression(100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		if _, err := trans.SendRequest(ctx, request); err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+		if receivedEncoding != "" {
+			t.Errorf("Expected small requests to be sent as is, got %q", receivedEncoding)
+		}
+
+		large := request
+		large.Params = map[string]any{"text": strings.Repeat("y", 200)}
+		if _, err := trans.SendRequest(ctx, large); err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+		if receivedEncoding != "gzip" || !strings.Contains(received, strings.Repeat("y", 200)) {
+			t.Errorf("Expected a gzipped request, got %q encoded %q", received, receivedEncoding)
+		}
+	})
+}
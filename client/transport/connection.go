@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSessionTerminated is returned when the server answers 404 Not Found to a
+// request carrying a session ID: the session expired or was deleted, and a
+// new one must be initialized.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/transports#session-management
+var ErrSessionTerminated = errors.New("session terminated")
+
+// ConnectionState is the state of the connection to the server.
+type ConnectionState int32
+
+const (
+	// ConnectionConnecting means the server has not answered yet
+	ConnectionConnecting ConnectionState = iota
+	// ConnectionConnected means the server answers
+	ConnectionConnected
+	// ConnectionLost means a request or the listening stream failed, or the
+	// session expired
+	ConnectionLost
+	// ConnectionReconnecting means the listening stream is being reopened or
+	// the session re-established
+	ConnectionReconnecting
+	// ConnectionFailed means reconnecting was given up
+	ConnectionFailed
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionConnecting:
+		return "connecting"
+	case ConnectionConnected:
+		return "connected"
+	case ConnectionLost:
+		return "lost"
+	case ConnectionReconnecting:
+		return "reconnecting"
+	case ConnectionFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("ConnectionState(%d)", int32(s))
+}
+
+// ConnectionStateHandler is called on every change of the connection state,
+// with the error causing it, if any. It runs on the goroutine that observed
+// the change and should not block.
+type ConnectionStateHandler func(state ConnectionState, err error)
+
+// WithMaxReconnectAttempts makes the listening stream give up, entering
+// ConnectionFailed, after n consecutive attempts to reopen it fail. By
+// default it is reopened for as long as the transport is open.
+func WithMaxReconnectAttempts(n int) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.maxReconnectAttempts = n
+	}
+}
+
+// SetConnectionStateHandler sets the handler of connection state changes,
+// replacing the previous one. A nil handler removes it.
+func (c *StreamableHTTP) SetConnectionStateHandler(handler ConnectionStateHandler) {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	c.connHandler = handler
+}
+
+// ConnectionState returns the current state of the connection.
+func (c *StreamableHTTP) ConnectionState() ConnectionState {
+	return ConnectionState(c.connState.Load())
+}
+
+// setConnectionState records a new connection state and reports it if it
+// changed.
+func (c *StreamableHTTP) setConnectionState(state ConnectionState, err error) {
+	if ConnectionState(c.connState.Swap(int32(state))) == state {
+		return
+	}
+	c.connMu.RLock()
+	handler := c.connHandler
+	c.connMu.RUnlock()
+	if handler != nil {
+		handler(state, err)
+	}
+}
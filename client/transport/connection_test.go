@@ -0,0 +1,157 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+// stateRecorder collects the connection states reported to its handler.
+type stateRecorder struct {
+	mu     sync.Mutex
+	states []ConnectionState
+	errs   []error
+}
+
+func (r *stateRecorder) handle(state ConnectionState, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states = append(r.states, state)
+	r.errs = append(r.errs, err)
+}
+
+// wait returns the recorded states once one of them is state.
+func (r *stateRecorder) wait(t *testing.T, state ConnectionState) []ConnectionState {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		for _, s := range r.states {
+			if s == state {
+				states := append([]ConnectionState(nil), r.states...)
+				r.mu.Unlock()
+				return states
+			}
+		}
+		r.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for the %s state", state)
+	return nil
+}
+
+func TestStreamableHTTPConnectionState(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.Start()
+	defer srv.Close()
+	ctx := context.Background()
+
+	retryDelay := listenRetryDelay
+	listenRetryDelay = time.Millisecond
+	defer func() { listenRetryDelay = retryDelay }()
+
+	// proxy answers GET requests with getStatus, ending the streams it opens
+	proxy := func(getStatus int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				srv.Config.Handler.ServeHTTP(w, r)
+				return
+			}
+			if getStatus != http.StatusOK {
+				http.Error(w, http.StatusText(getStatus), getStatus)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+
+	t.Run("String", func(t *testing.T) {
+		if got := ConnectionReconnecting.String(); got != "reconnecting" {
+			t.Errorf("Expected reconnecting, got %s", got)
+		}
+		if got := ConnectionState(42).String(); got != "ConnectionState(42)" {
+			t.Errorf("Expected ConnectionState(42), got %s", got)
+		}
+	})
+
+	t.Run("StreamLost", func(t *testing.T) {
+		server := proxy(http.StatusOK)
+		defer server.Close()
+		var recorder stateRecorder
+		trans, err := NewStreamableHTTP(server.URL, WithListening())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		trans.SetConnectionStateHandler(recorder.handle)
+		if got := trans.ConnectionState(); got != ConnectionConnecting {
+			t.Errorf("Expected %s before the first request, got %s", ConnectionConnecting, got)
+		}
+		if _, err := trans.Initialize(ctx, mcp.LATEST_PROTOCOL_VERSION, nil, nil); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+		states := recorder.wait(t, ConnectionReconnecting)
+		want := []ConnectionState{ConnectionConnected, ConnectionLost, ConnectionReconnecting}
+		for i, state := range want {
+			if i >= len(states) || states[i] != state {
+				t.Fatalf("Expected states to start with %v, got %v", want, states)
+			}
+		}
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		if !errors.Is(recorder.errs[1], errStreamEnded) {
+			t.Errorf("Expected the stream end to be the cause, got %v", recorder.errs[1])
+		}
+	})
+
+	t.Run("GaveUp", func(t *testing.T) {
+		server := proxy(http.StatusServiceUnavailable)
+		defer server.Close()
+		var recorder stateRecorder
+		trans, err := NewStreamableHTTP(server.URL, WithListening(), WithMaxReconnectAttempts(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		trans.SetConnectionStateHandler(recorder.handle)
+		if _, err := trans.Initialize(ctx, mcp.LATEST_PROTOCOL_VERSION, nil, nil); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+		recorder.wait(t, ConnectionFailed)
+		if got := trans.ConnectionState(); got != ConnectionFailed {
+			t.Errorf("Expected %s, got %s", ConnectionFailed, got)
+		}
+	})
+
+	t.Run("SessionTerminated", func(t *testing.T) {
+		var recorder stateRecorder
+		trans, err := NewStreamableHTTP(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		trans.SetConnectionStateHandler(recorder.handle)
+		if _, err := trans.Initialize(ctx, mcp.LATEST_PROTOCOL_VERSION, nil, nil); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+		srv.ExpireSession()
+		_, err = trans.Request(ctx, "tools/list", nil)
+		if !errors.Is(err, ErrSessionTerminated) {
+			t.Fatalf("Expected ErrSessionTerminated, got %v", err)
+		}
+		if got := trans.ConnectionState(); got != ConnectionLost {
+			t.Errorf("Expected %s, got %s", ConnectionLost, got)
+		}
+		if trans.GetSessionId() != "" {
+			t.Error("Expected the terminated session to be forgotten")
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package transport
+
+import "context"
+
+type requestNotificationHandlerKey struct{}
+
+// WithRequestNotificationHandler returns a context that passes notifications
+// streamed in the response to a request sent with it to handler, in addition
+// to the transport-wide notification handler. The handler runs on the stream
+// reader, so it may be called concurrently with a cancelled SendRequest returning.
+func WithRequestNotificationHandler(ctx context.Context, handler func(JSONRPCNotification)) context.Context {
+	return context.WithValue(ctx, requestNotificationHandlerKey{}, handler)
+}
+
+// requestNotificationHandler returns the handler set by WithRequestNotificationHandler, or nil.
+func requestNotificationHandler(ctx context.Context) func(JSONRPCNotification) {
+	handler, _ := ctx.Value(requestNotificationHandlerKey{}).(func(JSONRPCNotification))
+	return handler
+}
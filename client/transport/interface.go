@@ -3,6 +3,9 @@ package transport
 import (
 	"context"
 	"encoding/json"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
 )
 
 // Interface for the transport layer.
@@ -11,7 +14,8 @@ type Interface interface {
 	Start(ctx context.Context) error
 
 	// Initialize the transport with protocol version, client info, and capabilities.
-	Initialize(ctx context.Context, protocolVersion string, clientInfo map[string]interface{}, capabilities map[string]interface{}) error
+	// It returns the server's initialize result.
+	Initialize(ctx context.Context, protocolVersion string, clientInfo map[string]interface{}, capabilities map[string]interface{}) (*mcp.InitializeResult, error)
 
 	// SendRequest sends a json RPC request and returns the response synchronously.
 	SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error)
@@ -23,30 +27,37 @@ type Interface interface {
 	// Any notification before the handler is set will be discarded.
 	SetNotificationHandler(handler func(notification JSONRPCNotification))
 
+	// SetRequestHandler sets the handler for requests the server sends to the client.
+	// Ping is answered by the transport; without a handler, other requests
+	// are answered with a method-not-found error.
+	SetRequestHandler(handler RequestHandler)
+
 	// Ping sends a ping request to the server and waits for a response.
 	// This can be used to check if the server is still alive.
-	Ping(ctx context.Context) error
+	// It returns the measured round-trip time.
+	Ping(ctx context.Context) (time.Duration, error)
 
 	// Close the connection.
 	Close() error
 }
 
+// RequestHandler answers a server -> client request with a result to be
+// marshaled, or an error. Errors of type *mcp.Error are sent as-is; other
+// errors become internal errors.
+type RequestHandler func(ctx context.Context, request JSONRPCRequest) (any, error)
+
 type JSONRPCRequest struct {
-	JSONRPC string `json:"jsonrpc"`
-	ID      string `json:"id"`
-	Method  string `json:"method"`
-	Params  any    `json:"params,omitempty"`
+	JSONRPC string        `json:"jsonrpc"`
+	ID      mcp.RequestId `json:"id"`
+	Method  string        `json:"method"`
+	Params  any           `json:"params,omitempty"`
 }
 
 type JSONRPCResponse struct {
 	JSONRPC string          `json:"jsonrpc"`
-	ID      *string         `json:"id"`
-	Result  json.RawMessage `json:"result"`
-	Error   *struct {
-		Code    int             `json:"code"`
-		Message string          `json:"message"`
-		Data    json.RawMessage `json:"data"`
-	} `json:"error"`
+	ID      mcp.RequestId   `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *mcp.Error      `json:"error,omitempty"`
 }
 
 type JSONRPCNotification struct {
@@ -57,6 +68,21 @@ type JSONRPCNotification struct {
 	} `json:"-"`
 }
 
+// MarshalJSON implements the json.Marshaler interface.
+// Params are encoded from AdditionalFields, which is omitted when empty.
+func (n JSONRPCNotification) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		JSONRPC string                 `json:"jsonrpc"`
+		Method  string                 `json:"method"`
+		Params  map[string]interface{} `json:"params,omitempty"`
+	}{
+		JSONRPC: n.JSONRPC,
+		Method:  n.Method,
+		Params:  n.Params.AdditionalFields,
+	}
+	return json.Marshal(aux)
+}
+
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (n *JSONRPCNotification) UnmarshalJSON(data []byte) error {
 	type alias JSONRPCNotification
@@ -0,0 +1,37 @@
+package transport
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func FuzzJSONRPCNotificationUnmarshal(f *testing.F) {
+	f.Add([]byte(`{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":"t","progress":0.5}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","method":"m","params":null}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","method":"m","params":[1,2]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var notification JSONRPCNotification
+		if err := json.Unmarshal(data, &notification); err != nil {
+			return
+		}
+
+		// A decoded notification must survive a marshal/unmarshal round trip
+		encoded, err := json.Marshal(notification)
+		if err != nil {
+			t.Fatalf("Failed to marshal notification: %v", err)
+		}
+		var again JSONRPCNotification
+		if err := json.Unmarshal(encoded, &again); err != nil {
+			t.Fatalf("Failed to unmarshal %s: %v", encoded, err)
+		}
+		// Empty params are omitted when marshaling, which is equivalent
+		got, want := again.Params.AdditionalFields, notification.Params.AdditionalFields
+		sameParams := (len(got) == 0 && len(want) == 0) || reflect.DeepEqual(got, want)
+		if again.Method != notification.Method || !sameParams {
+			t.Fatalf("Round trip mismatch: %s gave %+v, want %+v", encoded, again, notification)
+		}
+	})
+}
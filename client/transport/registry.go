@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnsupportedScheme is returned by New for addresses whose scheme no
+// transport handles.
+var ErrUnsupportedScheme = errors.New("unsupported transport scheme")
+
+// Factory creates a transport for an address of the scheme it is registered
+// for. The transport is not started.
+type Factory func(address string) (Interface, error)
+
+// StdioScheme prefixes the addresses of stdio servers, followed by the
+// command line running the server, as in "stdio:./server --flag".
+const StdioScheme = "stdio"
+
+// builtinSchemes are the schemes of the transports of this package.
+var builtinSchemes = []string{"http", "https", StdioScheme}
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// Register makes the transports created by factory available to New for
+// addresses of scheme, such as "ws" for "ws://host/mcp". Like
+// database/sql.Register, it is meant to be called from init and panics if
+// factory is nil or the scheme is built in or already registered.
+func Register(scheme string, factory Factory) {
+	scheme = strings.ToLower(scheme)
+	if factory == nil {
+		panic("transport: Register factory is nil")
+	}
+	if slices.Contains(builtinSchemes, scheme) {
+		panic("transport: Register called for built-in scheme " + scheme)
+	}
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if _, ok := factories[scheme]; ok {
+		panic("transport: Register called twice for scheme " + scheme)
+	}
+	factories[scheme] = factory
+}
+
+// Schemes returns the sorted schemes New handles.
+func Schemes() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	schemes := slices.Clone(builtinSchemes)
+	for scheme := range factories {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// Scheme returns the lowercased scheme of an address, or "" if it has none.
+func Scheme(address string) string {
+	scheme, _, ok := strings.Cut(address, ":")
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(scheme)
+}
+
+// ParseStdioAddress splits a "stdio:" address into the command and the
+// arguments of its command line, separated by spaces.
+func ParseStdioAddress(address string) (command string, args []string, err error) {
+	if Scheme(address) != StdioScheme {
+		return "", nil, fmt.Errorf("not a %s address: %q", StdioScheme, address)
+	}
+	fields := strings.Fields(address[len(StdioScheme)+1:])
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("missing command after %q", StdioScheme+":")
+	}
+	return fields[0], fields[1:], nil
+}
+
+// New creates a transport for address, picked by its scheme: a
+// StreamableHTTP transport for http and https URLs, a Stdio transport for
+// "stdio:" command lines, or the transport of the factory registered for
+// the scheme. Built-in transports get their default options.
+func New(address string) (Interface, error) {
+	switch scheme := Scheme(address); scheme {
+	case "http", "https":
+		return NewStreamableHTTP(address)
+	case StdioScheme:
+		command, args, err := ParseStdioAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		return NewStdio(command, args), nil
+	default:
+		factoriesMu.RLock()
+		factory, ok := factories[scheme]
+		factoriesMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("%w %q in %q", ErrUnsupportedScheme, scheme, address)
+		}
+		return factory(address)
+	}
+}
@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestScheme(t *testing.T) {
+	for address, want := range map[string]string{
+		"https://example.com/mcp": "https",
+		"HTTP://example.com":      "http",
+		"stdio:./server --flag":   "stdio",
+		"./server":                "",
+	} {
+		if got := Scheme(address); got != want {
+			t.Errorf("Expected scheme %q for %q, got %q", want, address, got)
+		}
+	}
+}
+
+func TestParseStdioAddress(t *testing.T) {
+	t.Run("Command line", func(t *testing.T) {
+		command, args, err := ParseStdioAddress("stdio:./server  --flag value")
+		if err != nil {
+			t.Fatalf("ParseStdioAddress failed: %v", err)
+		}
+		if command != "./server" || !slices.Equal(args, []string{"--flag", "value"}) {
+			t.Errorf("Expected ./server [--flag value], got %s %v", command, args)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, address := range []string{"stdio:", "stdio:  ", "https://example.com"} {
+			if _, _, err := ParseStdioAddress(address); err == nil {
+				t.Errorf("Expected an error for %q", address)
+			}
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("Built-in schemes", func(t *testing.T) {
+		trans, err := New("https://example.com/mcp")
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		if _, ok := trans.(*StreamableHTTP); !ok {
+			t.Errorf("Expected a *StreamableHTTP, got %T", trans)
+		}
+		trans, err = New("stdio:./server --flag")
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		if _, ok := trans.(*Stdio); !ok {
+			t.Errorf("Expected a *Stdio, got %T", trans)
+		}
+	})
+
+	t.Run("Unsupported scheme", func(t *testing.T) {
+		if _, err := New("unknown://example.com"); !errors.Is(err, ErrUnsupportedScheme) {
+			t.Errorf("Expected ErrUnsupportedScheme, got %v", err)
+		}
+	})
+
+	t.Run("Registered scheme", func(t *testing.T) {
+		var got string
+		Register("Test-Registry", func(address string) (Interface, error) {
+			got = address
+			return NewStreamableHTTP("http://example.com/mcp")
+		})
+		if _, err := New("test-registry://example.com/mcp"); err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		if got != "test-registry://example.com/mcp" {
+			t.Errorf("Expected the factory to get the address, got %q", got)
+		}
+		if !slices.Contains(Schemes(), "test-registry") {
+			t.Errorf("Expected test-registry in %v", Schemes())
+		}
+	})
+
+	t.Run("Register panics", func(t *testing.T) {
+		factory := func(string) (Interface, error) { return nil, nil }
+		for name, register := range map[string]func(){
+			"nil factory": func() { Register("test-nil", nil) },
+			"built-in":    func() { Register("https", factory) },
+			"duplicate":   func() { Register("test-registry", factory) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("Expected Register to panic for a %s", name)
+					}
+				}()
+				register()
+			}()
+		}
+	})
+}
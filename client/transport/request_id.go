@@ -0,0 +1,50 @@
+package transport
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/oklog/ulid"
+)
+
+// RequestIDGenerator returns the IDs of outgoing requests. It is called
+// concurrently and must not return the same ID twice within a session.
+type RequestIDGenerator func() mcp.RequestId
+
+// CounterRequestIDs returns a generator of the number IDs 1, 2, 3 and so on,
+// which are deterministic in tests.
+func CounterRequestIDs() RequestIDGenerator {
+	var last atomic.Int64
+	return func() mcp.RequestId {
+		return mcp.NewIntRequestId(last.Add(1))
+	}
+}
+
+// UUIDRequestIDs returns a generator of random (version 4) UUID string IDs.
+func UUIDRequestIDs() RequestIDGenerator {
+	return func() mcp.RequestId {
+		var b [16]byte
+		_, _ = rand.Read(b[:])
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return mcp.NewStringRequestId(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]))
+	}
+}
+
+// ULIDRequestIDs returns a generator of ULID string IDs, which sort by time.
+// It is the default of the transports. The IDs share a monotonic entropy
+// source seeded from crypto/rand, so IDs generated within the same
+// millisecond still increase.
+func ULIDRequestIDs() RequestIDGenerator {
+	var mu sync.Mutex
+	entropy := ulid.Monotonic(rand.Reader, 0)
+	return func() mcp.RequestId {
+		// The monotonic entropy source is not safe for concurrent use
+		mu.Lock()
+		defer mu.Unlock()
+		return mcp.NewStringRequestId(ulid.MustNew(ulid.Now(), entropy).String())
+	}
+}
@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestRequestIDGenerators(t *testing.T) {
+	generators := map[string]RequestIDGenerator{
+		"Counter": CounterRequestIDs(),
+		"UUID":    UUIDRequestIDs(),
+		"ULID":    ULIDRequestIDs(),
+	}
+	for name, generate := range generators {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			seen := map[mcp.RequestId]bool{}
+			var wg sync.WaitGroup
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 1000 {
+						id := generate()
+						mu.Lock()
+						if seen[id] {
+							t.Errorf("Expected unique IDs, got %v twice", id)
+						}
+						seen[id] = true
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+
+	t.Run("CounterSequence", func(t *testing.T) {
+		generate := CounterRequestIDs()
+		for i := int64(1); i <= 3; i++ {
+			if id := generate(); id != mcp.NewIntRequestId(i) {
+				t.Errorf("Expected %d, got %v", i, id)
+			}
+		}
+	})
+
+	t.Run("ULIDOrder", func(t *testing.T) {
+		generate := ULIDRequestIDs()
+		last := generate().String()
+		for range 1000 {
+			id := generate().String()
+			if id <= last {
+				t.Fatalf("Expected increasing IDs, got %s after %s", id, last)
+			}
+			last = id
+		}
+	})
+
+	t.Run("UUIDFormat", func(t *testing.T) {
+		uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+		if id := UUIDRequestIDs()().String(); !uuid.MatchString(id) {
+			t.Errorf("Expected a version 4 UUID, got %s", id)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// answerServerRequest builds the response to a request the server sent to the client.
+// Pings are answered with an empty result, after calling pingHandler if set;
+// other methods go to handler, or get a method-not-found error without one.
+func answerServerRequest(ctx context.Context, request JSONRPCRequest, pingHandler func(id mcp.RequestId), handler RequestHandler) JSONRPCResponse {
+	var (
+		result any
+		err    error
+	)
+	switch {
+	case request.Method == "ping":
+		if pingHandler != nil {
+			pingHandler(request.ID)
+		}
+		result = struct{}{}
+	case handler != nil:
+		result, err = handler(ctx, request)
+	default:
+		err = mcp.NewError(mcp.ErrorMethodNotFound, fmt.Sprintf("method not found: %s", request.Method))
+	}
+
+	response := JSONRPCResponse{JSONRPC: "2.0", ID: request.ID}
+	if err != nil {
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = mcp.NewError(mcp.ErrorInternalError, err.Error())
+		}
+		response.Error = rpcErr
+	} else if response.Result, err = json.Marshal(result); err != nil {
+		response.Error = mcp.NewError(mcp.ErrorInternalError, fmt.Sprintf("failed to marshal result: %v", err))
+	}
+	return response
+}
@@ -0,0 +1,391 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// stdioCloseTimeout is how long Close waits for the server to exit after its
+// stdin is closed before killing it.
+const stdioCloseTimeout = 2 * time.Second
+
+// ErrTransportClosed is returned for requests in flight or sent after the
+// stdio transport was closed or the server process exited.
+var ErrTransportClosed = errors.New("transport closed")
+
+type StdioOption func(*Stdio)
+
+// WithStdioEnv sets environment variables, as "KEY=value" entries, for the
+// server process in addition to the ones of the current process.
+func WithStdioEnv(env []string) StdioOption {
+	return func(s *Stdio) {
+		s.env = env
+	}
+}
+
+// WithStdioStderr sets where the server's stderr goes. It defaults to os.Stderr.
+func WithStdioStderr(w io.Writer) StdioOption {
+	return func(s *Stdio) {
+		s.stderr = w
+	}
+}
+
+// WithStdioPingHandler sets a function called whenever the server pings the client.
+// The transport answers the ping itself; the handler only observes it.
+func WithStdioPingHandler(handler func(id mcp.RequestId)) StdioOption {
+	return func(s *Stdio) {
+		s.pingHandler = handler
+	}
+}
+
+// WithStdioRequestIDs sets how the IDs of the pings sent by Ping are
+// generated, ULIDRequestIDs by default.
+func WithStdioRequestIDs(generate RequestIDGenerator) StdioOption {
+	return func(s *Stdio) {
+		s.requestIDs = generate
+	}
+}
+
+// Stdio implements the stdio transport.
+//
+// It launches the server as a subprocess and exchanges newline-delimited
+// JSON-RPC messages over its stdin and stdout.
+//
+// http://spec.modelcontextprotocol.io/2025-03-26/basic/transports#stdio
+//
+// Notifications are not tied to requests over stdio, so handlers set with
+// WithRequestNotificationHandler are never called.
+type Stdio struct {
+	command string
+	args    []string
+	env     []string
+	stderr  io.Writer
+
+	cmd     *exec.Cmd
+	reader  io.Reader
+	writer  io.WriteCloser
+	writeMu sync.Mutex
+
+	pending   map[mcp.RequestId]chan *JSONRPCResponse
+	pendingMu sync.Mutex
+
+	notificationHandler func(JSONRPCNotification)
+	notifyMu            sync.RWMutex
+
+	pingHandler func(id mcp.RequestId)
+	requestIDs  RequestIDGenerator
+
+	requestHandler RequestHandler
+	requestMu      sync.RWMutex
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	done      chan struct{}
+}
+
+// NewStdio creates a stdio transport for the server started by running
+// command with args. The process is started by Start.
+func NewStdio(command string, args []string, options ...StdioOption) *Stdio {
+	s := &Stdio{
+		command:    command,
+		args:       args,
+		stderr:     os.Stderr,
+		pending:    make(map[mcp.RequestId]chan *JSONRPCResponse),
+		requestIDs: ULIDRequestIDs(),
+		closed:     make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+// NewStdioPipe creates a stdio transport over an existing connection to a
+// server, reading its messages from r and writing to w. Start begins reading;
+// Close closes w.
+func NewStdioPipe(r io.Reader, w io.WriteCloser, options ...StdioOption) *Stdio {
+	s := NewStdio("", nil, options...)
+	s.reader = r
+	s.writer = w
+	return s
+}
+
+// Start launches the server process, unless the transport was created with
+// NewStdioPipe, and starts reading its messages.
+func (s *Stdio) Start(ctx context.Context) error {
+	if s.reader == nil {
+		cmd := exec.Command(s.command, s.args...)
+		cmd.Env = append(os.Environ(), s.env...)
+		cmd.Stderr = s.stderr
+
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return fmt.Errorf("failed to create stdin pipe: %w", err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("failed to create stdout pipe: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to start %s: %w", s.command, err)
+		}
+		s.cmd = cmd
+		s.reader = stdout
+		s.writer = stdin
+	}
+
+	go s.readLoop()
+	return nil
+}
+
+// Initialize sends the initialize request to the server with protocol version, client info, and capabilities.
+// Returns the server's result if successful.
+func (s *Stdio) Initialize(ctx context.Context, protocolVersion string, clientInfo map[string]interface{}, capabilities map[string]interface{}) (*mcp.InitializeResult, error) {
+	request := JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      mcp.NewIntRequestId(1),
+		Method:  initializeMethod,
+		Params: map[string]interface{}{
+			"protocolVersion": protocolVersion,
+			"clientInfo":      clientInfo,
+			"capabilities":    capabilities,
+		},
+	}
+
+	response, err := s.SendRequest(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", response.Error)
+	}
+
+	var result mcp.InitializeResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode initialize result: %w", err)
+	}
+	return &result, nil
+}
+
+// SendRequest sends a JSON-RPC request to the server and waits for the response with the same ID.
+func (s *Stdio) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+	responseChan := make(chan *JSONRPCResponse, 1)
+	s.pendingMu.Lock()
+	s.pending[request.ID] = responseChan
+	s.pendingMu.Unlock()
+	defer func() {
+		s.pendingMu.Lock()
+		delete(s.pending, request.ID)
+		s.pendingMu.Unlock()
+	}()
+
+	if err := s.write(request); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	select {
+	case response := <-responseChan:
+		return response, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.closed:
+		return nil, ErrTransportClosed
+	case <-s.done:
+		return nil, ErrTransportClosed
+	}
+}
+
+func (s *Stdio) SendNotification(ctx context.Context, notification JSONRPCNotification) error {
+	if err := s.write(notification); err != nil {
+		return fmt.Errorf("notification failed: %w", err)
+	}
+	return nil
+}
+
+// write sends a message to the server as a single line.
+func (s *Stdio) write(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	select {
+	case <-s.closed:
+		return ErrTransportClosed
+	case <-s.done:
+		return ErrTransportClosed
+	default:
+	}
+	if s.writer == nil {
+		return errors.New("transport not started")
+	}
+	_, err = s.writer.Write(append(data, '\n'))
+	return err
+}
+
+// readLoop dispatches the messages of the server until its output ends.
+func (s *Stdio) readLoop() {
+	defer close(s.done)
+
+	br := bufio.NewReader(s.reader)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			s.handleMessage(line)
+		}
+		if err != nil {
+			if err != io.EOF {
+				select {
+				case <-s.closed:
+				default:
+					fmt.Fprintf(os.Stderr, "stdio read error: %v\n", err)
+				}
+			}
+			return
+		}
+	}
+}
+
+// handleMessage routes a message of the server to the waiting request, the
+// request handler or the notification handler.
+func (s *Stdio) handleMessage(data []byte) {
+	var message JSONRPCResponse
+	if err := json.Unmarshal(data, &message); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to unmarshal message: %v\n", err)
+		return
+	}
+
+	// Handle server -> client request
+	if !message.ID.IsNil() && isRequest(data) {
+		var request JSONRPCRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to unmarshal request: %v\n", err)
+			return
+		}
+		// Answer on another goroutine so that slow handlers, such as
+		// sampling, do not hold up the responses they may depend on
+		go s.handleServerRequest(request)
+		return
+	}
+
+	// Handle notification
+	if message.ID.IsNil() {
+		var notification JSONRPCNotification
+		if err := json.Unmarshal(data, &notification); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to unmarshal notification: %v\n", err)
+			return
+		}
+		s.notifyMu.RLock()
+		if s.notificationHandler != nil {
+			s.notificationHandler(notification)
+		}
+		s.notifyMu.RUnlock()
+		return
+	}
+
+	s.pendingMu.Lock()
+	responseChan, ok := s.pending[message.ID]
+	s.pendingMu.Unlock()
+	if ok {
+		select {
+		case responseChan <- &message:
+		default:
+			// duplicate response
+		}
+	}
+}
+
+// handleServerRequest answers a request the server sent.
+func (s *Stdio) handleServerRequest(request JSONRPCRequest) {
+	s.requestMu.RLock()
+	handler := s.requestHandler
+	s.requestMu.RUnlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	response := answerServerRequest(ctx, request, s.pingHandler, handler)
+	if err := s.write(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to respond to %s request: %v\n", request.Method, err)
+	}
+}
+
+func (s *Stdio) SetNotificationHandler(handler func(JSONRPCNotification)) {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	s.notificationHandler = handler
+}
+
+func (s *Stdio) SetRequestHandler(handler RequestHandler) {
+	s.requestMu.Lock()
+	defer s.requestMu.Unlock()
+	s.requestHandler = handler
+}
+
+// Ping sends a ping request to the server and waits for a response.
+// It returns the measured round-trip time.
+func (s *Stdio) Ping(ctx context.Context) (time.Duration, error) {
+	request := JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      s.requestIDs(),
+		Method:  "ping",
+	}
+
+	start := time.Now()
+	resp, err := s.SendRequest(ctx, request)
+	latency := time.Since(start)
+	if err != nil {
+		return 0, fmt.Errorf("ping failed: %w", err)
+	}
+	if resp.Error != nil {
+		return 0, fmt.Errorf("ping failed: %w", resp.Error)
+	}
+	return latency, nil
+}
+
+// Close closes the server's stdin and waits for it to exit, killing it if it
+// does not within a short delay.
+func (s *Stdio) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		s.writeMu.Lock()
+		if s.writer != nil {
+			err = s.writer.Close()
+		}
+		s.writeMu.Unlock()
+
+		if s.cmd == nil {
+			return
+		}
+		exited := make(chan error, 1)
+		go func() { exited <- s.cmd.Wait() }()
+		select {
+		case <-exited:
+		case <-time.After(stdioCloseTimeout):
+			_ = s.cmd.Process.Kill()
+			<-exited
+		}
+	})
+	return err
+}
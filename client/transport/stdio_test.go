@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+// startStdioServer serves srv over a pair of pipes and returns a transport connected to it.
+func startStdioServer(t *testing.T, srv *mcptest.Server, options ...StdioOption) *Stdio {
+	t.Helper()
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	go func() {
+		_ = srv.ServeStdio(context.Background(), serverReader, serverWriter)
+		serverWriter.Close()
+	}()
+
+	trans := NewStdioPipe(clientReader, clientWriter, options...)
+	if err := trans.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(func() { trans.Close() })
+	return trans
+}
+
+func TestStdio(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("hello"), nil
+	})
+	srv.HandleMethod("work", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		if err := mcptest.SendRequest(ctx, mcp.NewStringRequestId("srv-1"), "ping", nil); err != nil {
+			return nil, err
+		}
+		if err := mcptest.SendNotification(ctx, "notifications/message", map[string]any{"data": "working"}); err != nil {
+			return nil, err
+		}
+		return map[string]any{}, nil
+	})
+
+	pinged := make(chan mcp.RequestId, 1)
+	trans := startStdioServer(t, srv, WithStdioPingHandler(func(id mcp.RequestId) { pinged <- id }))
+	notifications := make(chan JSONRPCNotification, 1)
+	trans.SetNotificationHandler(func(notification JSONRPCNotification) { notifications <- notification })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := trans.Initialize(ctx, mcp.LATEST_PROTOCOL_VERSION, map[string]any{"name": "test"}, map[string]any{})
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if result.Capabilities.Tools == nil {
+		t.Errorf("Expected the tools capability, got %+v", result.Capabilities)
+	}
+
+	t.Run("SendRequest", func(t *testing.T) {
+		response, err := trans.SendRequest(ctx, JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      mcp.NewIntRequestId(2),
+			Method:  "tools/call",
+			Params:  map[string]any{"name": "echo"},
+		})
+		if err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+		var result mcp.CallToolResult
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			t.Fatalf("Failed to decode result: %v", err)
+		}
+		if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "hello" {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("Ping", func(t *testing.T) {
+		if _, err := trans.Ping(ctx); err != nil {
+			t.Fatalf("Ping failed: %v", err)
+		}
+	})
+
+	t.Run("server messages", func(t *testing.T) {
+		if _, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewIntRequestId(3), Method: "work"}); err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+		select {
+		case id := <-pinged:
+			if id != mcp.NewStringRequestId("srv-1") {
+				t.Errorf("Unexpected ping ID %v", id)
+			}
+		case <-ctx.Done():
+			t.Fatalf("Expected the server ping to be observed")
+		}
+		select {
+		case notification := <-notifications:
+			if notification.Method != "notifications/message" {
+				t.Errorf("Unexpected notification %q", notification.Method)
+			}
+		case <-ctx.Done():
+			t.Fatalf("Expected a notification")
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		if err := trans.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if _, err := trans.Ping(ctx); !errors.Is(err, ErrTransportClosed) {
+			t.Errorf("Expected ErrTransportClosed, got %v", err)
+		}
+	})
+}
@@ -5,32 +5,138 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"math/rand"
+	"log/slog"
 	"mime"
+	"net"
 	"net/http"
 	"net/url"
-	"strings"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/oklog/ulid"
+	"github.com/contriboss/mcpgopher/mcp"
 )
 
 type StreamableHTTPCOption func(*StreamableHTTP)
 
+// DefaultMaxResponseSize is the size of the largest response body or SSE
+// event the transport accepts unless WithMaxResponseSize is given.
+const DefaultMaxResponseSize = 32 << 20
+
+// ResponseTooLargeError is returned for a response body or SSE event larger
+// than the maximum response size.
+type ResponseTooLargeError struct {
+	// Limit is the maximum response size in bytes
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response exceeds the maximum size of %d bytes", e.Limit)
+}
+
 func WithHTTPHeaders(headers map[string]string) StreamableHTTPCOption {
 	return func(sc *StreamableHTTP) {
 		sc.headers = headers
 	}
 }
 
-// WithHTTPTimeout sets the timeout for a HTTP request and stream.
+// WithHTTPTimeout sets the deadline of each request, see WithRequestTimeout.
+//
+// Deprecated: Use WithRequestTimeout. The timeout no longer applies to the
+// stream opened by WithListening.
 func WithHTTPTimeout(timeout time.Duration) StreamableHTTPCOption {
+	return WithRequestTimeout(timeout)
+}
+
+// WithRequestTimeout sets the deadline of each request, from sending it to
+// receiving its response, whether the response is JSON or streamed with SSE.
+func WithRequestTimeout(timeout time.Duration) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.requestTimeout = timeout
+	}
+}
+
+// WithLogger records to logger the errors the transport cannot return, such
+// as those of messages it fails to decode. They are discarded by default.
+func WithLogger(logger *slog.Logger) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.logger = logger
+	}
+}
+
+// WithHTTPClient sends the requests with client instead of a client of the
+// transport's own. WithConnectTimeout and WithResponseHeaderTimeout do not
+// apply to it.
+func WithHTTPClient(client *http.Client) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.httpClient = client
+	}
+}
+
+// WithConnectTimeout limits how long connecting to the server takes,
+// including the TLS handshake.
+func WithConnectTimeout(timeout time.Duration) StreamableHTTPCOption {
 	return func(sc *StreamableHTTP) {
-		sc.httpClient.Timeout = timeout
+		sc.httpTransport.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+		sc.httpTransport.TLSHandshakeTimeout = timeout
+	}
+}
+
+// WithResponseHeaderTimeout limits how long the server takes to send the
+// headers of a response once a request is sent.
+func WithResponseHeaderTimeout(timeout time.Duration) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.httpTransport.ResponseHeaderTimeout = timeout
+	}
+}
+
+// WithIdleStreamTimeout ends the SSE streams on which nothing is received for
+// timeout. A request whose response stream goes idle fails, and the stream
+// opened by WithListening is reopened. Servers keep quiet streams alive by
+// sending comments or pings more often than timeout.
+func WithIdleStreamTimeout(timeout time.Duration) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.idleStreamTimeout = timeout
+	}
+}
+
+// WithPingHandler sets a function called whenever the server pings the client.
+// The transport answers the ping itself; the handler only observes it.
+func WithPingHandler(handler func(id mcp.RequestId)) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.pingHandler = handler
+	}
+}
+
+// WithRequestIDs sets how the IDs of the requests sent by Request and Ping
+// are generated, ULIDRequestIDs by default.
+func WithRequestIDs(generate RequestIDGenerator) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.requestIDs = generate
+	}
+}
+
+// WithMaxResponseSize limits the size of JSON response bodies, and of the
+// events of SSE streams as sent, to size bytes, DefaultMaxResponseSize by default.
+// Larger responses fail with a *ResponseTooLargeError. A size of zero or less
+// removes the limit.
+func WithMaxResponseSize(size int64) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.maxResponseSize = size
+	}
+}
+
+// WithListening makes the transport open an SSE stream with an HTTP GET once
+// initialized, on which the server can send notifications and requests
+// outside of any client request. The stream is reopened when it ends, unless
+// the server answers 405 Method Not Allowed, meaning it does not offer one.
+func WithListening() StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.listen = true
 	}
 }
 
@@ -42,17 +148,29 @@ func WithHTTPTimeout(timeout time.Duration) StreamableHTTPCOption {
 //
 // http://spec.modelcontextprotocol.io/2025-03-26/base-protocol
 //
+// Listening for server messages when no request is in flight is opt-in, see WithListening.
+// That stream is resumed with Last-Event-ID when reopened.
+//
 // The current implementation does not support the following features:
 //   - batching
-//   - continuously listening for server notifications when no request is in flight
+//   - resuming the stream of a request that ends before its response
 //     (http://spec.modelcontextprotocol.io/2025-03-26/base-protocol#transport)
-//   - resuming stream
-//     (http://spec.modelcontextprotocol.io/2025-03-26/base-protocol#transport)
-//   - server -> client request
 type StreamableHTTP struct {
-	baseURL    *url.URL
-	httpClient *http.Client
-	headers    map[string]string
+	baseURL       *url.URL
+	httpClient    *http.Client
+	httpTransport *http.Transport
+	headers       map[string]string
+
+	requestTimeout    time.Duration
+	idleStreamTimeout time.Duration
+	maxResponseSize   int64
+
+	compressRequests bool
+	compressMinSize  int
+
+	// token replaces the Authorization header once set by onUnauthorized
+	onUnauthorized UnauthorizedHandler
+	token          atomic.Value
 
 	sessionID   atomic.Value
 	initialized atomic.Bool
@@ -60,6 +178,23 @@ type StreamableHTTP struct {
 	notificationHandler func(JSONRPCNotification)
 	notifyMu            sync.RWMutex
 
+	pingHandler func(id mcp.RequestId)
+	requestIDs  RequestIDGenerator
+
+	requestHandler RequestHandler
+	requestMu      sync.RWMutex
+
+	wireDump *wireDumper
+	logger   *slog.Logger
+
+	listen    bool
+	listening atomic.Bool
+
+	connState            atomic.Int32
+	connHandler          ConnectionStateHandler
+	connMu               sync.RWMutex
+	maxReconnectAttempts int
+
 	closed chan struct{}
 }
 
@@ -71,11 +206,16 @@ func NewStreamableHTTP(baseURL string, options ...StreamableHTTPCOption) (*Strea
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
 	smc := &StreamableHTTP{
-		baseURL:    parsedURL,
-		httpClient: &http.Client{},
-		headers:    make(map[string]string),
-		closed:     make(chan struct{}),
+		baseURL:         parsedURL,
+		httpClient:      &http.Client{Transport: httpTransport},
+		httpTransport:   httpTransport,
+		headers:         make(map[string]string),
+		requestIDs:      ULIDRequestIDs(),
+		maxResponseSize: DefaultMaxResponseSize,
+		logger:          slog.New(slog.DiscardHandler),
+		closed:          make(chan struct{}),
 	}
 	smc.sessionID.Store("") // set initial value to simplify later usage
 
@@ -93,11 +233,11 @@ func (c *StreamableHTTP) Start(ctx context.Context) error {
 }
 
 // Initialize sends the initialize request to the server with protocol version, client info, and capabilities.
-// Stores the session ID if successful.
-func (c *StreamableHTTP) Initialize(ctx context.Context, protocolVersion string, clientInfo map[string]interface{}, capabilities map[string]interface{}) error {
+// Stores the session ID and returns the server's result if successful.
+func (c *StreamableHTTP) Initialize(ctx context.Context, protocolVersion string, clientInfo map[string]interface{}, capabilities map[string]interface{}) (*mcp.InitializeResult, error) {
 	request := JSONRPCRequest{
 		JSONRPC: "2.0",
-		ID:      "1",
+		ID:      mcp.NewIntRequestId(1),
 		Method:  initializeMethod,
 		Params: map[string]interface{}{
 			"protocolVersion": protocolVersion,
@@ -106,16 +246,24 @@ func (c *StreamableHTTP) Initialize(ctx context.Context, protocolVersion string,
 		},
 	}
 
-	_, err := c.SendRequest(ctx, request)
+	response, err := c.SendRequest(ctx, request)
 	if err != nil {
-		return fmt.Errorf("failed to initialize: %w", err)
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", response.Error)
 	}
 
 	// Note: The sessionID is already stored in SendRequest when processing
 	// the HTTP headers for the initialize method
 
+	var result mcp.InitializeResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode initialize result: %w", err)
+	}
+
 	c.initialized.Store(true)
-	return nil
+	return &result, nil
 }
 
 // Close closes the all the HTTP connections to the server.
@@ -138,13 +286,14 @@ func (c *StreamableHTTP) Close() error {
 			defer cancel()
 			req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL.String(), nil)
 			if err != nil {
-				fmt.Printf("failed to create close request\n: %v", err)
+				c.logger.Error("failed to create close request", "error", err)
 				return
 			}
 			req.Header.Set(headerKeySessionID, sessionId)
-			res, err := c.httpClient.Do(req)
+			c.wireDump.request(req, nil)
+			res, err := c.do(req)
 			if err != nil {
-				fmt.Printf("failed to send close request\n: %v", err)
+				c.logger.Error("failed to send close request", "error", err)
 				return
 			}
 			res.Body.Close()
@@ -165,13 +314,21 @@ func (c *StreamableHTTP) SendRequest(
 	ctx context.Context,
 	request JSONRPCRequest,
 ) (*JSONRPCResponse, error) {
-	// Print debug info for ping requests
-	if request.Method == "ping" {
-		fmt.Printf("DEBUG SendRequest: Method=%s, ID=%s\n", request.Method, request.ID)
+	response, err := c.sendRequest(ctx, request)
+	if err == nil && response.Error == nil && request.Method == initializeMethod && c.listen {
+		if c.listening.CompareAndSwap(false, true) {
+			go c.listenLoop()
+		}
 	}
+	return response, err
+}
 
+func (c *StreamableHTTP) sendRequest(
+	ctx context.Context,
+	request JSONRPCRequest,
+) (*JSONRPCResponse, error) {
 	// Create a combined context that could be canceled when the client is closed
-	newCtx, cancel := context.WithCancel(ctx)
+	newCtx, cancel := c.requestContext(ctx)
 	defer cancel()
 	go func() {
 		select {
@@ -184,31 +341,34 @@ func (c *StreamableHTTP) SendRequest(
 	ctx = newCtx
 
 	// Marshal request
-	requestBody, err := json.Marshal(request)
+	requestBody, err := marshalBody(request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL.String(), bytes.NewReader(requestBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json, text/event-stream")
-	sessionID := c.sessionID.Load()
-	if sessionID != "" {
-		req.Header.Set(headerKeySessionID, sessionID.(string))
-	}
-	for k, v := range c.headers {
-		req.Header.Set(k, v)
-	}
+	defer requestBody.release()
 
 	// Send request
-	resp, err := c.httpClient.Do(req)
+	sessionID := c.sessionID.Load()
+	resp, err := c.doAuthorized(ctx, func() (*http.Request, error) {
+		req, err := c.newPost(ctx, requestBody)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			req.Header.Set(headerKeySessionID, sessionID.(string))
+		}
+		for k, v := range c.headers {
+			req.Header.Set(k, v)
+		}
+		c.wireDump.request(req, requestBody.Bytes())
+		return req, nil
+	})
 	if err != nil {
+		if ctx.Err() == nil {
+			c.setConnectionState(ConnectionLost, err)
+		}
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
@@ -217,19 +377,28 @@ func (c *StreamableHTTP) SendRequest(
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		// handle session closed
 		if resp.StatusCode == http.StatusNotFound {
+			c.wireDump.response(resp, nil)
+			if sessionID == "" {
+				return nil, fmt.Errorf("session terminated (404). need to re-initialize")
+			}
 			c.sessionID.CompareAndSwap(sessionID, "")
-			return nil, fmt.Errorf("session terminated (404). need to re-initialize")
+			err := fmt.Errorf("%w (404). need to re-initialize", ErrSessionTerminated)
+			c.setConnectionState(ConnectionLost, err)
+			return nil, err
 		}
 
 		// handle error response
 		var errResponse JSONRPCResponse
-		body, _ := io.ReadAll(resp.Body)
+		body, _ := c.readBody(resp.Body)
+		c.wireDump.response(resp, body)
 		if err := json.Unmarshal(body, &errResponse); err == nil {
 			return &errResponse, nil
 		}
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, body)
+		return nil, mcp.NewHTTPError(resp.StatusCode, body)
 	}
 
+	c.setConnectionState(ConnectionConnected, nil)
+
 	if request.Method == initializeMethod {
 		// saved the received session ID in the response
 		// empty session ID is allowed
@@ -243,20 +412,19 @@ func (c *StreamableHTTP) SendRequest(
 	switch mediaType {
 	case "application/json":
 		// Single response
-		body, _ := io.ReadAll(resp.Body)
-		
-		// Log the raw response for debugging if it's a ping
-		if request.Method == "ping" {
-			fmt.Printf("DEBUG Raw response: %s\n", string(body))
+		body, err := c.readBody(resp.Body)
+		c.wireDump.response(resp, body)
+		if err != nil {
+			return nil, err
 		}
-		
+
 		var response JSONRPCResponse
 		if err := json.Unmarshal(body, &response); err != nil {
 			return nil, fmt.Errorf("failed to decode response: %w\nRaw payload: %s", err, string(body))
 		}
 
 		// Special handling for ping requests - allow null ID
-		if response.ID == nil && request.Method != "ping" {
+		if response.ID.IsNil() && request.Method != "ping" {
 			return nil, fmt.Errorf("response should contain RPC id. Raw payload: %s", string(body))
 		}
 
@@ -264,18 +432,19 @@ func (c *StreamableHTTP) SendRequest(
 
 	case "text/event-stream":
 		// Server is using SSE for streaming responses
+		c.wireDump.response(resp, nil)
 		return c.handleSSEResponse(ctx, resp.Body)
 
 	default:
+		c.wireDump.response(resp, nil)
 		return nil, fmt.Errorf("unexpected content type: %s", resp.Header.Get("Content-Type"))
 	}
 }
 
 func (c *StreamableHTTP) Request(ctx context.Context, method string, params interface{}) (*JSONRPCResponse, error) {
-	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
 	request := JSONRPCRequest{
 		JSONRPC: "2.0",
-		ID:      ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String(),
+		ID:      c.requestIDs(),
 		Method:  method,
 		Params:  params,
 	}
@@ -283,47 +452,40 @@ func (c *StreamableHTTP) Request(ctx context.Context, method string, params inte
 	return c.SendRequest(ctx, request)
 }
 
+// requestContext returns the context of a request, which ends with the
+// request timeout, if set.
+func (c *StreamableHTTP) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.requestTimeout > 0 {
+		return context.WithTimeout(ctx, c.requestTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
 // handleSSEResponse processes an SSE stream for a specific request.
 // It returns the final result for the request once received, or an error.
 func (c *StreamableHTTP) handleSSEResponse(ctx context.Context, reader io.ReadCloser) (*JSONRPCResponse, error) {
 
 	// Create a channel for this specific request
 	responseChan := make(chan *JSONRPCResponse, 1)
+	requestHandler := requestNotificationHandler(ctx)
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	// Start a goroutine to process the SSE stream
+	var streamErr error
 	go func() {
 		// only close responseChan after readingSSE()
 		defer close(responseChan)
 
-		c.readSSE(ctx, reader, func(event, data string) {
-
-			// (unsupported: batching)
-
-			var message JSONRPCResponse
-			if err := json.Unmarshal([]byte(data), &message); err != nil {
-				fmt.Printf("failed to unmarshal message: %v\n", err)
+		streamErr = c.readSSE(ctx, reader, nil, func(event, data string) {
+			c.wireDump.event(event, data)
+			if event != "message" {
 				return
 			}
-
-			// Handle notification
-			if message.ID == nil {
-				var notification JSONRPCNotification
-				if err := json.Unmarshal([]byte(data), &notification); err != nil {
-					fmt.Printf("failed to unmarshal notification: %v\n", err)
-					return
-				}
-				c.notifyMu.RLock()
-				if c.notificationHandler != nil {
-					c.notificationHandler(notification)
-				}
-				c.notifyMu.RUnlock()
-				return
+			if response := c.handleSSEMessage(ctx, data, requestHandler); response != nil {
+				responseChan <- response
 			}
-
-			responseChan <- &message
 		})
 	}()
 
@@ -331,6 +493,9 @@ func (c *StreamableHTTP) handleSSEResponse(ctx context.Context, reader io.ReadCl
 	select {
 	case response := <-responseChan:
 		if response == nil {
+			if streamErr != nil {
+				return nil, streamErr
+			}
 			return nil, fmt.Errorf("unexpected nil response")
 		}
 		return response, nil
@@ -339,97 +504,427 @@ func (c *StreamableHTTP) handleSSEResponse(ctx context.Context, reader io.ReadCl
 	}
 }
 
-// readSSE reads the SSE stream(reader) and calls the handler for each event and data pair.
-// It will end when the reader is closed (or the context is done).
-func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, handler func(event, data string)) {
-	defer reader.Close()
+// handleSSEMessage dispatches a message received on an SSE stream: server
+// requests are answered, notifications passed to requestHandler, if set, and
+// to the notification handler. Responses are returned.
+func (c *StreamableHTTP) handleSSEMessage(ctx context.Context, data string, requestHandler func(JSONRPCNotification)) *JSONRPCResponse {
+	// (unsupported: batching)
 
-	br := bufio.NewReader(reader)
-	var event, data string
+	var message JSONRPCResponse
+	if err := json.Unmarshal([]byte(data), &message); err != nil {
+		c.logger.Error("failed to unmarshal message", "error", err)
+		return nil
+	}
+
+	// Handle server -> client request
+	if !message.ID.IsNil() && isRequest([]byte(data)) {
+		var request JSONRPCRequest
+		if err := json.Unmarshal([]byte(data), &request); err != nil {
+			c.logger.Error("failed to unmarshal request", "error", err)
+			return nil
+		}
+		c.handleServerRequest(ctx, request)
+		return nil
+	}
+
+	// Handle notification
+	if message.ID.IsNil() {
+		var notification JSONRPCNotification
+		if err := json.Unmarshal([]byte(data), &notification); err != nil {
+			c.logger.Error("failed to unmarshal notification", "error", err)
+			return nil
+		}
+		if requestHandler != nil {
+			requestHandler(notification)
+		}
+		c.notifyMu.RLock()
+		if c.notificationHandler != nil {
+			c.notificationHandler(notification)
+		}
+		c.notifyMu.RUnlock()
+		return nil
+	}
 
+	return &message
+}
+
+// listenRetryDelay is how long listenLoop waits before reopening a stream
+// that failed or ended.
+var listenRetryDelay = time.Second
+
+// listenLoop keeps a GET stream open until the transport is closed or the
+// server turns out not to offer one.
+func (c *StreamableHTTP) listenLoop() {
+	defer c.listening.Store(false)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.closed
+		cancel()
+	}()
+
+	// The stream resumes after the last event received, on servers
+	// supporting it, and is reopened after the delay they ask for
+	state := &sseState{}
+	failures := 0
 	for {
+		reopen, opened := c.listenOnce(ctx, state)
+		if !reopen {
+			return
+		}
+		if opened {
+			failures = 0
+		} else {
+			failures++
+		}
+		if c.maxReconnectAttempts > 0 && failures > c.maxReconnectAttempts {
+			c.setConnectionState(ConnectionFailed, fmt.Errorf("gave up reopening the stream after %d attempts", c.maxReconnectAttempts))
+			return
+		}
+		c.setConnectionState(ConnectionReconnecting, nil)
+		delay := listenRetryDelay
+		if state.retry > 0 {
+			delay = state.retry
+		}
 		select {
+		case <-time.After(delay):
 		case <-ctx.Done():
 			return
+		}
+	}
+}
+
+// listenOnce opens a GET stream and reads it until it ends. It reports
+// whether the stream should be reopened, and whether it was opened.
+func (c *StreamableHTTP) listenOnce(ctx context.Context, state *sseState) (reopen, opened bool) {
+	sessionID := c.sessionID.Load().(string)
+	resp, err := c.doAuthorized(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		if sessionID != "" {
+			req.Header.Set(headerKeySessionID, sessionID)
+		}
+		if state.lastEventID != "" {
+			req.Header.Set("Last-Event-ID", state.lastEventID)
+		}
+		for k, v := range c.headers {
+			req.Header.Set(k, v)
+		}
+		c.wireDump.request(req, nil)
+		return req, nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, false
+		}
+		c.setConnectionState(ConnectionLost, err)
+		return true, false
+	}
+	c.wireDump.response(resp, nil)
+	switch {
+	case resp.StatusCode == http.StatusMethodNotAllowed:
+		resp.Body.Close()
+		return false, false
+	case resp.StatusCode == http.StatusNotFound:
+		// The session is gone; requests will report it
+		resp.Body.Close()
+		c.sessionID.CompareAndSwap(sessionID, "")
+		c.setConnectionState(ConnectionLost, fmt.Errorf("%w (404)", ErrSessionTerminated))
+		return false, false
+	case resp.StatusCode != http.StatusOK:
+		resp.Body.Close()
+		c.setConnectionState(ConnectionLost, mcp.NewHTTPError(resp.StatusCode, nil))
+		return true, false
+	}
+
+	c.setConnectionState(ConnectionConnected, nil)
+	err = c.readSSE(ctx, resp.Body, state, func(event, data string) {
+		c.wireDump.event(event, data)
+		if event != "message" {
+			return
+		}
+		// Responses are not expected on this stream
+		c.handleSSEMessage(ctx, data, nil)
+	})
+	if ctx.Err() != nil {
+		return false, true
+	}
+	if err == nil {
+		err = errStreamEnded
+	}
+	c.setConnectionState(ConnectionLost, err)
+	return true, true
+}
+
+// errStreamEnded is the cause of a lost connection when the server ended the
+// listening stream.
+var errStreamEnded = errors.New("SSE stream ended")
+
+// errStreamIdle ends the SSE streams idle for longer than the idle stream
+// timeout.
+var errStreamIdle = errors.New("SSE stream idle")
+
+// sseState is the state of an SSE stream kept across reconnections.
+type sseState struct {
+	// lastEventID is the ID of the last event received, or set by an id
+	// field alone
+	lastEventID string
+	// retry is the reconnection delay requested by the server, zero if none
+	retry time.Duration
+}
+
+// readSSE reads the SSE stream(reader) and calls the handler for each event,
+// following https://html.spec.whatwg.org/multipage/server-sent-events.html:
+// the data lines of an event are joined with line breaks, comment lines are
+// ignored, and events without an event field are "message" events. The id and
+// retry fields are recorded in state, which may be nil. An event cut short by
+// the end of the stream is dropped.
+// It will end when the reader is closed (or the context is done), or when
+// nothing is read for the idle stream timeout, returning errStreamIdle.
+func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, state *sseState, handler func(event, data string)) error {
+	defer reader.Close()
+	if state == nil {
+		state = &sseState{}
+	}
+
+	var idle atomic.Bool
+	if c.idleStreamTimeout > 0 {
+		// Closing the body unblocks the pending read
+		body := reader
+		timer := time.AfterFunc(c.idleStreamTimeout, func() {
+			idle.Store(true)
+			body.Close()
+		})
+		defer timer.Stop()
+		reader = readNotifier{reader, func() { timer.Reset(c.idleStreamTimeout) }}
+	}
+
+	// The buffers are pooled, as agents open a stream for many requests
+	br := getReader(reader)
+	defer putReader(br)
+	data, long := getBuffer(), getBuffer()
+	defer putBuffer(data)
+	defer putBuffer(long)
+	var event string
+	var hasData bool
+	// eventSize counts the bytes of the event being read
+	var eventSize int
+	// afterCR is set when the last line ended with CR, maybe the start of CRLF
+	var afterCR bool
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
 		default:
-			line, err := br.ReadString('\n')
-			if err != nil {
-				if err == io.EOF {
-					// Process any pending event before exit
-					if event != "" && data != "" {
-						handler(event, data)
-					}
-					return
-				}
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					fmt.Printf("SSE stream error: %v\n", err)
-					return
-				}
+		}
+		line, err := c.readLine(br, long, eventSize, &afterCR)
+		if err != nil {
+			var tooLarge *ResponseTooLargeError
+			if errors.As(err, &tooLarge) {
+				return err
+			}
+			if idle.Load() {
+				return fmt.Errorf("%w for %v", errStreamIdle, c.idleStreamTimeout)
+			}
+			if err == io.EOF {
+				// A line without its end belongs to an event cut short
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+				c.logger.Error("SSE stream error", "error", err)
+				return err
 			}
+		}
 
-			// Remove only newline markers
-			line = strings.TrimRight(line, "\r\n")
-			if line == "" {
-				// Empty line means end of event
-				if event != "" && data != "" {
-					handler(event, data)
-					event = ""
-					data = ""
+		eventSize += len(line) + 1
+		if len(line) == 0 {
+			// Empty line means end of event
+			if hasData {
+				if event == "" {
+					event = "message"
 				}
-				continue
+				handler(event, data.String())
 			}
+			event = ""
+			data.Reset()
+			hasData = false
+			eventSize = 0
+			continue
+		}
+		if line[0] == ':' {
+			continue
+		}
 
-			if strings.HasPrefix(line, "event:") {
-				event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
-			} else if strings.HasPrefix(line, "data:") {
-				data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "event":
+			event = string(value)
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.Write(value)
+			hasData = true
+		case "id":
+			if bytes.IndexByte(value, 0) < 0 && string(value) != state.lastEventID {
+				state.lastEventID = string(value)
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(string(value), 10, 32); err == nil && len(bytes.Trim(value, "0123456789")) == 0 {
+				state.retry = time.Duration(ms) * time.Millisecond
 			}
 		}
 	}
 }
 
-func (c *StreamableHTTP) SendNotification(ctx context.Context, notification JSONRPCNotification) error {
-
-	// Marshal request
-	requestBody, err := json.Marshal(notification)
+// readBody reads a response body, returning a *ResponseTooLargeError with
+// the first bytes if it is larger than the maximum response size.
+func (c *StreamableHTTP) readBody(body io.Reader) ([]byte, error) {
+	if c.maxResponseSize <= 0 {
+		return io.ReadAll(body)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, c.maxResponseSize+1))
 	if err != nil {
-		return fmt.Errorf("failed to marshal notification: %w", err)
+		return data, err
+	}
+	if int64(len(data)) > c.maxResponseSize {
+		return data[:c.maxResponseSize], &ResponseTooLargeError{Limit: c.maxResponseSize}
 	}
+	return data, nil
+}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL.String(), bytes.NewReader(requestBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// readLine reads a line of an SSE stream without its end, CRLF, LF or CR,
+// returning a *ResponseTooLargeError if it would make an event of pending
+// bytes exceed the maximum response size. afterCR tracks the lines ending
+// with CR, so that the LF of a CRLF is skipped without waiting for it. The
+// line is gathered in long and valid until the next call. At the end of the
+// stream, the line read so far is returned with the error.
+func (c *StreamableHTTP) readLine(br *bufio.Reader, long *bytes.Buffer, pending int, afterCR *bool) ([]byte, error) {
+	if *afterCR {
+		*afterCR = false
+		if next, err := br.Peek(1); err == nil && next[0] == '\n' {
+			br.Discard(1)
+		}
+	}
+	long.Reset()
+	for {
+		if _, err := br.Peek(1); err != nil {
+			return long.Bytes(), err
+		}
+		buffered, _ := br.Peek(br.Buffered())
+		end := bytes.IndexAny(buffered, "\r\n")
+		n := end
+		if end < 0 {
+			n = len(buffered)
+		}
+		if c.maxResponseSize > 0 && int64(pending+long.Len()+n) > c.maxResponseSize {
+			return nil, &ResponseTooLargeError{Limit: c.maxResponseSize}
+		}
+		long.Write(buffered[:n])
+		if end >= 0 {
+			*afterCR = buffered[end] == '\r'
+			br.Discard(n + 1)
+			return long.Bytes(), nil
+		}
+		br.Discard(n)
+	}
+}
+
+// readNotifier calls read after every read of at least one byte.
+type readNotifier struct {
+	io.ReadCloser
+	read func()
+}
+
+func (r readNotifier) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.read()
+	}
+	return n, err
+}
+
+// isRequest reports whether a JSON-RPC message carries a method, which
+// distinguishes requests from responses.
+func isRequest(data []byte) bool {
+	var probe struct {
+		Method string `json:"method"`
 	}
+	return json.Unmarshal(data, &probe) == nil && probe.Method != ""
+}
+
+// handleServerRequest answers a request the server sent on a stream.
+func (c *StreamableHTTP) handleServerRequest(ctx context.Context, request JSONRPCRequest) {
+	c.requestMu.RLock()
+	handler := c.requestHandler
+	c.requestMu.RUnlock()
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json, text/event-stream")
-	if sessionID := c.sessionID.Load(); sessionID != "" {
-		req.Header.Set(headerKeySessionID, sessionID.(string))
+	response := answerServerRequest(ctx, request, c.pingHandler, handler)
+	if err := c.post(ctx, response); err != nil {
+		c.logger.Error("failed to respond to server request", "method", request.Method, "error", err)
 	}
-	for k, v := range c.headers {
-		req.Header.Set(k, v)
+}
+
+func (c *StreamableHTTP) SendNotification(ctx context.Context, notification JSONRPCNotification) error {
+	if err := c.post(ctx, notification); err != nil {
+		return fmt.Errorf("notification failed: %w", err)
 	}
+	return nil
+}
+
+// post sends a message that expects no JSON-RPC response, such as a
+// notification or a response to a server request.
+func (c *StreamableHTTP) post(ctx context.Context, message any) error {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	// Marshal message
+	requestBody, err := marshalBody(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	defer requestBody.release()
 
 	// Send request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doAuthorized(ctx, func() (*http.Request, error) {
+		req, err := c.newPost(ctx, requestBody)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID := c.sessionID.Load(); sessionID != "" {
+			req.Header.Set(headerKeySessionID, sessionID.(string))
+		}
+		for k, v := range c.headers {
+			req.Header.Set(k, v)
+		}
+		c.wireDump.request(req, requestBody.Bytes())
+		return req, nil
+	})
 	if err != nil {
+		if ctx.Err() == nil {
+			c.setConnectionState(ConnectionLost, err)
+		}
 		return fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf(
-			"notification failed with status %d: %s",
-			resp.StatusCode,
-			body,
-		)
+		body, _ := c.readBody(resp.Body)
+		c.wireDump.response(resp, body)
+		return mcp.NewHTTPError(resp.StatusCode, body)
 	}
+	c.wireDump.response(resp, nil)
+	c.setConnectionState(ConnectionConnected, nil)
 
 	return nil
 }
@@ -440,44 +935,33 @@ func (c *StreamableHTTP) SetNotificationHandler(handler func(JSONRPCNotification
 	c.notificationHandler = handler
 }
 
+func (c *StreamableHTTP) SetRequestHandler(handler RequestHandler) {
+	c.requestMu.Lock()
+	defer c.requestMu.Unlock()
+	c.requestHandler = handler
+}
+
 func (c *StreamableHTTP) GetSessionId() string {
 	return c.sessionID.Load().(string)
 }
 
 // Ping sends a ping request to the server and waits for a response.
-// This can be used to check if the server is still alive and measure latency.
-func (c *StreamableHTTP) Ping(ctx context.Context) error {
-	// For ping request
-	pingParams := map[string]interface{}{
-		"timestamp": time.Now().UnixNano(),
-	}
-	
-	// Create request ID for ping
-	requestID := fmt.Sprintf("ping-%d", time.Now().UnixNano())
-	fmt.Printf("DEBUG: Using request ID: %s\n", requestID)
-	
-	// Try using SendRequest instead of direct HTTP request
+// It returns the measured round-trip time.
+func (c *StreamableHTTP) Ping(ctx context.Context) (time.Duration, error) {
 	request := JSONRPCRequest{
 		JSONRPC: "2.0",
-		ID:      requestID,
+		ID:      c.requestIDs(),
 		Method:  "ping",
-		Params:  pingParams,
-	}
-	
-	// Marshal request for logging
-	requestBody, _ := json.Marshal(request)
-	fmt.Printf("DEBUG: Sending ping request: %s\n", string(requestBody))
-	
-	// Send the ping request
+	}
+
+	start := time.Now()
 	resp, err := c.SendRequest(ctx, request)
+	latency := time.Since(start)
 	if err != nil {
-		fmt.Printf("DEBUG: Ping error: %v\n", err)
-		return fmt.Errorf("ping failed: %w", err)
-	}
-	
-	// Log response
-	respJSON, _ := json.Marshal(resp)
-	fmt.Printf("DEBUG: Ping response: %s\n", string(respJSON))
-	
-	return nil
+		return 0, fmt.Errorf("ping failed: %w", err)
+	}
+	if resp.Error != nil {
+		return 0, fmt.Errorf("ping failed: %w", resp.Error)
+	}
+	return latency, nil
 }
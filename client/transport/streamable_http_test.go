@@ -1,105 +1,39 @@
 package transport
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
 )
 
-// startMockStreamableHTTPServer starts a test HTTP server that implements
-// a minimal Streamable HTTP server for testing purposes.
+// startMockStreamableHTTPServer starts an mcptest server whose ping method
+// echoes the request back and whose ping_error method fails with the request as message.
 // It returns the server URL and a function to close the server.
 func startMockStreamableHTTPServer() (string, func()) {
-	var sessionID string
-	var mu sync.Mutex
-
-	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Handle only POST requests
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		// Set content type for all responses
-		w.Header().Set("Content-Type", "application/json")
-
-		// Parse incoming JSON-RPC request
-		var request map[string]any
-		decoder := json.NewDecoder(r.Body)
-		if err := decoder.Decode(&request); err != nil {
-			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
-			return
-		}
-
-		method := request["method"]
-		switch method {
-		case "initialize":
-			// Generate a new session ID
-			mu.Lock()
-			sessionID = fmt.Sprintf("test-session-%d", time.Now().UnixNano())
-			mu.Unlock()
-			w.Header().Set("Mcp-Session-Id", sessionID)
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusAccepted)
-			if err := json.NewEncoder(w).Encode(map[string]any{
-				"jsonrpc": "2.0",
-				"id":      request["id"],
-				"result":  "initialized",
-			}); err != nil {
-				http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-				return
-			}
-
-		case "ping":
-			// Check session ID
-			if r.Header.Get("Mcp-Session-Id") != sessionID {
-				http.Error(w, "Invalid session ID", http.StatusNotFound)
-				return
-			}
-			// Respond to ping with echo of params
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			if err := json.NewEncoder(w).Encode(map[string]any{
-				"jsonrpc": "2.0",
-				"id":      request["id"],
-				"result":  request,
-			}); err != nil {
-				http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-				return
-			}
-		case "ping_error":
-			// Check session ID
-			if r.Header.Get("Mcp-Session-Id") != sessionID {
-				http.Error(w, "Invalid session ID", http.StatusNotFound)
-				return
-			}
-			// Return an error response
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			data, _ := json.Marshal(request)
-			if err := json.NewEncoder(w).Encode(map[string]any{
-				"jsonrpc": "2.0",
-				"id":      request["id"],
-				"error": map[string]any{
-					"code":    -1,
-					"message": string(data),
-				},
-			}); err != nil {
-				http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-				return
-			}
-		}
+	srv := mcptest.NewServer()
+	srv.HandleMethod("ping", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		return request, nil
 	})
-
-	// Start test server
-	testServer := httptest.NewServer(handler)
-	return testServer.URL, testServer.Close
+	srv.HandleMethod("ping_error", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		data, _ := json.Marshal(request)
+		return nil, &mcp.Error{Code: -1, Message: string(data)}
+	})
+	srv.Start()
+	return srv.URL, srv.Close
 }
 
 func TestStreamableHTTP(t *testing.T) {
@@ -120,7 +54,7 @@ func TestStreamableHTTP(t *testing.T) {
 
 	initRequest := JSONRPCRequest{
 		JSONRPC: "2.0",
-		ID:      "1",
+		ID:      mcp.NewStringRequestId("1"),
 		Method:  "initialize",
 	}
 
@@ -130,13 +64,41 @@ func TestStreamableHTTP(t *testing.T) {
 	}
 
 	// Now run the tests
+	t.Run("RequestIdTypes", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		for _, id := range []mcp.RequestId{mcp.NewIntRequestId(7), mcp.NewStringRequestId("7")} {
+			response, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: "ping"})
+			if err != nil {
+				t.Fatalf("SendRequest failed: %v", err)
+			}
+			if response.ID != id {
+				t.Errorf("Expected response ID %#v, got %#v", id, response.ID)
+			}
+		}
+	})
+
+	t.Run("Ping", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		latency, err := trans.Ping(ctx)
+		if err != nil {
+			t.Fatalf("Ping failed: %v", err)
+		}
+		if latency <= 0 {
+			t.Errorf("Expected a positive latency, got %s", latency)
+		}
+	})
+
 	t.Run("SendRequest", func(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
 		request := JSONRPCRequest{
 			JSONRPC: "2.0",
-			ID:      "1",
+			ID:      mcp.NewStringRequestId("1"),
 			Method:  "ping",
 			Params: map[string]any{
 				"string": "hello world",
@@ -190,7 +152,7 @@ func TestStreamableHTTP(t *testing.T) {
 		// Prepare a request
 		request := JSONRPCRequest{
 			JSONRPC: "2.0",
-			ID:      "2",
+			ID:      mcp.NewStringRequestId("2"),
 			Method:  "ping",
 			Params:  map[string]any{"string": "timeout"},
 		}
@@ -294,7 +256,7 @@ func TestStreamableHTTP(t *testing.T) {
 				// Each request has a unique ID and payload
 				request := JSONRPCRequest{
 					JSONRPC: "2.0",
-					ID:      fmt.Sprintf("%d", 100+idx),
+					ID:      mcp.NewStringRequestId(fmt.Sprintf("%d", 100+idx)),
 					Method:  "ping",
 					Params:  map[string]any{"requestIndex": idx},
 				}
@@ -351,7 +313,7 @@ func TestStreamableHTTP(t *testing.T) {
 		defer cancel()
 
 		// Prepare a request
-		requestID := "999"
+		requestID := mcp.NewStringRequestId("999")
 		request := JSONRPCRequest{
 			JSONRPC: "2.0",
 			ID:      requestID,
@@ -405,7 +367,7 @@ func TestStreamableHTTPErrors(t *testing.T) {
 
 		request := JSONRPCRequest{
 			JSONRPC: "2.0",
-			ID:      "1",
+			ID:      mcp.NewStringRequestId("1"),
 			Method:  "initialize",
 		}
 
@@ -415,3 +377,539 @@ func TestStreamableHTTPErrors(t *testing.T) {
 		}
 	})
 }
+
+func TestStreamableHTTPWireDump(t *testing.T) {
+	url, closeF := startMockStreamableHTTPServer()
+	defer closeF()
+
+	var dump bytes.Buffer
+	trans, err := NewStreamableHTTP(url,
+		WithHTTPHeaders(map[string]string{"Authorization": "Bearer secret"}),
+		WithWireDump(&dump),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewStringRequestId("1"), Method: "initialize"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := dump.String()
+	if !strings.Contains(out, "--> POST "+url) {
+		t.Errorf("Expected outgoing request in dump, got:\n%s", out)
+	}
+	if !strings.Contains(out, "<-- 200 OK") {
+		t.Errorf("Expected incoming response in dump, got:\n%s", out)
+	}
+	if !strings.Contains(out, `"method":"initialize"`) {
+		t.Errorf("Expected request body in dump, got:\n%s", out)
+	}
+	if strings.Contains(out, "secret") || !strings.Contains(out, "Authorization: [REDACTED]") {
+		t.Errorf("Expected Authorization header to be redacted, got:\n%s", out)
+	}
+}
+
+func FuzzReadSSE(f *testing.F) {
+	f.Add("message", `{"jsonrpc":"2.0","id":1,"result":{}}`)
+	f.Add("message", " leading space")
+	f.Add("ping", "x")
+
+	f.Fuzz(func(t *testing.T, event, data string) {
+		if strings.ContainsAny(event+data, "\r\n") || strings.TrimSpace(event) == "" || strings.TrimSpace(data) == "" {
+			t.Skip()
+		}
+
+		// A single well-formed event must come back exactly as it was sent
+		stream := fmt.Sprintf("event: %s\ndata: %s\n\n", event, data)
+		var gotEvent, gotData []string
+		c := &StreamableHTTP{}
+		c.readSSE(context.Background(), io.NopCloser(strings.NewReader(stream)), nil, func(e, d string) {
+			gotEvent = append(gotEvent, e)
+			gotData = append(gotData, d)
+		})
+		if len(gotEvent) != 1 || gotEvent[0] != event || gotData[0] != data {
+			t.Fatalf("Parsing %q gave events %q with data %q", stream, gotEvent, gotData)
+		}
+	})
+}
+
+func FuzzReadSSEStream(f *testing.F) {
+	f.Add([]byte("event: message\ndata: {}\n\n"))
+	f.Add([]byte("data: a\ndata: b\n\n: comment\nid: 1\nretry: 10\n\n"))
+	f.Add([]byte("event:\r\ndata:x\r\n\r\n"))
+
+	f.Fuzz(func(t *testing.T, stream []byte) {
+		c := &StreamableHTTP{}
+		c.readSSE(context.Background(), io.NopCloser(bytes.NewReader(stream)), nil, func(event, data string) {
+			// Data lines are joined with LF, whatever the line endings
+			if strings.Contains(data, "\r") || strings.Contains(event, "\n") {
+				t.Fatalf("Event %q has a line break in data %q", event, data)
+			}
+		})
+	})
+}
+
+func TestStreamableHTTPServerPing(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.HandleMethod("work", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		if err := mcptest.SendRequest(ctx, mcp.NewStringRequestId("srv-1"), "ping", nil); err != nil {
+			return nil, err
+		}
+		if err := mcptest.SendRequest(ctx, mcp.NewIntRequestId(2), "roots/list", nil); err != nil {
+			return nil, err
+		}
+		return map[string]any{}, nil
+	})
+	srv.SetBehavior("work", mcptest.Behavior{SSE: true})
+	srv.Start()
+	defer srv.Close()
+
+	var pings []mcp.RequestId
+	trans, err := NewStreamableHTTP(srv.URL, WithPingHandler(func(id mcp.RequestId) {
+		pings = append(pings, id)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewIntRequestId(1), Method: "initialize"}); err != nil {
+		t.Fatal(err)
+	}
+	response, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewIntRequestId(2), Method: "work"})
+	if err != nil {
+		t.Fatalf("SendRequest failed: %v", err)
+	}
+	if response.ID != mcp.NewIntRequestId(2) || response.Error != nil {
+		t.Errorf("Expected the final response to the request, got %+v", response)
+	}
+
+	if len(pings) != 1 || pings[0] != mcp.NewStringRequestId("srv-1") {
+		t.Errorf("Expected the ping to be observed, got %v", pings)
+	}
+
+	var answers []mcptest.Request
+	for _, request := range srv.Requests() {
+		if request.IsResponse() {
+			answers = append(answers, request)
+		}
+	}
+	if len(answers) != 2 {
+		t.Fatalf("Expected 2 answers to server requests, got %d", len(answers))
+	}
+	if answers[0].ID != mcp.NewStringRequestId("srv-1") || string(answers[0].Result) != "{}" {
+		t.Errorf("Expected an empty ping result, got %+v", answers[0])
+	}
+	if answers[1].Error == nil || answers[1].Error.Code != mcp.ErrorMethodNotFound {
+		t.Errorf("Expected unsupported requests to get MethodNotFound, got %+v", answers[1])
+	}
+}
+
+func TestStreamableHTTPListening(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.Start()
+	defer srv.Close()
+
+	trans, err := NewStreamableHTTP(srv.URL, WithListening())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+	received := make(chan JSONRPCNotification, 1)
+	trans.SetNotificationHandler(func(notification JSONRPCNotification) {
+		received <- notification
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := trans.Initialize(ctx, mcp.LATEST_PROTOCOL_VERSION, nil, nil); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	// The stream opens in the background
+	for srv.Notify("notifications/message", map[string]any{"data": "idle"}) == 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("Timed out waiting for the GET stream")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	select {
+	case notification := <-received:
+		if notification.Method != "notifications/message" || notification.Params.AdditionalFields["data"] != "idle" {
+			t.Errorf("Unexpected notification: %+v", notification)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for the notification")
+	}
+
+	t.Run("NotOffered", func(t *testing.T) {
+		var gets atomic.Int32
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				gets.Add(1)
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			srv.Config.Handler.ServeHTTP(w, r)
+		}))
+		defer proxy.Close()
+
+		retryDelay := listenRetryDelay
+		listenRetryDelay = time.Millisecond
+		defer func() { listenRetryDelay = retryDelay }()
+
+		trans, err := NewStreamableHTTP(proxy.URL, WithListening())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		if _, err := trans.Initialize(ctx, mcp.LATEST_PROTOCOL_VERSION, nil, nil); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+		if got := gets.Load(); got != 1 {
+			t.Errorf("Expected a single GET attempt, got %d", got)
+		}
+	})
+}
+
+func TestStreamableHTTPTimeouts(t *testing.T) {
+	// The server opens an SSE stream for every request and never answers
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("slow") {
+			select {
+			case <-time.After(time.Second):
+			case <-block:
+			}
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-block:
+		}
+	}))
+	defer srv.Close()
+	defer close(block)
+	ctx := context.Background()
+
+	t.Run("RequestTimeout", func(t *testing.T) {
+		trans, err := NewStreamableHTTP(srv.URL, WithRequestTimeout(50*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		start := time.Now()
+		_, err = trans.Request(ctx, "wait", nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected the request to end after its timeout, took %v", elapsed)
+		}
+	})
+
+	t.Run("IdleStreamTimeout", func(t *testing.T) {
+		trans, err := NewStreamableHTTP(srv.URL, WithIdleStreamTimeout(50*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		_, err = trans.Request(ctx, "wait", nil)
+		if !errors.Is(err, errStreamIdle) {
+			t.Errorf("Expected errStreamIdle, got %v", err)
+		}
+	})
+
+	t.Run("ResponseHeaderTimeout", func(t *testing.T) {
+		trans, err := NewStreamableHTTP(srv.URL+"?slow", WithResponseHeaderTimeout(50*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		start := time.Now()
+		if _, err = trans.Request(ctx, "wait", nil); err == nil || !strings.Contains(err.Error(), "timeout awaiting response headers") {
+			t.Errorf("Expected a response header timeout, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("Expected the request to end after its timeout, took %v", elapsed)
+		}
+	})
+
+	t.Run("ListeningOutlivesRequestTimeout", func(t *testing.T) {
+		srv := mcptest.NewServer()
+		srv.Start()
+		defer srv.Close()
+
+		trans, err := NewStreamableHTTP(srv.URL, WithListening(), WithRequestTimeout(50*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		received := make(chan JSONRPCNotification, 1)
+		trans.SetNotificationHandler(func(notification JSONRPCNotification) {
+			received <- notification
+		})
+		if _, err := trans.Initialize(ctx, mcp.LATEST_PROTOCOL_VERSION, nil, nil); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+
+		// Wait for the stream to open, then past the request timeout
+		for srv.Notify("notifications/message", map[string]any{"data": "open"}) == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		<-received
+		time.Sleep(100 * time.Millisecond)
+		if n := srv.Notify("notifications/message", map[string]any{"data": "later"}); n == 0 {
+			t.Fatal("Expected the GET stream to stay open")
+		}
+		select {
+		case notification := <-received:
+			if notification.Params.AdditionalFields["data"] != "later" {
+				t.Errorf("Unexpected notification: %+v", notification)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for the notification")
+		}
+	})
+}
+
+func TestReadSSE(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		events []string
+		state  sseState
+	}{
+		{"Event", "event: message\ndata: {}\n\n", []string{"message {}"}, sseState{}},
+		{"DefaultEvent", "data: {}\n\n", []string{"message {}"}, sseState{}},
+		{"MultiLineData", "data: a\ndata:b\ndata\n\n", []string{"message a\nb\n"}, sseState{}},
+		{"Comments", ": keep-alive\n\ndata: x\n: between\n\n", []string{"message x"}, sseState{}},
+		{"NoData", "event: ping\n\nid: 1\n\n", nil, sseState{lastEventID: "1"}},
+		{"IDAndRetry", "id: 42\nretry: 250\ndata: x\n\n", []string{"message x"}, sseState{lastEventID: "42", retry: 250 * time.Millisecond}},
+		{"InvalidRetry", "retry: 1s\ndata: x\n\n", []string{"message x"}, sseState{}},
+		{"CRLF", "event: other\r\ndata: x\r\n\r\n", []string{"other x"}, sseState{}},
+		{"CR", "data: a\rdata: b\r\r\n", []string{"message a\nb"}, sseState{}},
+		{"CROnly", "data: a\r\rdata: b\r\r", []string{"message a", "message b"}, sseState{}},
+		{"MixedLineEnds", "data: a\r\n\rdata: b\n\r\n", []string{"message a", "message b"}, sseState{}},
+		{"Incomplete", "data: a\n\ndata: b\n", []string{"message a"}, sseState{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []string
+			var state sseState
+			c := &StreamableHTTP{}
+			c.readSSE(context.Background(), io.NopCloser(strings.NewReader(tt.stream)), &state, func(event, data string) {
+				events = append(events, event+" "+data)
+			})
+			if fmt.Sprintf("%q", events) != fmt.Sprintf("%q", tt.events) {
+				t.Errorf("Expected events %q, got %q", tt.events, events)
+			}
+			if state != tt.state {
+				t.Errorf("Expected state %+v, got %+v", tt.state, state)
+			}
+		})
+	}
+}
+
+func TestReadSSECarriageReturns(t *testing.T) {
+	t.Run("Dispatch before the end", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		events := make(chan string, 1)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c := &StreamableHTTP{}
+			c.readSSE(context.Background(), pr, nil, func(event, data string) { events <- data })
+		}()
+		fmt.Fprint(pw, "data: a\r\r")
+		select {
+		case data := <-events:
+			if data != "a" {
+				t.Errorf("Expected data a, got %q", data)
+			}
+		case <-time.After(time.Second):
+			t.Error("Expected the event to be dispatched while the stream is open")
+		}
+		pw.Close()
+		<-done
+	})
+
+	t.Run("Size limit per event", func(t *testing.T) {
+		stream := strings.Repeat("data: 0123456789\r\r", 100)
+		c := &StreamableHTTP{maxResponseSize: 64}
+		var events int
+		err := c.readSSE(context.Background(), io.NopCloser(strings.NewReader(stream)), nil, func(event, data string) { events++ })
+		if err != nil || events != 100 {
+			t.Errorf("Expected 100 events within the limit, got %d: %v", events, err)
+		}
+	})
+}
+
+func TestStreamableHTTPListeningResumes(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.Start()
+	defer srv.Close()
+
+	lastEventIDs := make(chan string, 2)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			srv.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		lastEventIDs <- r.Header.Get("Last-Event-ID")
+		w.Header().Set("Content-Type", "text/event-stream")
+		// End the first stream right away, asking to reconnect soon
+		fmt.Fprint(w, "id: 7\nretry: 1\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\"}\n\n")
+		if r.Header.Get("Last-Event-ID") != "" {
+			<-r.Context().Done()
+		}
+	}))
+	defer proxy.Close()
+
+	trans, err := NewStreamableHTTP(proxy.URL, WithListening())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := trans.Initialize(ctx, mcp.LATEST_PROTOCOL_VERSION, nil, nil); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	for i, expected := range []string{"", "7"} {
+		select {
+		case id := <-lastEventIDs:
+			if id != expected {
+				t.Errorf("Expected Last-Event-ID %q on GET %d, got %q", expected, i+1, id)
+			}
+		case <-ctx.Done():
+			t.Fatalf("Timed out waiting for GET %d", i+1)
+		}
+	}
+}
+
+func TestStreamableHTTPMaxResponseSize(t *testing.T) {
+	result := `{"jsonrpc":"2.0","id":"1","result":{"text":"` + strings.Repeat("x", 100) + `"}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("as") {
+		case "sse":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "data: %s\n\n", result)
+		case "error":
+			http.Error(w, strings.Repeat("e", 100), http.StatusBadGateway)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, result)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	request := JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewStringRequestId("1"), Method: "big"}
+
+	for _, as := range []string{"json", "sse"} {
+		t.Run(as, func(t *testing.T) {
+			trans, err := NewStreamableHTTP(srv.URL+"?as="+as, WithMaxResponseSize(64))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer trans.Close()
+			var tooLarge *ResponseTooLargeError
+			if _, err := trans.SendRequest(ctx, request); !errors.As(err, &tooLarge) || tooLarge.Limit != 64 {
+				t.Errorf("Expected a ResponseTooLargeError, got %v", err)
+			}
+
+			// SSE events are limited with their field names and line breaks
+			size := len(result)
+			if as == "sse" {
+				size += len("data: \n\n")
+			}
+			trans, err = NewStreamableHTTP(srv.URL+"?as="+as, WithMaxResponseSize(int64(size)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer trans.Close()
+			if _, err := trans.SendRequest(ctx, request); err != nil {
+				t.Errorf("Expected a response at the limit, got %v", err)
+			}
+		})
+	}
+
+	t.Run("ErrorBodyTruncated", func(t *testing.T) {
+		trans, err := NewStreamableHTTP(srv.URL+"?as=error", WithMaxResponseSize(10))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		_, err = trans.SendRequest(ctx, request)
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || strings.Contains(err.Error(), strings.Repeat("e", 11)) {
+			t.Errorf("Expected an HTTP error with a truncated body, got %v", err)
+		}
+	})
+}
+
+func TestStreamableHTTPWithHTTPClient(t *testing.T) {
+	url, closeServer := startMockStreamableHTTPServer()
+	defer closeServer()
+
+	var sent atomic.Int32
+	httpClient := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		sent.Add(1)
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	trans, err := NewStreamableHTTP(url, WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+	request := JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewStringRequestId("1"), Method: "ping"}
+	if _, err := trans.SendRequest(context.Background(), request); err != nil {
+		t.Fatalf("SendRequest failed: %v", err)
+	}
+	if sent.Load() == 0 {
+		t.Error("Expected the request to be sent with the given client")
+	}
+}
+
+func TestStreamableHTTPLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: not json\n\n")
+		fmt.Fprint(w, `data: {"jsonrpc":"2.0","id":"1","result":{}}`+"\n\n")
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	trans, err := NewStreamableHTTP(srv.URL, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+	request := JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewStringRequestId("1"), Method: "work"}
+	if _, err := trans.SendRequest(context.Background(), request); err != nil {
+		t.Fatalf("SendRequest failed: %v", err)
+	}
+	if !strings.Contains(logs.String(), "failed to unmarshal message") {
+		t.Errorf("Expected the undecodable message to be logged, got %q", logs.String())
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
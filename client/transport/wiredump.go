@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithWireDump writes every outgoing request, incoming response and SSE event
+// to w, timestamped and tagged with its direction ("-->" for outgoing, "<--" for incoming).
+// The Authorization header is redacted unless disabled with WithWireDumpRedaction.
+func WithWireDump(w io.Writer) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.wireDump = &wireDumper{w: w, redact: true}
+	}
+}
+
+// WithWireDumpRedaction controls whether the Authorization header is redacted in the wire dump.
+// It must be given after WithWireDump.
+func WithWireDumpRedaction(redact bool) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		if sc.wireDump != nil {
+			sc.wireDump.redact = redact
+		}
+	}
+}
+
+// wireDumper serializes traffic records to a writer. A nil *wireDumper discards everything.
+type wireDumper struct {
+	mu     sync.Mutex
+	w      io.Writer
+	redact bool
+}
+
+// request dumps an outgoing HTTP request and its body.
+func (d *wireDumper) request(req *http.Request, body []byte) {
+	if d == nil {
+		return
+	}
+	d.write(fmt.Sprintf("--> %s %s", req.Method, req.URL), d.headers(req.Header), body)
+}
+
+// response dumps the status line and headers of an incoming HTTP response, with its body if already read.
+func (d *wireDumper) response(resp *http.Response, body []byte) {
+	if d == nil {
+		return
+	}
+	d.write(fmt.Sprintf("<-- %s", resp.Status), d.headers(resp.Header), body)
+}
+
+// event dumps a single SSE event.
+func (d *wireDumper) event(event, data string) {
+	if d == nil {
+		return
+	}
+	d.write(fmt.Sprintf("<-- sse event=%s", event), nil, []byte(data))
+}
+
+func (d *wireDumper) headers(h http.Header) []string {
+	lines := make([]string, 0, len(h))
+	for k, values := range h {
+		for _, v := range values {
+			if d.redact && strings.EqualFold(k, "Authorization") {
+				v = "[REDACTED]"
+			}
+			lines = append(lines, k+": "+v)
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func (d *wireDumper) write(line string, headers []string, body []byte) {
+	var b strings.Builder
+	b.WriteString(time.Now().UTC().Format(time.RFC3339Nano))
+	b.WriteByte(' ')
+	b.WriteString(line)
+	b.WriteByte('\n')
+	for _, h := range headers {
+		b.WriteString(h)
+		b.WriteByte('\n')
+	}
+	if len(body) > 0 {
+		b.Write(body)
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, _ = io.WriteString(d.w, b.String())
+}
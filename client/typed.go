@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// ToolError is returned by CallToolAs when the tool reports an error through
+// isError. Result holds the error content.
+type ToolError struct {
+	Tool   string
+	Result *mcp.CallToolResult
+}
+
+func (e *ToolError) Error() string {
+	for _, content := range e.Result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return fmt.Sprintf("tool %s reported an error: %s", e.Tool, text.Text)
+		}
+	}
+	return fmt.Sprintf("tool %s reported an error", e.Tool)
+}
+
+// CallToolAs calls a tool with arguments encoded from args, typically a
+// struct with json tags, and decodes its result into a TResult. The result is
+// decoded from structuredContent when present, else from the first text
+// content parsed as JSON. A tool reporting an error yields a *ToolError.
+func CallToolAs[TArgs any, TResult any](ctx context.Context, c *HTTPClient, name string, args TArgs) (TResult, error) {
+	var zero TResult
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return zero, fmt.Errorf("failed to encode arguments of %s: %w", name, err)
+	}
+	var arguments map[string]any
+	if err := json.Unmarshal(data, &arguments); err != nil {
+		return zero, fmt.Errorf("arguments of %s are not a JSON object: %w", name, err)
+	}
+
+	result, err := c.CallTool(ctx, name, arguments)
+	if err != nil {
+		return zero, err
+	}
+	if result.IsError {
+		return zero, &ToolError{Tool: name, Result: result}
+	}
+
+	raw, err := structuredResult(result)
+	if err != nil {
+		return zero, fmt.Errorf("failed to decode result of %s: %w", name, err)
+	}
+	var value TResult
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return zero, fmt.Errorf("failed to decode result of %s: %w", name, err)
+	}
+	return value, nil
+}
+
+// structuredResult returns the JSON value of a tool result: its
+// structuredContent, or else its first text content.
+func structuredResult(result *mcp.CallToolResult) ([]byte, error) {
+	if len(result.StructuredContent) > 0 {
+		return result.StructuredContent, nil
+	}
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return []byte(text.Text), nil
+		}
+	}
+	return nil, errors.New("result has neither structuredContent nor text content")
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestCallToolAs(t *testing.T) {
+	type addArgs struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+	type sum struct {
+		Sum int `json:"sum"`
+	}
+
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "add"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		total := arguments["a"].(float64) + arguments["b"].(float64)
+		result := mcp.NewToolResultText("ignored")
+		result.StructuredContent, _ = json.Marshal(sum{Sum: int(total)})
+		return result, nil
+	})
+	srv.AddTool(mcp.Tool{Name: "add_text"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		total := arguments["a"].(float64) + arguments["b"].(float64)
+		data, _ := json.Marshal(sum{Sum: int(total)})
+		return mcp.NewToolResultText(string(data)), nil
+	})
+	srv.AddTool(mcp.Tool{Name: "fail"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText("division by zero")
+		result.IsError = true
+		return result, nil
+	})
+	srv.Start()
+	defer srv.Close()
+
+	client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	t.Run("StructuredContent", func(t *testing.T) {
+		got, err := CallToolAs[addArgs, sum](ctx, client, "add", addArgs{A: 2, B: 3})
+		if err != nil {
+			t.Fatalf("CallToolAs failed: %v", err)
+		}
+		if got.Sum != 5 {
+			t.Errorf("Expected sum 5, got %d", got.Sum)
+		}
+	})
+
+	t.Run("TextContent", func(t *testing.T) {
+		got, err := CallToolAs[addArgs, sum](ctx, client, "add_text", addArgs{A: 4, B: 5})
+		if err != nil {
+			t.Fatalf("CallToolAs failed: %v", err)
+		}
+		if got.Sum != 9 {
+			t.Errorf("Expected sum 9, got %d", got.Sum)
+		}
+	})
+
+	t.Run("ToolError", func(t *testing.T) {
+		_, err := CallToolAs[addArgs, sum](ctx, client, "fail", addArgs{})
+		var toolErr *ToolError
+		if !errors.As(err, &toolErr) || toolErr.Tool != "fail" {
+			t.Fatalf("Expected *ToolError, got %v", err)
+		}
+		if err.Error() != "tool fail reported an error: division by zero" {
+			t.Errorf("Unexpected error message: %v", err)
+		}
+	})
+
+	t.Run("ArgumentsNotObject", func(t *testing.T) {
+		if _, err := CallToolAs[[]int, sum](ctx, client, "add", []int{1}); err == nil {
+			t.Error("Expected an error for non-object arguments")
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcp/jsonschema"
+)
+
+// InvalidArgumentsError is returned by CallTool, without contacting the
+// server, when argument validation is enabled and the arguments do not match
+// the tool's inputSchema. Err is usually a *jsonschema.ValidationError listing
+// the violations.
+type InvalidArgumentsError struct {
+	Tool string
+	Err  error
+}
+
+func (e *InvalidArgumentsError) Error() string {
+	return fmt.Sprintf("invalid arguments for tool %s: %v", e.Tool, e.Err)
+}
+
+func (e *InvalidArgumentsError) Unwrap() error {
+	return e.Err
+}
+
+// WithArgumentValidation validates tool arguments against the tool's
+// inputSchema before sending tools/call, using validator, for instance
+// jsonschema.Default. Schemas come from tools/list results, which are fetched
+// when a tool has not been listed yet. Tools the server does not list are not
+// validated.
+func WithArgumentValidation(validator jsonschema.Validator) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.argumentValidator = validator
+	}
+}
+
+// toolIndex holds the tools seen in tools/list results, by name.
+type toolIndex struct {
+	mu    sync.Mutex
+	tools map[string]mcp.Tool
+}
+
+func (ti *toolIndex) add(tools []mcp.Tool) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	if ti.tools == nil {
+		ti.tools = map[string]mcp.Tool{}
+	}
+	for _, tool := range tools {
+		ti.tools[tool.Name] = tool
+	}
+}
+
+func (ti *toolIndex) get(name string) (mcp.Tool, bool) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	tool, ok := ti.tools[name]
+	return tool, ok
+}
+
+func (ti *toolIndex) reset() {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	ti.tools = nil
+}
+
+// lookupTool returns the definition of a tool, listing the tools of the
+// server if it has not been seen yet.
+func (c *HTTPClient) lookupTool(ctx context.Context, name string) (mcp.Tool, bool, error) {
+	if tool, ok := c.toolIndex.get(name); ok {
+		return tool, true, nil
+	}
+	if _, err := allTools(ctx, c); err != nil {
+		return mcp.Tool{}, false, fmt.Errorf("failed to list tools: %w", err)
+	}
+	tool, ok := c.toolIndex.get(name)
+	return tool, ok, nil
+}
+
+// validateArguments checks arguments against the inputSchema of the tool when
+// argument validation is enabled.
+func (c *HTTPClient) validateArguments(ctx context.Context, name string, arguments map[string]any) error {
+	if c.argumentValidator == nil {
+		return nil
+	}
+	tool, ok, err := c.lookupTool(ctx, name)
+	if err != nil || !ok || len(tool.InputSchema) == 0 {
+		return err
+	}
+	// Absent arguments are validated as an empty object
+	var instance any = arguments
+	if arguments == nil {
+		instance = map[string]any{}
+	}
+	if err := c.argumentValidator.Validate(tool.InputSchema, instance); err != nil {
+		return &InvalidArgumentsError{Tool: name, Err: err}
+	}
+	return nil
+}
+
+// OutputValidationMode selects how CallTool reports a structured result that
+// does not match the tool's outputSchema.
+type OutputValidationMode int
+
+const (
+	// OutputValidationStrict fails the call with an *InvalidOutputError.
+	OutputValidationStrict OutputValidationMode = iota
+	// OutputValidationLenient returns the result together with the
+	// *InvalidOutputError, so callers may still use it.
+	OutputValidationLenient
+)
+
+// InvalidOutputError is returned by CallTool when output validation is enabled
+// and the result of a tool declaring an outputSchema lacks structuredContent or
+// does not match the schema. Err is usually a *jsonschema.ValidationError.
+type InvalidOutputError struct {
+	Tool   string
+	Result *mcp.CallToolResult
+	Err    error
+}
+
+func (e *InvalidOutputError) Error() string {
+	return fmt.Sprintf("invalid structured result of tool %s: %v", e.Tool, e.Err)
+}
+
+func (e *InvalidOutputError) Unwrap() error {
+	return e.Err
+}
+
+// WithOutputValidation validates the structuredContent of tool results against
+// the tool's outputSchema using validator, for instance jsonschema.Default.
+// Results flagged isError and tools without an outputSchema are not validated.
+func WithOutputValidation(validator jsonschema.Validator, mode OutputValidationMode) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.outputValidator = validator
+		c.outputValidationMode = mode
+	}
+}
+
+// validateOutput checks the structured content of a result against the
+// outputSchema of the tool when output validation is enabled, and returns
+// what CallTool should.
+func (c *HTTPClient) validateOutput(ctx context.Context, name string, result *mcp.CallToolResult) (*mcp.CallToolResult, error) {
+	if c.outputValidator == nil || result.IsError {
+		return result, nil
+	}
+	tool, ok, err := c.lookupTool(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || len(tool.OutputSchema) == 0 {
+		return result, nil
+	}
+
+	if len(result.StructuredContent) == 0 {
+		err = errors.New("structuredContent is missing")
+	} else {
+		err = c.outputValidator.Validate(tool.OutputSchema, result.StructuredContent)
+	}
+	if err == nil {
+		return result, nil
+	}
+	invalid := &InvalidOutputError{Tool: name, Result: result, Err: err}
+	if c.outputValidationMode == OutputValidationLenient {
+		return result, invalid
+	}
+	return nil, invalid
+}
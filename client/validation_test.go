@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcp/jsonschema"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestArgumentValidation(t *testing.T) {
+	var calls atomic.Int32
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{
+		Name:        "search",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"query":{"type":"string"},"limit":{"type":"integer"}},"required":["query"]}`),
+	}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv.Start()
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	t.Run("Invalid", func(t *testing.T) {
+		client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithArgumentValidation(jsonschema.Default))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer client.Close()
+
+		calls.Store(0)
+		_, err = client.CallTool(ctx, "search", map[string]any{"limit": "ten"})
+		var invalid *InvalidArgumentsError
+		if !errors.As(err, &invalid) || invalid.Tool != "search" {
+			t.Fatalf("Expected *InvalidArgumentsError for search, got %v", err)
+		}
+		var violations *jsonschema.ValidationError
+		if !errors.As(err, &violations) || len(violations.Violations) != 2 {
+			t.Errorf("Expected 2 violations, got %v", err)
+		}
+		if calls.Load() != 0 {
+			t.Errorf("Expected the tool not to be called")
+		}
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithArgumentValidation(jsonschema.Default))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer client.Close()
+
+		if _, err := client.CallTool(ctx, "search", map[string]any{"query": "go", "limit": 10}); err != nil {
+			t.Errorf("CallTool failed: %v", err)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		client, err := NewHTTPClient(&Options{BaseURL: srv.URL})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer client.Close()
+
+		calls.Store(0)
+		if _, err := client.CallTool(ctx, "search", map[string]any{"limit": "ten"}); err != nil {
+			t.Errorf("CallTool failed: %v", err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("Expected the tool to be called")
+		}
+	})
+}
+
+func TestOutputValidation(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{
+		Name:         "count",
+		OutputSchema: json.RawMessage(`{"type":"object","properties":{"n":{"type":"integer"}},"required":["n"]}`),
+	}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText("counted")
+		if structured, ok := arguments["structured"].(string); ok {
+			result.StructuredContent = json.RawMessage(structured)
+		}
+		return result, nil
+	})
+	srv.Start()
+	defer srv.Close()
+
+	ctx := context.Background()
+	newClient := func(t *testing.T, mode OutputValidationMode) *HTTPClient {
+		t.Helper()
+		client, err := NewHTTPClient(&Options{BaseURL: srv.URL}, WithOutputValidation(jsonschema.Default, mode))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		client := newClient(t, OutputValidationStrict)
+		result, err := client.CallTool(ctx, "count", map[string]any{"structured": `{"n":3}`})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if string(result.StructuredContent) != `{"n":3}` {
+			t.Errorf("Expected structured content {\"n\":3}, got %s", result.StructuredContent)
+		}
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		client := newClient(t, OutputValidationStrict)
+		result, err := client.CallTool(ctx, "count", map[string]any{"structured": `{"n":"three"}`})
+		var invalid *InvalidOutputError
+		if !errors.As(err, &invalid) || invalid.Tool != "count" || invalid.Result == nil {
+			t.Fatalf("Expected *InvalidOutputError for count, got %v", err)
+		}
+		var violations *jsonschema.ValidationError
+		if !errors.As(err, &violations) {
+			t.Errorf("Expected a *jsonschema.ValidationError, got %v", err)
+		}
+		if result != nil {
+			t.Errorf("Expected no result, got %+v", result)
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		client := newClient(t, OutputValidationStrict)
+		_, err := client.CallTool(ctx, "count", nil)
+		var invalid *InvalidOutputError
+		if !errors.As(err, &invalid) {
+			t.Fatalf("Expected *InvalidOutputError, got %v", err)
+		}
+	})
+
+	t.Run("Lenient", func(t *testing.T) {
+		client := newClient(t, OutputValidationLenient)
+		result, err := client.CallTool(ctx, "count", map[string]any{"structured": `{"n":"three"}`})
+		var invalid *InvalidOutputError
+		if !errors.As(err, &invalid) {
+			t.Fatalf("Expected *InvalidOutputError, got %v", err)
+		}
+		if result == nil || string(result.StructuredContent) != `{"n":"three"}` {
+			t.Errorf("Expected the result to be returned, got %+v", result)
+		}
+	})
+}
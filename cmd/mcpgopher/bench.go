@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/contriboss/mcpgopher/bench"
+	"github.com/contriboss/mcpgopher/client"
+)
+
+var benchCommand = &command{
+	name:       "bench",
+	usage:      "bench [-c workers] [-sessions n] [-n requests] [-d duration] [-json] (-args JSON <server> <tool> | -read <server> <uri>)",
+	summary:    "load-test a server with tool calls or resource reads",
+	args:       []argKind{argAlias, argTool},
+	valueFlags: []string{"c", "sessions", "n", "d", "args"},
+	run:        runBench,
+}
+
+// runBench calls a tool, or reads a resource with -read, from concurrent
+// workers and prints the latency percentiles, errors and reconnects.
+func runBench(ctx context.Context, env *cliEnv, args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	concurrency := fs.Int("c", 10, "number of workers sending requests at once")
+	sessions := fs.Int("sessions", 1, "number of sessions the workers are spread over")
+	requests := fs.Int("n", 0, "number of requests to send, 100 unless -d is given")
+	duration := fs.Duration("d", 0, "time to send requests for")
+	rawArgs := fs.String("args", "", "tool arguments as a JSON object")
+	read := fs.Bool("read", false, "read the resource at the given URI instead of calling a tool")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 || *read && *rawArgs != "" {
+		return errUsage
+	}
+
+	request := bench.ReadResource(fs.Arg(1))
+	if !*read {
+		var arguments map[string]any
+		if *rawArgs != "" {
+			if err := json.Unmarshal([]byte(*rawArgs), &arguments); err != nil {
+				return fmt.Errorf("invalid -args: %w", err)
+			}
+		}
+		request = bench.CallTool(fs.Arg(1), arguments)
+	}
+
+	server, err := resolveServer(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	connect := func(ctx context.Context, opts ...client.HTTPClientOption) (*client.HTTPClient, error) {
+		// Listening makes the reconnects of the SSE streams show
+		return server.Connect(&client.Options{Listen: true}, opts...)
+	}
+	report, err := bench.Run(ctx, connect, request, bench.Options{
+		Concurrency: *concurrency,
+		Sessions:    *sessions,
+		Requests:    *requests,
+		Duration:    *duration,
+	})
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(env.stdout, report)
+	}
+	printReport(env, report)
+	return nil
+}
+
+// printReport prints a bench report as a table, errors by decreasing count.
+func printReport(env *cliEnv, report *bench.Report) {
+	tw := tabwriter.NewWriter(env.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Requests:\t%d in %s (%.1f/s)\n", report.Requests, report.Elapsed.Round(time.Millisecond), report.Throughput())
+	fmt.Fprintf(tw, "Errors:\t%d (%.2f%%)\n", report.Errors, 100*report.ErrorRate())
+	fmt.Fprintf(tw, "Reconnects:\t%d\n", report.Reconnects)
+	fmt.Fprintf(tw, "Latency:\tmin %s\tmean %s\tp50 %s\tp90 %s\tp99 %s\tmax %s\n",
+		round(report.Min), round(report.Mean), round(report.P50), round(report.P90), round(report.P99), round(report.Max))
+	tw.Flush()
+
+	messages := make([]string, 0, len(report.ErrorCounts))
+	for message := range report.ErrorCounts {
+		messages = append(messages, message)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		if report.ErrorCounts[messages[i]] != report.ErrorCounts[messages[j]] {
+			return report.ErrorCounts[messages[i]] > report.ErrorCounts[messages[j]]
+		}
+		return messages[i] < messages[j]
+	})
+	for _, message := range messages {
+		fmt.Fprintf(env.stdout, "%6d  %s\n", report.ErrorCounts[message], message)
+	}
+}
+
+// round rounds a latency for display.
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Microsecond)
+}
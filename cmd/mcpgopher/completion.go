@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// argKind describes what a positional argument completes to.
+type argKind int
+
+const (
+	argNone argKind = iota
+	argAlias
+	argTool
+	argShell
+)
+
+var completionCommand = &command{
+	name:    "completion",
+	usage:   "completion bash|zsh|fish",
+	summary: "print a shell completion script",
+	args:    []argKind{argShell},
+	run:     runCompletion,
+}
+
+// completeCommand is invoked by the generated scripts with the words typed so
+// far, excluding the program name and the word being completed.
+var completeCommand = &command{
+	name:   "__complete",
+	usage:  "__complete [words...]",
+	run:    runComplete,
+	hidden: true,
+}
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion for mcpgopher
+# Install: source <(mcpgopher completion bash)
+_mcpgopher() {
+    local cur=${COMP_WORDS[COMP_CWORD]}
+    local IFS=$'\n'
+    COMPREPLY=($(compgen -W "$(mcpgopher __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)" -- "$cur"))
+}
+complete -F _mcpgopher mcpgopher
+`,
+	"zsh": `#compdef mcpgopher
+# zsh completion for mcpgopher
+# Install: mcpgopher completion zsh > "${fpath[1]}/_mcpgopher"
+_mcpgopher() {
+    local -a candidates
+    candidates=(${(f)"$(mcpgopher __complete ${words[2,CURRENT-1]} 2>/dev/null)"})
+    compadd -a candidates
+}
+if [ "$funcstack[1]" = "_mcpgopher" ]; then
+    _mcpgopher "$@"
+else
+    compdef _mcpgopher mcpgopher
+fi
+`,
+	"fish": `# fish completion for mcpgopher
+# Install: mcpgopher completion fish > ~/.config/fish/completions/mcpgopher.fish
+complete -c mcpgopher -f -a '(mcpgopher __complete (commandline -opc)[2..-1] 2>/dev/null)'
+`,
+}
+
+func runCompletion(_ context.Context, env *cliEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		return fmt.Errorf("unsupported shell %q", args[0])
+	}
+	_, err := io.WriteString(env.stdout, script)
+	return err
+}
+
+// runComplete prints one completion candidate per line. Errors are swallowed
+// so a broken config never spills into the user's prompt.
+func runComplete(ctx context.Context, env *cliEnv, args []string) error {
+	for _, candidate := range complete(ctx, args) {
+		fmt.Fprintln(env.stdout, candidate)
+	}
+	return nil
+}
+
+// complete returns the candidates for the word following words.
+func complete(ctx context.Context, words []string) []string {
+	cmds := commands
+	var cmd *command
+	for len(words) > 0 {
+		next := findCommand(cmds, words[0])
+		if next == nil {
+			return nil
+		}
+		cmd, words = next, words[1:]
+		if len(cmd.subcommands) == 0 {
+			break
+		}
+		cmds = cmd.subcommands
+	}
+	if cmd == nil || len(cmd.subcommands) > 0 {
+		return commandNames(cmds)
+	}
+
+	// Flags don't count as positional arguments
+	var positional []string
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if !strings.HasPrefix(word, "-") {
+			positional = append(positional, word)
+			continue
+		}
+		name := strings.TrimLeft(word, "-")
+		if !strings.Contains(name, "=") && slices.Contains(cmd.valueFlags, name) {
+			i++
+		}
+	}
+	if len(positional) >= len(cmd.args) {
+		return nil
+	}
+
+	switch cmd.args[len(positional)] {
+	case argAlias:
+		cfg, err := loadConfig()
+		if err != nil {
+			return nil
+		}
+		return cfg.aliases()
+	case argTool:
+		// Tools belong to the alias given earlier on the line
+		for i := len(positional) - 1; i >= 0; i-- {
+			if cmd.args[i] != argAlias {
+				continue
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				return nil
+			}
+			server, err := cfg.lookup(positional[i])
+			if err != nil {
+				return nil
+			}
+			return cachedToolNames(ctx, positional[i], server)
+		}
+	case argShell:
+		return []string{"bash", "fish", "zsh"}
+	}
+	return nil
+}
+
+func commandNames(cmds []*command) []string {
+	var names []string
+	for _, cmd := range cmds {
+		if !cmd.hidden {
+			names = append(names, cmd.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/client/transport"
+)
+
+// serverConfig is a server alias entry. The file uses the "mcpServers"
+// layout shared by most MCP hosts, so entries may name a stdio command too.
+type serverConfig = client.ServerConfig
+
+type configFile struct {
+	MCPServers map[string]serverConfig `json:"mcpServers"`
+}
+
+// configDir returns the directory holding the CLI configuration.
+// MCPGOPHER_CONFIG_DIR overrides the default user configuration directory.
+func configDir() (string, error) {
+	if dir := os.Getenv("MCPGOPHER_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mcpgopher"), nil
+}
+
+// cacheDir returns the directory holding cached server data.
+// MCPGOPHER_CACHE_DIR overrides the default user cache directory.
+func cacheDir() (string, error) {
+	if dir := os.Getenv("MCPGOPHER_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mcpgopher"), nil
+}
+
+func configPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "servers.json"), nil
+}
+
+// loadConfig reads the alias configuration. A missing file yields an empty configuration.
+func loadConfig() (*configFile, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &configFile{MCPServers: map[string]serverConfig{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if cfg.MCPServers == nil {
+		cfg.MCPServers = map[string]serverConfig{}
+	}
+	return cfg, nil
+}
+
+// save writes the configuration atomically.
+func (c *configFile) save() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// aliases returns the configured aliases in sorted order.
+func (c *configFile) aliases() []string {
+	names := make([]string, 0, len(c.MCPServers))
+	for name := range c.MCPServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup returns the server registered under alias.
+func (c *configFile) lookup(alias string) (serverConfig, error) {
+	server, ok := c.MCPServers[alias]
+	if !ok {
+		return serverConfig{}, fmt.Errorf("unknown server alias %q (see \"mcpgopher server list\")", alias)
+	}
+	return server, nil
+}
+
+// resolveServer returns the server an argument designates: an http(s) URL,
+// a "stdio:" prefixed command line, or the alias of a registered server.
+func resolveServer(arg string) (serverConfig, error) {
+	if transport.Scheme(arg) == transport.StdioScheme {
+		command, args, err := transport.ParseStdioAddress(arg)
+		if err != nil {
+			return serverConfig{}, err
+		}
+		return serverConfig{Command: command, Args: args}, nil
+	}
+	if u, err := url.Parse(arg); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return serverConfig{URL: arg}, nil
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return serverConfig{}, err
+	}
+	return cfg.lookup(arg)
+}
+
+// describe returns the URL or command line of a server for display.
+func describe(server serverConfig) string {
+	if server.Command != "" {
+		return strings.Join(append([]string{server.Command}, server.Args...), " ")
+	}
+	return server.URL
+}
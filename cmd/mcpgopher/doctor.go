@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcp/jsonschema"
+	"github.com/contriboss/mcpgopher/mcp/spec"
+)
+
+var doctorCommand = &command{
+	name:       "doctor",
+	usage:      "doctor [-slow duration] [-json] <server>",
+	summary:    "check a server for common problems",
+	args:       []argKind{argAlias},
+	valueFlags: []string{"slow"},
+	run:        runDoctor,
+}
+
+// doctorMaxPages bounds the pages followed when checking pagination, so that
+// a server handing out cursors forever is reported rather than followed.
+const doctorMaxPages = 100
+
+// Severities of the findings.
+const (
+	severityOK      = "ok"
+	severityWarning = "warning"
+	severityError   = "error"
+)
+
+// finding is the outcome of a check, with how to fix the problem found.
+type finding struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// doctor collects the findings of the checks run on a server.
+type doctor struct {
+	findings []finding
+}
+
+func (d *doctor) ok(check, format string, args ...any) {
+	d.findings = append(d.findings, finding{Check: check, Severity: severityOK, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *doctor) warn(check, fix, format string, args ...any) {
+	d.findings = append(d.findings, finding{Check: check, Severity: severityWarning, Message: fmt.Sprintf(format, args...), Fix: fix})
+}
+
+func (d *doctor) fail(check, fix, format string, args ...any) {
+	d.findings = append(d.findings, finding{Check: check, Severity: severityError, Message: fmt.Sprintf(format, args...), Fix: fix})
+}
+
+// errors returns the number of findings that are errors.
+func (d *doctor) errors() int {
+	n := 0
+	for _, f := range d.findings {
+		if f.Severity == severityError {
+			n++
+		}
+	}
+	return n
+}
+
+// runDoctor runs the checks on a server and prints the findings. It fails if
+// any check found an error.
+func runDoctor(ctx context.Context, env *cliEnv, args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	slow := fs.Duration("slow", 500*time.Millisecond, "ping latency from which the server is reported slow")
+	asJSON := fs.Bool("json", false, "print the findings as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	server, err := resolveServer(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	d := &doctor{}
+	if server.URL != "" {
+		d.checkHTTP(ctx, server.URL, server.Headers)
+	}
+	d.checkSession(ctx, server, *slow)
+
+	if *asJSON {
+		if err := printJSON(env.stdout, d.findings); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range d.findings {
+			fmt.Fprintf(env.stdout, "%-8s %-12s %s\n", f.Severity, f.Check, f.Message)
+			if f.Fix != "" {
+				fmt.Fprintf(env.stdout, "%-8s %-12s fix: %s\n", "", "", f.Fix)
+			}
+		}
+	}
+	if n := d.errors(); n > 0 {
+		return fmt.Errorf("found %d problem(s)", n)
+	}
+	return nil
+}
+
+// checkHTTP initializes a session by hand to check what the client library
+// would otherwise hide: the status, content types and session header of the
+// server's answers.
+func (d *doctor) checkHTTP(ctx context.Context, url string, headers map[string]string) {
+	post := func(sessionID string, message any) (*http.Response, error) {
+		body, _ := json.Marshal(message)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		return http.DefaultClient.Do(req)
+	}
+
+	resp, err := post("", map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      1,
+		"method":  mcp.MethodInitialize,
+		"params": map[string]any{
+			"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+			"capabilities":    map[string]any{},
+			"clientInfo":      map[string]any{"name": "mcpgopher-doctor", "version": client.Version},
+		},
+	})
+	if err != nil {
+		d.fail("http", "Check the URL and that the server is running.", "initialize failed: %v", err)
+		return
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, transport.DefaultMaxResponseSize))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		d.fail("http", "Answer initialize with 200 OK.", "initialize was answered %s: %s", resp.Status, firstLine(string(body)))
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		d.ok("content-type", "initialize was answered with %s", mediaType)
+	case "text/event-stream":
+		d.ok("content-type", "initialize was answered with %s", mediaType)
+		body = firstSSEData(body)
+	default:
+		d.fail("content-type", "Answer POST requests with Content-Type application/json or text/event-stream.",
+			"initialize was answered with Content-Type %q", resp.Header.Get("Content-Type"))
+	}
+	var response struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || len(response.Result) == 0 {
+		d.fail("http", "Answer initialize with a JSON-RPC response.", "initialize was not answered with a JSON-RPC result: %q", firstLine(string(body)))
+	}
+
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	if sessionID == "" {
+		d.warn("session", "Return an Mcp-Session-Id header from initialize, unless the server is meant to be stateless.",
+			"initialize was answered without an Mcp-Session-Id header, so requests cannot be tied to a session")
+	} else {
+		d.ok("session", "initialize was answered with a session ID")
+	}
+
+	resp, err = post(sessionID, map[string]any{"jsonrpc": mcp.JSONRPC_VERSION, "method": mcp.MethodNotificationInitialized})
+	if err != nil {
+		d.fail("http", "Check that the server accepts notifications.", "notifications/initialized failed: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		d.warn("http", "Answer notifications with 202 Accepted and no body.", "notifications/initialized was answered %s", resp.Status)
+	}
+
+	// End the session of the probe
+	if sessionID != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+		if err == nil {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+			if resp, err := http.DefaultClient.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}
+	}
+}
+
+// firstSSEData returns the data of the first event of an SSE stream.
+func firstSSEData(stream []byte) []byte {
+	var data []byte
+	scanner := bufio.NewScanner(bytes.NewReader(stream))
+	scanner.Buffer(nil, len(stream)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 && data != nil {
+			break
+		}
+		if value, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+			data = append(data, bytes.TrimPrefix(value, []byte(" "))...)
+		}
+	}
+	return data
+}
+
+// checkSession connects with the client library and checks the negotiated
+// protocol version, the lists and their pagination, the tool schemas and the
+// ping latency.
+func (d *doctor) checkSession(ctx context.Context, server serverConfig, slow time.Duration) {
+	var version string
+	hooks := &client.Hooks{
+		OnResponse: func(ctx context.Context, request *transport.JSONRPCRequest, response *transport.JSONRPCResponse, elapsed time.Duration) {
+			if request.Method == string(mcp.MethodInitialize) {
+				var result struct {
+					ProtocolVersion string `json:"protocolVersion"`
+				}
+				_ = json.Unmarshal(response.Result, &result)
+				version = result.ProtocolVersion
+			}
+		},
+	}
+	c, err := server.Connect(&client.Options{Hooks: hooks})
+	if err != nil {
+		d.fail("connect", "Fix the problems above, or run mcpgopher inspect -wire to see the exchange.", "failed to initialize a session: %v", err)
+		return
+	}
+	defer c.Close()
+
+	switch {
+	case version == mcp.LATEST_PROTOCOL_VERSION:
+		d.ok("protocol", "the server speaks %s", version)
+	case slices.Contains(spec.Revisions, version):
+		d.warn("protocol", fmt.Sprintf("Upgrade the server's MCP implementation to %s.", mcp.LATEST_PROTOCOL_VERSION),
+			"the server answered %s to a request for %s", version, mcp.LATEST_PROTOCOL_VERSION)
+	default:
+		d.fail("protocol", fmt.Sprintf("Answer with the requested version, or one of %s.", strings.Join(spec.Revisions, ", ")),
+			"the server answered the unknown protocol version %q", version)
+	}
+
+	capabilities := c.ServerCapabilities()
+	if capabilities.Tools != nil {
+		tools, ok := checkPages(d, "tools", func(cursor mcp.Cursor) ([]mcp.Tool, mcp.Cursor, error) {
+			result, err := c.ListTools(ctx, cursor)
+			if err != nil {
+				return nil, "", err
+			}
+			return result.Tools, result.NextCursor, nil
+		}, func(tool mcp.Tool) string { return tool.Name })
+		if ok {
+			d.checkSchemas(tools)
+		}
+	}
+	if capabilities.Resources != nil {
+		checkPages(d, "resources", func(cursor mcp.Cursor) ([]mcp.Resource, mcp.Cursor, error) {
+			result, err := c.ListResources(ctx, cursor)
+			if err != nil {
+				return nil, "", err
+			}
+			return result.Resources, result.NextCursor, nil
+		}, func(resource mcp.Resource) string { return resource.URI })
+	}
+	if capabilities.Prompts != nil {
+		checkPages(d, "prompts", func(cursor mcp.Cursor) ([]mcp.Prompt, mcp.Cursor, error) {
+			result, err := c.ListPrompts(ctx, cursor)
+			if err != nil {
+				return nil, "", err
+			}
+			return result.Prompts, result.NextCursor, nil
+		}, func(prompt mcp.Prompt) string { return prompt.Name })
+	}
+
+	latency, err := c.Ping(ctx)
+	switch {
+	case err != nil:
+		d.fail("ping", "Answer ping with an empty result; clients use it to check the server is alive.", "ping failed: %v", err)
+	case latency > slow:
+		d.warn("ping", "Answer ping without waiting on other work, and check the network path to the server.",
+			"ping took %s, more than %s", latency.Round(time.Millisecond), slow)
+	default:
+		d.ok("ping", "ping answered in %s", latency.Round(time.Microsecond))
+	}
+}
+
+// checkPages lists every page of a list, checking that cursors do not repeat,
+// that pages do not repeat items and that the list ends.
+func checkPages[T any](d *doctor, list string, page func(mcp.Cursor) ([]T, mcp.Cursor, error), key func(T) string) ([]T, bool) {
+	var items []T
+	seen := map[string]bool{}
+	cursors := map[mcp.Cursor]bool{}
+	var cursor mcp.Cursor
+	for pages := 1; ; pages++ {
+		pageItems, next, err := page(cursor)
+		if err != nil {
+			if pages == 1 {
+				d.fail(list, fmt.Sprintf("Implement %s/list, or stop declaring the %s capability.", list, list), "listing %s failed: %v", list, err)
+			} else {
+				d.fail(list, "Accept the cursors the server hands out until they expire.", "page %d of %s failed: %v", pages, list, err)
+			}
+			return nil, false
+		}
+		for _, item := range pageItems {
+			if k := key(item); seen[k] {
+				d.warn(list, "Make each page start where the previous one ended.", "%q is listed more than once", k)
+			} else {
+				seen[k] = true
+			}
+		}
+		items = append(items, pageItems...)
+		if next == "" {
+			d.ok(list, "%d %s in %d page(s)", len(items), list, pages)
+			return items, true
+		}
+		if cursors[next] {
+			d.fail(list, "Hand out a new cursor with each page and none with the last.", "the cursor %q of page %d was already handed out, so the list never ends", next, pages)
+			return nil, false
+		}
+		if pages == doctorMaxPages {
+			d.fail(list, "Hand out no cursor with the last page.", "%s did not end after %d pages", list, pages)
+			return nil, false
+		}
+		cursors[next] = true
+		cursor = next
+	}
+}
+
+// checkSchemas checks that the inputSchema of each tool is a JSON Schema of
+// an object, as clients need to build arguments.
+func (d *doctor) checkSchemas(tools []mcp.Tool) {
+	invalid := 0
+	for _, tool := range tools {
+		if err := checkSchema(tool.InputSchema); err != nil {
+			d.fail("schemas", "Give the tool an inputSchema of type object, whose properties are schemas.", "tool %s: %v", tool.Name, err)
+			invalid++
+		}
+	}
+	if invalid == 0 {
+		d.ok("schemas", "the input schemas of the %d tools are valid", len(tools))
+	}
+}
+
+// checkSchema returns why a tool input schema is invalid, if it is.
+func checkSchema(raw json.RawMessage) error {
+	var schema struct {
+		Type       any                        `json:"type"`
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return fmt.Errorf("inputSchema is not a schema object: %w", err)
+	}
+	if schema.Type != "object" {
+		return fmt.Errorf("inputSchema has type %v, not object", schema.Type)
+	}
+	for name, property := range schema.Properties {
+		var object map[string]any
+		if err := json.Unmarshal(property, &object); err != nil && string(property) != "true" && string(property) != "false" {
+			return fmt.Errorf("property %s is not a schema", name)
+		}
+	}
+	for _, name := range schema.Required {
+		if _, ok := schema.Properties[name]; !ok && schema.Properties != nil {
+			return fmt.Errorf("required property %s is not among the properties", name)
+		}
+	}
+	// Validating an instance compiles the schema; violations are expected,
+	// but other errors mean the validator could not use it
+	var violations *jsonschema.ValidationError
+	if err := jsonschema.Default.Validate(raw, map[string]any{}); err != nil && !errors.As(err, &violations) {
+		return err
+	}
+	return nil
+}
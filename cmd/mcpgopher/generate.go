@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/contriboss/mcpgopher/codegen"
+)
+
+var generateCommand = &command{
+	name:       "generate",
+	usage:      "generate [-package name] [-o file] <server>",
+	summary:    "generate typed Go bindings for the tools of a server",
+	args:       []argKind{argAlias},
+	valueFlags: []string{"package", "o"},
+	run:        runGenerate,
+}
+
+func runGenerate(ctx context.Context, env *cliEnv, args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	pkg := fs.String("package", "tools", "name of the generated package")
+	output := fs.String("o", "", "write the bindings to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+
+	server, err := resolveServer(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	c, err := server.Connect(nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", fs.Arg(0), err)
+	}
+	defer c.Close()
+
+	info := c.ServerInfo()
+	src, err := codegen.GenerateFromClient(ctx, c, codegen.Options{
+		Package: *pkg,
+		Source:  fmt.Sprintf("%s %s (%s)", info.Name, info.Version, describe(server)),
+	})
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = env.stdout.Write(src)
+		return err
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	return nil
+}
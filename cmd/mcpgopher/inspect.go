@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+	"golang.org/x/term"
+)
+
+var inspectCommand = &command{
+	name:    "inspect",
+	usage:   "inspect [-wire] [-color=auto|always|never] <server>",
+	summary: "show what a server negotiates and tail its traffic",
+	args:    []argKind{argAlias},
+	run:     runInspect,
+}
+
+// ANSI colors of the traffic kinds.
+const (
+	colorReset        = "\x1b[0m"
+	colorRequest      = "\x1b[36m" // cyan
+	colorResponse     = "\x1b[32m" // green
+	colorError        = "\x1b[31m" // red
+	colorNotification = "\x1b[33m" // yellow
+	colorServer       = "\x1b[35m" // magenta
+	colorHeading      = "\x1b[1m"  // bold
+)
+
+// trafficPrinter writes timestamped, optionally colored, traffic lines.
+type trafficPrinter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	color bool
+}
+
+// newTrafficPrinter returns a printer writing to w, colored depending on
+// mode: auto, always or never.
+func newTrafficPrinter(w io.Writer, mode string) (*trafficPrinter, error) {
+	printer := &trafficPrinter{w: w}
+	switch mode {
+	case "always":
+		printer.color = true
+	case "auto":
+		f, ok := w.(*os.File)
+		printer.color = ok && term.IsTerminal(int(f.Fd())) && os.Getenv("NO_COLOR") == ""
+	case "never":
+	default:
+		return nil, fmt.Errorf("invalid -color %q", mode)
+	}
+	return printer, nil
+}
+
+func (p *trafficPrinter) print(color, arrow, kind, detail string, payload any) {
+	var data []byte
+	if payload != nil {
+		data, _ = json.Marshal(payload)
+	}
+	line := fmt.Sprintf("%s %s %-12s %s", time.Now().Format("15:04:05.000"), arrow, kind, detail)
+	if len(data) > 0 {
+		line += " " + string(data)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.color {
+		fmt.Fprintf(p.w, "%s%s%s\n", color, line, colorReset)
+	} else {
+		fmt.Fprintln(p.w, line)
+	}
+}
+
+// section writes a heading and its body, if any.
+func (p *trafficPrinter) section(title, body string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.color {
+		fmt.Fprintf(p.w, "%s%s%s\n", colorHeading, title, colorReset)
+	} else {
+		fmt.Fprintln(p.w, title)
+	}
+	if body != "" {
+		fmt.Fprintln(p.w, body)
+	}
+}
+
+// hooks returns client hooks printing every message of the session.
+func (p *trafficPrinter) hooks() *client.Hooks {
+	return &client.Hooks{
+		OnRequest: func(ctx context.Context, request *transport.JSONRPCRequest) {
+			p.print(colorRequest, "-->", "request", fmt.Sprintf("%s id=%s", request.Method, request.ID), request.Params)
+		},
+		OnResponse: func(ctx context.Context, request *transport.JSONRPCRequest, response *transport.JSONRPCResponse, elapsed time.Duration) {
+			detail := fmt.Sprintf("%s id=%s (%s)", request.Method, response.ID, elapsed.Round(time.Microsecond))
+			if response.Error != nil {
+				p.print(colorError, "<--", "error", detail, response.Error)
+				return
+			}
+			p.print(colorResponse, "<--", "response", detail, response.Result)
+		},
+		OnError: func(ctx context.Context, request *transport.JSONRPCRequest, err error, elapsed time.Duration) {
+			p.print(colorError, "<--", "failed", fmt.Sprintf("%s id=%s: %v", request.Method, request.ID, err), nil)
+		},
+		OnNotification: func(notification *transport.JSONRPCNotification, receivedAt time.Time) {
+			var params any
+			if notification.Params.AdditionalFields != nil {
+				params = notification.Params.AdditionalFields
+			}
+			p.print(colorNotification, "<--", "notification", notification.Method, params)
+		},
+		OnServerPing: func(id mcp.RequestId, receivedAt time.Time) {
+			p.print(colorServer, "<--", "server ping", "id="+id.String(), nil)
+		},
+	}
+}
+
+// runInspect initializes a session, prints what the server declared, then
+// prints its traffic until interrupted.
+func runInspect(ctx context.Context, env *cliEnv, args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	wire := fs.Bool("wire", false, "also dump raw HTTP requests, responses and SSE events to stderr")
+	colorMode := fs.String("color", "auto", "color output: auto, always or never")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+
+	printer, err := newTrafficPrinter(env.stdout, *colorMode)
+	if err != nil {
+		return err
+	}
+
+	server, err := resolveServer(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	options := &client.Options{Listen: true, Hooks: printer.hooks()}
+	if *wire {
+		options.Debug = true
+		options.Logger = env.stderr
+	}
+	c, err := server.Connect(options)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", fs.Arg(0), err)
+	}
+	defer c.Close()
+
+	info := c.ServerInfo()
+	capabilities, _ := json.MarshalIndent(c.ServerCapabilities(), "", "  ")
+	printer.section(fmt.Sprintf("Server: %s %s", info.Name, info.Version), "")
+	if id := c.GetSessionID(); id != "" {
+		printer.section("Session: "+id, "")
+	}
+	printer.section("Capabilities:", string(capabilities))
+	if instructions := c.Instructions(); instructions != "" {
+		printer.section("Instructions:", instructions)
+	}
+	printer.section("Traffic (interrupt to stop):", "")
+
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,160 @@
+// Command mcpgopher is a command-line client for MCP servers.
+//
+// Usage:
+//
+//	mcpgopher <command> [arguments]
+//
+// Run "mcpgopher help" for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// command is a top-level CLI command.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	// args describes the positional arguments for shell completion
+	args []argKind
+	// valueFlags are the flags taking a value as the next word, which
+	// completion must not count as a positional argument
+	valueFlags []string
+	// subcommands, if any, are dispatched by the command itself
+	subcommands []*command
+	run         func(ctx context.Context, env *cliEnv, args []string) error
+	hidden      bool
+}
+
+// cliEnv carries the process streams so commands can be tested.
+type cliEnv struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// errUsage signals that the command line was malformed; usage is printed.
+var errUsage = errors.New("usage error")
+
+// commands lists the top-level commands. It is filled in by init because
+// completion walks the list itself.
+var commands []*command
+
+func init() {
+	commands = []*command{
+		serverCommand,
+		listCommand,
+		callCommand,
+		readCommand,
+		pingCommand,
+		replCommand,
+		tuiCommand,
+		inspectCommand,
+		doctorCommand,
+		generateCommand,
+		schemaCommand,
+		proxyCommand,
+		mitmCommand,
+		benchCommand,
+		completionCommand,
+		completeCommand,
+	}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	env := &cliEnv{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	os.Exit(run(ctx, env, os.Args[1:]))
+}
+
+// run executes the command line and returns the process exit code.
+func run(ctx context.Context, env *cliEnv, args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(env.stdout)
+		return 0
+	}
+
+	cmd := findCommand(commands, args[0])
+	if cmd == nil {
+		fmt.Fprintf(env.stderr, "mcpgopher: unknown command %q\n", args[0])
+		printUsage(env.stderr)
+		return 2
+	}
+
+	if err := dispatch(ctx, env, cmd, args[1:]); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(env.stderr, "usage: mcpgopher %s\n", usageOf(cmd, args[1:]))
+			return 2
+		}
+		fmt.Fprintf(env.stderr, "mcpgopher: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// dispatch runs cmd, descending into its subcommands if it has any.
+func dispatch(ctx context.Context, env *cliEnv, cmd *command, args []string) error {
+	if len(cmd.subcommands) == 0 {
+		return cmd.run(ctx, env, args)
+	}
+	if len(args) == 0 {
+		return errUsage
+	}
+	sub := findCommand(cmd.subcommands, args[0])
+	if sub == nil {
+		return errUsage
+	}
+	return dispatch(ctx, env, sub, args[1:])
+}
+
+func findCommand(cmds []*command, name string) *command {
+	for _, cmd := range cmds {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func usageOf(cmd *command, args []string) string {
+	if len(cmd.subcommands) > 0 && len(args) > 0 {
+		if sub := findCommand(cmd.subcommands, args[0]); sub != nil {
+			return cmd.name + " " + usageOf(sub, args[1:])
+		}
+	}
+	if len(cmd.subcommands) > 0 {
+		names := make([]string, len(cmd.subcommands))
+		for i, sub := range cmd.subcommands {
+			names[i] = sub.name
+		}
+		return cmd.name + " " + strings.Join(names, "|") + " ..."
+	}
+	return cmd.usage
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "mcpgopher is a command-line client for MCP servers.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "\tmcpgopher <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintln(w)
+	for _, cmd := range commands {
+		if cmd.hidden {
+			continue
+		}
+		fmt.Fprintf(w, "\t%-12s %s\n", cmd.name, cmd.summary)
+	}
+}
@@ -0,0 +1,801 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+	"unicode/utf8"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+	"golang.org/x/term"
+)
+
+// setupDirs points the config and cache at per-test directories.
+func setupDirs(t *testing.T) {
+	t.Helper()
+	t.Setenv("MCPGOPHER_CONFIG_DIR", t.TempDir())
+	t.Setenv("MCPGOPHER_CACHE_DIR", t.TempDir())
+}
+
+// runCLI runs the command line and returns its exit code and output.
+func runCLI(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	return runCLIWithInput(t, "", args...)
+}
+
+// runCLIWithInput runs the command line with stdin as its input.
+func runCLIWithInput(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), &cliEnv{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}, args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestServerAliases(t *testing.T) {
+	setupDirs(t)
+
+	if code, _, stderr := runCLI(t, "server", "add", "-H", "Authorization: Bearer x", "prod", "https://example.com/mcp"); code != 0 {
+		t.Fatalf("server add failed: %s", stderr)
+	}
+	if code, _, stderr := runCLI(t, "server", "add", "local", "http://localhost:8080"); code != 0 {
+		t.Fatalf("server add failed: %s", stderr)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if got := cfg.MCPServers["prod"].Headers["Authorization"]; got != "Bearer x" {
+		t.Errorf("Expected header to be persisted, got %q", got)
+	}
+
+	_, stdout, _ := runCLI(t, "server", "list")
+	if !strings.Contains(stdout, "local") || !strings.Contains(stdout, "https://example.com/mcp") {
+		t.Errorf("Unexpected list output: %q", stdout)
+	}
+	if strings.Index(stdout, "local") > strings.Index(stdout, "prod") {
+		t.Errorf("Expected aliases to be sorted: %q", stdout)
+	}
+
+	if code, _, stderr := runCLI(t, "server", "remove", "local"); code != 0 {
+		t.Fatalf("server remove failed: %s", stderr)
+	}
+	if code, _, _ := runCLI(t, "server", "remove", "local"); code != 1 {
+		t.Errorf("Expected removing an unknown alias to fail, got exit code %d", code)
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		if code, _, _ := runCLI(t, "server", "add", "bad", "ftp://example.com"); code != 1 {
+			t.Errorf("Expected invalid URL to fail, got exit code %d", code)
+		}
+		if code, _, _ := runCLI(t, "server", "add", "onlyalias"); code != 2 {
+			t.Errorf("Expected usage error, got exit code %d", code)
+		}
+	})
+}
+
+func TestCompletionScripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			code, stdout, _ := runCLI(t, "completion", shell)
+			if code != 0 || !strings.Contains(stdout, "mcpgopher __complete") {
+				t.Errorf("Unexpected %s script (exit %d): %q", shell, code, stdout)
+			}
+		})
+	}
+
+	if code, _, _ := runCLI(t, "completion", "powershell"); code != 1 {
+		t.Errorf("Expected unsupported shell to fail, got exit code %d", code)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	setupDirs(t)
+	runCLI(t, "server", "add", "beta", "http://localhost:1")
+	runCLI(t, "server", "add", "alpha", "http://localhost:2")
+
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{nil, []string{"server", "list", "call", "read", "ping", "repl", "tui", "inspect", "doctor", "generate", "schema", "proxy", "mitm", "bench", "completion"}},
+		{[]string{"list"}, []string{"tools", "resources", "prompts"}},
+		{[]string{"call", "-args", "{}"}, []string{"alpha", "beta"}},
+		{[]string{"proxy"}, []string{"alpha", "beta"}},
+		{[]string{"server"}, []string{"add", "list", "remove", "refresh"}},
+		{[]string{"server", "remove"}, []string{"alpha", "beta"}},
+		{[]string{"server", "remove", "alpha"}, nil},
+		{[]string{"completion"}, []string{"bash", "fish", "zsh"}},
+		{[]string{"unknown"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.words, " "), func(t *testing.T) {
+			got := complete(context.Background(), tt.words)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("complete(%q) = %q, want %q", tt.words, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedToolNames(t *testing.T) {
+	setupDirs(t)
+
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, nil)
+	srv.AddTool(mcp.Tool{Name: "sum"}, nil)
+	srv.Start()
+	defer srv.Close()
+
+	server := serverConfig{URL: srv.URL}
+	got := cachedToolNames(context.Background(), "dev", server)
+	if !reflect.DeepEqual(got, []string{"echo", "sum"}) {
+		t.Fatalf("Expected live tool names, got %q", got)
+	}
+
+	// A fresh cache is served without contacting the server
+	srv.Close()
+	got = cachedToolNames(context.Background(), "dev", server)
+	if !reflect.DeepEqual(got, []string{"echo", "sum"}) {
+		t.Errorf("Expected cached tool names, got %q", got)
+	}
+
+	if err := forgetTools("dev"); err != nil {
+		t.Fatalf("forgetTools: %v", err)
+	}
+	if got := cachedToolNames(context.Background(), "dev", server); got != nil {
+		t.Errorf("Expected no names once the cache is gone and the server is down, got %q", got)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	setupDirs(t)
+	srv := mcptest.NewServer()
+	srv.Start()
+	defer srv.Close()
+
+	if code, _, stderr := runCLI(t, "server", "add", "test", srv.URL); code != 0 {
+		t.Fatalf("server add failed: %s", stderr)
+	}
+
+	input := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}` + "\n"
+	code, stdout, stderr := runCLIWithInput(t, input, "proxy", "test")
+	if code != 0 {
+		t.Fatalf("proxy failed: %s", stderr)
+	}
+	var response struct {
+		ID     int                  `json:"id"`
+		Result mcp.InitializeResult `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &response); err != nil {
+		t.Fatalf("Expected a single JSON-RPC response, got %q: %v", stdout, err)
+	}
+	if response.ID != 1 || response.Result.ServerInfo.Name != "mcptest" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	if code, _, _ := runCLI(t, "proxy", "-listen", ":0"); code != 2 {
+		t.Errorf("Expected -listen without a command to be a usage error, got exit code %d", code)
+	}
+}
+
+// TestStdioServerProcess is not a real test: it serves a test server over
+// stdio when run as a subprocess by TestRequests.
+func TestStdioServerProcess(t *testing.T) {
+	if os.Getenv("MCPGOPHER_TEST_STDIO_SERVER") != "1" {
+		t.Skip("helper process")
+	}
+	srv := mcptest.NewServer()
+	_ = srv.ServeStdio(context.Background(), os.Stdin, os.Stdout)
+	os.Exit(0)
+}
+
+func TestRequests(t *testing.T) {
+	setupDirs(t)
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo", Description: "Echoes its input\nat length"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		text, _ := arguments["text"].(string)
+		return mcp.NewToolResultText(text), nil
+	})
+	srv.AddTool(mcp.Tool{Name: "fail"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText("boom")
+		result.IsError = true
+		return result, nil
+	})
+	srv.AddResource(mcp.Resource{URI: "file:///readme", Name: "readme", MimeType: "text/plain"},
+		mcp.TextResourceContents{URI: "file:///readme", Text: "hello"})
+	srv.AddPrompt(mcp.Prompt{Name: "greet", Arguments: []mcp.PromptArgument{{Name: "name", Required: true}, {Name: "tone"}}}, nil)
+	srv.Start()
+	defer srv.Close()
+
+	if code, _, stderr := runCLI(t, "server", "add", "test", srv.URL); code != 0 {
+		t.Fatalf("server add failed: %s", stderr)
+	}
+
+	t.Run("list", func(t *testing.T) {
+		_, stdout, stderr := runCLI(t, "list", "tools", "test")
+		if !strings.Contains(stdout, "echo  Echoes its input\n") || strings.Contains(stdout, "at length") {
+			t.Errorf("Unexpected tools table: %q %s", stdout, stderr)
+		}
+		_, stdout, _ = runCLI(t, "list", "resources", srv.URL)
+		if !strings.Contains(stdout, "file:///readme  readme  text/plain") {
+			t.Errorf("Unexpected resources table: %q", stdout)
+		}
+		_, stdout, _ = runCLI(t, "list", "prompts", "test")
+		if !strings.Contains(stdout, "greet  name,tone?") {
+			t.Errorf("Unexpected prompts table: %q", stdout)
+		}
+
+		_, stdout, _ = runCLI(t, "list", "tools", "-json", "test")
+		var tools []mcp.Tool
+		if err := json.Unmarshal([]byte(stdout), &tools); err != nil || len(tools) != 2 {
+			t.Errorf("Expected a JSON array of 2 tools, got %q: %v", stdout, err)
+		}
+	})
+
+	t.Run("call", func(t *testing.T) {
+		code, stdout, stderr := runCLI(t, "call", "--args", `{"text":"hi"}`, "test", "echo")
+		if code != 0 || stdout != "hi\n" {
+			t.Errorf("Expected echoed text, got exit %d %q: %s", code, stdout, stderr)
+		}
+		code, stdout, _ = runCLI(t, "call", "test", "fail")
+		if code != 1 || stdout != "boom\n" {
+			t.Errorf("Expected a failing tool to print its content and exit 1, got exit %d %q", code, stdout)
+		}
+		if code, _, _ := runCLI(t, "call", "-args", "[", "test", "echo"); code != 1 {
+			t.Errorf("Expected invalid arguments to fail, got exit code %d", code)
+		}
+	})
+
+	t.Run("read", func(t *testing.T) {
+		_, stdout, stderr := runCLI(t, "read", "test", "file:///readme")
+		if stdout != "hello\n" {
+			t.Errorf("Expected resource text, got %q: %s", stdout, stderr)
+		}
+	})
+
+	t.Run("ping", func(t *testing.T) {
+		code, stdout, stderr := runCLI(t, "ping", "test")
+		if code != 0 || !strings.HasPrefix(stdout, "mcptest answered in ") {
+			t.Errorf("Unexpected ping output (exit %d): %q %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("stdio", func(t *testing.T) {
+		t.Setenv("MCPGOPHER_TEST_STDIO_SERVER", "1")
+		code, stdout, stderr := runCLI(t, "ping", "stdio:"+os.Args[0]+" -test.run=^TestStdioServerProcess$")
+		if code != 0 || !strings.HasPrefix(stdout, "mcptest answered in ") {
+			t.Errorf("Unexpected ping output (exit %d): %q %s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("generate", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "tools.go")
+		code, _, stderr := runCLI(t, "generate", "-package", "testtools", "-o", output, "test")
+		if code != 0 {
+			t.Fatalf("generate failed (exit %d): %s", code, stderr)
+		}
+		src, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("Failed to read bindings: %v", err)
+		}
+		for _, want := range []string{"package testtools\n", "func (c *Client) Echo(ctx context.Context)", "// Source: mcptest "} {
+			if !strings.Contains(string(src), want) {
+				t.Errorf("Expected bindings to contain %q, got:\n%s", want, src)
+			}
+		}
+	})
+}
+
+func TestRepl(t *testing.T) {
+	setupDirs(t)
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo", InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"},"times":{"type":"integer"}}}`)},
+		func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+			text, _ := arguments["text"].(string)
+			times, _ := arguments["times"].(float64)
+			return mcp.NewToolResultText(strings.Repeat(text, int(times))), nil
+		})
+	srv.AddTool(mcp.Tool{Name: "exit_code"}, nil)
+	srv.AddPrompt(mcp.Prompt{Name: "greet", Arguments: []mcp.PromptArgument{{Name: "language"}, {Name: "name"}}}, nil)
+	srv.HandleMethod(string(mcp.MethodCompleteList), func(ctx context.Context, request *mcptest.Request) (any, error) {
+		var result mcp.CompleteResult
+		result.Completion.Values = []string{"go", "gleam"}
+		return result, nil
+	})
+	srv.SetBehavior(string(mcp.MethodToolsCall), mcptest.Behavior{
+		Notifications: []mcp.JSONRPCNotification{{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			Method:  "notifications/message",
+			Params:  map[string]any{"data": "working"},
+		}},
+	})
+	srv.Start()
+	defer srv.Close()
+
+	t.Run("session", func(t *testing.T) {
+		code, stdout, stderr := runCLIWithInput(t, "call echo text=ab times=2\nbogus\nexit\n", "repl", srv.URL)
+		if code != 0 {
+			t.Fatalf("repl failed: %s", stderr)
+		}
+		for _, want := range []string{"Connected to mcptest", "abab", `<- notifications/message {"data":"working"}`, `unknown command "bogus"`} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("Expected output to contain %q, got %q", want, stdout)
+			}
+		}
+
+		path, _ := historyPath()
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != "call echo text=ab times=2\nbogus\nexit\n" {
+			t.Errorf("Unexpected history %q: %v", data, err)
+		}
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		c, err := connect(context.Background(), srv.URL)
+		if err != nil {
+			t.Fatalf("connect: %v", err)
+		}
+		defer c.Close()
+		var out bytes.Buffer
+		r := &repl{ctx: context.Background(), client: c, term: term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{strings.NewReader(""), &out}, "> ")}
+
+		tests := []struct {
+			line string
+			want string
+		}{
+			{"ca", "call "},
+			{"call ec", "call echo "},
+			{"call echo ", "call echo t"},
+			{"call echo text=hi t", "call echo text=hi times="},
+			{"prompt greet la", "prompt greet language="},
+			{"prompt greet language=", "prompt greet language=g"},
+			{"prompt greet language=go", "prompt greet language=go "},
+			{"read ", "read "},
+		}
+		for _, tt := range tests {
+			got, pos, ok := r.autoComplete(tt.line, len(tt.line), '\t')
+			if !ok || got != tt.want || pos != len(tt.want) {
+				t.Errorf("autoComplete(%q) = %q, %d, %v, want %q", tt.line, got, pos, ok, tt.want)
+			}
+		}
+
+		// Ambiguous words are listed
+		out.Reset()
+		if got, _, _ := r.autoComplete("p", 1, '\t'); got != "p" || !strings.Contains(out.String(), "prompts  prompt  ping") {
+			t.Errorf("Expected candidates to be listed, got %q and output %q", got, out.String())
+		}
+		if _, _, ok := r.autoComplete("c", 1, 'x'); ok {
+			t.Errorf("Expected keys other than tab to be left alone")
+		}
+	})
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestInspect(t *testing.T) {
+	setupDirs(t)
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, nil)
+	srv.SetInstructions("Use echo to echo.")
+	srv.Start()
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var stdout, stderr syncBuffer
+	done := make(chan int)
+	go func() {
+		done <- run(ctx, &cliEnv{stdin: strings.NewReader(""), stdout: &stdout, stderr: &stderr}, []string{"inspect", "-color=always", srv.URL})
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for srv.Notify("notifications/tools/list_changed", nil) == 0 {
+		select {
+		case <-deadline:
+			cancel()
+			t.Fatalf("Timed out waiting for the GET stream: %s", stderr.String())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	for !strings.Contains(stdout.String(), "notifications/tools/list_changed") {
+		select {
+		case <-deadline:
+			cancel()
+			t.Fatalf("Timed out waiting for the notification, got %q", stdout.String())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	cancel()
+	if code := <-done; code != 0 {
+		t.Errorf("Expected inspect to exit cleanly when interrupted, got %d: %s", code, stderr.String())
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"--> request      initialize id=",
+		"<-- response     initialize id=",
+		"Server: mcptest 0.0.1",
+		`"tools": {}`,
+		"Instructions:" + colorReset + "\nUse echo to echo.",
+		colorNotification,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got %q", want, out)
+		}
+	}
+
+	if code, _, _ := runCLI(t, "inspect", "-color=sometimes", srv.URL); code != 1 {
+		t.Errorf("Expected an invalid -color to fail, got exit code %d", code)
+	}
+}
+
+func TestMitm(t *testing.T) {
+	setupDirs(t)
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("hello"), nil
+	})
+	srv.Start()
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL)
+
+	t.Run("Logs both directions", func(t *testing.T) {
+		var out syncBuffer
+		relay := httptest.NewServer(newMITM(target, &trafficPrinter{w: &out}, nil))
+		defer relay.Close()
+
+		if code, stdout, stderr := runCLI(t, "call", relay.URL, "echo"); code != 0 || !strings.Contains(stdout, "hello") {
+			t.Fatalf("call through the relay failed: %s%s", stdout, stderr)
+		}
+		for _, want := range []string{
+			"--> request      initialize id=",
+			"<-- response     initialize id=",
+			"--> notification notifications/initialized",
+			`--> request      tools/call id=`,
+			`"name":"echo"`,
+			`<-- response     tools/call id=`,
+			`"text":"hello"`,
+		} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("Expected output to contain %q, got %q", want, out.String())
+			}
+		}
+	})
+
+	t.Run("Filters by method", func(t *testing.T) {
+		var out syncBuffer
+		relay := httptest.NewServer(newMITM(target, &trafficPrinter{w: &out}, []string{"tools/*"}))
+		defer relay.Close()
+
+		if code, stdout, stderr := runCLI(t, "call", relay.URL, "echo"); code != 0 {
+			t.Fatalf("call through the relay failed: %s%s", stdout, stderr)
+		}
+		if got := out.String(); !strings.Contains(got, "<-- response     tools/call") || strings.Contains(got, "initialize") {
+			t.Errorf("Expected only tools/call, got %q", got)
+		}
+	})
+
+	t.Run("SSE events", func(t *testing.T) {
+		var events []string
+		tap := &sseTap{
+			ReadCloser: io.NopCloser(strings.NewReader("event: message\r\ndata: {\"a\":\ndata: 1}\r\n\r\n: comment\n\ndata: 2\n\n")),
+			event:      func(data []byte) { events = append(events, string(data)) },
+		}
+		body, _ := io.ReadAll(iotest.OneByteReader(tap))
+		if len(body) == 0 || !reflect.DeepEqual(events, []string{"{\"a\":\n1}", "2"}) {
+			t.Errorf("Unexpected events: %q", events)
+		}
+	})
+
+	if code, _, _ := runCLI(t, "mitm", "-listen", ":0"); code != 2 {
+		t.Errorf("Expected mitm without -target to be a usage error, got exit code %d", code)
+	}
+}
+
+func TestTUI(t *testing.T) {
+	setupDirs(t)
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo", Description: "Echoes its input", InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"}}}`)}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("echo: " + arguments["text"].(string)), nil
+	})
+	srv.AddResource(mcp.Resource{URI: "file:///notes.txt", Name: "notes"}, mcp.TextResourceContents{URI: "file:///notes.txt", Text: "remember the milk"})
+	srv.AddPrompt(mcp.Prompt{Name: "greet", Arguments: []mcp.PromptArgument{{Name: "name", Required: true}}}, func(ctx context.Context, arguments map[string]string) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{Messages: []mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("Hello "+arguments["name"]))}}, nil
+	})
+	srv.Start()
+	defer srv.Close()
+
+	t.Run("Panes and composer", func(t *testing.T) {
+		keys := "\rhi\r" + // call echo, its composer filled with text=
+			"\t\r" + // read the resource
+			"\x1b[C\rAl\x1b" + // compose greet, cancel
+			"\rBob\r" + // get greet
+			"q"
+		code, stdout, stderr := runCLIWithInput(t, keys, "tui", srv.URL)
+		if code != 0 {
+			t.Fatalf("tui failed: %s", stderr)
+		}
+		for _, want := range []string{
+			"mcptest 0.0.1  [Tools]  Resources   Prompts ",
+			"> echo",
+			"│ Echoes its input",
+			"echo> text=_",
+			"│ echo: hi",
+			" Tools  [Resources]",
+			"│ remember the milk",
+			"greet> name=Bob_",
+			"│ user: Hello Bob",
+			tuiHelp,
+		} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("Expected output to contain %q", want)
+			}
+		}
+		if strings.Contains(stdout, "error:") || strings.Contains(stdout, "Hello Al") {
+			t.Errorf("Unexpected error in output: %q", stdout)
+		}
+	})
+
+	t.Run("Notification feed", func(t *testing.T) {
+		var out bytes.Buffer
+		ui := &tui{w: &out, size: func() (int, int) { return 60, 12 }, title: "test"}
+		for i := range 5 {
+			ui.notify(mcp.Notification{Method: "notifications/message", Params: map[string]any{"n": i}})
+		}
+		lines := ui.render(60, 12)
+		if len(lines) != 12 {
+			t.Fatalf("Expected 12 lines, got %d", len(lines))
+		}
+		feed := strings.Join(lines[len(lines)-4:len(lines)-1], "\n")
+		if strings.Contains(feed, `{"n":1}`) || !strings.Contains(feed, `notifications/message {"n":2}`) || !strings.Contains(feed, `{"n":4}`) {
+			t.Errorf("Expected the feed to show the last notifications, got %q", feed)
+		}
+		for _, line := range lines {
+			if n := utf8.RuneCountInString(line); n > 60 {
+				t.Errorf("Expected lines of at most 60 characters, got %d: %q", n, line)
+			}
+		}
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		r := bufio.NewReader(strings.NewReader("a\x1b[A\x1b[1;5B\x1bq\t\x7f\x03"))
+		var keys []string
+		for {
+			key, err := readKey(r)
+			if err != nil {
+				break
+			}
+			keys = append(keys, key)
+		}
+		want := []string{"a", "up", "down", "esc", "q", "tab", "backspace", "ctrl-c"}
+		if !reflect.DeepEqual(keys, want) {
+			t.Errorf("Expected %q, got %q", want, keys)
+		}
+	})
+}
+
+func TestBench(t *testing.T) {
+	setupDirs(t)
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		if arguments["fail"] == true {
+			return &mcp.CallToolResult{Content: []mcp.Content{mcp.NewTextContent("failed")}, IsError: true}, nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv.AddResource(mcp.Resource{URI: "file:///a.txt", Name: "a"}, mcp.TextResourceContents{URI: "file:///a.txt", Text: "a"})
+	srv.Start()
+	defer srv.Close()
+
+	code, stdout, stderr := runCLI(t, "bench", "-c", "3", "-n", "12", "-args", `{"fail":true}`, srv.URL, "echo")
+	if code != 0 {
+		t.Fatalf("bench failed: %s", stderr)
+	}
+	for _, want := range []string{"Requests:    12 in ", "Errors:      12 (100.00%)", "Reconnects:  0", "Latency:     min ", "    12  tool reported an error"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected output to contain %q, got %q", want, stdout)
+		}
+	}
+
+	code, stdout, stderr = runCLI(t, "bench", "-json", "-n", "5", "-read", srv.URL, "file:///a.txt")
+	if code != 0 {
+		t.Fatalf("bench -read failed: %s", stderr)
+	}
+	var report struct {
+		Requests int `json:"requests"`
+		Errors   int `json:"errors"`
+		P99      int `json:"p99"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil || report.Requests != 5 || report.Errors != 0 || report.P99 <= 0 {
+		t.Errorf("Unexpected JSON report %q: %v", stdout, err)
+	}
+
+	if code, _, _ := runCLI(t, "bench", "-read", "-args", "{}", srv.URL, "file:///a.txt"); code != 2 {
+		t.Errorf("Expected -read with -args to be a usage error, got exit code %d", code)
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	setupDirs(t)
+
+	t.Run("Healthy server", func(t *testing.T) {
+		srv := mcptest.NewServer()
+		srv.AddTool(mcp.Tool{Name: "echo", InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"}},"required":["text"]}`)}, nil)
+		srv.AddResource(mcp.Resource{URI: "file:///a.txt", Name: "a"}, mcp.TextResourceContents{URI: "file:///a.txt", Text: "a"})
+		srv.Start()
+		defer srv.Close()
+
+		code, stdout, stderr := runCLI(t, "doctor", srv.URL)
+		if code != 0 {
+			t.Fatalf("doctor failed: %s%s", stdout, stderr)
+		}
+		for _, want := range []string{
+			"ok       content-type initialize was answered with application/json",
+			"ok       session      ",
+			"ok       protocol     the server speaks 2025-03-26",
+			"ok       tools        1 tools in 1 page(s)",
+			"ok       schemas      ",
+			"ok       resources    1 resources in 1 page(s)",
+			"ok       ping         ",
+		} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("Expected output to contain %q, got %q", want, stdout)
+			}
+		}
+	})
+
+	t.Run("Broken server", func(t *testing.T) {
+		srv := mcptest.NewServer()
+		srv.HandleMethod("initialize", func(ctx context.Context, request *mcptest.Request) (any, error) {
+			return mcp.InitializeResult{
+				ProtocolVersion: "2024-11-05",
+				ServerInfo:      mcp.Implementation{Name: "broken", Version: "1"},
+				Capabilities:    mcp.ServerCapabilities{Tools: &mcp.ToolsCapabilities{}, Resources: &mcp.ResourcesCapabilities{}},
+			}, nil
+		})
+		srv.HandleMethod("tools/list", func(ctx context.Context, request *mcptest.Request) (any, error) {
+			var params struct {
+				Cursor string `json:"cursor"`
+			}
+			_ = json.Unmarshal(request.Params, &params)
+			if params.Cursor == "" {
+				return mcp.ListToolsResult{Tools: []mcp.Tool{{Name: "good", InputSchema: json.RawMessage(`{"type":"object"}`)}}, PaginatedResult: mcp.PaginatedResult{NextCursor: "2"}}, nil
+			}
+			return mcp.ListToolsResult{Tools: []mcp.Tool{{Name: "bad", InputSchema: json.RawMessage(`{"type":"string"}`)}}}, nil
+		})
+		srv.HandleMethod("resources/list", func(ctx context.Context, request *mcptest.Request) (any, error) {
+			return mcp.ListResourcesResult{PaginatedResult: mcp.PaginatedResult{NextCursor: "again"}}, nil
+		})
+		srv.SetBehavior("ping", mcptest.Behavior{Delay: 50 * time.Millisecond})
+		srv.Start()
+		defer srv.Close()
+
+		code, stdout, _ := runCLI(t, "doctor", "-slow", "10ms", srv.URL)
+		if code != 1 {
+			t.Errorf("Expected doctor to fail, got exit code %d", code)
+		}
+		for _, want := range []string{
+			"warning  protocol     the server answered 2024-11-05 to a request for 2025-03-26",
+			"ok       tools        2 tools in 2 page(s)",
+			"error    schemas      tool bad: inputSchema has type string, not object",
+			`error    resources    the cursor "again" of page 2 was already handed out`,
+			"warning  ping         ping took ",
+			"fix: ",
+		} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("Expected output to contain %q, got %q", want, stdout)
+			}
+		}
+	})
+
+	t.Run("Not an MCP endpoint", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = io.WriteString(w, "hello")
+		}))
+		defer srv.Close()
+
+		code, stdout, _ := runCLI(t, "doctor", "-json", srv.URL)
+		if code != 1 {
+			t.Errorf("Expected doctor to fail, got exit code %d", code)
+		}
+		var findings []finding
+		if err := json.Unmarshal([]byte(stdout), &findings); err != nil {
+			t.Fatalf("Expected JSON findings, got %q: %v", stdout, err)
+		}
+		got := map[string]string{}
+		for _, f := range findings {
+			if _, ok := got[f.Check]; !ok {
+				got[f.Check] = f.Severity
+			}
+		}
+		want := map[string]string{"content-type": "error", "http": "error", "session": "warning", "connect": "error"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected findings %v, got %v", want, got)
+		}
+	})
+}
+
+func TestSchema(t *testing.T) {
+	setupDirs(t)
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{
+		Name:         "weather/forecast",
+		Description:  "Forecasts the weather",
+		InputSchema:  json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}`),
+		OutputSchema: json.RawMessage(`{"type":"object","properties":{"celsius":{"type":"number"}}}`),
+	}, nil)
+	srv.AddTool(mcp.Tool{Name: "ping"}, nil)
+	srv.Start()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	code, stdout, stderr := runCLI(t, "schema", "-o", dir, srv.URL)
+	if code != 0 {
+		t.Fatalf("schema failed: %s", stderr)
+	}
+	want := []string{"weather_forecast.input.json", "weather_forecast.output.json", "ping.input.json"}
+	if got := strings.Fields(stdout); len(got) != len(want) {
+		t.Errorf("Expected %d files, got %q", len(want), stdout)
+	}
+	for _, name := range want {
+		if !strings.Contains(stdout, filepath.Join(dir, name)) {
+			t.Errorf("Expected %s to be written, got %q", name, stdout)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "weather_forecast.output.json"))
+	if err != nil || !strings.Contains(string(data), `"celsius": {`) {
+		t.Errorf("Expected the indented output schema, got %q: %v", data, err)
+	}
+
+	for format, want := range map[string]string{
+		"openai":    `"type": "function"`,
+		"anthropic": `"input_schema": {`,
+		"gemini":    `"parameters": {`,
+	} {
+		if code, _, stderr := runCLI(t, "schema", "-format", format, "-o", dir, srv.URL); code != 0 {
+			t.Fatalf("schema -format %s failed: %s", format, stderr)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "weather_forecast."+format+".json"))
+		if err != nil || !strings.Contains(string(data), want) || !strings.Contains(string(data), `"city"`) {
+			t.Errorf("Expected a %s tool definition, got %q: %v", format, data, err)
+		}
+	}
+
+	if code, _, _ := runCLI(t, "schema", "-format", "yaml", "-o", dir, srv.URL); code != 1 {
+		t.Errorf("Expected an invalid -format to fail, got exit code %d", code)
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+var mitmCommand = &command{
+	name:       "mitm",
+	usage:      "mitm [-listen <addr>] -target <url> [-method <pattern,...>] [-color=auto|always|never]",
+	summary:    "relay HTTP traffic to a server, logging its messages",
+	valueFlags: []string{"listen", "target", "method"},
+	run:        runMITM,
+}
+
+// runMITM relays Streamable HTTP traffic to the target server, printing the
+// JSON-RPC messages going through in both directions.
+func runMITM(ctx context.Context, env *cliEnv, args []string) error {
+	fs := flag.NewFlagSet("mitm", flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	listen := fs.String("listen", ":7000", "address to serve on; point the client there")
+	target := fs.String("target", "", "URL of the MCP endpoint to relay to")
+	methods := fs.String("method", "", "only log these methods, comma-separated; a trailing * matches a prefix, as in notifications/*")
+	colorMode := fs.String("color", "auto", "color output: auto, always or never")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *target == "" || fs.NArg() != 0 {
+		return errUsage
+	}
+	targetURL, err := url.Parse(*target)
+	if err != nil || targetURL.Scheme == "" || targetURL.Host == "" {
+		return fmt.Errorf("invalid -target %q", *target)
+	}
+
+	printer, err := newTrafficPrinter(env.stdout, *colorMode)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stderr, "relaying http://%s to %s\n", listener.Addr(), targetURL)
+	relay := newMITM(targetURL, printer, splitList(*methods))
+	return serveUntilDone(ctx, listener, relay)
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// mitm relays HTTP requests to a target server unchanged, printing the
+// JSON-RPC messages of the request bodies and of the JSON or SSE responses.
+type mitm struct {
+	proxy   *httputil.ReverseProxy
+	printer *trafficPrinter
+	methods []string
+	// pending maps the IDs of the requests in flight, prefixed with the
+	// arrow of their direction, to their method, to name their responses
+	pending sync.Map
+}
+
+func newMITM(target *url.URL, printer *trafficPrinter, methods []string) *mitm {
+	m := &mitm{printer: printer, methods: methods}
+	m.proxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.Out.URL.Path = target.Path
+			r.Out.URL.RawPath = target.RawPath
+			r.Out.Host = target.Host
+			// Let the transport negotiate and decode compression, so that
+			// responses can be read
+			r.Out.Header.Del("Accept-Encoding")
+		},
+		ModifyResponse: m.tapResponse,
+		// SSE events are relayed as they arrive
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			m.printer.print(colorError, "<--", "failed", fmt.Sprintf("%s %s: %v", r.Method, target, err), nil)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		},
+	}
+	return m
+}
+
+func (m *mitm) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		m.messages("-->", decodeBody(body, r.Header.Get("Content-Encoding")))
+	}
+	m.proxy.ServeHTTP(w, r)
+}
+
+// decodeBody returns body decoded from its content encoding, if supported.
+func decodeBody(body []byte, encoding string) []byte {
+	if !strings.EqualFold(encoding, "gzip") {
+		return body
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return body
+	}
+	decoded, err := io.ReadAll(zr)
+	if err != nil {
+		return body
+	}
+	return decoded
+}
+
+// tapResponse prints the messages of a JSON response, or of an SSE stream as
+// its events go through.
+func (m *mitm) tapResponse(resp *http.Response) error {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "text/event-stream":
+		resp.Body = &sseTap{ReadCloser: resp.Body, event: func(data []byte) { m.messages("<--", data) }}
+	case mediaType == "application/json":
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		m.messages("<--", body)
+	case resp.StatusCode >= http.StatusBadRequest:
+		m.printer.print(colorError, "<--", "http", resp.Status, nil)
+	}
+	return nil
+}
+
+// jsonrpcMessage is any JSON-RPC message.
+type jsonrpcMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *mcp.Error      `json:"error"`
+}
+
+// messages prints the message, or batch of messages, data holds.
+func (m *mitm) messages(arrow string, data []byte) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return
+	}
+	if data[0] != '[' {
+		m.message(arrow, data)
+		return
+	}
+	var batch []json.RawMessage
+	if err := json.Unmarshal(data, &batch); err != nil {
+		m.printer.print(colorError, arrow, "invalid", err.Error(), nil)
+		return
+	}
+	for _, item := range batch {
+		m.message(arrow, item)
+	}
+}
+
+// message prints a JSON-RPC message, named after its method or, for a
+// response, the method of its request.
+func (m *mitm) message(arrow string, data []byte) {
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		m.printer.print(colorError, arrow, "invalid", err.Error(), nil)
+		return
+	}
+	id := string(msg.ID)
+	if id == "null" {
+		id = ""
+	}
+
+	method, kind, color, payload := msg.Method, "", "", []byte(msg.Params)
+	switch {
+	case msg.Method != "" && id != "":
+		m.pending.Store(arrow+id, msg.Method)
+		kind, color = "request", colorRequest
+		if arrow == "<--" {
+			color = colorServer
+		}
+	case msg.Method != "":
+		kind, color = "notification", colorNotification
+	default:
+		// The request of a response went the other way
+		request := "-->" + id
+		if arrow == "-->" {
+			request = "<--" + id
+		}
+		if name, ok := m.pending.LoadAndDelete(request); ok {
+			method = name.(string)
+		}
+		kind, color, payload = "response", colorResponse, msg.Result
+		if msg.Error != nil {
+			kind, color = "error", colorError
+			payload, _ = json.Marshal(msg.Error)
+		}
+	}
+	if !m.logs(method) {
+		return
+	}
+
+	detail := method
+	if id != "" {
+		detail += " id=" + id
+	}
+	var value any
+	if len(payload) > 0 {
+		value = json.RawMessage(payload)
+	}
+	m.printer.print(color, arrow, kind, detail, value)
+}
+
+// logs reports whether messages of method are printed.
+func (m *mitm) logs(method string) bool {
+	if len(m.methods) == 0 {
+		return true
+	}
+	for _, pattern := range m.methods {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(method, prefix) || pattern == method {
+			return true
+		}
+	}
+	return false
+}
+
+// sseTap passes an SSE stream through, calling event with the data of every
+// event read.
+type sseTap struct {
+	io.ReadCloser
+	event func(data []byte)
+	// line holds the end of the stream read so far not ending a line
+	line []byte
+	data bytes.Buffer
+}
+
+func (t *sseTap) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	t.line = append(t.line, p[:n]...)
+	for {
+		i := bytes.IndexByte(t.line, '\n')
+		if i < 0 {
+			break
+		}
+		t.parse(bytes.TrimSuffix(t.line[:i], []byte("\r")))
+		t.line = t.line[i+1:]
+	}
+	return n, err
+}
+
+// parse handles a line of the stream.
+func (t *sseTap) parse(line []byte) {
+	if len(line) == 0 {
+		if t.data.Len() > 0 {
+			t.event(bytes.Clone(t.data.Bytes()))
+		}
+		t.data.Reset()
+		return
+	}
+	if value, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+		if t.data.Len() > 0 {
+			t.data.WriteByte('\n')
+		}
+		t.data.Write(bytes.TrimPrefix(value, []byte(" ")))
+	}
+}
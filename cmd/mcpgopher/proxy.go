@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/proxy"
+)
+
+var proxyCommand = &command{
+	name:    "proxy",
+	usage:   "proxy <alias|url> | proxy -listen <addr> <command> [args...]",
+	summary: "bridge stdio and HTTP servers",
+	args:    []argKind{argAlias},
+	run:     runProxy,
+}
+
+// runProxy serves stdio and forwards to an HTTP server, or with -listen,
+// serves HTTP and forwards to a stdio server it starts.
+func runProxy(ctx context.Context, env *cliEnv, args []string) error {
+	fs := flag.NewFlagSet("proxy", flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	listen := fs.String("listen", "", "serve Streamable HTTP on this address and forward to a stdio server command")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *listen != "" {
+		if fs.NArg() == 0 {
+			return errUsage
+		}
+		return proxyHTTPToStdio(ctx, env, *listen, fs.Arg(0), fs.Args()[1:])
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	server, err := resolveServer(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if server.URL == "" {
+		return fmt.Errorf("%s is not an HTTP server", fs.Arg(0))
+	}
+	return proxyStdioToHTTP(ctx, env, server)
+}
+
+// proxyStdioToHTTP forwards the stdio client of the process to server.
+func proxyStdioToHTTP(ctx context.Context, env *cliEnv, server serverConfig) error {
+	upstream, err := transport.NewStreamableHTTP(server.URL, transport.WithHTTPHeaders(server.Headers))
+	if err != nil {
+		return err
+	}
+	defer upstream.Close()
+
+	err = proxy.ServeStdio(ctx, env.stdin, env.stdout, upstream)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// proxyHTTPToStdio starts the stdio server command and serves it over HTTP on addr.
+func proxyHTTPToStdio(ctx context.Context, env *cliEnv, addr, command string, args []string) error {
+	upstream := transport.NewStdio(command, args, transport.WithStdioStderr(env.stderr))
+	if err := upstream.Start(ctx); err != nil {
+		return err
+	}
+	defer upstream.Close()
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stderr, "serving %s on http://%s\n", command, listener.Addr())
+
+	return serveUntilDone(ctx, listener, proxy.NewHTTPHandler(upstream))
+}
+
+// serveUntilDone serves handler on listener until ctx is done.
+func serveUntilDone(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	srv := &http.Server{Handler: handler}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,457 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+	"golang.org/x/term"
+)
+
+var replCommand = &command{
+	name:    "repl",
+	usage:   "repl <server>",
+	summary: "start an interactive session with a server",
+	args:    []argKind{argAlias},
+	run:     runRepl,
+}
+
+// historySize bounds the lines kept in the history file.
+const historySize = 500
+
+// replCompleteTimeout bounds the requests made while completing, so a slow
+// server never freezes the prompt.
+const replCompleteTimeout = 2 * time.Second
+
+const replHelp = `Commands:
+  tools                        list tools
+  resources                    list resources
+  prompts                      list prompts
+  call <tool> [key=value...]   call a tool; values are JSON or plain strings
+  call <tool> {json}           call a tool with a JSON object of arguments
+  read <uri>                   read a resource
+  prompt <name> [key=value...] get a prompt
+  ping                         ping the server
+  help                         show this help
+  exit                         end the session
+Tab completes commands, names and arguments. Notifications are shown as they arrive.
+`
+
+var replCommands = []string{"tools", "resources", "prompts", "call", "read", "prompt", "ping", "help", "exit"}
+
+// repl is an interactive session with a server.
+type repl struct {
+	ctx    context.Context
+	client *client.HTTPClient
+	term   *term.Terminal
+}
+
+func runRepl(ctx context.Context, env *cliEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	c, err := connect(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	// Raw mode lets the terminal handle tab and the arrow keys itself
+	if f, ok := env.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return fmt.Errorf("failed to set up terminal: %w", err)
+		}
+		defer term.Restore(int(f.Fd()), state)
+	}
+	r := &repl{
+		ctx:    ctx,
+		client: c,
+		term: term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{env.stdin, env.stdout}, args[0]+"> "),
+	}
+	r.term.AutoCompleteCallback = r.autoComplete
+	loadHistory(r.term.History)
+
+	unsubscribe := c.Subscribe("*", r.printNotification)
+	defer unsubscribe()
+
+	info := c.ServerInfo()
+	fmt.Fprintf(r.term, "Connected to %s %s. Type help for commands.\n", info.Name, info.Version)
+	for {
+		line, err := r.term.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		appendHistory(line)
+		if line == "exit" || line == "quit" {
+			return nil
+		}
+		if err := r.exec(line); err != nil {
+			fmt.Fprintf(r.term, "error: %v\n", err)
+		}
+	}
+}
+
+// exec runs a command line of the session.
+func (r *repl) exec(line string) error {
+	name, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+	switch name {
+	case "help":
+		_, err := io.WriteString(r.term, replHelp)
+		return err
+	case "tools":
+		tools, err := listTools(r.ctx, r.client)
+		if err != nil {
+			return err
+		}
+		return printTools(r.term, tools)
+	case "resources":
+		resources, err := listResources(r.ctx, r.client)
+		if err != nil {
+			return err
+		}
+		return printResources(r.term, resources)
+	case "prompts":
+		prompts, err := listPrompts(r.ctx, r.client)
+		if err != nil {
+			return err
+		}
+		return printPrompts(r.term, prompts)
+	case "call":
+		tool, rawArgs, _ := strings.Cut(rest, " ")
+		if tool == "" {
+			return errors.New("usage: call <tool> [key=value...]")
+		}
+		arguments, err := parseArguments(rawArgs)
+		if err != nil {
+			return err
+		}
+		result, err := r.client.CallTool(r.ctx, tool, arguments)
+		if err != nil {
+			return err
+		}
+		if err := printContent(r.term, result.Content); err != nil {
+			return err
+		}
+		if result.IsError {
+			return fmt.Errorf("tool %s reported an error", tool)
+		}
+		return nil
+	case "read":
+		if rest == "" {
+			return errors.New("usage: read <uri>")
+		}
+		result, err := r.client.ReadResource(r.ctx, rest)
+		if err != nil {
+			return err
+		}
+		printResourceContents(r.term, result.Contents)
+		return nil
+	case "prompt":
+		prompt, rawArgs, _ := strings.Cut(rest, " ")
+		if prompt == "" {
+			return errors.New("usage: prompt <name> [key=value...]")
+		}
+		arguments, err := parsePromptArguments(rawArgs)
+		if err != nil {
+			return err
+		}
+		result, err := r.client.GetPrompt(r.ctx, prompt, arguments)
+		if err != nil {
+			return err
+		}
+		for _, message := range result.Messages {
+			fmt.Fprintf(r.term, "%s: ", message.Role)
+			if err := printContent(r.term, []mcp.Content{message.Content}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "ping":
+		latency, err := r.client.Ping(r.ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(r.term, "pong in %s\n", latency.Round(time.Microsecond))
+		return nil
+	}
+	return fmt.Errorf("unknown command %q, type help for commands", name)
+}
+
+// parseArguments parses tool arguments given either as a JSON object or as
+// key=value fields whose values are decoded as JSON when they can be.
+func parseArguments(raw string) (map[string]any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	arguments := map[string]any{}
+	if strings.HasPrefix(raw, "{") {
+		if err := json.Unmarshal([]byte(raw), &arguments); err != nil {
+			return nil, fmt.Errorf("invalid JSON arguments: %w", err)
+		}
+		return arguments, nil
+	}
+	for _, field := range strings.Fields(raw) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("argument %q is not in the form key=value", field)
+		}
+		var decoded any
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			decoded = value
+		}
+		arguments[key] = decoded
+	}
+	return arguments, nil
+}
+
+// parsePromptArguments parses prompt arguments given as key=value fields.
+func parsePromptArguments(raw string) (map[string]string, error) {
+	arguments := map[string]string{}
+	for _, field := range strings.Fields(raw) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("argument %q is not in the form key=value", field)
+		}
+		arguments[key] = value
+	}
+	return arguments, nil
+}
+
+// printNotification shows a notification above the prompt.
+func (r *repl) printNotification(notification mcp.Notification) {
+	var params []byte
+	if notification.Params != nil {
+		params, _ = json.Marshal(notification.Params)
+	}
+	fmt.Fprintf(r.term, "<- %s %s\n", notification.Method, params)
+}
+
+// autoComplete completes the word before the cursor when tab is pressed. A
+// single candidate replaces the word; several are extended to their common
+// prefix, or listed when that adds nothing.
+func (r *repl) autoComplete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	prefix := line[:pos]
+	words := strings.Fields(prefix)
+	current := ""
+	if len(words) > 0 && !strings.HasSuffix(prefix, " ") {
+		current, words = words[len(words)-1], words[:len(words)-1]
+	}
+
+	var matches []string
+	for _, candidate := range r.candidates(words, current) {
+		if strings.HasPrefix(candidate, current) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return line, pos, true
+	}
+
+	completion := matches[0]
+	if len(matches) == 1 {
+		if !strings.HasSuffix(completion, "=") {
+			completion += " "
+		}
+	} else {
+		for _, match := range matches[1:] {
+			for !strings.HasPrefix(match, completion) {
+				completion = completion[:len(completion)-1]
+			}
+		}
+		if completion == current {
+			fmt.Fprintln(r.term, strings.Join(matches, "  "))
+			return line, pos, true
+		}
+	}
+	start := pos - len(current)
+	return line[:start] + completion + line[pos:], start + len(completion), true
+}
+
+// candidates returns the completions of the word following words.
+func (r *repl) candidates(words []string, current string) []string {
+	if len(words) == 0 {
+		return replCommands
+	}
+	ctx, cancel := context.WithTimeout(r.ctx, replCompleteTimeout)
+	defer cancel()
+
+	switch words[0] {
+	case "call":
+		tools, err := listTools(ctx, r.client)
+		if err != nil {
+			return nil
+		}
+		if len(words) == 1 {
+			names := make([]string, len(tools))
+			for i, tool := range tools {
+				names[i] = tool.Name
+			}
+			return names
+		}
+		if strings.Contains(current, "=") {
+			return nil
+		}
+		for _, tool := range tools {
+			if tool.Name == words[1] {
+				return argumentNames(schemaProperties(tool.InputSchema), words[2:])
+			}
+		}
+	case "read":
+		if len(words) > 1 {
+			return nil
+		}
+		resources, err := listResources(ctx, r.client)
+		if err != nil {
+			return nil
+		}
+		uris := make([]string, len(resources))
+		for i, resource := range resources {
+			uris[i] = resource.URI
+		}
+		return uris
+	case "prompt":
+		prompts, err := listPrompts(ctx, r.client)
+		if err != nil {
+			return nil
+		}
+		if len(words) == 1 {
+			names := make([]string, len(prompts))
+			for i, prompt := range prompts {
+				names[i] = prompt.Name
+			}
+			return names
+		}
+		// Values come from the server's completion/complete
+		if name, value, ok := strings.Cut(current, "="); ok {
+			result, err := r.client.Complete(ctx, mcp.NewPromptReference(words[1]), name, value)
+			if err != nil {
+				return nil
+			}
+			values := make([]string, len(result.Completion.Values))
+			for i, v := range result.Completion.Values {
+				values[i] = name + "=" + v
+			}
+			return values
+		}
+		for _, prompt := range prompts {
+			if prompt.Name == words[1] {
+				names := make([]string, len(prompt.Arguments))
+				for i, arg := range prompt.Arguments {
+					names[i] = arg.Name
+				}
+				return argumentNames(names, words[2:])
+			}
+		}
+	}
+	return nil
+}
+
+// argumentNames returns "name=" for each argument not already given in fields.
+func argumentNames(names []string, fields []string) []string {
+	given := map[string]bool{}
+	for _, field := range fields {
+		key, _, _ := strings.Cut(field, "=")
+		given[key] = true
+	}
+	var candidates []string
+	for _, name := range names {
+		if !given[name] {
+			candidates = append(candidates, name+"=")
+		}
+	}
+	return candidates
+}
+
+// schemaProperties returns the sorted property names of a JSON Schema object.
+func schemaProperties(schema json.RawMessage) []string {
+	var object struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(schema, &object); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(object.Properties))
+	for name := range object.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func historyPath() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history"), nil
+}
+
+// loadHistory fills history with the lines of past sessions, trimming the
+// file to the last historySize lines. Errors only cost the history.
+func loadHistory(history term.History) {
+	path, err := historyPath()
+	if err != nil {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	f.Close()
+
+	if len(lines) > historySize {
+		lines = lines[len(lines)-historySize:]
+		_ = os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+	}
+	for _, line := range lines {
+		history.Add(line)
+	}
+}
+
+// appendHistory records a line in the history file.
+func appendHistory(line string) {
+	path, err := historyPath()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+var listCommand = &command{
+	name:    "list",
+	summary: "list the tools, resources or prompts of a server",
+	subcommands: []*command{
+		{
+			name:  "tools",
+			usage: "list tools [-json] <server>",
+			args:  []argKind{argAlias},
+			run:   runListTools,
+		},
+		{
+			name:  "resources",
+			usage: "list resources [-json] <server>",
+			args:  []argKind{argAlias},
+			run:   runListResources,
+		},
+		{
+			name:  "prompts",
+			usage: "list prompts [-json] <server>",
+			args:  []argKind{argAlias},
+			run:   runListPrompts,
+		},
+	},
+}
+
+var callCommand = &command{
+	name:       "call",
+	usage:      "call [-json] [-args JSON] <server> <tool>",
+	summary:    "call a tool",
+	args:       []argKind{argAlias, argTool},
+	valueFlags: []string{"args"},
+	run:        runCall,
+}
+
+var readCommand = &command{
+	name:    "read",
+	usage:   "read [-json] <server> <uri>",
+	summary: "read a resource",
+	args:    []argKind{argAlias, argNone},
+	run:     runRead,
+}
+
+var pingCommand = &command{
+	name:    "ping",
+	usage:   "ping <server>",
+	summary: "check that a server answers",
+	args:    []argKind{argAlias},
+	run:     runPing,
+}
+
+// serverFlags parses the flags shared by the commands talking to a server,
+// whose first positional argument is an alias, a URL or a "stdio:" command line.
+type serverFlags struct {
+	*flag.FlagSet
+	json bool
+}
+
+func newServerFlags(name string, env *cliEnv) *serverFlags {
+	fs := &serverFlags{FlagSet: flag.NewFlagSet(name, flag.ContinueOnError)}
+	fs.SetOutput(env.stderr)
+	fs.BoolVar(&fs.json, "json", false, "print the result as JSON")
+	return fs
+}
+
+// connect resolves the server argument and connects to it.
+func connect(ctx context.Context, arg string) (*client.HTTPClient, error) {
+	server, err := resolveServer(arg)
+	if err != nil {
+		return nil, err
+	}
+	c, err := server.Connect(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", arg, err)
+	}
+	return c, nil
+}
+
+func runListTools(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newServerFlags("list tools", env)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	c, err := connect(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	tools, err := listTools(ctx, c)
+	if err != nil {
+		return err
+	}
+	if fs.json {
+		return printJSON(env.stdout, tools)
+	}
+	return printTools(env.stdout, tools)
+}
+
+func runListResources(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newServerFlags("list resources", env)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	c, err := connect(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	resources, err := listResources(ctx, c)
+	if err != nil {
+		return err
+	}
+	if fs.json {
+		return printJSON(env.stdout, resources)
+	}
+	return printResources(env.stdout, resources)
+}
+
+func runListPrompts(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newServerFlags("list prompts", env)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	c, err := connect(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	prompts, err := listPrompts(ctx, c)
+	if err != nil {
+		return err
+	}
+	if fs.json {
+		return printJSON(env.stdout, prompts)
+	}
+	return printPrompts(env.stdout, prompts)
+}
+
+// runCall calls a tool and prints its text content, or the whole result with
+// -json. A result flagged as an error makes the command fail.
+func runCall(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newServerFlags("call", env)
+	rawArgs := fs.String("args", "", "tool arguments as a JSON object")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errUsage
+	}
+
+	var arguments map[string]any
+	if *rawArgs != "" {
+		if err := json.Unmarshal([]byte(*rawArgs), &arguments); err != nil {
+			return fmt.Errorf("invalid -args: %w", err)
+		}
+	}
+
+	c, err := connect(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	result, err := c.CallTool(ctx, fs.Arg(1), arguments)
+	if err != nil {
+		return err
+	}
+	if fs.json {
+		err = printJSON(env.stdout, result)
+	} else {
+		err = printContent(env.stdout, result.Content)
+	}
+	if err != nil {
+		return err
+	}
+	if result.IsError {
+		return fmt.Errorf("tool %s reported an error", fs.Arg(1))
+	}
+	return nil
+}
+
+// runRead reads a resource and prints its text contents, or the whole result with -json.
+func runRead(ctx context.Context, env *cliEnv, args []string) error {
+	fs := newServerFlags("read", env)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errUsage
+	}
+	c, err := connect(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	result, err := c.ReadResource(ctx, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	if fs.json {
+		return printJSON(env.stdout, result)
+	}
+	printResourceContents(env.stdout, result.Contents)
+	return nil
+}
+
+func runPing(ctx context.Context, env *cliEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	c, err := connect(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	latency, err := c.Ping(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stdout, "%s answered in %s\n", c.ServerInfo().Name, latency.Round(time.Microsecond))
+	return nil
+}
+
+// listTools returns every tool of the server, following pagination.
+func listTools(ctx context.Context, c *client.HTTPClient) ([]mcp.Tool, error) {
+	var tools []mcp.Tool
+	var cursor mcp.Cursor
+	for {
+		result, err := c.ListTools(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, result.Tools...)
+		if cursor = result.NextCursor; cursor == "" {
+			return tools, nil
+		}
+	}
+}
+
+// listResources returns every resource of the server, following pagination.
+func listResources(ctx context.Context, c *client.HTTPClient) ([]mcp.Resource, error) {
+	var resources []mcp.Resource
+	var cursor mcp.Cursor
+	for {
+		result, err := c.ListResources(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, result.Resources...)
+		if cursor = result.NextCursor; cursor == "" {
+			return resources, nil
+		}
+	}
+}
+
+// listPrompts returns every prompt of the server, following pagination.
+func listPrompts(ctx context.Context, c *client.HTTPClient) ([]mcp.Prompt, error) {
+	var prompts []mcp.Prompt
+	var cursor mcp.Cursor
+	for {
+		result, err := c.ListPrompts(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		prompts = append(prompts, result.Prompts...)
+		if cursor = result.NextCursor; cursor == "" {
+			return prompts, nil
+		}
+	}
+}
+
+func printTools(w io.Writer, tools []mcp.Tool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tDESCRIPTION")
+	for _, tool := range tools {
+		fmt.Fprintf(tw, "%s\t%s\n", tool.Name, firstLine(tool.Description))
+	}
+	return tw.Flush()
+}
+
+func printResources(w io.Writer, resources []mcp.Resource) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "URI\tNAME\tMIME TYPE")
+	for _, resource := range resources {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", resource.URI, resource.Name, resource.MimeType)
+	}
+	return tw.Flush()
+}
+
+// printPrompts prints a table of prompts. Optional arguments are marked with "?".
+func printPrompts(w io.Writer, prompts []mcp.Prompt) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tARGUMENTS\tDESCRIPTION")
+	for _, prompt := range prompts {
+		names := make([]string, len(prompt.Arguments))
+		for i, arg := range prompt.Arguments {
+			names[i] = arg.Name
+			if !arg.Required {
+				names[i] += "?"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", prompt.Name, strings.Join(names, ","), firstLine(prompt.Description))
+	}
+	return tw.Flush()
+}
+
+// printResourceContents prints text contents as-is and summarizes blobs.
+func printResourceContents(w io.Writer, contents []mcp.ResourceContents) {
+	for _, item := range contents {
+		switch item := item.(type) {
+		case mcp.TextResourceContents:
+			fmt.Fprintln(w, item.Text)
+		case mcp.BlobResourceContents:
+			fmt.Fprintf(w, "[%s blob, %d base64 bytes]\n", item.MimeType, len(item.Blob))
+		}
+	}
+}
+
+// printContent prints text content as-is and other content as JSON.
+func printContent(w io.Writer, content []mcp.Content) error {
+	for _, item := range content {
+		if text, ok := item.(mcp.TextContent); ok {
+			fmt.Fprintln(w, text.Text)
+			continue
+		}
+		if err := printJSON(w, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// firstLine returns the first line of a description, for table cells.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
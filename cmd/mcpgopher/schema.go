@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/contriboss/mcpgopher/client"
+)
+
+var schemaCommand = &command{
+	name:       "schema",
+	usage:      "schema [-format mcp|openai|anthropic|gemini] [-o dir] <server>",
+	summary:    "write the schemas of the tools of a server to files",
+	args:       []argKind{argAlias},
+	valueFlags: []string{"format", "o"},
+	run:        runSchema,
+}
+
+// runSchema lists the tools of a server and writes a file per schema: the
+// input and output schemas as the server declares them, or the tool
+// definitions of a model provider API with -format.
+func runSchema(ctx context.Context, env *cliEnv, args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	format := fs.String("format", "mcp", "schema format: mcp, openai, anthropic or gemini")
+	dir := fs.String("o", "schemas", "directory to write the files to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+
+	c, err := connect(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	files, err := toolSchemas(ctx, c, *format)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", *dir, err)
+	}
+	for _, file := range files {
+		path := filepath.Join(*dir, file.name)
+		if err := os.WriteFile(path, file.data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Fprintln(env.stdout, path)
+	}
+	return nil
+}
+
+// schemaFile is a file to write, named after its tool.
+type schemaFile struct {
+	name string
+	data []byte
+}
+
+// toolSchemas returns the schema files of the tools of the server in format.
+func toolSchemas(ctx context.Context, c *client.HTTPClient, format string) ([]schemaFile, error) {
+	var files []schemaFile
+	add := func(tool, suffix string, v any) error {
+		data, err := indentJSON(v)
+		if err != nil {
+			return fmt.Errorf("invalid schema of tool %s: %w", tool, err)
+		}
+		files = append(files, schemaFile{name: fileName(tool) + suffix, data: data})
+		return nil
+	}
+
+	switch format {
+	case "mcp":
+		tools, err := listTools(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		for _, tool := range tools {
+			if err := add(tool.Name, ".input.json", tool.InputSchema); err != nil {
+				return nil, err
+			}
+			if len(tool.OutputSchema) > 0 {
+				if err := add(tool.Name, ".output.json", tool.OutputSchema); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case "openai":
+		tools, err := c.OpenaiTools()
+		if err != nil {
+			return nil, err
+		}
+		for _, tool := range tools {
+			if err := add(tool.Name, ".openai.json", map[string]any{"type": "function", "function": tool}); err != nil {
+				return nil, err
+			}
+		}
+	case "anthropic":
+		tools, err := c.AnthropicTools(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, tool := range tools {
+			if err := add(tool.Name, ".anthropic.json", tool); err != nil {
+				return nil, err
+			}
+		}
+	case "gemini":
+		tools, err := c.GeminiTools(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, tool := range tools {
+			if err := add(tool.Name, ".gemini.json", tool); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid -format %q", format)
+	}
+	return files, nil
+}
+
+// indentJSON encodes v as indented JSON ending with a newline.
+func indentJSON(v any) ([]byte, error) {
+	var data []byte
+	var err error
+	if raw, ok := v.(json.RawMessage); ok {
+		var buf bytes.Buffer
+		err = json.Indent(&buf, raw, "", "  ")
+		data = buf.Bytes()
+	} else {
+		data, err = json.MarshalIndent(v, "", "  ")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// fileName turns a tool name into a file name, replacing the characters
+// that are not safe in paths.
+func fileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+}
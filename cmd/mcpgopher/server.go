@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+)
+
+var serverCommand = &command{
+	name:    "server",
+	summary: "manage server aliases",
+	subcommands: []*command{
+		{
+			name:  "add",
+			usage: "server add [-H 'Name: value']... <alias> <url>",
+			run:   runServerAdd,
+		},
+		{
+			name:  "list",
+			usage: "server list",
+			run:   runServerList,
+		},
+		{
+			name:  "remove",
+			usage: "server remove <alias>",
+			args:  []argKind{argAlias},
+			run:   runServerRemove,
+		},
+		{
+			name:  "refresh",
+			usage: "server refresh <alias>",
+			args:  []argKind{argAlias},
+			run:   runServerRefresh,
+		},
+	},
+}
+
+// headerFlags collects repeated -H flags.
+type headerFlags map[string]string
+
+func (h headerFlags) String() string {
+	return fmt.Sprint(map[string]string(h))
+}
+
+func (h headerFlags) Set(value string) error {
+	name, val, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header must be in the form 'Name: value'")
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(val)
+	return nil
+}
+
+func runServerAdd(_ context.Context, env *cliEnv, args []string) error {
+	headers := headerFlags{}
+	fs := flag.NewFlagSet("server add", flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.Var(headers, "H", "HTTP header sent with every request, repeatable")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errUsage
+	}
+	alias, rawURL := fs.Arg(0), fs.Arg(1)
+
+	if strings.ContainsAny(alias, " \t\n/") {
+		return fmt.Errorf("invalid alias %q", alias)
+	}
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid server URL %q", rawURL)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	server := serverConfig{URL: rawURL}
+	if len(headers) > 0 {
+		server.Headers = headers
+	}
+	cfg.MCPServers[alias] = server
+	if err := cfg.save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	// The alias may point somewhere new, so drop any cached tool names
+	return forgetTools(alias)
+}
+
+func runServerList(_ context.Context, env *cliEnv, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(env.stdout, 0, 0, 2, ' ', 0)
+	for _, alias := range cfg.aliases() {
+		fmt.Fprintf(tw, "%s\t%s\n", alias, describe(cfg.MCPServers[alias]))
+	}
+	return tw.Flush()
+}
+
+func runServerRemove(_ context.Context, _ *cliEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.lookup(args[0]); err != nil {
+		return err
+	}
+	delete(cfg.MCPServers, args[0])
+	if err := cfg.save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return forgetTools(args[0])
+}
+
+func runServerRefresh(ctx context.Context, env *cliEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	server, err := cfg.lookup(args[0])
+	if err != nil {
+		return err
+	}
+	names, err := refreshTools(ctx, args[0], server, 0)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		fmt.Fprintln(env.stdout, name)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// toolsCacheTTL is how long cached tool names are used before completion
+// asks the server again.
+const toolsCacheTTL = 10 * time.Minute
+
+// toolsFetchTimeout bounds the connection made while completing, in seconds,
+// so a slow server never hangs the shell.
+const toolsFetchTimeout = 2
+
+type toolsCache struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Tools     []string  `json:"tools"`
+}
+
+func toolsCachePath(alias string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tools", alias+".json"), nil
+}
+
+func readToolsCache(alias string) (*toolsCache, error) {
+	path, err := toolsCachePath(alias)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cache toolsCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+func writeToolsCache(alias string, names []string) error {
+	path, err := toolsCachePath(alias)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(toolsCache{FetchedAt: time.Now(), Tools: names})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// forgetTools drops the cached tool names for alias.
+func forgetTools(alias string) error {
+	path, err := toolsCachePath(alias)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// fetchToolNames lists every tool the server exposes, following pagination.
+// A zero timeout uses the client default.
+func fetchToolNames(ctx context.Context, server serverConfig, timeout int) ([]string, error) {
+	c, err := server.Connect(&client.Options{Timeout: timeout})
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	var names []string
+	params := map[string]any{}
+	for {
+		raw, err := c.Request(ctx, "tools/list", params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		var result mcp.ListToolsResult
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse tools/list result: %w", err)
+		}
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		if result.NextCursor == "" {
+			return names, nil
+		}
+		params = map[string]any{"cursor": result.NextCursor}
+	}
+}
+
+// refreshTools fetches the tool names for alias and updates the cache.
+func refreshTools(ctx context.Context, alias string, server serverConfig, timeout int) ([]string, error) {
+	names, err := fetchToolNames(ctx, server, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeToolsCache(alias, names); err != nil {
+		return nil, fmt.Errorf("failed to cache tools: %w", err)
+	}
+	return names, nil
+}
+
+// cachedToolNames returns the tool names for alias, refreshing a stale cache.
+// If the server cannot be reached, stale names are better than none.
+func cachedToolNames(ctx context.Context, alias string, server serverConfig) []string {
+	cache, err := readToolsCache(alias)
+	if err == nil && time.Since(cache.FetchedAt) < toolsCacheTTL {
+		return cache.Tools
+	}
+
+	names, fetchErr := refreshTools(ctx, alias, server, toolsFetchTimeout)
+	if fetchErr == nil {
+		return names
+	}
+	if err == nil {
+		return cache.Tools
+	}
+	return nil
+}
@@ -0,0 +1,569 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+	"golang.org/x/term"
+)
+
+var tuiCommand = &command{
+	name:    "tui",
+	usage:   "tui <server>",
+	summary: "explore a server in a terminal UI",
+	args:    []argKind{argAlias},
+	run:     runTUI,
+}
+
+// tuiFeedSize bounds the notifications kept for the feed.
+const tuiFeedSize = 100
+
+const tuiHelp = "tab: switch pane  up/down: select  enter: compose  r: refresh  p: ping  q: quit"
+
+// Panes of the TUI.
+const (
+	paneTools = iota
+	paneResources
+	panePrompts
+	paneCount
+)
+
+var paneNames = [paneCount]string{"Tools", "Resources", "Prompts"}
+
+// tui is a terminal UI exploring a server. Keys are handled on one goroutine
+// while notifications arrive on others, so the state is guarded by mu.
+type tui struct {
+	ctx    context.Context
+	client *client.HTTPClient
+	w      io.Writer
+	// size returns the width and height of the terminal
+	size func() (width, height int)
+
+	mu        sync.Mutex
+	title     string
+	pane      int
+	selected  [paneCount]int
+	tools     []mcp.Tool
+	resources []mcp.Resource
+	prompts   []mcp.Prompt
+	// output holds the lines of the last result, shown in place of the
+	// details of the selected item until the selection changes
+	output []string
+	// composing is set while the request composer is open, editing input
+	// for the target item of the pane
+	composing bool
+	target    string
+	input     []rune
+	feed      []string
+	status    string
+}
+
+// runTUI connects to a server and runs the TUI until q or ctrl-c is pressed.
+func runTUI(ctx context.Context, env *cliEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	server, err := resolveServer(args[0])
+	if err != nil {
+		return err
+	}
+	c, err := server.Connect(&client.Options{Listen: true})
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", args[0], err)
+	}
+	defer c.Close()
+
+	if f, ok := env.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return fmt.Errorf("failed to set up terminal: %w", err)
+		}
+		defer term.Restore(int(f.Fd()), state)
+	}
+	// Draw on the alternate screen, without the cursor, so that the shell
+	// comes back untouched
+	fmt.Fprint(env.stdout, "\x1b[?1049h\x1b[?25l\x1b[2J")
+	defer fmt.Fprint(env.stdout, "\x1b[?25h\x1b[?1049l")
+
+	info := c.ServerInfo()
+	t := &tui{
+		ctx:    ctx,
+		client: c,
+		w:      env.stdout,
+		size:   terminalSize(env.stdout),
+		title:  info.Name + " " + info.Version,
+	}
+	unsubscribe := c.Subscribe("*", t.notify)
+	defer unsubscribe()
+
+	t.refresh()
+	return t.run(bufio.NewReader(env.stdin))
+}
+
+// terminalSize returns a function reporting the size of the terminal w
+// writes to, or 80x24 if it is not one.
+func terminalSize(w io.Writer) func() (int, int) {
+	return func() (int, int) {
+		if f, ok := w.(*os.File); ok {
+			if width, height, err := term.GetSize(int(f.Fd())); err == nil {
+				return width, height
+			}
+		}
+		return 80, 24
+	}
+}
+
+// run handles keys until the input ends or the user quits.
+func (t *tui) run(input *bufio.Reader) error {
+	for {
+		key, err := readKey(input)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !t.handleKey(key) {
+			return nil
+		}
+	}
+}
+
+// readKey reads a key press: a character, or the name of a special key such
+// as "up", "enter" or "ctrl-c". Unknown escape sequences read as "".
+func readKey(r *bufio.Reader) (string, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch c {
+	case '\r', '\n':
+		return "enter", nil
+	case '\t':
+		return "tab", nil
+	case 0x7f, 0x08:
+		return "backspace", nil
+	case 0x03, 0x04:
+		return "ctrl-c", nil
+	case 0x1b:
+		// A lone escape is the key itself, not the start of a sequence
+		if r.Buffered() == 0 {
+			return "esc", nil
+		}
+		if next, _ := r.Peek(1); next[0] != '[' && next[0] != 'O' {
+			return "esc", nil
+		}
+		_, _ = r.ReadByte()
+		// Skip the parameters of the sequence up to its final byte
+		for {
+			b, err := r.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			if b < 0x30 || b > 0x3f {
+				return escapeKeys[b], nil
+			}
+		}
+	}
+	return string(c), nil
+}
+
+// escapeKeys names the keys sending an escape sequence by its final byte.
+var escapeKeys = map[byte]string{
+	'A': "up",
+	'B': "down",
+	'C': "right",
+	'D': "left",
+	'Z': "shift-tab",
+}
+
+// handleKey acts on a key press and reports whether the TUI keeps running.
+func (t *tui) handleKey(key string) bool {
+	if key == "ctrl-c" {
+		return false
+	}
+	t.mu.Lock()
+	composing := t.composing
+	t.mu.Unlock()
+	if composing {
+		t.compose(key)
+		return true
+	}
+
+	switch key {
+	case "q":
+		return false
+	case "tab", "right":
+		t.update(func() { t.pane, t.output = (t.pane+1)%paneCount, nil })
+	case "shift-tab", "left":
+		t.update(func() { t.pane, t.output = (t.pane+paneCount-1)%paneCount, nil })
+	case "up", "k":
+		t.update(func() { t.move(-1) })
+	case "down", "j":
+		t.update(func() { t.move(1) })
+	case "enter":
+		t.open()
+	case "r":
+		t.client.Refresh()
+		t.refresh()
+	case "p":
+		t.ping()
+	}
+	return true
+}
+
+// update changes the state with fn and redraws.
+func (t *tui) update(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn()
+	t.draw()
+}
+
+// move moves the selection of the current pane by delta items.
+func (t *tui) move(delta int) {
+	n := len(t.items())
+	if n == 0 {
+		return
+	}
+	t.selected[t.pane] = min(max(t.selected[t.pane]+delta, 0), n-1)
+	t.output = nil
+}
+
+// open reads the selected resource, or opens the composer for the selected
+// tool or prompt with its arguments to fill in.
+func (t *tui) open() {
+	t.mu.Lock()
+	pane, i := t.pane, t.selected[t.pane]
+	var target, input string
+	switch {
+	case pane == paneTools && i < len(t.tools):
+		tool := t.tools[i]
+		target = tool.Name
+		input = strings.Join(argumentNames(schemaProperties(tool.InputSchema), nil), " ")
+	case pane == paneResources && i < len(t.resources):
+		target = t.resources[i].URI
+	case pane == panePrompts && i < len(t.prompts):
+		prompt := t.prompts[i]
+		target = prompt.Name
+		names := make([]string, len(prompt.Arguments))
+		for i, arg := range prompt.Arguments {
+			names[i] = arg.Name
+		}
+		input = strings.Join(argumentNames(names, nil), " ")
+	}
+	t.mu.Unlock()
+
+	switch {
+	case target == "":
+	case pane == paneResources:
+		t.send(pane, target, "")
+	default:
+		t.update(func() { t.composing, t.target, t.input = true, target, []rune(input) })
+	}
+}
+
+// compose edits the line of the composer, sending it on enter.
+func (t *tui) compose(key string) {
+	switch key {
+	case "esc":
+		t.update(func() { t.composing = false })
+	case "enter":
+		t.mu.Lock()
+		pane, target, line := t.pane, t.target, string(t.input)
+		t.composing = false
+		t.mu.Unlock()
+		t.send(pane, target, line)
+	case "backspace":
+		t.update(func() {
+			if len(t.input) > 0 {
+				t.input = t.input[:len(t.input)-1]
+			}
+		})
+	default:
+		if r, _ := utf8.DecodeRuneInString(key); utf8.RuneLen(r) == len(key) && r >= ' ' {
+			t.update(func() { t.input = append(t.input, r) })
+		}
+	}
+}
+
+// send makes the request of the pane for target, showing its result.
+func (t *tui) send(pane int, target, line string) {
+	t.update(func() { t.status = "waiting for " + target + "..." })
+	var out bytes.Buffer
+	err := t.request(pane, target, line, &out)
+	t.update(func() {
+		t.output = strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+		t.status = ""
+		if err != nil {
+			t.status = "error: " + err.Error()
+		}
+	})
+}
+
+// request calls a tool, reads a resource or gets a prompt, printing the
+// result to w.
+func (t *tui) request(pane int, target, line string, w io.Writer) error {
+	switch pane {
+	case paneTools:
+		arguments, err := parseArguments(line)
+		if err != nil {
+			return err
+		}
+		result, err := t.client.CallTool(t.ctx, target, arguments)
+		if err != nil {
+			return err
+		}
+		if err := printContent(w, result.Content); err != nil {
+			return err
+		}
+		if result.IsError {
+			return fmt.Errorf("tool %s reported an error", target)
+		}
+	case paneResources:
+		result, err := t.client.ReadResource(t.ctx, target)
+		if err != nil {
+			return err
+		}
+		printResourceContents(w, result.Contents)
+	case panePrompts:
+		arguments, err := parsePromptArguments(line)
+		if err != nil {
+			return err
+		}
+		result, err := t.client.GetPrompt(t.ctx, target, arguments)
+		if err != nil {
+			return err
+		}
+		for _, message := range result.Messages {
+			fmt.Fprintf(w, "%s: ", message.Role)
+			if err := printContent(w, []mcp.Content{message.Content}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// refresh lists the tools, resources and prompts of the server. Lists the
+// server does not support stay empty.
+func (t *tui) refresh() {
+	t.update(func() { t.status = "loading..." })
+	tools, toolsErr := listTools(t.ctx, t.client)
+	resources, resourcesErr := listResources(t.ctx, t.client)
+	prompts, promptsErr := listPrompts(t.ctx, t.client)
+	t.update(func() {
+		t.tools, t.resources, t.prompts = tools, resources, prompts
+		t.status = ""
+		for _, err := range []error{toolsErr, resourcesErr, promptsErr} {
+			if err != nil && !errors.Is(err, client.ErrCapabilityNotSupported) {
+				t.status = "error: " + err.Error()
+			}
+		}
+		for pane, n := range []int{len(tools), len(resources), len(prompts)} {
+			t.selected[pane] = max(min(t.selected[pane], n-1), 0)
+		}
+	})
+}
+
+// ping pings the server, showing the latency.
+func (t *tui) ping() {
+	latency, err := t.client.Ping(t.ctx)
+	t.update(func() {
+		t.status = fmt.Sprintf("pong in %s", latency.Round(time.Microsecond))
+		if err != nil {
+			t.status = "error: " + err.Error()
+		}
+	})
+}
+
+// notify adds a notification to the feed, refreshing the lists when the
+// server reports they changed.
+func (t *tui) notify(notification mcp.Notification) {
+	line := time.Now().Format("15:04:05") + " " + notification.Method
+	if notification.Params != nil {
+		params, _ := json.Marshal(notification.Params)
+		line += " " + string(params)
+	}
+	t.update(func() {
+		t.feed = append(t.feed, line)
+		if len(t.feed) > tuiFeedSize {
+			t.feed = t.feed[len(t.feed)-tuiFeedSize:]
+		}
+	})
+	if strings.HasSuffix(notification.Method, "/list_changed") {
+		go t.refresh()
+	}
+}
+
+// items returns the names of the items of the current pane.
+func (t *tui) items() []string {
+	var items []string
+	switch t.pane {
+	case paneTools:
+		for _, tool := range t.tools {
+			items = append(items, tool.Name)
+		}
+	case paneResources:
+		for _, resource := range t.resources {
+			items = append(items, resource.URI)
+		}
+	case panePrompts:
+		for _, prompt := range t.prompts {
+			items = append(items, prompt.Name)
+		}
+	}
+	return items
+}
+
+// details returns the lines describing the selected item, or the last result.
+func (t *tui) details() []string {
+	if t.output != nil {
+		return t.output
+	}
+	i := t.selected[t.pane]
+	var b strings.Builder
+	switch {
+	case t.pane == paneTools && i < len(t.tools):
+		tool := t.tools[i]
+		if tool.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", tool.Description)
+		}
+		b.WriteString("Input schema:\n")
+		var schema bytes.Buffer
+		if err := json.Indent(&schema, tool.InputSchema, "", "  "); err != nil {
+			schema.Write(tool.InputSchema)
+		}
+		fmt.Fprintf(&b, "%s\n", schema.Bytes())
+	case t.pane == paneResources && i < len(t.resources):
+		resource := t.resources[i]
+		fmt.Fprintf(&b, "URI: %s\nName: %s\n", resource.URI, resource.Name)
+		if resource.MimeType != "" {
+			fmt.Fprintf(&b, "MIME type: %s\n", resource.MimeType)
+		}
+		if resource.Description != "" {
+			fmt.Fprintf(&b, "\n%s\n", resource.Description)
+		}
+	case t.pane == panePrompts && i < len(t.prompts):
+		prompt := t.prompts[i]
+		if prompt.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", prompt.Description)
+		}
+		if len(prompt.Arguments) > 0 {
+			b.WriteString("Arguments:\n")
+		}
+		for _, arg := range prompt.Arguments {
+			required := ""
+			if arg.Required {
+				required = " (required)"
+			}
+			fmt.Fprintf(&b, "  %s%s %s\n", arg.Name, required, arg.Description)
+		}
+	default:
+		fmt.Fprintf(&b, "No %s\n", strings.ToLower(paneNames[t.pane]))
+	}
+	return strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+}
+
+// draw writes a frame over the previous one.
+func (t *tui) draw() {
+	width, height := t.size()
+	var frame strings.Builder
+	frame.WriteString("\x1b[H")
+	for i, line := range t.render(width, height) {
+		if i > 0 {
+			frame.WriteString("\r\n")
+		}
+		frame.WriteString(line)
+		frame.WriteString("\x1b[K")
+	}
+	_, _ = io.WriteString(t.w, frame.String())
+}
+
+// render lays out a frame of height lines of at most width characters: the
+// pane tabs, the list of the pane beside the details of its selected item,
+// the notification feed, then the composer or the status line.
+func (t *tui) render(width, height int) []string {
+	feedHeight := max(height/4, 1)
+	bodyHeight := max(height-feedHeight-3, 1)
+	listWidth := width / 3
+	lines := make([]string, 0, height)
+
+	tabs := make([]string, paneCount)
+	for i, name := range paneNames {
+		tabs[i] = " " + name + " "
+		if i == t.pane {
+			tabs[i] = "[" + name + "]"
+		}
+	}
+	lines = append(lines, t.title+"  "+strings.Join(tabs, " "))
+
+	items, details := t.items(), t.details()
+	selected := t.selected[t.pane]
+	first := max(selected-bodyHeight+1, 0)
+	for i := range bodyHeight {
+		left := ""
+		if n := first + i; n < len(items) {
+			marker := "  "
+			if n == selected {
+				marker = "> "
+			}
+			left = marker + items[n]
+		}
+		right := ""
+		if i < len(details) {
+			right = details[i]
+		}
+		lines = append(lines, padRight(left, listWidth)+"│ "+right)
+	}
+
+	lines = append(lines, "── Notifications "+strings.Repeat("─", max(width-17, 0)))
+	feed := t.feed[max(len(t.feed)-feedHeight, 0):]
+	for i := range feedHeight {
+		line := ""
+		if i < len(feed) {
+			line = feed[i]
+		}
+		lines = append(lines, line)
+	}
+
+	switch {
+	case t.composing:
+		lines = append(lines, t.target+"> "+string(t.input)+"_")
+	case t.status != "":
+		lines = append(lines, t.status)
+	default:
+		lines = append(lines, tuiHelp)
+	}
+
+	for i, line := range lines {
+		lines[i] = truncate(strings.ReplaceAll(line, "\t", "  "), width)
+	}
+	return lines
+}
+
+// truncate cuts s to at most width characters.
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:max(width, 0)])
+}
+
+// padRight cuts or pads s with spaces to width characters.
+func padRight(s string, width int) string {
+	s = truncate(s, width)
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
@@ -0,0 +1,55 @@
+// Command mcpschema generates Go types from the JSON Schema of an MCP
+// revision. It is meant to be run by go generate:
+//
+//	//go:generate go run ../../../cmd/mcpschema -package v20250326 -o types.go ../2025-03-26.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/contriboss/mcpgopher/codegen"
+)
+
+func main() {
+	pkg := flag.String("package", "spec", "name of the generated package")
+	output := flag.String("o", "", "write the types to this file instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: mcpschema [-package name] [-o file] <schema.json>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *pkg, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "mcpschema:", err)
+		os.Exit(1)
+	}
+}
+
+func run(path, pkg, output string) error {
+	document, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	src, err := codegen.GenerateSchema(document, codegen.SchemaOptions{
+		Package: pkg,
+		Source:  "MCP schema " + strings.TrimSuffix(filepath.Base(path), ".json"),
+	})
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	if err := os.WriteFile(output, src, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	return nil
+}
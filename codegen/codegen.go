@@ -0,0 +1,379 @@
+// Package codegen generates typed Go bindings for the tools of an MCP server.
+//
+// For every tool, the generated file declares a struct mirroring the tool's
+// inputSchema and a method calling the tool with it:
+//
+//	src, err := codegen.GenerateFromClient(ctx, c, codegen.Options{Package: "weather"})
+//
+// The generated package is used as:
+//
+//	w := weather.New(c)
+//	result, err := w.Forecast(ctx, weather.ForecastArgs{City: "Paris"})
+//
+// Required properties become plain fields; optional ones become pointers, unless
+// already nillable, so that an unset value is omitted. Schemas that do not map to a Go type, such
+// as unions, become any.
+package codegen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// Options configures Generate.
+type Options struct {
+	// Package is the name of the generated package, "tools" by default
+	Package string
+	// Source describes where the tools came from, such as a server URL; it is
+	// recorded in the header of the generated file
+	Source string
+}
+
+// GenerateFromClient lists the tools of the server c is connected to and
+// generates bindings for them.
+func GenerateFromClient(ctx context.Context, c *client.HTTPClient, options Options) ([]byte, error) {
+	var (
+		tools  []mcp.Tool
+		cursor mcp.Cursor
+	)
+	for {
+		result, err := c.ListTools(ctx, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	return Generate(tools, options)
+}
+
+// Generate returns the gofmt-ed source of a Go file declaring typed bindings
+// for tools.
+func Generate(tools []mcp.Tool, options Options) ([]byte, error) {
+	if options.Package == "" {
+		options.Package = "tools"
+	}
+	g := &generator{names: map[string]bool{"Client": true, "New": true}}
+
+	tools = slices.Clone(tools)
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	for _, tool := range tools {
+		if err := g.tool(tool); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintln(&out, "// Code generated by mcpgopher generate; DO NOT EDIT.")
+	if options.Source != "" {
+		fmt.Fprintf(&out, "// Source: %s\n", options.Source)
+	}
+	fmt.Fprintf(&out, "\npackage %s\n\n", options.Package)
+	out.WriteString(header)
+	out.Write(g.types.Bytes())
+	out.Write(g.methods.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+const header = `import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// Client calls the tools of the server with typed arguments.
+type Client struct {
+	c *client.HTTPClient
+}
+
+// New returns a Client calling tools through c.
+func New(c *client.HTTPClient) *Client {
+	return &Client{c: c}
+}
+
+// call encodes args as the arguments of the tool and calls it.
+func (c *Client) call(ctx context.Context, name string, args any) (*mcp.CallToolResult, error) {
+	var arguments map[string]any
+	if args != nil {
+		data, err := json.Marshal(args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode arguments of %s: %w", name, err)
+		}
+		if err := json.Unmarshal(data, &arguments); err != nil {
+			return nil, fmt.Errorf("failed to encode arguments of %s: %w", name, err)
+		}
+	}
+	return c.c.CallTool(ctx, name, arguments)
+}
+`
+
+// generator accumulates the declarations of the generated file.
+type generator struct {
+	types   bytes.Buffer
+	methods bytes.Buffer
+	// names are the top-level identifiers already declared
+	names map[string]bool
+	// refs maps the references to definitions to their types, and
+	// nillables holds the definitions needing no pointer when optional
+	refs      map[string]string
+	nillables map[string]bool
+}
+
+// schema is the part of a JSON Schema the generator understands.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 any                `json:"type"`
+	Const                any                `json:"const"`
+	Description          string             `json:"description"`
+	Enum                 []any              `json:"enum"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	AnyOf                []*schema          `json:"anyOf"`
+	OneOf                []*schema          `json:"oneOf"`
+}
+
+// UnmarshalJSON accepts the boolean schemas true and false as empty schemas.
+func (s *schema) UnmarshalJSON(data []byte) error {
+	if string(data) == "true" || string(data) == "false" {
+		*s = schema{}
+		return nil
+	}
+	type plain schema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// typeName returns the single non-null type of the schema, if any.
+func (s *schema) typeName() string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []any:
+		var name string
+		for _, item := range t {
+			if item == "null" {
+				continue
+			}
+			if name != "" {
+				return ""
+			}
+			name, _ = item.(string)
+		}
+		return name
+	}
+	if s.Properties != nil {
+		return "object"
+	}
+	return schemaTypeOf(s.Const)
+}
+
+func (g *generator) tool(tool mcp.Tool) error {
+	var input schema
+	if len(tool.InputSchema) > 0 {
+		if err := json.Unmarshal(tool.InputSchema, &input); err != nil {
+			return fmt.Errorf("failed to parse input schema of tool %s: %w", tool.Name, err)
+		}
+	}
+	method := g.declare(goName(tool.Name))
+
+	writeComment(&g.methods, "", fmt.Sprintf("%s calls the %s tool. %s", method, tool.Name, tool.Description))
+	if len(input.Properties) == 0 {
+		fmt.Fprintf(&g.methods, "func (c *Client) %s(ctx context.Context) (*mcp.CallToolResult, error) {\n", method)
+		fmt.Fprintf(&g.methods, "\treturn c.call(ctx, %q, nil)\n}\n\n", tool.Name)
+		return nil
+	}
+
+	args := g.declare(method + "Args")
+	g.structType(args, fmt.Sprintf("%s are the arguments of the %s tool.", args, tool.Name), &input)
+	fmt.Fprintf(&g.methods, "func (c *Client) %s(ctx context.Context, args %s) (*mcp.CallToolResult, error) {\n", method, args)
+	fmt.Fprintf(&g.methods, "\treturn c.call(ctx, %q, args)\n}\n\n", tool.Name)
+	return nil
+}
+
+// structType declares a struct named name with the properties of s.
+func (g *generator) structType(name, doc string, s *schema) {
+	properties := make([]string, 0, len(s.Properties))
+	for property := range s.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	var body bytes.Buffer
+	fields := map[string]bool{}
+	for _, property := range properties {
+		field := unique(goName(property), fields)
+		fields[field] = true
+
+		ps := s.Properties[property]
+		required := slices.Contains(s.Required, property)
+		goType := g.goType(name+field, ps)
+		tag := property
+		if !required {
+			tag += ",omitempty"
+			if !g.isNillable(goType) {
+				goType = "*" + goType
+			}
+		}
+
+		doc := ps.Description
+		if len(ps.Enum) > 0 {
+			values, _ := json.Marshal(ps.Enum)
+			doc = strings.TrimSpace(doc + " One of " + string(values) + ".")
+		}
+		writeComment(&body, "\t", doc)
+		fmt.Fprintf(&body, "\t%s %s `json:%q`\n", field, goType, tag)
+	}
+
+	var decl bytes.Buffer
+	writeComment(&decl, "", doc)
+	fmt.Fprintf(&decl, "type %s struct {\n%s}\n\n", name, body.Bytes())
+	g.types.Write(decl.Bytes())
+}
+
+// goType returns the Go type of s, declaring a struct named name for objects
+// with properties.
+func (g *generator) goType(name string, s *schema) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		if goType, ok := g.refs[s.Ref]; ok {
+			return goType
+		}
+		return "any"
+	}
+	switch s.typeName() {
+	case "string":
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(name+"Item", s.Items)
+	case "object":
+		if len(s.Properties) > 0 {
+			name = g.declare(name)
+			doc := s.Description
+			if doc == "" && g.refs != nil {
+				doc = name + " is an object nested in a definition of the schema."
+			}
+			g.structType(name, doc, s)
+			return name
+		}
+		var additional schema
+		if err := json.Unmarshal(s.AdditionalProperties, &additional); err == nil && additional.typeName() != "" {
+			return "map[string]" + g.goType(name+"Value", &additional)
+		}
+		return "map[string]any"
+	}
+	return "any"
+}
+
+// declare reserves a top-level identifier derived from name.
+func (g *generator) declare(name string) string {
+	name = unique(name, g.names)
+	g.names[name] = true
+	return name
+}
+
+// unique returns name, or name followed by the smallest number making it
+// absent from taken.
+func unique(name string, taken map[string]bool) string {
+	if !taken[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		if candidate := fmt.Sprintf("%s%d", name, i); !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// nillable reports whether the zero value of goType is nil, and thus omitted
+// from JSON without a pointer.
+func nillable(goType string) bool {
+	return goType == "any" || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[")
+}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "JSON": true, "JSONRPC": true,
+	"SQL": true, "URI": true, "URL": true, "UUID": true,
+}
+
+// goName converts a tool or property name such as "get_user-id" or
+// "getUserId" to an exported Go identifier such as GetUserID.
+func goName(name string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		wr := []rune(w)
+		b.WriteRune(unicode.ToUpper(wr[0]))
+		b.WriteString(string(wr[1:]))
+	}
+	if b.Len() == 0 || !unicode.IsLetter([]rune(b.String())[0]) {
+		return "X" + b.String()
+	}
+	return b.String()
+}
+
+// writeComment writes text as a line comment, one comment line per line of text.
+func writeComment(b *bytes.Buffer, indent, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, strings.TrimRight("// "+line, " \t\r"))
+	}
+}
@@ -0,0 +1,161 @@
+package codegen
+
+import (
+	"context"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestGenerate(t *testing.T) {
+	tools := []mcp.Tool{
+		{
+			Name:        "get_weather",
+			Description: "Weather of a city.",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"city": {"type": "string", "description": "City name"},
+					"days": {"type": "integer"},
+					"unit": {"type": "string", "enum": ["c", "f"]},
+					"area": {"type": "object", "properties": {"radius": {"type": "number"}}, "required": ["radius"]},
+					"tags": {"type": "array", "items": {"type": "string"}},
+					"scores": {"type": "object", "additionalProperties": {"type": "integer"}},
+					"user-id": {"type": ["string", "null"]},
+					"extra": {}
+				},
+				"required": ["city"]
+			}`),
+		},
+		{Name: "now"},
+		{Name: "Client"},
+	}
+	src, err := Generate(tools, Options{Package: "weather", Source: "weather 1.0"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "weather.go", src, parser.AllErrors); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, src)
+	}
+
+	// Compare modulo gofmt's alignment
+	spaces := regexp.MustCompile(`[ \t]+`)
+	normalized := spaces.ReplaceAllString(string(src), " ")
+	for _, want := range []string{
+		"// Code generated by mcpgopher generate; DO NOT EDIT.\n// Source: weather 1.0\n",
+		"package weather\n",
+		"type GetWeatherArgs struct {",
+		"\t// City name\n\tCity string `json:\"city\"`",
+		"Days *int64 `json:\"days,omitempty\"`",
+		"\t// One of [\"c\",\"f\"].\n\tUnit *string `json:\"unit,omitempty\"`",
+		"Area *GetWeatherArgsArea `json:\"area,omitempty\"`",
+		"type GetWeatherArgsArea struct {\n\tRadius float64 `json:\"radius\"`",
+		"Tags []string `json:\"tags,omitempty\"`",
+		"Scores map[string]int64 `json:\"scores,omitempty\"`",
+		"UserID *string `json:\"user-id,omitempty\"`",
+		"Extra any `json:\"extra,omitempty\"`",
+		"// GetWeather calls the get_weather tool. Weather of a city.\nfunc (c *Client) GetWeather(ctx context.Context, args GetWeatherArgs) (*mcp.CallToolResult, error) {",
+		"func (c *Client) Now(ctx context.Context) (*mcp.CallToolResult, error) {\n\treturn c.call(ctx, \"now\", nil)",
+		"func (c *Client) Client2(ctx context.Context) (*mcp.CallToolResult, error) {",
+	} {
+		if !strings.Contains(normalized, spaces.ReplaceAllString(want, " ")) {
+			t.Errorf("Expected generated code to contain %q, got:\n%s", want, src)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"get_weather":   "GetWeather",
+		"getUserId":     "GetUserID",
+		"fetch-url":     "FetchURL",
+		"list.items":    "ListItems",
+		"2fa":           "X2fa",
+		"already_Upper": "AlreadyUpper",
+	}
+	for name, want := range tests {
+		if got := goName(name); got != want {
+			t.Errorf("goName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGenerateFromClient(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo", InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"}}}`)}, nil)
+	srv.Start()
+	defer srv.Close()
+
+	c, err := client.NewHTTPClient(&client.Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	src, err := GenerateFromClient(context.Background(), c, Options{})
+	if err != nil {
+		t.Fatalf("GenerateFromClient failed: %v", err)
+	}
+	if !strings.Contains(string(src), "package tools\n") || !strings.Contains(string(src), "type EchoArgs struct {") {
+		t.Errorf("Expected bindings for echo in package tools, got:\n%s", src)
+	}
+}
+
+func TestGenerateSchema(t *testing.T) {
+	document := []byte(`{
+		"definitions": {
+			"Role": {"type": "string", "enum": ["user", "assistant"]},
+			"Content": {"anyOf": [{"$ref": "#/definitions/Text"}]},
+			"Text": {"type": "object", "properties": {"type": {"const": "text"}, "text": {"type": "string"}}, "required": ["type", "text"]},
+			"Message": {
+				"description": "A message.",
+				"type": "object",
+				"properties": {
+					"role": {"$ref": "#/definitions/Role"},
+					"content": {"$ref": "#/definitions/Content"},
+					"meta": {"type": "object", "properties": {"id": {"type": "string"}}},
+					"data": true
+				},
+				"required": ["role"]
+			}
+		}
+	}`)
+	src, err := GenerateSchema(document, SchemaOptions{Package: "v1", Source: "test"})
+	if err != nil {
+		t.Fatalf("GenerateSchema failed: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "types.go", src, parser.AllErrors); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, src)
+	}
+
+	spaces := regexp.MustCompile(`[ \t]+`)
+	normalized := spaces.ReplaceAllString(string(src), " ")
+	for _, want := range []string{
+		"// Code generated by mcpschema; DO NOT EDIT.\n// Source: test\n",
+		"package v1\n",
+		"type Role string",
+		"RoleAssistant Role = \"assistant\"",
+		"type Content = any",
+		"// A message.\ntype Message struct {",
+		"Role Role `json:\"role\"`",
+		"Content Content `json:\"content,omitempty\"`",
+		"Meta *MessageMeta `json:\"meta,omitempty\"`",
+		"Data any `json:\"data,omitempty\"`",
+		"type Text struct {\n\tText string `json:\"text\"`\n\tType string `json:\"type\"`",
+	} {
+		if !strings.Contains(normalized, spaces.ReplaceAllString(want, " ")) {
+			t.Errorf("Expected generated code to contain %q, got:\n%s", want, src)
+		}
+	}
+
+	if _, err := GenerateSchema([]byte(`{"type": "object"}`), SchemaOptions{}); err == nil {
+		t.Error("Expected an error for a schema without definitions")
+	}
+}
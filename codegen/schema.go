@@ -0,0 +1,157 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// SchemaOptions configures GenerateSchema.
+type SchemaOptions struct {
+	// Package is the name of the generated package, "spec" by default
+	Package string
+	// Source describes the schema, such as its revision; it is recorded in
+	// the header of the generated file
+	Source string
+}
+
+// schemaDocument is a JSON Schema document holding its definitions under
+// $defs or, as the MCP schema does, definitions.
+type schemaDocument struct {
+	Defs        map[string]*schema `json:"$defs"`
+	Definitions map[string]*schema `json:"definitions"`
+}
+
+// GenerateSchema returns the gofmt-ed source of a Go file declaring a type for
+// every definition of a JSON Schema document, such as the schema.json of an
+// MCP revision. Objects become structs, string enums become string types with
+// a constant per value, and references to definitions become their types.
+// Unions and other schemas that do not map to a Go type become any.
+func GenerateSchema(document []byte, options SchemaOptions) ([]byte, error) {
+	if options.Package == "" {
+		options.Package = "spec"
+	}
+	var doc schemaDocument
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	prefix, defs := "#/$defs/", doc.Defs
+	if len(defs) == 0 {
+		prefix, defs = "#/definitions/", doc.Definitions
+	}
+	if len(defs) == 0 {
+		return nil, fmt.Errorf("schema has no definitions")
+	}
+
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g := &generator{names: map[string]bool{}, refs: map[string]string{}, nillables: map[string]bool{}}
+	for _, name := range names {
+		g.refs[prefix+name] = g.declare(goName(name))
+	}
+	// Types whose zero value is nil need no pointer when optional
+	for _, name := range names {
+		if def := defs[name]; def.Ref == "" && nillable(g.underlyingType(def)) {
+			g.nillables[g.refs[prefix+name]] = true
+		}
+	}
+	for _, name := range names {
+		g.definition(g.refs[prefix+name], defs[name])
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintln(&out, "// Code generated by mcpschema; DO NOT EDIT.")
+	if options.Source != "" {
+		fmt.Fprintf(&out, "// Source: %s\n", options.Source)
+	}
+	fmt.Fprintf(&out, "\npackage %s\n\n", options.Package)
+	out.Write(g.types.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// definition declares the type name of a definition.
+func (g *generator) definition(name string, s *schema) {
+	doc := s.Description
+	if doc == "" {
+		doc = name + " mirrors a definition of the schema."
+	}
+	switch {
+	case len(s.AnyOf) > 0 || len(s.OneOf) > 0:
+		writeComment(&g.types, "", doc)
+		fmt.Fprintf(&g.types, "type %s = any\n\n", name)
+	case s.Ref != "":
+		writeComment(&g.types, "", doc)
+		fmt.Fprintf(&g.types, "type %s = %s\n\n", name, g.goType(name, s))
+	case s.typeName() == "object" && len(s.Properties) > 0:
+		g.structType(name, doc, s)
+	case s.typeName() == "string" && len(s.Enum) > 0:
+		writeComment(&g.types, "", doc)
+		fmt.Fprintf(&g.types, "type %s string\n\n", name)
+		fmt.Fprintf(&g.types, "// Values of %s.\nconst (\n", name)
+		for _, value := range s.Enum {
+			if value, ok := value.(string); ok {
+				fmt.Fprintf(&g.types, "\t%s%s %s = %q\n", name, goName(value), name, value)
+			}
+		}
+		g.types.WriteString(")\n\n")
+	default:
+		goType := g.underlyingType(s)
+		writeComment(&g.types, "", doc)
+		if goType == "any" {
+			fmt.Fprintf(&g.types, "type %s = any\n\n", name)
+		} else {
+			fmt.Fprintf(&g.types, "type %s %s\n\n", name, g.goType(name, s))
+		}
+	}
+}
+
+// underlyingType returns the Go type of s without declaring anything, for
+// schemas that are not structs.
+func (g *generator) underlyingType(s *schema) string {
+	if len(s.AnyOf) > 0 || len(s.OneOf) > 0 {
+		return "any"
+	}
+	switch s.typeName() {
+	case "string", "integer", "number", "boolean":
+		return g.goType("", s)
+	case "array":
+		return "[]"
+	case "object":
+		if len(s.Properties) > 0 {
+			return "struct"
+		}
+		return "map["
+	}
+	return "any"
+}
+
+// schemaTypeOf returns the JSON Schema type of a const value.
+func schemaTypeOf(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return ""
+}
+
+// isNillable reports whether the zero value of goType is nil, including the
+// declared definitions whose underlying type is.
+func (g *generator) isNillable(goType string) bool {
+	return nillable(goType) || g.nillables[goType] || strings.HasPrefix(goType, "*")
+}
@@ -54,9 +54,7 @@ func main() {
 
 	// Make a ping request using the dedicated Ping method
 	fmt.Println("Sending ping request...")
-	startTime := time.Now()
-	err = mcp.Ping(ctx)
-	pingDuration := time.Since(startTime)
+	pingDuration, err := mcp.Ping(ctx)
 	if err != nil {
 		fmt.Printf("Ping request failed: %v\n", err)
 	} else {
@@ -5,4 +5,7 @@ go 1.24.1
 require (
 	github.com/oklog/ulid v1.3.1
 	github.com/yosida95/uritemplate/v3 v3.0.2
+	golang.org/x/term v0.36.0
 )
+
+require golang.org/x/sys v0.37.0 // indirect
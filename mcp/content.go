@@ -0,0 +1,300 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ContentFromJSON decodes a content object into its concrete type, selected
+// by the "type" field: TextContent, ImageContent, AudioContent or EmbeddedResource.
+func ContentFromJSON(data []byte) (Content, error) {
+	var content contentJSON
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal content: %w", err)
+	}
+	return content.content()
+}
+
+// ResourceContentsFromJSON decodes a resource contents object into
+// TextResourceContents or BlobResourceContents, depending on whether it
+// carries a "text" or a "blob" field.
+func ResourceContentsFromJSON(data []byte) (ResourceContents, error) {
+	var contents resourceContentsJSON
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resource contents: %w", err)
+	}
+	return contents.contents()
+}
+
+// ReferenceFromJSON decodes a completion reference into PromptReference or
+// ResourceReference, selected by the "type" field.
+func ReferenceFromJSON(data []byte) (Reference, error) {
+	var probe struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reference: %w", err)
+	}
+
+	switch probe.Type {
+	case RefTypePrompt:
+		var ref PromptReference
+		err := json.Unmarshal(data, &ref)
+		return ref, err
+	case RefTypeResource:
+		var ref ResourceReference
+		err := json.Unmarshal(data, &ref)
+		return ref, err
+	}
+	return nil, fmt.Errorf("unsupported reference type: %s", probe.Type)
+}
+
+// contentJSON holds the fields of every content type, so that a content
+// object decodes in a single pass, without knowing its type first.
+type contentJSON struct {
+	Annotated
+	Type     string                `json:"type"`
+	Text     string                `json:"text"`
+	Data     string                `json:"data"`
+	MimeType string                `json:"mimeType"`
+	Resource *resourceContentsJSON `json:"resource"`
+}
+
+// content returns the content of the type the object declares.
+func (c *contentJSON) content() (Content, error) {
+	switch c.Type {
+	case "text":
+		return TextContent{Annotated: c.Annotated, Type: c.Type, Text: c.Text}, nil
+	case "image":
+		return ImageContent{Annotated: c.Annotated, Type: c.Type, Data: c.Data, MimeType: c.MimeType}, nil
+	case "audio":
+		return AudioContent{Annotated: c.Annotated, Type: c.Type, Data: c.Data, MimeType: c.MimeType}, nil
+	case "resource":
+		if c.Resource == nil {
+			return nil, fmt.Errorf("resource is missing")
+		}
+		resource, err := c.Resource.contents()
+		if err != nil {
+			return nil, err
+		}
+		return EmbeddedResource{Annotated: c.Annotated, Type: c.Type, Resource: resource}, nil
+	}
+	return nil, fmt.Errorf("unsupported content type: %s", c.Type)
+}
+
+// resourceContentsJSON holds the fields of both resource contents types.
+type resourceContentsJSON struct {
+	URI      string  `json:"uri"`
+	MimeType string  `json:"mimeType"`
+	Text     *string `json:"text"`
+	Blob     *string `json:"blob"`
+}
+
+// contents returns the text contents, if the object has a text, or else the
+// blob contents.
+func (c *resourceContentsJSON) contents() (ResourceContents, error) {
+	switch {
+	case c.Text != nil:
+		return TextResourceContents{URI: c.URI, MimeType: c.MimeType, Text: *c.Text}, nil
+	case c.Blob != nil:
+		return BlobResourceContents{URI: c.URI, MimeType: c.MimeType, Blob: *c.Blob}, nil
+	}
+	return nil, fmt.Errorf("unsupported resource type")
+}
+
+// contentsFromJSON converts a list of decoded content objects. The result is
+// never nil.
+func contentsFromJSON(items []contentJSON) ([]Content, error) {
+	contents := make([]Content, 0, len(items))
+	for i := range items {
+		content, err := items[i].content()
+		if err != nil {
+			return nil, fmt.Errorf("content %d: %w", i, err)
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
+
+// resourceContentsFromJSON converts a list of decoded resource contents
+// objects. The result is never nil.
+func resourceContentsFromJSON(items []resourceContentsJSON) ([]ResourceContents, error) {
+	contents := make([]ResourceContents, 0, len(items))
+	for i := range items {
+		item, err := items[i].contents()
+		if err != nil {
+			return nil, fmt.Errorf("contents %d: %w", i, err)
+		}
+		contents = append(contents, item)
+	}
+	return contents, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *EmbeddedResource) UnmarshalJSON(data []byte) error {
+	type alias EmbeddedResource
+	aux := struct {
+		*alias
+		Resource *resourceContentsJSON `json:"resource"`
+	}{
+		alias: (*alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Resource == nil {
+		return fmt.Errorf("resource is missing")
+	}
+
+	resource, err := aux.Resource.contents()
+	if err != nil {
+		return err
+	}
+	r.Resource = resource
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *CallToolResult) UnmarshalJSON(data []byte) error {
+	type alias CallToolResult
+	aux := struct {
+		*alias
+		Content []contentJSON `json:"content"`
+	}{
+		alias: (*alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	content, err := contentsFromJSON(aux.Content)
+	if err != nil {
+		return err
+	}
+	r.Content = content
+	r.StructuredContent = nullToNil(r.StructuredContent)
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *PromptMessage) UnmarshalJSON(data []byte) error {
+	type alias PromptMessage
+	aux := struct {
+		*alias
+		Content *contentJSON `json:"content"`
+	}{
+		alias: (*alias)(m),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Content == nil {
+		return fmt.Errorf("content is missing")
+	}
+
+	content, err := aux.Content.content()
+	if err != nil {
+		return err
+	}
+	m.Content = content
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *ReadResourceResult) UnmarshalJSON(data []byte) error {
+	type alias ReadResourceResult
+	aux := struct {
+		*alias
+		Contents []resourceContentsJSON `json:"contents"`
+	}{
+		alias: (*alias)(r),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	contents, err := resourceContentsFromJSON(aux.Contents)
+	if err != nil {
+		return err
+	}
+	r.Contents = contents
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *SamplingMessage) UnmarshalJSON(data []byte) error {
+	type alias SamplingMessage
+	aux := struct {
+		*alias
+		Content *contentJSON `json:"content"`
+	}{
+		alias: (*alias)(m),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Content == nil {
+		return fmt.Errorf("content is missing")
+	}
+
+	content, err := aux.Content.content()
+	if err != nil {
+		return err
+	}
+	m.Content = content
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It is needed because the embedded SamplingMessage's method would otherwise
+// be promoted and leave the other fields empty.
+func (r *CreateMessageResult) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Result
+		Model      string `json:"model"`
+		StopReason string `json:"stopReason,omitempty"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &r.SamplingMessage); err != nil {
+		return err
+	}
+	r.Result = aux.Result
+	r.Model = aux.Model
+	r.StopReason = aux.StopReason
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *CompleteRequestParams) UnmarshalJSON(data []byte) error {
+	type alias CompleteRequestParams
+	aux := struct {
+		*alias
+		Ref json.RawMessage `json:"ref"`
+	}{
+		alias: (*alias)(p),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Ref) == 0 {
+		return fmt.Errorf("ref is missing")
+	}
+
+	ref, err := ReferenceFromJSON(aux.Ref)
+	if err != nil {
+		return err
+	}
+	p.Ref = ref
+	return nil
+}
+
+// nullToNil returns nil for a JSON null, which decodes into a
+// json.RawMessage as is.
+func nullToNil(raw json.RawMessage) json.RawMessage {
+	if string(raw) == "null" {
+		return nil
+	}
+	return raw
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestContentRoundTrip(t *testing.T) {
+	text := NewTextContent("hello")
+	text.Annotations = &Annotations{Audience: []Role{RoleUser}, Priority: 0.5}
+	contents := []Content{
+		text,
+		NewImageContent("aW1n", "image/png"),
+		NewAudioContent("YXVkaW8=", "audio/wav"),
+		NewEmbeddedResource(TextResourceContents{URI: "file:///a.txt", MimeType: "text/plain", Text: ""}),
+		NewEmbeddedResource(BlobResourceContents{URI: "file:///b.bin", Blob: "YmxvYg=="}),
+	}
+
+	t.Run("CallToolResult", func(t *testing.T) {
+		want := CallToolResult{Result: Result{Meta: &Meta{Fields: map[string]any{"k": "v"}}}, Content: contents, IsError: true}
+		var got CallToolResult
+		roundTrip(t, want, &got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("GetPromptResult", func(t *testing.T) {
+		want := GetPromptResult{Description: "d"}
+		for _, content := range contents {
+			want.Messages = append(want.Messages, NewPromptMessage(RoleAssistant, content))
+		}
+		var got GetPromptResult
+		roundTrip(t, want, &got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("ReadResourceResult", func(t *testing.T) {
+		want := ReadResourceResult{Contents: []ResourceContents{
+			TextResourceContents{URI: "file:///a.txt", Text: "a"},
+			BlobResourceContents{URI: "file:///b.bin", MimeType: "application/octet-stream", Blob: "Yg=="},
+		}}
+		var got ReadResourceResult
+		roundTrip(t, want, &got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("CreateMessageResult", func(t *testing.T) {
+		want := CreateMessageResult{
+			Result:          Result{Meta: &Meta{Fields: map[string]any{"k": "v"}}},
+			SamplingMessage: SamplingMessage{Role: RoleAssistant, Content: contents[1]},
+			Model:           "m",
+			StopReason:      "endTurn",
+		}
+		var got CreateMessageResult
+		roundTrip(t, want, &got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("empty content", func(t *testing.T) {
+		var got CallToolResult
+		if err := json.Unmarshal([]byte(`{"content":null}`), &got); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if got.Content == nil || len(got.Content) != 0 {
+			t.Errorf("Expected an empty, non-nil content slice, got %#v", got.Content)
+		}
+	})
+}
+
+func TestContentFromJSONErrors(t *testing.T) {
+	inputs := []string{
+		`{"type":"video"}`,
+		`{"type":"resource"}`,
+		`{"type":"resource","resource":{"uri":"file:///x"}}`,
+		`[]`,
+	}
+	for _, input := range inputs {
+		if content, err := ContentFromJSON([]byte(input)); err == nil {
+			t.Errorf("Expected %s to fail, got %#v", input, content)
+		}
+	}
+
+	var result CallToolResult
+	if err := json.Unmarshal([]byte(`{"content":[{"type":"text","text":"a"},{"type":"nope"}]}`), &result); err == nil {
+		t.Errorf("Expected an unsupported content type to fail the result")
+	}
+}
+
+// roundTrip marshals in and unmarshals the JSON into out.
+func roundTrip(t *testing.T, in, out any) {
+	t.Helper()
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		t.Fatalf("Unmarshal of %s failed: %v", data, err)
+	}
+}
+
+func TestCompleteRequestParamsJSON(t *testing.T) {
+	t.Run("Marshal", func(t *testing.T) {
+		params := CompleteRequestParams{
+			Ref:      PromptReference{Name: "review"},
+			Argument: CompleteArgument{Name: "code", Value: "fu"},
+			Context:  &CompletionContext{Arguments: map[string]string{"language": "go"}},
+		}
+		data, err := json.Marshal(params)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		want := `{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"code","value":"fu"},"context":{"arguments":{"language":"go"}}}`
+		if string(data) != want {
+			t.Errorf("Expected %s, got %s", want, data)
+		}
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		var params CompleteRequestParams
+		data := `{"ref":{"type":"ref/resource","uri":"items://{id}"},"argument":{"name":"id","value":"1"}}`
+		if err := json.Unmarshal([]byte(data), &params); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if ref, ok := params.Ref.(ResourceReference); !ok || ref.URI != "items://{id}" {
+			t.Errorf("Expected a resource reference, got %#v", params.Ref)
+		}
+		if params.Argument.Name != "id" || params.Context != nil {
+			t.Errorf("Expected argument id without context, got %+v", params)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, data := range []string{`{"argument":{"name":"id","value":""}}`, `{"ref":{"type":"ref/tool","name":"x"}}`} {
+			var params CompleteRequestParams
+			if err := json.Unmarshal([]byte(data), &params); err == nil {
+				t.Errorf("Expected an error for %s", data)
+			}
+		}
+	})
+}
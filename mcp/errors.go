@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ErrorCode identifies the type of a JSON-RPC error
+type ErrorCode int
+
+// String returns the symbolic name of the error code
+func (c ErrorCode) String() string {
+	switch c {
+	case ErrorParseError:
+		return "ParseError"
+	case ErrorInvalidRequest:
+		return "InvalidRequest"
+	case ErrorMethodNotFound:
+		return "MethodNotFound"
+	case ErrorInvalidParams:
+		return "InvalidParams"
+	case ErrorInternalError:
+		return "InternalError"
+	case ErrorConnectionClosed:
+		return "ConnectionClosed"
+	case ErrorRequestTimeout:
+		return "RequestTimeout"
+	case ErrorResourceNotFound:
+		return "ResourceNotFound"
+	case ErrorToolNotFound:
+		return "ToolNotFound"
+	case ErrorUnauthorized:
+		return "Unauthorized"
+	case ErrorDuplicateName:
+		return "DuplicateName"
+	case ErrorInvalidProtocol:
+		return "InvalidProtocol"
+	case ErrorRateLimited:
+		return "RateLimited"
+	case ErrorServiceUnavailable:
+		return "ServiceUnavailable"
+	case ErrorSessionTerminated:
+		return "SessionTerminated"
+	}
+	if c <= -32000 && c >= -32099 {
+		return fmt.Sprintf("ServerError(%d)", int(c))
+	}
+	return fmt.Sprintf("ErrorCode(%d)", int(c))
+}
+
+// IsRetryable reports whether a request that failed with the given code
+// may succeed when sent again unchanged.
+func IsRetryable(code ErrorCode) bool {
+	switch code {
+	case ErrorConnectionClosed, ErrorRequestTimeout, ErrorRateLimited, ErrorServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// ErrorCodeFromHTTPStatus maps a non-successful HTTP status code to the
+// JSON-RPC error code that best describes it. Streamable HTTP servers answer
+// 404 Not Found to the requests of a terminated session, so that status maps
+// to ErrorSessionTerminated.
+func ErrorCodeFromHTTPStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest, http.StatusMethodNotAllowed, http.StatusNotAcceptable,
+		http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType:
+		return ErrorInvalidRequest
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorUnauthorized
+	case http.StatusNotFound:
+		return ErrorSessionTerminated
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrorRequestTimeout
+	case http.StatusConflict:
+		return ErrorDuplicateName
+	case http.StatusTooManyRequests:
+		return ErrorRateLimited
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return ErrorServiceUnavailable
+	}
+	if status >= 400 && status < 500 {
+		return ErrorInvalidRequest
+	}
+	return ErrorInternalError
+}
+
+// HTTPStatusFromErrorCode maps a JSON-RPC error code to the HTTP status code
+// a server should answer with when the error prevents a JSON-RPC response.
+// Only ErrorSessionTerminated maps to 404 Not Found, which clients take as
+// the end of their session.
+func HTTPStatusFromErrorCode(code ErrorCode) int {
+	switch code {
+	case ErrorParseError, ErrorInvalidRequest, ErrorInvalidParams, ErrorInvalidProtocol,
+		ErrorMethodNotFound, ErrorResourceNotFound, ErrorToolNotFound:
+		return http.StatusBadRequest
+	case ErrorUnauthorized:
+		return http.StatusUnauthorized
+	case ErrorSessionTerminated:
+		return http.StatusNotFound
+	case ErrorRequestTimeout:
+		return http.StatusRequestTimeout
+	case ErrorDuplicateName:
+		return http.StatusConflict
+	case ErrorRateLimited:
+		return http.StatusTooManyRequests
+	case ErrorConnectionClosed, ErrorServiceUnavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// Error is a JSON-RPC error object. It implements the error interface so it
+// can be returned as-is from client calls.
+type Error struct {
+	// Error code identifying the error type
+	Code ErrorCode `json:"code"`
+	// Concise error description
+	Message string `json:"message"`
+	// Additional error details
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// NewError creates a new Error with the given code and message.
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// NewHTTPError creates an Error describing a failed HTTP exchange.
+func NewHTTPError(status int, body []byte) *Error {
+	return &Error{
+		Code:    ErrorCodeFromHTTPStatus(status),
+		Message: fmt.Sprintf("request failed with status %d: %s", status, body),
+	}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("error %d: %s", e.Code, e.Message)
+}
+
+// Retryable reports whether the error is transient.
+func (e *Error) Retryable() bool {
+	return IsRetryable(e.Code)
+}
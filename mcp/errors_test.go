@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestErrorCodeString(t *testing.T) {
+	tests := map[ErrorCode]string{
+		ErrorParseError:    "ParseError",
+		ErrorRateLimited:   "RateLimited",
+		ErrorCode(-32050):  "ServerError(-32050)",
+		ErrorCode(42):      "ErrorCode(42)",
+		ErrorToolNotFound:  "ToolNotFound",
+		ErrorInternalError: "InternalError",
+	}
+	for code, expected := range tests {
+		if got := code.String(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	if !IsRetryable(ErrorRateLimited) || !IsRetryable(ErrorRequestTimeout) {
+		t.Errorf("Expected rate limiting and timeouts to be retryable")
+	}
+	if IsRetryable(ErrorInvalidParams) || IsRetryable(ErrorMethodNotFound) {
+		t.Errorf("Expected client errors not to be retryable")
+	}
+}
+
+func TestHTTPStatusMapping(t *testing.T) {
+	tests := []struct {
+		status int
+		code   ErrorCode
+	}{
+		{http.StatusBadRequest, ErrorInvalidRequest},
+		{http.StatusUnauthorized, ErrorUnauthorized},
+		{http.StatusNotFound, ErrorSessionTerminated},
+		{http.StatusTooManyRequests, ErrorRateLimited},
+		{http.StatusServiceUnavailable, ErrorServiceUnavailable},
+		{http.StatusTeapot, ErrorInvalidRequest},
+		{http.StatusInternalServerError, ErrorInternalError},
+	}
+	for _, tt := range tests {
+		if got := ErrorCodeFromHTTPStatus(tt.status); got != tt.code {
+			t.Errorf("Status %d: expected %s, got %s", tt.status, tt.code, got)
+		}
+	}
+
+	// The statuses a server answers with should map back to their code, and
+	// the codes to their status
+	for _, tt := range tests {
+		if tt.status == http.StatusTeapot {
+			continue
+		}
+		if got := HTTPStatusFromErrorCode(tt.code); got != tt.status {
+			t.Errorf("Code %s: expected status %d, got %d", tt.code, tt.status, got)
+		}
+	}
+	for _, code := range []ErrorCode{ErrorInvalidRequest, ErrorUnauthorized, ErrorSessionTerminated, ErrorRequestTimeout,
+		ErrorDuplicateName, ErrorRateLimited, ErrorServiceUnavailable, ErrorInternalError} {
+		if got := ErrorCodeFromHTTPStatus(HTTPStatusFromErrorCode(code)); got != code {
+			t.Errorf("Round trip of %s gave %s", code, got)
+		}
+	}
+	for _, code := range []ErrorCode{ErrorMethodNotFound, ErrorResourceNotFound, ErrorToolNotFound} {
+		if got := HTTPStatusFromErrorCode(code); got == http.StatusNotFound {
+			t.Errorf("Expected %s not to map to 404, which ends the session", code)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	var err error = NewHTTPError(http.StatusTooManyRequests, []byte("slow down"))
+
+	var mcpErr *Error
+	if !errors.As(err, &mcpErr) {
+		t.Fatalf("Expected *Error, got %T", err)
+	}
+	if mcpErr.Code != ErrorRateLimited || !mcpErr.Retryable() {
+		t.Errorf("Expected retryable RateLimited error, got %v", mcpErr)
+	}
+
+	var decoded Error
+	if err := json.Unmarshal([]byte(`{"code":-32602,"message":"bad","data":{"field":"x"}}`), &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal error: %v", err)
+	}
+	if decoded.Code != ErrorInvalidParams || string(decoded.Data) != `{"field":"x"}` {
+		t.Errorf("Unexpected decoded error: %+v", decoded)
+	}
+}
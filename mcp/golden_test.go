@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// goldenTypes maps the fixtures of testdata/golden, named after the types,
+// to a constructor of the type. The fixtures are the examples of the spec,
+// as the type encodes them: without the jsonrpc and id members for the
+// request, result and notification types.
+var goldenTypes = map[string]func() any{
+	"JSONRPCRequest":                  func() any { return new(JSONRPCRequest) },
+	"JSONRPCNotification":             func() any { return new(JSONRPCNotification) },
+	"JSONRPCResponse":                 func() any { return new(JSONRPCResponse) },
+	"JSONRPCError":                    func() any { return new(JSONRPCError) },
+	"InitializeRequest":               func() any { return new(InitializeRequest) },
+	"InitializeResult":                func() any { return new(InitializeResult) },
+	"InitializedNotification":         func() any { return new(InitializedNotification) },
+	"PingRequest":                     func() any { return new(PingRequest) },
+	"PingResult":                      func() any { return new(PingResult) },
+	"CancelledNotification":           func() any { return new(CancelledNotification) },
+	"ProgressNotification":            func() any { return new(ProgressNotification) },
+	"ListToolsRequest":                func() any { return new(ListToolsRequest) },
+	"ListToolsResult":                 func() any { return new(ListToolsResult) },
+	"CallToolRequest":                 func() any { return new(CallToolRequest) },
+	"CallToolResult":                  func() any { return new(CallToolResult) },
+	"ToolListChangedNotification":     func() any { return new(ToolListChangedNotification) },
+	"ListResourcesRequest":            func() any { return new(ListResourcesRequest) },
+	"ListResourcesResult":             func() any { return new(ListResourcesResult) },
+	"ListResourceTemplatesRequest":    func() any { return new(ListResourceTemplatesRequest) },
+	"ListResourceTemplatesResult":     func() any { return new(ListResourceTemplatesResult) },
+	"ReadResourceRequest":             func() any { return new(ReadResourceRequest) },
+	"ReadResourceResult":              func() any { return new(ReadResourceResult) },
+	"SubscribeRequest":                func() any { return new(SubscribeRequest) },
+	"UnsubscribeRequest":              func() any { return new(UnsubscribeRequest) },
+	"ResourceUpdatedNotification":     func() any { return new(ResourceUpdatedNotification) },
+	"ResourceListChangedNotification": func() any { return new(ResourceListChangedNotification) },
+	"ListPromptsRequest":              func() any { return new(ListPromptsRequest) },
+	"ListPromptsResult":               func() any { return new(ListPromptsResult) },
+	"GetPromptRequest":                func() any { return new(GetPromptRequest) },
+	"GetPromptResult":                 func() any { return new(GetPromptResult) },
+	"PromptListChangedNotification":   func() any { return new(PromptListChangedNotification) },
+	"SetLevelRequest":                 func() any { return new(SetLevelRequest) },
+	"LoggingMessageNotification":      func() any { return new(LoggingMessageNotification) },
+	"CompleteRequest":                 func() any { return new(CompleteRequest) },
+	"CompleteResult":                  func() any { return new(CompleteResult) },
+	"ListRootsRequest":                func() any { return new(ListRootsRequest) },
+	"ListRootsResult":                 func() any { return new(ListRootsResult) },
+	"RootsListChangedNotification":    func() any { return new(RootsListChangedNotification) },
+	"CreateMessageRequest":            func() any { return new(CreateMessageRequest) },
+	"CreateMessageResult":             func() any { return new(CreateMessageResult) },
+}
+
+// TestGolden decodes each fixture into its type, rejecting unknown fields,
+// and checks that encoding it again gives the same JSON, catching renamed
+// fields and zero values encoded without omitempty.
+func TestGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "golden", "*.json"))
+	if err != nil {
+		t.Fatalf("Failed to list fixtures: %v", err)
+	}
+	found := map[string]bool{}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		found[name] = true
+		t.Run(name, func(t *testing.T) {
+			newValue, ok := goldenTypes[name]
+			if !ok {
+				t.Fatalf("Expected a type for fixture %s", file)
+			}
+			golden, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("Failed to read fixture: %v", err)
+			}
+
+			value := newValue()
+			decoder := json.NewDecoder(bytes.NewReader(golden))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(value); err != nil {
+				t.Fatalf("Failed to decode fixture: %v", err)
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				t.Fatalf("Failed to encode %T: %v", value, err)
+			}
+
+			if want, got := decodeAny(t, golden), decodeAny(t, encoded); !reflect.DeepEqual(got, want) {
+				indented, _ := json.MarshalIndent(got, "", "  ")
+				t.Errorf("Expected %T to encode as the fixture, got:\n%s", value, indented)
+			}
+		})
+	}
+	for name := range goldenTypes {
+		if !found[name] {
+			t.Errorf("Expected a fixture for %s in testdata/golden", name)
+		}
+	}
+}
+
+// decodeAny decodes JSON into generic values, keeping numbers as written.
+func decodeAny(t *testing.T, data []byte) any {
+	t.Helper()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		t.Fatalf("Failed to decode %s: %v", data, err)
+	}
+	return v
+}
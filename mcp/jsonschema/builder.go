@@ -0,0 +1,143 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Schema is a JSON Schema under construction. Its methods set a keyword and
+// return the schema, so that schemas read as a single expression:
+//
+//	tool.InputSchema = jsonschema.Object().
+//		Prop("query", jsonschema.String().Desc("Text to search for")).
+//		Prop("limit", jsonschema.Integer().Min(1).Max(100)).
+//		Required("query").
+//		Build()
+type Schema struct {
+	keywords map[string]any
+}
+
+func newSchema(schemaType string) *Schema {
+	s := &Schema{keywords: map[string]any{}}
+	if schemaType != "" {
+		s.keywords["type"] = schemaType
+	}
+	return s
+}
+
+// Object returns an object schema.
+func Object() *Schema { return newSchema("object") }
+
+// String returns a string schema.
+func String() *Schema { return newSchema("string") }
+
+// Integer returns an integer schema.
+func Integer() *Schema { return newSchema("integer") }
+
+// Number returns a number schema.
+func Number() *Schema { return newSchema("number") }
+
+// Boolean returns a boolean schema.
+func Boolean() *Schema { return newSchema("boolean") }
+
+// Array returns an array schema whose items match items.
+func Array(items *Schema) *Schema {
+	return newSchema("array").Set("items", items)
+}
+
+// Any returns a schema accepting every value.
+func Any() *Schema { return newSchema("") }
+
+// Set sets a keyword the builder has no method for.
+func (s *Schema) Set(keyword string, value any) *Schema {
+	s.keywords[keyword] = value
+	return s
+}
+
+// Desc sets the description.
+func (s *Schema) Desc(description string) *Schema { return s.Set("description", description) }
+
+// Title sets the title.
+func (s *Schema) Title(title string) *Schema { return s.Set("title", title) }
+
+// Default sets the default value.
+func (s *Schema) Default(value any) *Schema { return s.Set("default", value) }
+
+// Enum restricts the value to values.
+func (s *Schema) Enum(values ...any) *Schema { return s.Set("enum", values) }
+
+// Const restricts the value to value.
+func (s *Schema) Const(value any) *Schema { return s.Set("const", value) }
+
+// Format sets the format, such as "date-time" or "uri".
+func (s *Schema) Format(format string) *Schema { return s.Set("format", format) }
+
+// Pattern sets the regular expression strings must match.
+func (s *Schema) Pattern(pattern string) *Schema { return s.Set("pattern", pattern) }
+
+// MinLength sets the minimum length of strings.
+func (s *Schema) MinLength(n int) *Schema { return s.Set("minLength", n) }
+
+// MaxLength sets the maximum length of strings.
+func (s *Schema) MaxLength(n int) *Schema { return s.Set("maxLength", n) }
+
+// Min sets the inclusive minimum of numbers.
+func (s *Schema) Min(n float64) *Schema { return s.Set("minimum", n) }
+
+// Max sets the inclusive maximum of numbers.
+func (s *Schema) Max(n float64) *Schema { return s.Set("maximum", n) }
+
+// MinItems sets the minimum length of arrays.
+func (s *Schema) MinItems(n int) *Schema { return s.Set("minItems", n) }
+
+// MaxItems sets the maximum length of arrays.
+func (s *Schema) MaxItems(n int) *Schema { return s.Set("maxItems", n) }
+
+// UniqueItems requires the items of arrays to differ.
+func (s *Schema) UniqueItems() *Schema { return s.Set("uniqueItems", true) }
+
+// Nullable also accepts null.
+func (s *Schema) Nullable() *Schema {
+	if t, ok := s.keywords["type"].(string); ok {
+		return s.Set("type", []string{t, "null"})
+	}
+	return s
+}
+
+// Prop adds the property name to an object schema.
+func (s *Schema) Prop(name string, property *Schema) *Schema {
+	properties, _ := s.keywords["properties"].(map[string]*Schema)
+	if properties == nil {
+		properties = map[string]*Schema{}
+		s.keywords["properties"] = properties
+	}
+	properties[name] = property
+	return s
+}
+
+// Required marks properties of an object schema as required.
+func (s *Schema) Required(names ...string) *Schema {
+	required, _ := s.keywords["required"].([]string)
+	return s.Set("required", append(required, names...))
+}
+
+// AdditionalProperties allows or forbids properties not declared with Prop.
+func (s *Schema) AdditionalProperties(allowed bool) *Schema {
+	return s.Set("additionalProperties", allowed)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.keywords)
+}
+
+// Build returns the schema as JSON, for instance for mcp.Tool.InputSchema.
+// It panics if a value given to Default, Enum, Const or Set cannot be
+// encoded as JSON.
+func (s *Schema) Build() json.RawMessage {
+	data, err := json.Marshal(s)
+	if err != nil {
+		panic(fmt.Sprintf("jsonschema: failed to encode schema: %v", err))
+	}
+	return data
+}
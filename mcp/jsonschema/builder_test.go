@@ -0,0 +1,57 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	schema := Object().
+		Prop("query", String().Desc("Text to search for").MinLength(1)).
+		Prop("limit", Integer().Min(1).Max(100).Default(10)).
+		Prop("mode", String().Enum("fast", "exact")).
+		Prop("tags", Array(String()).UniqueItems()).
+		Prop("after", String().Format("date-time").Nullable()).
+		Required("query").
+		AdditionalProperties(false).
+		Build()
+
+	var got map[string]any
+	if err := json.Unmarshal(schema, &got); err != nil {
+		t.Fatalf("Failed to parse built schema: %v", err)
+	}
+	var want map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"query": {"type": "string", "description": "Text to search for", "minLength": 1},
+			"limit": {"type": "integer", "minimum": 1, "maximum": 100, "default": 10},
+			"mode": {"type": "string", "enum": ["fast", "exact"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+			"after": {"type": ["string", "null"], "format": "date-time"}
+		},
+		"required": ["query"],
+		"additionalProperties": false
+	}`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	t.Run("Validates", func(t *testing.T) {
+		if err := Default.Validate(schema, map[string]any{"query": "go", "after": nil}); err != nil {
+			t.Errorf("Expected a valid instance, got %v", err)
+		}
+		if err := Default.Validate(schema, map[string]any{"limit": 0}); err == nil {
+			t.Error("Expected an invalid instance")
+		}
+	})
+
+	t.Run("Any", func(t *testing.T) {
+		if got := string(Any().Build()); got != "{}" {
+			t.Errorf("Expected {}, got %s", got)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tagKeys are the keys understood in jsonschema struct tags.
+var tagKeys = []string{"description", "title", "enum", "format", "pattern", "minimum", "maximum", "minLength", "maxLength", "minItems", "maxItems", "default", "required"}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// SchemaFor derives the schema of the JSON encoding of T, typically the
+// arguments or result struct of a tool:
+//
+//	type searchArgs struct {
+//		Query string `json:"query" jsonschema:"description=Text to search for"`
+//		Mode  string `json:"mode,omitempty" jsonschema:"enum=fast|exact"`
+//	}
+//	tool.InputSchema = jsonschema.SchemaFor[searchArgs]().Build()
+//
+// Fields follow the json tags. A field is required unless it is a pointer or
+// tagged omitempty, and pointers not tagged omitempty also accept null; "required=false" or "required=true" in the jsonschema tag
+// overrides this. The jsonschema tag holds comma-separated key=value pairs
+// among description, title, enum (values separated by |), format, pattern,
+// minimum, maximum, minLength, maxLength, minItems, maxItems and default. A
+// comma not followed by one of those keys is part of the value.
+//
+// time.Time becomes a date-time string, []byte a base64 string, maps objects
+// with additionalProperties, and interfaces, json.RawMessage and recursive
+// types accept any value.
+func SchemaFor[T any]() *Schema {
+	return schemaForType(reflect.TypeFor[T](), map[reflect.Type]bool{})
+}
+
+func schemaForType(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return String().Format("date-time")
+	case t == rawMessageType:
+		return Any()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return String()
+	case reflect.Bool:
+		return Boolean()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Integer()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return Integer().Min(0)
+	case reflect.Float32, reflect.Float64:
+		return Number()
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return String().Set("contentEncoding", "base64")
+		}
+		return Array(schemaForType(t.Elem(), visiting))
+	case reflect.Map:
+		return Object().Set("additionalProperties", schemaForType(t.Elem(), visiting))
+	case reflect.Struct:
+		if visiting[t] {
+			return Any()
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		s := Object()
+		addFields(s, t, visiting)
+		return s
+	}
+	return Any()
+}
+
+// addFields adds the properties of the fields of struct type t to s,
+// flattening embedded structs the way encoding/json does.
+func addFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				addFields(s, fieldType, visiting)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := schemaForType(field.Type, visiting)
+		omitempty := strings.Contains(","+options+",", ",omitempty,")
+		required := field.Type.Kind() != reflect.Pointer && !omitempty
+		// A nil pointer is encoded as null unless omitted
+		if field.Type.Kind() == reflect.Pointer && !omitempty {
+			property.Nullable()
+		}
+		for key, value := range parseTag(field.Tag.Get("jsonschema")) {
+			if key == "required" {
+				required = value == "true"
+				continue
+			}
+			applyTag(property, key, value)
+		}
+		s.Prop(name, property)
+		if required {
+			s.Required(name)
+		}
+	}
+}
+
+// parseTag splits a jsonschema tag into its key=value pairs.
+func parseTag(tag string) map[string]string {
+	pairs := map[string]string{}
+	if tag == "" {
+		return pairs
+	}
+	var key string
+	for _, part := range strings.Split(tag, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if ok && isTagKey(k) {
+			key = k
+			pairs[key] = v
+			continue
+		}
+		if key != "" {
+			pairs[key] += "," + part
+		}
+	}
+	return pairs
+}
+
+func isTagKey(key string) bool {
+	for _, k := range tagKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTag sets the keyword of a jsonschema tag pair on s, converting the
+// value to the type of the schema where needed.
+func applyTag(s *Schema, key, value string) {
+	switch key {
+	case "enum":
+		var values []any
+		for _, v := range strings.Split(value, "|") {
+			values = append(values, s.literal(v))
+		}
+		s.Enum(values...)
+	case "default":
+		s.Default(s.literal(value))
+	case "minimum", "maximum":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			s.Set(key, n)
+		}
+	case "minLength", "maxLength", "minItems", "maxItems":
+		if n, err := strconv.Atoi(value); err == nil {
+			s.Set(key, n)
+		}
+	default:
+		s.Set(key, value)
+	}
+}
+
+// literal converts a tag value to a value of the schema's type.
+func (s *Schema) literal(value string) any {
+	switch s.keywords["type"] {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
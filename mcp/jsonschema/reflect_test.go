@@ -0,0 +1,86 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type paging struct {
+	Limit int `json:"limit,omitempty" jsonschema:"minimum=1,maximum=100,default=10"`
+}
+
+type node struct {
+	Name     string  `json:"name"`
+	Children []*node `json:"children,omitempty"`
+}
+
+type searchArgs struct {
+	paging
+	Query    string             `json:"query" jsonschema:"description=Text to search for, in any language"`
+	Mode     string             `json:"mode,omitempty" jsonschema:"enum=fast|exact"`
+	Tags     []string           `json:"tags"`
+	After    *time.Time         `json:"after"`
+	Weights  map[string]float64 `json:"weights" jsonschema:"required=false"`
+	Raw      []byte             `json:"raw,omitempty"`
+	Extra    json.RawMessage    `json:"extra,omitempty"`
+	Tree     *node              `json:"tree,omitempty"`
+	Ignored  string             `json:"-"`
+	internal string
+}
+
+func TestSchemaFor(t *testing.T) {
+	var got map[string]any
+	if err := json.Unmarshal(SchemaFor[searchArgs]().Build(), &got); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	var want map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"limit": {"type": "integer", "minimum": 1, "maximum": 100, "default": 10},
+			"query": {"type": "string", "description": "Text to search for, in any language"},
+			"mode": {"type": "string", "enum": ["fast", "exact"]},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"after": {"type": ["string", "null"], "format": "date-time"},
+			"weights": {"type": "object", "additionalProperties": {"type": "number"}},
+			"raw": {"type": "string", "contentEncoding": "base64"},
+			"extra": {},
+			"tree": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {}}
+				},
+				"required": ["name"]
+			}
+		},
+		"required": ["query", "tags"]
+	}`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		t.Errorf("Unexpected schema:\n%s", gotJSON)
+	}
+
+	t.Run("Validates", func(t *testing.T) {
+		schema := SchemaFor[searchArgs]().Build()
+		if err := Default.Validate(schema, searchArgs{Query: "go", Tags: []string{}, Weights: map[string]float64{}, Mode: "fast"}); err != nil {
+			t.Errorf("Expected a valid instance, got %v", err)
+		}
+		if err := Default.Validate(schema, map[string]any{"query": "go", "tags": []string{}, "mode": "slow"}); err == nil {
+			t.Error("Expected an invalid mode to be rejected")
+		}
+	})
+
+	t.Run("Scalars", func(t *testing.T) {
+		if got := string(SchemaFor[uint8]().Build()); got != `{"minimum":0,"type":"integer"}` {
+			t.Errorf("Unexpected schema for uint8: %s", got)
+		}
+		if got := string(SchemaFor[[]bool]().Build()); got != `{"items":{"type":"boolean"},"type":"array"}` {
+			t.Errorf("Unexpected schema for []bool: %s", got)
+		}
+	})
+}
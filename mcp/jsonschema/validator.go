@@ -0,0 +1,461 @@
+// Package jsonschema provides JSON Schema support for MCP tool schemas.
+//
+// Validation is abstracted behind the Validator interface so applications can
+// plug in a full implementation, for instance github.com/santhosh-tekuri/jsonschema:
+//
+//	v := jsonschema.ValidatorFunc(func(schema json.RawMessage, instance any) error {
+//		compiled, err := santhosh.CompileString("schema.json", string(schema))
+//		if err != nil {
+//			return err
+//		}
+//		return compiled.Validate(instance)
+//	})
+//
+// or disable validation entirely with Nop.
+//
+// Schemas can be written with the fluent builder instead of JSON strings:
+//
+//	jsonschema.Object().Prop("q", jsonschema.String().Desc("query")).Required("q").Build()
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Validator validates a JSON instance against a JSON Schema.
+// The instance is either raw JSON (json.RawMessage or []byte) or a Go value
+// that encodes to JSON, such as a map[string]any of tool arguments.
+type Validator interface {
+	Validate(schema json.RawMessage, instance any) error
+}
+
+// ValidatorFunc adapts a function to the Validator interface.
+type ValidatorFunc func(schema json.RawMessage, instance any) error
+
+// Validate implements Validator.
+func (f ValidatorFunc) Validate(schema json.RawMessage, instance any) error {
+	return f(schema, instance)
+}
+
+// Nop is a Validator that accepts every instance, disabling validation.
+var Nop Validator = ValidatorFunc(func(json.RawMessage, any) error { return nil })
+
+// Default is the bundled lightweight Validator.
+var Default Validator = NewLiteValidator()
+
+// Violation describes one way in which an instance fails a schema.
+type Violation struct {
+	// Path is a JSON Pointer to the offending value, empty for the root
+	Path string
+	// Message describes the violation
+	Message string
+}
+
+func (v Violation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + v.Message
+}
+
+// ValidationError lists all violations found in an instance.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return "schema validation failed: " + strings.Join(parts, "; ")
+}
+
+// maxRefDepth bounds $ref resolution for a single value.
+const maxRefDepth = 32
+
+// LiteValidator is a dependency-free validator covering the JSON Schema
+// keywords commonly found in tool schemas: type, enum, const, properties,
+// required, additionalProperties, items, numeric and length bounds, pattern,
+// allOf, anyOf, oneOf, not, and local $ref into $defs or definitions.
+// Unknown keywords, including format, are ignored.
+type LiteValidator struct {
+	schemas sync.Map // string(schema) -> map[string]any
+	regexps sync.Map // pattern -> *regexp.Regexp
+}
+
+// NewLiteValidator creates a LiteValidator.
+func NewLiteValidator() *LiteValidator {
+	return &LiteValidator{}
+}
+
+// Validate implements Validator.
+func (v *LiteValidator) Validate(schema json.RawMessage, instance any) error {
+	root, err := v.compile(schema)
+	if err != nil {
+		return err
+	}
+	value, err := normalize(instance)
+	if err != nil {
+		return fmt.Errorf("invalid instance: %w", err)
+	}
+
+	var violations []Violation
+	v.validate(root, root, value, "", 0, &violations)
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func (v *LiteValidator) compile(schema json.RawMessage) (any, error) {
+	if len(schema) == 0 {
+		return true, nil
+	}
+	if cached, ok := v.schemas.Load(string(schema)); ok {
+		return cached, nil
+	}
+	var parsed any
+	if err := json.Unmarshal(schema, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	v.schemas.Store(string(schema), parsed)
+	return parsed, nil
+}
+
+// normalize converts an instance into the generic representation produced by encoding/json.
+func normalize(instance any) (any, error) {
+	var data []byte
+	switch i := instance.(type) {
+	case json.RawMessage:
+		data = i
+	case []byte:
+		data = i
+	default:
+		var err error
+		if data, err = json.Marshal(instance); err != nil {
+			return nil, err
+		}
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (v *LiteValidator) validate(root, schema, value any, path string, refs int, violations *[]Violation) {
+	fail := func(format string, args ...any) {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var s map[string]any
+	switch typed := schema.(type) {
+	case bool:
+		if !typed {
+			fail("no value is allowed")
+		}
+		return
+	case map[string]any:
+		s = typed
+	default:
+		return
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		target, err := resolveRef(root, ref)
+		if err != nil {
+			fail("%v", err)
+			return
+		}
+		// Guard against references that loop without consuming the instance
+		if refs >= maxRefDepth {
+			fail("too many nested $ref")
+			return
+		}
+		v.validate(root, target, value, path, refs+1, violations)
+	}
+
+	if types, ok := schemaTypes(s["type"]); ok && !matchesAnyType(value, types) {
+		fail("expected %s, got %s", strings.Join(types, " or "), typeOf(value))
+		return
+	}
+
+	if enum, ok := s["enum"].([]any); ok {
+		found := false
+		for _, candidate := range enum {
+			if reflect.DeepEqual(candidate, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value must be one of %s", compact(enum))
+		}
+	}
+	if constant, ok := s["const"]; ok && !reflect.DeepEqual(constant, value) {
+		fail("value must be %s", compact(constant))
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateObject(root, s, val, path, violations)
+	case []any:
+		v.validateArray(root, s, val, path, violations)
+	case string:
+		length := utf8.RuneCountInString(val)
+		if min, ok := number(s["minLength"]); ok && float64(length) < min {
+			fail("string shorter than %v characters", min)
+		}
+		if max, ok := number(s["maxLength"]); ok && float64(length) > max {
+			fail("string longer than %v characters", max)
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			re, err := v.regexp(pattern)
+			if err != nil {
+				fail("invalid pattern %q: %v", pattern, err)
+			} else if !re.MatchString(val) {
+				fail("string does not match pattern %q", pattern)
+			}
+		}
+	case float64:
+		if min, ok := number(s["minimum"]); ok && val < min {
+			fail("value must be >= %v", min)
+		}
+		if max, ok := number(s["maximum"]); ok && val > max {
+			fail("value must be <= %v", max)
+		}
+		if min, ok := number(s["exclusiveMinimum"]); ok && val <= min {
+			fail("value must be > %v", min)
+		}
+		if max, ok := number(s["exclusiveMaximum"]); ok && val >= max {
+			fail("value must be < %v", max)
+		}
+		if multiple, ok := number(s["multipleOf"]); ok && multiple > 0 {
+			if !isMultiple(val, multiple) {
+				fail("value must be a multiple of %v", multiple)
+			}
+		}
+	}
+
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			v.validate(root, sub, value, path, refs, violations)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok && v.countMatches(root, anyOf, value, path, refs) == 0 {
+		fail("value does not match any of the allowed schemas")
+	}
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		if n := v.countMatches(root, oneOf, value, path, refs); n != 1 {
+			fail("value must match exactly one schema, matched %d", n)
+		}
+	}
+	if not, ok := s["not"]; ok && v.countMatches(root, []any{not}, value, path, refs) == 1 {
+		fail("value must not match the schema")
+	}
+}
+
+func (v *LiteValidator) validateObject(root any, s map[string]any, obj map[string]any, path string, violations *[]Violation) {
+	if required, ok := s["required"].([]any); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, present := obj[key]; !present {
+					*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf("missing required property %q", key)})
+				}
+			}
+		}
+	}
+	if min, ok := number(s["minProperties"]); ok && float64(len(obj)) < min {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf("object must have at least %v properties", min)})
+	}
+	if max, ok := number(s["maxProperties"]); ok && float64(len(obj)) > max {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf("object must have at most %v properties", max)})
+	}
+
+	properties, _ := s["properties"].(map[string]any)
+	additional, hasAdditional := s["additionalProperties"]
+
+	// Visit keys in order so violations are reported deterministically
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "/" + escapePointer(key)
+		if sub, ok := properties[key]; ok {
+			v.validate(root, sub, obj[key], childPath, 0, violations)
+			continue
+		}
+		if hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				*violations = append(*violations, Violation{Path: childPath, Message: "additional property is not allowed"})
+				continue
+			}
+			v.validate(root, additional, obj[key], childPath, 0, violations)
+		}
+	}
+}
+
+func (v *LiteValidator) validateArray(root any, s map[string]any, arr []any, path string, violations *[]Violation) {
+	if min, ok := number(s["minItems"]); ok && float64(len(arr)) < min {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf("array must have at least %v items", min)})
+	}
+	if max, ok := number(s["maxItems"]); ok && float64(len(arr)) > max {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf("array must have at most %v items", max)})
+	}
+	if unique, ok := s["uniqueItems"].(bool); ok && unique {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if reflect.DeepEqual(arr[i], arr[j]) {
+					*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf("items %d and %d are equal", i, j)})
+				}
+			}
+		}
+	}
+	if items, ok := s["items"]; ok {
+		for i, item := range arr {
+			v.validate(root, items, item, fmt.Sprintf("%s/%d", path, i), 0, violations)
+		}
+	}
+}
+
+// countMatches returns how many of the schemas the value satisfies.
+func (v *LiteValidator) countMatches(root any, schemas []any, value any, path string, refs int) int {
+	n := 0
+	for _, sub := range schemas {
+		var violations []Violation
+		v.validate(root, sub, value, path, refs, &violations)
+		if len(violations) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (v *LiteValidator) regexp(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := v.regexps.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	v.regexps.Store(pattern, re)
+	return re, nil
+}
+
+// resolveRef resolves a local JSON Pointer reference such as "#/$defs/item".
+func resolveRef(root any, ref string) (any, error) {
+	if ref == "#" {
+		return root, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	current := root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		if current, ok = obj[token]; !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+	}
+	return current, nil
+}
+
+func schemaTypes(t any) ([]string, bool) {
+	switch t := t.(type) {
+	case string:
+		return []string{t}, true
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types, len(types) > 0
+	}
+	return nil, false
+}
+
+func matchesAnyType(value any, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if f, ok := value.(float64); ok && f == math.Trunc(f) && !math.IsInf(f, 0) {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		default:
+			if typeOf(value) == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func number(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func compact(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// isMultiple reports whether val is a multiple of multiple. Their shortest
+// decimal forms are divided exactly, as float division makes 0.3 / 0.1
+// 2.9999999999999996.
+func isMultiple(val, multiple float64) bool {
+	v, ok := new(big.Rat).SetString(strconv.FormatFloat(val, 'g', -1, 64))
+	m, mok := new(big.Rat).SetString(strconv.FormatFloat(multiple, 'g', -1, 64))
+	if !ok || !mok {
+		return false
+	}
+	return v.Quo(v, m).IsInt()
+}
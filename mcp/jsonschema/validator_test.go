@@ -0,0 +1,151 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const searchSchema = `{
+	"type": "object",
+	"properties": {
+		"query": {"type": "string", "minLength": 1},
+		"limit": {"type": "integer", "minimum": 1, "maximum": 100},
+		"mode": {"enum": ["fast", "exact"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+		"filter": {"$ref": "#/$defs/filter"}
+	},
+	"required": ["query"],
+	"additionalProperties": false,
+	"$defs": {
+		"filter": {
+			"type": "object",
+			"properties": {"field": {"type": "string", "pattern": "^[a-z]+$"}},
+			"required": ["field"]
+		}
+	}
+}`
+
+func TestLiteValidator(t *testing.T) {
+	v := NewLiteValidator()
+
+	tests := []struct {
+		name       string
+		instance   any
+		violations []string
+	}{
+		{
+			name:     "Valid",
+			instance: map[string]any{"query": "go", "limit": 10, "mode": "fast", "tags": []string{"a", "b"}, "filter": map[string]any{"field": "name"}},
+		},
+		{
+			name:       "MissingRequired",
+			instance:   map[string]any{"limit": 10},
+			violations: []string{`(root): missing required property "query"`},
+		},
+		{
+			name:     "WrongTypes",
+			instance: map[string]any{"query": 42, "limit": 2.5},
+			violations: []string{
+				"/limit: expected integer, got number",
+				"/query: expected string, got number",
+			},
+		},
+		{
+			name:     "Bounds",
+			instance: map[string]any{"query": "", "limit": 500, "mode": "slow"},
+			violations: []string{
+				"/limit: value must be <= 100",
+				`/mode: value must be one of ["fast","exact"]`,
+				"/query: string shorter than 1 characters",
+			},
+		},
+		{
+			name:     "NestedRefAndAdditional",
+			instance: json.RawMessage(`{"query":"q","tags":["x","x"],"filter":{"field":"Bad!"},"extra":true}`),
+			violations: []string{
+				"/extra: additional property is not allowed",
+				`/filter/field: string does not match pattern "^[a-z]+$"`,
+				"/tags: items 0 and 1 are equal",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(json.RawMessage(searchSchema), tt.instance)
+			if len(tt.violations) == 0 {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+			var got []string
+			for _, violation := range validationErr.Violations {
+				got = append(got, violation.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.violations, "\n") {
+				t.Errorf("Expected violations:\n%s\ngot:\n%s", strings.Join(tt.violations, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestLiteValidatorCombinators(t *testing.T) {
+	schema := json.RawMessage(`{"oneOf":[{"type":"string"},{"type":"integer"}],"not":{"const":"forbidden"}}`)
+	v := NewLiteValidator()
+
+	for _, valid := range []any{"ok", 3} {
+		if err := v.Validate(schema, valid); err != nil {
+			t.Errorf("Expected %v to be valid, got %v", valid, err)
+		}
+	}
+	for _, invalid := range []any{"forbidden", 1.5, true} {
+		if err := v.Validate(schema, invalid); err == nil {
+			t.Errorf("Expected %v to be invalid", invalid)
+		}
+	}
+}
+
+func TestLiteValidatorMultipleOf(t *testing.T) {
+	v := NewLiteValidator()
+	for schema, tests := range map[string]map[float64]bool{
+		`{"multipleOf": 0.1}`:  {0.3: true, 0.7: true, 1.1: true, 123.4: true, 0.35: false},
+		`{"multipleOf": 0.01}`: {19.99: true, 0.07: true, 0.005: false},
+		`{"multipleOf": 3}`:    {9: true, -6: true, 1e15: false, 10: false},
+	} {
+		for value, valid := range tests {
+			err := v.Validate(json.RawMessage(schema), value)
+			if valid && err != nil {
+				t.Errorf("Expected %v to be valid against %s, got %v", value, schema, err)
+			}
+			if !valid && err == nil {
+				t.Errorf("Expected %v to be invalid against %s", value, schema)
+			}
+		}
+	}
+}
+
+func TestLiteValidatorRecursiveRef(t *testing.T) {
+	v := NewLiteValidator()
+	if err := v.Validate(json.RawMessage(`{"$ref":"#"}`), "anything"); err == nil {
+		t.Errorf("Expected self-referencing schema to be rejected")
+	}
+
+	tree := json.RawMessage(`{"type":"object","properties":{"children":{"type":"array","items":{"$ref":"#"}}}}`)
+	if err := v.Validate(tree, json.RawMessage(`{"children":[{"children":[{}]}]}`)); err != nil {
+		t.Errorf("Expected recursive tree to be valid, got %v", err)
+	}
+}
+
+func TestNop(t *testing.T) {
+	if err := Nop.Validate(json.RawMessage(`false`), "anything"); err != nil {
+		t.Errorf("Expected Nop to accept everything, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NewImageContentFromFile returns the image in the file at path as
+// ImageContent, its MIME type detected from the file extension or contents.
+func NewImageContentFromFile(path string) (ImageContent, error) {
+	data, mimeType, err := readMediaFile(path, "image/")
+	if err != nil {
+		return ImageContent{}, err
+	}
+	return NewImageContent(data, mimeType), nil
+}
+
+// NewImageContentFromReader returns the image read from r as ImageContent.
+// An empty mimeType is detected from the contents.
+func NewImageContentFromReader(r io.Reader, mimeType string) (ImageContent, error) {
+	data, mimeType, err := readMedia(r, mimeType, "", "image/")
+	if err != nil {
+		return ImageContent{}, err
+	}
+	return NewImageContent(data, mimeType), nil
+}
+
+// NewAudioContentFromFile returns the audio in the file at path as
+// AudioContent, its MIME type detected from the file extension or contents.
+func NewAudioContentFromFile(path string) (AudioContent, error) {
+	data, mimeType, err := readMediaFile(path, "audio/")
+	if err != nil {
+		return AudioContent{}, err
+	}
+	return NewAudioContent(data, mimeType), nil
+}
+
+// NewAudioContentFromReader returns the audio read from r as AudioContent.
+// An empty mimeType is detected from the contents.
+func NewAudioContentFromReader(r io.Reader, mimeType string) (AudioContent, error) {
+	data, mimeType, err := readMedia(r, mimeType, "", "audio/")
+	if err != nil {
+		return AudioContent{}, err
+	}
+	return NewAudioContent(data, mimeType), nil
+}
+
+// NewBlobResourceContents returns binary data as BlobResourceContents.
+func NewBlobResourceContents(uri string, data []byte, mimeType string) BlobResourceContents {
+	return BlobResourceContents{URI: uri, MimeType: mimeType, Blob: base64.StdEncoding.EncodeToString(data)}
+}
+
+// Decode returns the bytes of the image.
+func (c ImageContent) Decode() ([]byte, error) {
+	return decodeBase64(c.Data, "image")
+}
+
+// Decode returns the bytes of the audio.
+func (c AudioContent) Decode() ([]byte, error) {
+	return decodeBase64(c.Data, "audio")
+}
+
+// Decode returns the bytes of the blob.
+func (c BlobResourceContents) Decode() ([]byte, error) {
+	return decodeBase64(c.Blob, "blob")
+}
+
+func decodeBase64(data, kind string) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", kind, err)
+	}
+	return decoded, nil
+}
+
+// readMediaFile reads a file as base64 data of a MIME type starting with
+// prefix.
+func readMediaFile(path, prefix string) (string, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	return readMedia(f, "", filepath.Ext(path), prefix)
+}
+
+// readMedia reads r as base64 data. Without a mimeType, it is detected from
+// the file extension ext, if any, then from the contents, and must start
+// with prefix.
+func readMedia(r io.Reader, mimeType, ext, prefix string) (string, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read media: %w", err)
+	}
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(ext)
+		if !strings.HasPrefix(mimeType, prefix) {
+			mimeType = http.DetectContentType(data)
+		}
+		mimeType, _, _ = strings.Cut(mimeType, ";")
+		if !strings.HasPrefix(mimeType, prefix) {
+			return "", "", fmt.Errorf("unsupported media type %s", mimeType)
+		}
+	}
+	return base64.StdEncoding.EncodeToString(data), mimeType, nil
+}
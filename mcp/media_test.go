@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// png is the signature of a PNG file, enough for content sniffing.
+var png = []byte("\x89PNG\r\n\x1a\n")
+
+func TestMediaContent(t *testing.T) {
+	t.Run("ImageFromFile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "image")
+		if err := os.WriteFile(path, png, 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		content, err := NewImageContentFromFile(path)
+		if err != nil {
+			t.Fatalf("NewImageContentFromFile failed: %v", err)
+		}
+		if content.Type != "image" || content.MimeType != "image/png" {
+			t.Errorf("Expected a PNG image, got %+v", content)
+		}
+		data, err := content.Decode()
+		if err != nil || !bytes.Equal(data, png) {
+			t.Errorf("Expected the file contents, got %q, %v", data, err)
+		}
+	})
+
+	t.Run("ImageFromReader", func(t *testing.T) {
+		content, err := NewImageContentFromReader(bytes.NewReader(png), "image/x-custom")
+		if err != nil {
+			t.Fatalf("NewImageContentFromReader failed: %v", err)
+		}
+		if content.MimeType != "image/x-custom" {
+			t.Errorf("Expected the given MIME type, got %s", content.MimeType)
+		}
+		if _, err := NewImageContentFromReader(strings.NewReader("text"), ""); err == nil {
+			t.Error("Expected an error for text")
+		}
+	})
+
+	t.Run("AudioFromReader", func(t *testing.T) {
+		wav := []byte("RIFF\x00\x00\x00\x00WAVEfmt ")
+		content, err := NewAudioContentFromReader(bytes.NewReader(wav), "")
+		if err != nil {
+			t.Fatalf("NewAudioContentFromReader failed: %v", err)
+		}
+		if content.Type != "audio" || content.MimeType != "audio/wave" {
+			t.Errorf("Expected WAV audio, got %+v", content)
+		}
+	})
+
+	t.Run("Blob", func(t *testing.T) {
+		blob := NewBlobResourceContents("file:///b.bin", []byte{0, 1, 2}, "application/octet-stream")
+		data, err := blob.Decode()
+		if err != nil || !bytes.Equal(data, []byte{0, 1, 2}) {
+			t.Errorf("Expected the original bytes, got %v, %v", data, err)
+		}
+		blob.Blob = "not base64!"
+		if _, err := blob.Decode(); err == nil {
+			t.Error("Expected an error for invalid base64")
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// metaProgressToken is the _meta key of the progress token of a request.
+const metaProgressToken = "progressToken"
+
+// Meta is the _meta field the protocol reserves in the params of requests
+// and notifications, and in results, for metadata.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic#_meta
+type Meta struct {
+	// ProgressToken asks for progress notifications about a request, nil for
+	// none
+	ProgressToken ProgressToken
+	// Fields are the other metadata, by key
+	Fields map[string]any
+}
+
+// Get returns the field with the given key, or nil.
+func (m *Meta) Get(key string) any {
+	if m == nil {
+		return nil
+	}
+	if key == metaProgressToken {
+		return m.ProgressToken
+	}
+	return m.Fields[key]
+}
+
+// Set sets the field with the given key.
+func (m *Meta) Set(key string, value any) {
+	if key == metaProgressToken {
+		m.ProgressToken = value
+		return
+	}
+	if m.Fields == nil {
+		m.Fields = map[string]any{}
+	}
+	m.Fields[key] = value
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Meta) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.fieldMap())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *Meta) UnmarshalJSON(data []byte) error {
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*m = *newMetaFromMap(fields)
+	return nil
+}
+
+// fieldMap returns the fields of m as a map, including the progress token.
+func (m Meta) fieldMap() map[string]any {
+	fields := make(map[string]any, len(m.Fields)+1)
+	maps.Copy(fields, m.Fields)
+	if m.ProgressToken != nil {
+		fields[metaProgressToken] = m.ProgressToken
+	}
+	return fields
+}
+
+// newMetaFromMap returns the Meta of a decoded _meta object.
+func newMetaFromMap(fields map[string]any) *Meta {
+	m := &Meta{}
+	for key, value := range fields {
+		m.Set(key, value)
+	}
+	return m
+}
+
+// AttachMeta returns params, a map or a struct encoding to a JSON object,
+// as a map with meta set as its _meta. Fields of an existing _meta are kept
+// unless meta overrides them.
+func AttachMeta(params any, meta *Meta) (map[string]any, error) {
+	result := map[string]any{}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode params: %w", err)
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode params as an object: %w", err)
+		}
+		if result == nil {
+			result = map[string]any{}
+		}
+	}
+	if meta == nil {
+		return result, nil
+	}
+	fields := meta.fieldMap()
+	if existing, ok := result["_meta"].(map[string]any); ok {
+		maps.Copy(existing, fields)
+		fields = existing
+	}
+	result["_meta"] = fields
+	return result, nil
+}
+
+// MetaFromParams returns the _meta of the raw params of a request or
+// notification, or nil if they have none.
+func MetaFromParams(params json.RawMessage) *Meta {
+	var request struct {
+		Meta *Meta `json:"_meta"`
+	}
+	if json.Unmarshal(params, &request) != nil {
+		return nil
+	}
+	return request.Meta
+}
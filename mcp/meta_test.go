@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMeta(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		var meta Meta
+		if err := json.Unmarshal([]byte(`{"progressToken":"t1","traceparent":"00-abc"}`), &meta); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if meta.ProgressToken != "t1" || meta.Get("traceparent") != "00-abc" {
+			t.Errorf("Expected the progress token and field, got %+v", meta)
+		}
+		if _, ok := meta.Fields["progressToken"]; ok {
+			t.Error("Expected the progress token out of the fields")
+		}
+		data, err := json.Marshal(meta)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if string(data) != `{"progressToken":"t1","traceparent":"00-abc"}` {
+			t.Errorf("Expected the original object, got %s", data)
+		}
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		var meta Meta
+		meta.Set("progressToken", 7)
+		meta.Set("k", "v")
+		if meta.ProgressToken != 7 || !reflect.DeepEqual(meta.Fields, map[string]any{"k": "v"}) {
+			t.Errorf("Expected the progress token and field, got %+v", meta)
+		}
+		var missing *Meta
+		if missing.Get("k") != nil {
+			t.Error("Expected nil from a nil Meta")
+		}
+	})
+
+	t.Run("AttachMeta", func(t *testing.T) {
+		params := struct {
+			Name string         `json:"name"`
+			Meta map[string]any `json:"_meta"`
+		}{Name: "tool", Meta: map[string]any{"a": "1", "b": "1"}}
+		got, err := AttachMeta(params, &Meta{ProgressToken: "t", Fields: map[string]any{"b": "2"}})
+		if err != nil {
+			t.Fatalf("AttachMeta failed: %v", err)
+		}
+		want := map[string]any{"name": "tool", "_meta": map[string]any{"a": "1", "b": "2", "progressToken": "t"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+		if _, err := AttachMeta([]string{"x"}, &Meta{}); err == nil {
+			t.Error("Expected an error for params that are not an object")
+		}
+	})
+
+	t.Run("MetaFromParams", func(t *testing.T) {
+		meta := MetaFromParams(json.RawMessage(`{"name":"tool","_meta":{"progressToken":3}}`))
+		if meta == nil || meta.ProgressToken != float64(3) {
+			t.Errorf("Expected progress token 3, got %+v", meta)
+		}
+		if MetaFromParams(json.RawMessage(`{"name":"tool"}`)) != nil {
+			t.Error("Expected nil without _meta")
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ContentsToText renders content as plain text, such as to put a tool result
+// into an LLM prompt: text is kept as-is, embedded text resources are
+// preceded by their URI, and images, audio and binary resources are
+// described in brackets. Items are separated by newlines.
+func ContentsToText(contents []Content) string {
+	texts := make([]string, 0, len(contents))
+	for _, content := range contents {
+		texts = append(texts, ContentToText(content))
+	}
+	return strings.Join(texts, "\n")
+}
+
+// ContentToText renders a content item as plain text. See ContentsToText.
+func ContentToText(content Content) string {
+	switch content := content.(type) {
+	case TextContent:
+		return content.Text
+	case ImageContent:
+		return fmt.Sprintf("[image: %s, %s]", content.MimeType, formatSize(content.Data))
+	case AudioContent:
+		return fmt.Sprintf("[audio: %s, %s]", content.MimeType, formatSize(content.Data))
+	case EmbeddedResource:
+		switch resource := content.Resource.(type) {
+		case TextResourceContents:
+			return fmt.Sprintf("[resource: %s]\n%s", resource.URI, resource.Text)
+		case BlobResourceContents:
+			return fmt.Sprintf("[resource: %s, %s, %s]", resource.URI, resource.MimeType, formatSize(resource.Blob))
+		}
+	}
+	return fmt.Sprintf("[%T]", content)
+}
+
+// ContentsToMarkdown renders content as Markdown: text is kept as-is, images
+// become data URI images, audio and binary resources data URI links, and
+// embedded text resources code blocks under their URI. Items are separated
+// by blank lines.
+func ContentsToMarkdown(contents []Content) string {
+	blocks := make([]string, 0, len(contents))
+	for _, content := range contents {
+		blocks = append(blocks, ContentToMarkdown(content))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// ContentToMarkdown renders a content item as Markdown. See
+// ContentsToMarkdown.
+func ContentToMarkdown(content Content) string {
+	switch content := content.(type) {
+	case TextContent:
+		return content.Text
+	case ImageContent:
+		return fmt.Sprintf("![image](data:%s;base64,%s)", content.MimeType, content.Data)
+	case AudioContent:
+		return fmt.Sprintf("[audio (%s)](data:%s;base64,%s)", content.MimeType, content.MimeType, content.Data)
+	case EmbeddedResource:
+		switch resource := content.Resource.(type) {
+		case TextResourceContents:
+			fence := strings.Repeat("`", max(3, longestRun(resource.Text, '`')+1))
+			return fmt.Sprintf("`%s`\n\n%s%s\n%s\n%s", resource.URI, fence, codeLanguage(resource.MimeType), resource.Text, fence)
+		case BlobResourceContents:
+			return fmt.Sprintf("[%s](data:%s;base64,%s) (%s, %s)", resource.URI, resource.MimeType, resource.Blob, resource.MimeType, formatSize(resource.Blob))
+		}
+	}
+	return ContentToText(content)
+}
+
+// formatSize describes the decoded size of base64 data.
+func formatSize(data string) string {
+	size := base64.StdEncoding.DecodedLen(len(data)) - strings.Count(data, "=")
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
+
+// codeLanguage returns the info string of a code block of the given MIME
+// type, such as "json" for application/json, or "" for plain text.
+func codeLanguage(mimeType string) string {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	_, subtype, _ := strings.Cut(strings.TrimSpace(mimeType), "/")
+	if _, suffix, ok := strings.Cut(subtype, "+"); ok {
+		subtype = suffix
+	}
+	subtype = strings.TrimPrefix(subtype, "x-")
+	if subtype == "plain" {
+		return ""
+	}
+	return subtype
+}
+
+// longestRun returns the length of the longest run of c in s.
+func longestRun(s string, c byte) int {
+	longest, run := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != c {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+	return longest
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"testing"
+)
+
+func TestContentsToText(t *testing.T) {
+	contents := []Content{
+		NewTextContent("hello"),
+		NewImageContent("aW1n", "image/png"),
+		NewAudioContent("YXVkaW8=", "audio/wav"),
+		NewEmbeddedResource(TextResourceContents{URI: "file:///a.json", MimeType: "application/json", Text: `{"a":1}`}),
+		NewEmbeddedResource(BlobResourceContents{URI: "file:///b.bin", MimeType: "application/octet-stream", Blob: "YmxvYg=="}),
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		expected := "hello\n" +
+			"[image: image/png, 3 bytes]\n" +
+			"[audio: audio/wav, 5 bytes]\n" +
+			"[resource: file:///a.json]\n{\"a\":1}\n" +
+			"[resource: file:///b.bin, application/octet-stream, 4 bytes]"
+		if got := ContentsToText(contents); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("Markdown", func(t *testing.T) {
+		expected := "hello\n\n" +
+			"![image](data:image/png;base64,aW1n)\n\n" +
+			"[audio (audio/wav)](data:audio/wav;base64,YXVkaW8=)\n\n" +
+			"`file:///a.json`\n\n```json\n{\"a\":1}\n```\n\n" +
+			"[file:///b.bin](data:application/octet-stream;base64,YmxvYg==) (application/octet-stream, 4 bytes)"
+		if got := ContentsToMarkdown(contents); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("Fence", func(t *testing.T) {
+		content := NewEmbeddedResource(TextResourceContents{URI: "file:///a.md", MimeType: "text/plain", Text: "```go\nx\n```"})
+		expected := "`file:///a.md`\n\n````\n```go\nx\n```\n````"
+		if got := ContentToMarkdown(content); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// RequestId uniquely identifies a JSON-RPC request. Per JSON-RPC 2.0 it is
+// either a string or an integer; the zero value is a null (absent) ID.
+//
+// RequestId is comparable, so it can be used as a map key to match responses
+// to requests. A string ID and a number ID never compare equal, even when they
+// print the same: servers must echo the ID with its original type.
+type RequestId struct {
+	value any // nil, string or int64
+}
+
+// NewStringRequestId returns a string request ID.
+func NewStringRequestId(id string) RequestId {
+	return RequestId{value: id}
+}
+
+// NewIntRequestId returns a number request ID.
+func NewIntRequestId(id int64) RequestId {
+	return RequestId{value: id}
+}
+
+// IsNil reports whether the ID is null or absent.
+func (id RequestId) IsNil() bool {
+	return id.value == nil
+}
+
+// Value returns the underlying ID: nil, a string or an int64.
+func (id RequestId) Value() any {
+	return id.value
+}
+
+// String returns the ID for display. Null IDs print as "<nil>".
+func (id RequestId) String() string {
+	switch v := id.value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return "<nil>"
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (id RequestId) MarshalJSON() ([]byte, error) {
+	return json.Marshal(id.value)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts strings, integers and null.
+func (id *RequestId) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		id.value = nil
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		id.value = s
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("request id must be a string or an integer, got %s", data)
+	}
+	if i, err := n.Int64(); err == nil {
+		id.value = i
+		return nil
+	}
+	// Some peers re-encode integers as floats, e.g. 1e3 or 2.0
+	f, err := n.Float64()
+	if err != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return fmt.Errorf("request id must be a string or an integer, got %s", data)
+	}
+	id.value = int64(f)
+	return nil
+}
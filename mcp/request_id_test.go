@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRequestIdJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want RequestId
+	}{
+		{"string", `"abc"`, NewStringRequestId("abc")},
+		{"numeric string", `"42"`, NewStringRequestId("42")},
+		{"number", `42`, NewIntRequestId(42)},
+		{"negative", `-7`, NewIntRequestId(-7)},
+		{"exponent", `1e3`, NewIntRequestId(1000)},
+		{"null", `null`, RequestId{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got RequestId
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %#v, got %#v", tt.want, got)
+			}
+
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			var again RequestId
+			if err := json.Unmarshal(data, &again); err != nil || again != got {
+				t.Errorf("Round trip of %s gave %#v (%v)", data, again, err)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{`true`, `1.5`, `{}`, `[]`, `99999999999999999999`} {
+			var id RequestId
+			if err := json.Unmarshal([]byte(input), &id); err == nil {
+				t.Errorf("Expected %s to be rejected, got %#v", input, id)
+			}
+		}
+	})
+}
+
+func TestRequestIdTypesDiffer(t *testing.T) {
+	pending := map[RequestId]string{
+		NewIntRequestId(1):      "number",
+		NewStringRequestId("1"): "string",
+	}
+	if len(pending) != 2 {
+		t.Fatalf("Expected string and number IDs to be distinct keys")
+	}
+	if NewIntRequestId(1).String() != "1" || NewStringRequestId("1").String() != "1" {
+		t.Errorf("Expected both IDs to print as 1")
+	}
+	if !(RequestId{}).IsNil() || NewIntRequestId(0).IsNil() {
+		t.Errorf("Expected only the zero value to be nil")
+	}
+}
+
+func TestJSONRPCRequestId(t *testing.T) {
+	var request JSONRPCRequest
+	if err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":7,"method":"ping"}`), &request); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if request.ID != NewIntRequestId(7) {
+		t.Errorf("Expected number ID 7, got %#v", request.ID)
+	}
+	data, _ := json.Marshal(request)
+	if string(data) != `{"jsonrpc":"2.0","id":7,"method":"ping"}` {
+		t.Errorf("Unexpected encoding %s", data)
+	}
+}
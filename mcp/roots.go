@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidRoot is wrapped by the errors of roots and URIs that are not
+// absolute file:// URIs.
+var ErrInvalidRoot = errors.New("invalid root")
+
+// NormalizeRootURI checks that uri is a file:// URI with an absolute path and
+// returns it in canonical form: without host, query or fragment, with its
+// path cleaned of "." and ".." elements and trailing slashes, and with the
+// percent-encoding of url.URL.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/client/roots#root
+func NormalizeRootURI(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrInvalidRoot, uri, err)
+	}
+	switch {
+	case !strings.EqualFold(u.Scheme, "file"):
+		return "", fmt.Errorf("%w %q: not a file:// URI", ErrInvalidRoot, uri)
+	case u.Host != "" && !strings.EqualFold(u.Host, "localhost"):
+		return "", fmt.Errorf("%w %q: remote host %s", ErrInvalidRoot, uri, u.Host)
+	case u.Opaque != "" || !strings.HasPrefix(u.Path, "/"):
+		return "", fmt.Errorf("%w %q: relative path", ErrInvalidRoot, uri)
+	case u.RawQuery != "" || u.Fragment != "":
+		return "", fmt.Errorf("%w %q: query or fragment", ErrInvalidRoot, uri)
+	}
+	return (&url.URL{Scheme: "file", Path: path.Clean(u.Path)}).String(), nil
+}
+
+// Normalize returns the root with its URI normalized by NormalizeRootURI.
+func (r Root) Normalize() (Root, error) {
+	uri, err := NormalizeRootURI(r.URI)
+	if err != nil {
+		return Root{}, err
+	}
+	r.URI = uri
+	return r, nil
+}
+
+// FileRoot returns the root of a directory, made absolute.
+func FileRoot(dir, name string) (Root, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Root{}, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	abs = filepath.ToSlash(abs)
+	if !strings.HasPrefix(abs, "/") {
+		// Windows drive letters
+		abs = "/" + abs
+	}
+	return Root{URI: (&url.URL{Scheme: "file", Path: abs}).String(), Name: name}, nil
+}
+
+// RootOf returns the innermost of roots containing uri, the root itself or a
+// file below it. Both are normalized first, so that ".." elements cannot
+// escape a root; invalid roots and URIs match nothing. Symbolic links are
+// not resolved: servers enforcing roots on the file system must resolve them
+// before checking the URI.
+func RootOf(roots []Root, uri string) (Root, bool) {
+	target, err := NormalizeRootURI(uri)
+	if err != nil {
+		return Root{}, false
+	}
+	var found Root
+	var foundLen int
+	for _, root := range roots {
+		normalized, err := NormalizeRootURI(root.URI)
+		if err != nil {
+			continue
+		}
+		if !containsURI(normalized, target) || len(normalized) < foundLen {
+			continue
+		}
+		found, foundLen = root, len(normalized)
+	}
+	return found, foundLen > 0
+}
+
+// InRoots reports whether uri is within one of roots, as RootOf checks.
+func InRoots(roots []Root, uri string) bool {
+	_, ok := RootOf(roots, uri)
+	return ok
+}
+
+// containsURI reports whether the normalized URI target is root or below it.
+func containsURI(root, target string) bool {
+	if target == root {
+		return true
+	}
+	if !strings.HasSuffix(root, "/") {
+		// Only the file:/// root ends with a slash once normalized
+		root += "/"
+	}
+	return strings.HasPrefix(target, root)
+}
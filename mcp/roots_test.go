@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeRootURI(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		for uri, want := range map[string]string{
+			"file:///project":          "file:///project",
+			"file:///project/src/../":  "file:///project",
+			"file://localhost/project": "file:///project",
+			"FILE:///":                 "file:///",
+			"file:///my%20project/./a": "file:///my%20project/a",
+			"file:///C:/Users/me":      "file:///C:/Users/me",
+		} {
+			got, err := NormalizeRootURI(uri)
+			if err != nil {
+				t.Errorf("NormalizeRootURI(%q) failed: %v", uri, err)
+			} else if got != want {
+				t.Errorf("Expected %q for %q, got %q", want, uri, got)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, uri := range []string{
+			"https://example.com/project",
+			"/project",
+			"file:project",
+			"file://server/share",
+			"file:///project?ref=main",
+			"file:///project#src",
+			"file://%zz",
+		} {
+			if _, err := NormalizeRootURI(uri); !errors.Is(err, ErrInvalidRoot) {
+				t.Errorf("Expected ErrInvalidRoot for %q, got %v", uri, err)
+			}
+		}
+	})
+}
+
+func TestRootNormalize(t *testing.T) {
+	root, err := Root{URI: "file:///project/", Name: "project"}.Normalize()
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if root != (Root{URI: "file:///project", Name: "project"}) {
+		t.Errorf("Expected the normalized root, got %+v", root)
+	}
+}
+
+func TestFileRoot(t *testing.T) {
+	dir := t.TempDir()
+	root, err := FileRoot(dir, "tmp")
+	if err != nil {
+		t.Fatalf("FileRoot failed: %v", err)
+	}
+	if normalized, err := NormalizeRootURI(root.URI); err != nil || normalized != root.URI {
+		t.Errorf("Expected a normalized root, got %q: %v", root.URI, err)
+	}
+	if !InRoots([]Root{root}, root.URI+"/file.txt") {
+		t.Errorf("Expected the files of %s to be in its root %s", dir, root.URI)
+	}
+}
+
+func TestRootOf(t *testing.T) {
+	roots := []Root{
+		{URI: "file:///project", Name: "project"},
+		{URI: "file:///project/vendor/", Name: "vendor"},
+		{URI: "https://example.com", Name: "invalid"},
+	}
+	for uri, want := range map[string]string{
+		"file:///project":                 "project",
+		"file:///project/main.go":         "project",
+		"file:///project/vendor/lib/a.go": "vendor",
+		"file:///project/../etc/passwd":   "",
+		"file:///projectile/main.go":      "",
+		"file:///project/%2e%2e/etc":      "",
+		"https://example.com/a":           "",
+		"relative/path":                   "",
+	} {
+		root, ok := RootOf(roots, uri)
+		if ok != (want != "") || root.Name != want {
+			t.Errorf("Expected root %q for %q, got %q (%v)", want, uri, root.Name, ok)
+		}
+	}
+
+	if !InRoots([]Root{{URI: "file:///"}}, "file:///etc/hosts") {
+		t.Error("Expected the file system root to contain every file")
+	}
+	if InRoots(nil, "file:///etc/hosts") {
+		t.Error("Expected no roots to contain nothing")
+	}
+}
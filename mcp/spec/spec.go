@@ -0,0 +1,32 @@
+// Package spec holds the JSON Schemas of the MCP revisions supported by this
+// module. The types of each revision are generated from its schema into a
+// subpackage, such as v20250326, with go generate; to support a new revision,
+// add its schema.json here and a subpackage generating from it.
+//
+// The generated types are not used by package mcp, whose types are written by
+// hand for their methods and interfaces and neither generated nor aliased.
+// Drift between the two is caught only by TestHandWrittenTypes, which checks
+// that the listed mcp types have every JSON field of their generated
+// counterparts of the latest revision. It does not compare field types, and
+// types missing from its list are not checked at all.
+package spec
+
+import (
+	"embed"
+	"fmt"
+)
+
+//go:embed *.json
+var schemas embed.FS
+
+// Revisions lists the protocol revisions with a schema, oldest first.
+var Revisions = []string{"2024-11-05", "2025-03-26"}
+
+// Schema returns the JSON Schema of a protocol revision.
+func Schema(revision string) ([]byte, error) {
+	data, err := schemas.ReadFile(revision + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("no schema for protocol version %q", revision)
+	}
+	return data, nil
+}
@@ -0,0 +1,117 @@
+package spec_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/codegen"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcp/spec"
+	"github.com/contriboss/mcpgopher/mcp/spec/v20250326"
+)
+
+func TestGeneratedTypes(t *testing.T) {
+	for _, revision := range spec.Revisions {
+		t.Run(revision, func(t *testing.T) {
+			schema, err := spec.Schema(revision)
+			if err != nil {
+				t.Fatalf("Schema failed: %v", err)
+			}
+			pkg := "v" + strings.ReplaceAll(revision, "-", "")
+			want, err := codegen.GenerateSchema(schema, codegen.SchemaOptions{Package: pkg, Source: "MCP schema " + revision})
+			if err != nil {
+				t.Fatalf("GenerateSchema failed: %v", err)
+			}
+			got, err := os.ReadFile(filepath.Join(pkg, "types.go"))
+			if err != nil {
+				t.Fatalf("Failed to read generated types: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Expected %s/types.go to match the schema; run go generate ./mcp/spec/...", pkg)
+			}
+		})
+	}
+
+	if _, err := spec.Schema("1999-01-01"); err == nil {
+		t.Error("Expected an error for an unknown revision")
+	}
+}
+
+// TestHandWrittenTypes checks that the types of package mcp have every field
+// of the types generated from the latest schema.
+func TestHandWrittenTypes(t *testing.T) {
+	pairs := []struct {
+		handWritten, generated any
+	}{
+		{mcp.Annotations{}, v20250326.Annotations{}},
+		{mcp.TextContent{}, v20250326.TextContent{}},
+		{mcp.ImageContent{}, v20250326.ImageContent{}},
+		{mcp.AudioContent{}, v20250326.AudioContent{}},
+		{mcp.EmbeddedResource{}, v20250326.EmbeddedResource{}},
+		{mcp.TextResourceContents{}, v20250326.TextResourceContents{}},
+		{mcp.BlobResourceContents{}, v20250326.BlobResourceContents{}},
+		{mcp.Implementation{}, v20250326.Implementation{}},
+		{mcp.ClientCapabilities{}, v20250326.ClientCapabilities{}},
+		{mcp.ServerCapabilities{}, v20250326.ServerCapabilities{}},
+		{mcp.InitializeResult{}, v20250326.InitializeResult{}},
+		{mcp.Tool{}, v20250326.Tool{}},
+		{mcp.ToolAnnotations{}, v20250326.ToolAnnotations{}},
+		{mcp.ListToolsResult{}, v20250326.ListToolsResult{}},
+		{mcp.CallToolResult{}, v20250326.CallToolResult{}},
+		{mcp.Resource{}, v20250326.Resource{}},
+		{mcp.ResourceTemplate{}, v20250326.ResourceTemplate{}},
+		{mcp.ListResourcesResult{}, v20250326.ListResourcesResult{}},
+		{mcp.ListResourceTemplatesResult{}, v20250326.ListResourceTemplatesResult{}},
+		{mcp.ReadResourceResult{}, v20250326.ReadResourceResult{}},
+		{mcp.Prompt{}, v20250326.Prompt{}},
+		{mcp.PromptArgument{}, v20250326.PromptArgument{}},
+		{mcp.PromptMessage{}, v20250326.PromptMessage{}},
+		{mcp.ListPromptsResult{}, v20250326.ListPromptsResult{}},
+		{mcp.GetPromptResult{}, v20250326.GetPromptResult{}},
+		{mcp.CompleteResult{}, v20250326.CompleteResult{}},
+		{mcp.Root{}, v20250326.Root{}},
+		{mcp.ListRootsResult{}, v20250326.ListRootsResult{}},
+		{mcp.SamplingMessage{}, v20250326.SamplingMessage{}},
+		{mcp.CreateMessageResult{}, v20250326.CreateMessageResult{}},
+		{mcp.JSONRPCRequest{}, v20250326.JSONRPCRequest{}},
+		{mcp.JSONRPCNotification{}, v20250326.JSONRPCNotification{}},
+		{mcp.JSONRPCResponse{}, v20250326.JSONRPCResponse{}},
+		{mcp.Error{}, v20250326.Error{}},
+	}
+	for _, pair := range pairs {
+		handWritten := reflect.TypeOf(pair.handWritten)
+		fields := jsonFields(handWritten)
+		for name := range jsonFields(reflect.TypeOf(pair.generated)) {
+			if !fields[name] {
+				t.Errorf("Expected mcp.%s to have the %s field of the schema", handWritten.Name(), name)
+			}
+		}
+	}
+}
+
+// jsonFields returns the names of the JSON fields of a struct type, including
+// those of embedded structs.
+func jsonFields(typ reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded := range jsonFields(field.Type) {
+				fields[embedded] = true
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
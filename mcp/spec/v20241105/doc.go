@@ -0,0 +1,6 @@
+// Package v20241105 declares the types of the 2024-11-05 revision of MCP, generated
+// from its schema. The hand-written types of package mcp follow the latest
+// revision and are not checked against them.
+package v20241105
+
+//go:generate go run ../../../cmd/mcpschema -package v20241105 -o types.go ../2024-11-05.json
@@ -0,0 +1,6 @@
+// Package v20250326 declares the types of the 2025-03-26 revision of MCP, generated
+// from its schema. The hand-written types of package mcp are not derived from
+// them; TestHandWrittenTypes in package spec checks their fields against them.
+package v20250326
+
+//go:generate go run ../../../cmd/mcpschema -package v20250326 -o types.go ../2025-03-26.json
@@ -0,0 +1,428 @@
+// Code generated by mcpschema; DO NOT EDIT.
+// Source: MCP schema 2025-03-26
+
+package v20250326
+
+// Annotations mirrors a definition of the schema.
+type Annotations struct {
+	Audience []Role   `json:"audience,omitempty"`
+	Priority *float64 `json:"priority,omitempty"`
+}
+
+// AudioContent mirrors a definition of the schema.
+type AudioContent struct {
+	Annotations *Annotations `json:"annotations,omitempty"`
+	Data        string       `json:"data"`
+	MimeType    string       `json:"mimeType"`
+	Type        string       `json:"type"`
+}
+
+// BlobResourceContents mirrors a definition of the schema.
+type BlobResourceContents struct {
+	Blob     string  `json:"blob"`
+	MimeType *string `json:"mimeType,omitempty"`
+	URI      string  `json:"uri"`
+}
+
+// CallToolRequestParams mirrors a definition of the schema.
+type CallToolRequestParams struct {
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Name      string         `json:"name"`
+}
+
+// CallToolResult mirrors a definition of the schema.
+type CallToolResult struct {
+	Meta    Meta      `json:"_meta,omitempty"`
+	Content []Content `json:"content"`
+	IsError *bool     `json:"isError,omitempty"`
+}
+
+// CancelledNotificationParams mirrors a definition of the schema.
+type CancelledNotificationParams struct {
+	Reason    *string   `json:"reason,omitempty"`
+	RequestID RequestID `json:"requestId"`
+}
+
+// ClientCapabilitiesRoots is an object nested in a definition of the schema.
+type ClientCapabilitiesRoots struct {
+	ListChanged *bool `json:"listChanged,omitempty"`
+}
+
+// ClientCapabilities mirrors a definition of the schema.
+type ClientCapabilities struct {
+	Experimental map[string]any           `json:"experimental,omitempty"`
+	Roots        *ClientCapabilitiesRoots `json:"roots,omitempty"`
+	Sampling     map[string]any           `json:"sampling,omitempty"`
+}
+
+// CompleteRequestParamsArgument is an object nested in a definition of the schema.
+type CompleteRequestParamsArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CompleteRequestParams mirrors a definition of the schema.
+type CompleteRequestParams struct {
+	Argument CompleteRequestParamsArgument `json:"argument"`
+	Ref      any                           `json:"ref"`
+}
+
+// CompleteResultCompletion is an object nested in a definition of the schema.
+type CompleteResultCompletion struct {
+	HasMore *bool    `json:"hasMore,omitempty"`
+	Total   *int64   `json:"total,omitempty"`
+	Values  []string `json:"values"`
+}
+
+// CompleteResult mirrors a definition of the schema.
+type CompleteResult struct {
+	Meta       Meta                     `json:"_meta,omitempty"`
+	Completion CompleteResultCompletion `json:"completion"`
+}
+
+// Content mirrors a definition of the schema.
+type Content = any
+
+// CreateMessageRequestParams mirrors a definition of the schema.
+type CreateMessageRequestParams struct {
+	// One of ["none","thisServer","allServers"].
+	IncludeContext *string           `json:"includeContext,omitempty"`
+	MaxTokens      int64             `json:"maxTokens"`
+	Messages       []SamplingMessage `json:"messages"`
+	StopSequences  []string          `json:"stopSequences,omitempty"`
+	SystemPrompt   *string           `json:"systemPrompt,omitempty"`
+	Temperature    *float64          `json:"temperature,omitempty"`
+}
+
+// CreateMessageResult mirrors a definition of the schema.
+type CreateMessageResult struct {
+	Meta       Meta    `json:"_meta,omitempty"`
+	Content    any     `json:"content"`
+	Model      string  `json:"model"`
+	Role       Role    `json:"role"`
+	StopReason *string `json:"stopReason,omitempty"`
+}
+
+// EmbeddedResource mirrors a definition of the schema.
+type EmbeddedResource struct {
+	Annotations *Annotations     `json:"annotations,omitempty"`
+	Resource    ResourceContents `json:"resource"`
+	Type        string           `json:"type"`
+}
+
+// Error mirrors a definition of the schema.
+type Error struct {
+	Code    int64  `json:"code"`
+	Message string `json:"message"`
+}
+
+// GetPromptRequestParams mirrors a definition of the schema.
+type GetPromptRequestParams struct {
+	Arguments map[string]string `json:"arguments,omitempty"`
+	Name      string            `json:"name"`
+}
+
+// GetPromptResult mirrors a definition of the schema.
+type GetPromptResult struct {
+	Meta        Meta            `json:"_meta,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// ImageContent mirrors a definition of the schema.
+type ImageContent struct {
+	Annotations *Annotations `json:"annotations,omitempty"`
+	Data        string       `json:"data"`
+	MimeType    string       `json:"mimeType"`
+	Type        string       `json:"type"`
+}
+
+// Implementation mirrors a definition of the schema.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InitializeRequestParams mirrors a definition of the schema.
+type InitializeRequestParams struct {
+	Capabilities    ClientCapabilities `json:"capabilities"`
+	ClientInfo      Implementation     `json:"clientInfo"`
+	ProtocolVersion string             `json:"protocolVersion"`
+}
+
+// InitializeResult mirrors a definition of the schema.
+type InitializeResult struct {
+	Meta            Meta               `json:"_meta,omitempty"`
+	Capabilities    ServerCapabilities `json:"capabilities"`
+	Instructions    *string            `json:"instructions,omitempty"`
+	ProtocolVersion string             `json:"protocolVersion"`
+	ServerInfo      Implementation     `json:"serverInfo"`
+}
+
+// JSONRPCError mirrors a definition of the schema.
+type JSONRPCError struct {
+	Error   Error     `json:"error"`
+	ID      RequestID `json:"id"`
+	JSONRPC string    `json:"jsonrpc"`
+}
+
+// JSONRPCNotification mirrors a definition of the schema.
+type JSONRPCNotification struct {
+	JSONRPC string         `json:"jsonrpc"`
+	Method  string         `json:"method"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// JSONRPCRequest mirrors a definition of the schema.
+type JSONRPCRequest struct {
+	ID      RequestID      `json:"id"`
+	JSONRPC string         `json:"jsonrpc"`
+	Method  string         `json:"method"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// JSONRPCResponse mirrors a definition of the schema.
+type JSONRPCResponse struct {
+	ID      RequestID      `json:"id"`
+	JSONRPC string         `json:"jsonrpc"`
+	Result  map[string]any `json:"result"`
+}
+
+// JSONRPCResponseOrError mirrors a definition of the schema.
+type JSONRPCResponseOrError = any
+
+// ListPromptsResult mirrors a definition of the schema.
+type ListPromptsResult struct {
+	Meta       Meta     `json:"_meta,omitempty"`
+	NextCursor *string  `json:"nextCursor,omitempty"`
+	Prompts    []Prompt `json:"prompts"`
+}
+
+// ListResourceTemplatesResult mirrors a definition of the schema.
+type ListResourceTemplatesResult struct {
+	Meta              Meta               `json:"_meta,omitempty"`
+	NextCursor        *string            `json:"nextCursor,omitempty"`
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
+// ListResourcesResult mirrors a definition of the schema.
+type ListResourcesResult struct {
+	Meta       Meta       `json:"_meta,omitempty"`
+	NextCursor *string    `json:"nextCursor,omitempty"`
+	Resources  []Resource `json:"resources"`
+}
+
+// ListRootsResult mirrors a definition of the schema.
+type ListRootsResult struct {
+	Meta  Meta   `json:"_meta,omitempty"`
+	Roots []Root `json:"roots"`
+}
+
+// ListToolsResult mirrors a definition of the schema.
+type ListToolsResult struct {
+	Meta       Meta    `json:"_meta,omitempty"`
+	NextCursor *string `json:"nextCursor,omitempty"`
+	Tools      []Tool  `json:"tools"`
+}
+
+// LoggingLevel mirrors a definition of the schema.
+type LoggingLevel string
+
+// Values of LoggingLevel.
+const (
+	LoggingLevelDebug     LoggingLevel = "debug"
+	LoggingLevelInfo      LoggingLevel = "info"
+	LoggingLevelNotice    LoggingLevel = "notice"
+	LoggingLevelWarning   LoggingLevel = "warning"
+	LoggingLevelError     LoggingLevel = "error"
+	LoggingLevelCritical  LoggingLevel = "critical"
+	LoggingLevelAlert     LoggingLevel = "alert"
+	LoggingLevelEmergency LoggingLevel = "emergency"
+)
+
+// LoggingMessageNotificationParams mirrors a definition of the schema.
+type LoggingMessageNotificationParams struct {
+	Data   any          `json:"data"`
+	Level  LoggingLevel `json:"level"`
+	Logger *string      `json:"logger,omitempty"`
+}
+
+// Meta mirrors a definition of the schema.
+type Meta map[string]any
+
+// NotificationParams mirrors a definition of the schema.
+type NotificationParams struct {
+	Meta Meta `json:"_meta,omitempty"`
+}
+
+// PaginatedRequestParams mirrors a definition of the schema.
+type PaginatedRequestParams struct {
+	Cursor *string `json:"cursor,omitempty"`
+}
+
+// ProgressNotificationParams mirrors a definition of the schema.
+type ProgressNotificationParams struct {
+	Message       *string       `json:"message,omitempty"`
+	Progress      float64       `json:"progress"`
+	ProgressToken ProgressToken `json:"progressToken"`
+	Total         *float64      `json:"total,omitempty"`
+}
+
+// ProgressToken mirrors a definition of the schema.
+type ProgressToken = any
+
+// Prompt mirrors a definition of the schema.
+type Prompt struct {
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+	Description *string          `json:"description,omitempty"`
+	Name        string           `json:"name"`
+}
+
+// PromptArgument mirrors a definition of the schema.
+type PromptArgument struct {
+	Description *string `json:"description,omitempty"`
+	Name        string  `json:"name"`
+	Required    *bool   `json:"required,omitempty"`
+}
+
+// PromptMessage mirrors a definition of the schema.
+type PromptMessage struct {
+	Content Content `json:"content"`
+	Role    Role    `json:"role"`
+}
+
+// ReadResourceResult mirrors a definition of the schema.
+type ReadResourceResult struct {
+	Meta     Meta               `json:"_meta,omitempty"`
+	Contents []ResourceContents `json:"contents"`
+}
+
+// RequestID mirrors a definition of the schema.
+type RequestID = any
+
+// Resource mirrors a definition of the schema.
+type Resource struct {
+	Annotations *Annotations `json:"annotations,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	MimeType    *string      `json:"mimeType,omitempty"`
+	Name        string       `json:"name"`
+	Size        *int64       `json:"size,omitempty"`
+	URI         string       `json:"uri"`
+}
+
+// ResourceContents mirrors a definition of the schema.
+type ResourceContents = any
+
+// ResourceRequestParams mirrors a definition of the schema.
+type ResourceRequestParams struct {
+	URI string `json:"uri"`
+}
+
+// ResourceTemplate mirrors a definition of the schema.
+type ResourceTemplate struct {
+	Annotations *Annotations `json:"annotations,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	MimeType    *string      `json:"mimeType,omitempty"`
+	Name        string       `json:"name"`
+	URITemplate string       `json:"uriTemplate"`
+}
+
+// ResourceUpdatedNotificationParams mirrors a definition of the schema.
+type ResourceUpdatedNotificationParams struct {
+	URI string `json:"uri"`
+}
+
+// Result mirrors a definition of the schema.
+type Result struct {
+	Meta Meta `json:"_meta,omitempty"`
+}
+
+// Role mirrors a definition of the schema.
+type Role string
+
+// Values of Role.
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Root mirrors a definition of the schema.
+type Root struct {
+	Name *string `json:"name,omitempty"`
+	URI  string  `json:"uri"`
+}
+
+// SamplingMessage mirrors a definition of the schema.
+type SamplingMessage struct {
+	Content any  `json:"content"`
+	Role    Role `json:"role"`
+}
+
+// ServerCapabilitiesPrompts is an object nested in a definition of the schema.
+type ServerCapabilitiesPrompts struct {
+	ListChanged *bool `json:"listChanged,omitempty"`
+}
+
+// ServerCapabilitiesResources is an object nested in a definition of the schema.
+type ServerCapabilitiesResources struct {
+	ListChanged *bool `json:"listChanged,omitempty"`
+	Subscribe   *bool `json:"subscribe,omitempty"`
+}
+
+// ServerCapabilitiesTools is an object nested in a definition of the schema.
+type ServerCapabilitiesTools struct {
+	ListChanged *bool `json:"listChanged,omitempty"`
+}
+
+// ServerCapabilities mirrors a definition of the schema.
+type ServerCapabilities struct {
+	Completions  map[string]any               `json:"completions,omitempty"`
+	Experimental map[string]any               `json:"experimental,omitempty"`
+	Logging      map[string]any               `json:"logging,omitempty"`
+	Prompts      *ServerCapabilitiesPrompts   `json:"prompts,omitempty"`
+	Resources    *ServerCapabilitiesResources `json:"resources,omitempty"`
+	Tools        *ServerCapabilitiesTools     `json:"tools,omitempty"`
+}
+
+// SetLevelRequestParams mirrors a definition of the schema.
+type SetLevelRequestParams struct {
+	Level LoggingLevel `json:"level"`
+}
+
+// TextContent mirrors a definition of the schema.
+type TextContent struct {
+	Annotations *Annotations `json:"annotations,omitempty"`
+	Text        string       `json:"text"`
+	Type        string       `json:"type"`
+}
+
+// TextResourceContents mirrors a definition of the schema.
+type TextResourceContents struct {
+	MimeType *string `json:"mimeType,omitempty"`
+	Text     string  `json:"text"`
+	URI      string  `json:"uri"`
+}
+
+// ToolInputSchema is an object nested in a definition of the schema.
+type ToolInputSchema struct {
+	Properties map[string]any `json:"properties,omitempty"`
+	Required   []string       `json:"required,omitempty"`
+	Type       string         `json:"type"`
+}
+
+// Tool mirrors a definition of the schema.
+type Tool struct {
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+	Description *string          `json:"description,omitempty"`
+	InputSchema ToolInputSchema  `json:"inputSchema"`
+	Name        string           `json:"name"`
+}
+
+// ToolAnnotations mirrors a definition of the schema.
+type ToolAnnotations struct {
+	DestructiveHint *bool   `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool   `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool   `json:"openWorldHint,omitempty"`
+	ReadOnlyHint    *bool   `json:"readOnlyHint,omitempty"`
+	Title           *string `json:"title,omitempty"`
+}
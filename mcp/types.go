@@ -4,6 +4,7 @@ package mcp
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/yosida95/uritemplate/v3"
 )
@@ -32,6 +33,14 @@ const (
 	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources
 	MethodResourcesRead MCPMethod = "resources/read"
 
+	// MethodResourcesSubscribe requests updates for a resource
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources
+	MethodResourcesSubscribe MCPMethod = "resources/subscribe"
+
+	// MethodResourcesUnsubscribe cancels updates for a resource
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources
+	MethodResourcesUnsubscribe MCPMethod = "resources/unsubscribe"
+
 	// MethodPromptsList retrieves available prompt templates
 	// https://modelcontextprotocol.io/specification/2025-03-26/server/prompts
 	MethodPromptsList MCPMethod = "prompts/list"
@@ -101,9 +110,40 @@ const (
 	MethodNotificationRootsListChanged MCPMethod = "notifications/roots/list_changed"
 )
 
-// URITemplate wraps URI template functionality for JSON serialization
+// URITemplate wraps URI template functionality for JSON serialization.
+// A template that failed to parse keeps its raw string and reports the failure through Err.
 type URITemplate struct {
 	*uritemplate.Template
+
+	raw string
+	err error
+}
+
+// NewURITemplate parses a RFC 6570 URI template.
+func NewURITemplate(raw string) (*URITemplate, error) {
+	t := &URITemplate{}
+	if err := t.parse(raw); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Raw returns the template string, including for templates that failed to parse
+func (t *URITemplate) Raw() string {
+	if t.Template != nil {
+		return t.Template.Raw()
+	}
+	return t.raw
+}
+
+// Valid reports whether the template was parsed successfully
+func (t *URITemplate) Valid() bool {
+	return t.Template != nil
+}
+
+// Err returns the parse error of an invalid template kept in lenient mode
+func (t *URITemplate) Err() error {
+	return t.err
 }
 
 func (t *URITemplate) MarshalJSON() ([]byte, error) {
@@ -115,14 +155,36 @@ func (t *URITemplate) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
+	return t.parse(raw)
+}
+
+func (t *URITemplate) parse(raw string) error {
+	t.raw = raw
 	template, err := uritemplate.New(raw)
 	if err != nil {
-		return err
+		t.Template = nil
+		t.err = &URITemplateError{Raw: raw, Err: err}
+		return t.err
 	}
 	t.Template = template
+	t.err = nil
 	return nil
 }
 
+// URITemplateError reports a URI template that failed to parse
+type URITemplateError struct {
+	Raw string
+	Err error
+}
+
+func (e *URITemplateError) Error() string {
+	return fmt.Sprintf("invalid uri template %q: %v", e.Raw, e.Err)
+}
+
+func (e *URITemplateError) Unwrap() error {
+	return e.Err
+}
+
 /* Constants */
 
 // LATEST_PROTOCOL_VERSION defines the current MCP protocol version
@@ -139,9 +201,6 @@ type ProgressToken interface{}
 // Cursor represents an opaque pagination token
 type Cursor string
 
-// RequestId uniquely identifies a JSON-RPC request
-type RequestId interface{}
-
 // JSONRPCMessage encompasses all JSON-RPC message types
 type JSONRPCMessage interface{}
 
@@ -173,7 +232,7 @@ type JSONRPCError struct {
 	ID      RequestId `json:"id"`
 	Error   struct {
 		// Error code identifying the error type
-		Code int `json:"code"`
+		Code ErrorCode `json:"code"`
 		// Concise error description
 		Message string `json:"message"`
 		// Additional error details
@@ -184,24 +243,29 @@ type JSONRPCError struct {
 // Standard error codes
 const (
 	// JSON-RPC standard errors
-	ErrorParseError     = -32700
-	ErrorInvalidRequest = -32600
-	ErrorMethodNotFound = -32601
-	ErrorInvalidParams  = -32602
-	ErrorInternalError  = -32603
+	ErrorParseError     ErrorCode = -32700
+	ErrorInvalidRequest ErrorCode = -32600
+	ErrorMethodNotFound ErrorCode = -32601
+	ErrorInvalidParams  ErrorCode = -32602
+	ErrorInternalError  ErrorCode = -32603
 
 	// MCP-specific errors
-	ErrorResourceNotFound = -32002
-	ErrorToolNotFound     = -32003
-	ErrorUnauthorized     = -32004
-	ErrorDuplicateName    = -32005
-	ErrorInvalidProtocol  = -32006
+	ErrorConnectionClosed   ErrorCode = -32000
+	ErrorRequestTimeout     ErrorCode = -32001
+	ErrorResourceNotFound   ErrorCode = -32002
+	ErrorToolNotFound       ErrorCode = -32003
+	ErrorUnauthorized       ErrorCode = -32004
+	ErrorDuplicateName      ErrorCode = -32005
+	ErrorInvalidProtocol    ErrorCode = -32006
+	ErrorRateLimited        ErrorCode = -32007
+	ErrorServiceUnavailable ErrorCode = -32008
+	ErrorSessionTerminated  ErrorCode = -32009
 )
 
 // Result represents a successful operation result
 type Result struct {
 	// Protocol-reserved metadata field
-	Meta map[string]interface{} `json:"_meta,omitempty"`
+	Meta *Meta `json:"_meta,omitempty"`
 }
 
 // EmptyResult indicates successful completion without data
@@ -230,6 +294,7 @@ type InitializeRequest struct {
 
 // InitializeResult completes protocol handshake
 type InitializeResult struct {
+	Result
 	// Protocol version server will use
 	ProtocolVersion string             `json:"protocolVersion"`
 	Capabilities    ServerCapabilities `json:"capabilities"`
@@ -265,6 +330,8 @@ type ClientCapabilities struct {
 type ServerCapabilities struct {
 	// Experimental capabilities
 	Experimental map[string]interface{} `json:"experimental,omitempty"`
+	// Argument completion support
+	Completions *CompletionsCapabilities `json:"completions,omitempty"`
 	// Logging support
 	Logging *LoggingCapabilities `json:"logging,omitempty"`
 	// Prompt template support
@@ -287,6 +354,9 @@ type SamplingCapabilities struct {
 	Features map[string]interface{} `json:"features,omitempty"`
 }
 
+// CompletionsCapabilities defines argument completion capabilities
+type CompletionsCapabilities struct{}
+
 // LoggingCapabilities defines logging capabilities
 type LoggingCapabilities struct {
 	// Supported log levels
@@ -389,6 +459,7 @@ func (BlobResourceContents) isResourceContents() {}
 
 // EmbeddedResource embeds resource content inline
 type EmbeddedResource struct {
+	Annotated
 	Type     string           `json:"type"`
 	Resource ResourceContents `json:"resource"`
 }
@@ -406,6 +477,8 @@ type Tool struct {
 	Description string `json:"description,omitempty"`
 	// JSON Schema for parameters
 	InputSchema json.RawMessage `json:"inputSchema"`
+	// JSON Schema for structured results
+	OutputSchema json.RawMessage `json:"outputSchema,omitempty"`
 	// Behavior hints for clients
 	Annotations *ToolAnnotations `json:"annotations,omitempty"`
 }
@@ -424,6 +497,21 @@ type ToolAnnotations struct {
 	OpenWorldHint *bool `json:"openWorldHint,omitempty"`
 }
 
+// IsReadOnly reports whether the tool declares not modifying its
+// environment. Tools are not read-only by default.
+func (t Tool) IsReadOnly() bool {
+	return t.Annotations != nil && t.Annotations.ReadOnlyHint != nil && *t.Annotations.ReadOnlyHint
+}
+
+// IsDestructive reports whether the tool may perform destructive updates, as
+// tools that are not read-only do unless they declare otherwise.
+func (t Tool) IsDestructive() bool {
+	if t.IsReadOnly() {
+		return false
+	}
+	return t.Annotations == nil || t.Annotations.DestructiveHint == nil || *t.Annotations.DestructiveHint
+}
+
 /* Prompts */
 
 // Prompt represents a template for LLM interactions
@@ -553,7 +641,8 @@ type ModelHint struct {
 
 /* Pagination */
 
-// PaginatedRequest supports result pagination
+// PaginatedRequest holds the params of the list requests supporting
+// pagination
 type PaginatedRequest struct {
 	// Pagination cursor
 	Cursor Cursor `json:"cursor,omitempty"`
@@ -561,6 +650,7 @@ type PaginatedRequest struct {
 
 // PaginatedResult provides pagination support
 type PaginatedResult struct {
+	Result
 	// Next page cursor
 	NextCursor Cursor `json:"nextCursor,omitempty"`
 }
@@ -620,6 +710,13 @@ type ProgressNotification struct {
 	} `json:"params"`
 }
 
+// ProgressReporter reports the progress of a request to the client that sent
+// it, as notifications/progress. Progress must increase with each report;
+// total is zero when unknown.
+type ProgressReporter interface {
+	Report(progress, total float64, message string) error
+}
+
 /* Cancellation */
 
 // CancelledNotification signals request cancellation
@@ -658,16 +755,30 @@ type ListRootsResult struct {
 
 // CompleteRequest seeks argument completions
 type CompleteRequest struct {
-	Method string `json:"method"`
-	Params struct {
-		// Reference to prompt or resource
-		Ref interface{} `json:"ref"`
-		// Argument to complete
-		Argument struct {
-			Name  string `json:"name"`
-			Value string `json:"value"`
-		} `json:"argument"`
-	} `json:"params"`
+	Method string                `json:"method"`
+	Params CompleteRequestParams `json:"params"`
+}
+
+// CompleteRequestParams holds the params of a completion/complete request
+type CompleteRequestParams struct {
+	// Reference to prompt or resource
+	Ref Reference `json:"ref"`
+	// Argument to complete
+	Argument CompleteArgument `json:"argument"`
+	// Arguments already filled in (2025-06-18)
+	Context *CompletionContext `json:"context,omitempty"`
+}
+
+// CompleteArgument is the argument being completed
+type CompleteArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CompletionContext lets completions depend on other arguments
+type CompletionContext struct {
+	// Values of the arguments already filled in, by name
+	Arguments map[string]string `json:"arguments,omitempty"`
 }
 
 // CompleteResult provides completion suggestions
@@ -683,24 +794,58 @@ type CompleteResult struct {
 	} `json:"completion"`
 }
 
+// Types of completion references
+const (
+	RefTypePrompt   = "ref/prompt"
+	RefTypeResource = "ref/resource"
+)
+
+// Reference identifies what a completion is for: a PromptReference or a
+// ResourceReference
+type Reference interface {
+	isReference()
+}
+
 // ResourceReference identifies a resource
 type ResourceReference struct {
-	Type string `json:"type"`
+	Type string `json:"type"` // Must be "ref/resource"
 	URI  string `json:"uri"`
 }
 
+func (ResourceReference) isReference() {}
+
+// MarshalJSON implements the json.Marshaler interface, defaulting Type.
+func (r ResourceReference) MarshalJSON() ([]byte, error) {
+	type alias ResourceReference
+	if r.Type == "" {
+		r.Type = RefTypeResource
+	}
+	return json.Marshal(alias(r))
+}
+
 // PromptReference identifies a prompt
 type PromptReference struct {
-	Type string `json:"type"`
+	Type string `json:"type"` // Must be "ref/prompt"
 	Name string `json:"name"`
 }
 
+func (PromptReference) isReference() {}
+
+// MarshalJSON implements the json.Marshaler interface, defaulting Type.
+func (r PromptReference) MarshalJSON() ([]byte, error) {
+	type alias PromptReference
+	if r.Type == "" {
+		r.Type = RefTypePrompt
+	}
+	return json.Marshal(alias(r))
+}
+
 /* Request/Response Types */
 
 // ListResourcesRequest queries available resources
 type ListResourcesRequest struct {
-	PaginatedRequest
-	Method string `json:"method"`
+	Method string           `json:"method"`
+	Params PaginatedRequest `json:"params,omitzero"`
 }
 
 // ListResourcesResult returns available resources
@@ -711,8 +856,8 @@ type ListResourcesResult struct {
 
 // ListResourceTemplatesRequest queries resource templates
 type ListResourceTemplatesRequest struct {
-	PaginatedRequest
-	Method string `json:"method"`
+	Method string           `json:"method"`
+	Params PaginatedRequest `json:"params,omitzero"`
 }
 
 // ListResourceTemplatesResult returns resource templates
@@ -772,8 +917,8 @@ type ResourceListChangedNotification struct {
 
 // ListToolsRequest queries available tools
 type ListToolsRequest struct {
-	PaginatedRequest
-	Method string `json:"method"`
+	Method string           `json:"method"`
+	Params PaginatedRequest `json:"params,omitzero"`
 }
 
 // ListToolsResult returns available tools
@@ -798,6 +943,8 @@ type CallToolResult struct {
 	Result
 	// Result content
 	Content []Content `json:"content"`
+	// Structured result, conforming to the tool's outputSchema
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
 	// Indicates error occurred
 	IsError bool `json:"isError,omitempty"`
 }
@@ -809,8 +956,8 @@ type ToolListChangedNotification struct {
 
 // ListPromptsRequest queries available prompts
 type ListPromptsRequest struct {
-	PaginatedRequest
-	Method string `json:"method"`
+	Method string           `json:"method"`
+	Params PaginatedRequest `json:"params,omitzero"`
 }
 
 // ListPromptsResult returns available prompts
@@ -0,0 +1,26 @@
+package mcp
+
+import "testing"
+
+func TestToolHints(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name        string
+		annotations *ToolAnnotations
+		readOnly    bool
+		destructive bool
+	}{
+		{"Default", nil, false, true},
+		{"ReadOnly", &ToolAnnotations{ReadOnlyHint: &yes, DestructiveHint: &yes}, true, false},
+		{"Additive", &ToolAnnotations{DestructiveHint: &no}, false, false},
+		{"NotReadOnly", &ToolAnnotations{ReadOnlyHint: &no}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := Tool{Name: "t", Annotations: tt.annotations}
+			if tool.IsReadOnly() != tt.readOnly || tool.IsDestructive() != tt.destructive {
+				t.Errorf("Expected read-only %v and destructive %v, got %v and %v", tt.readOnly, tt.destructive, tool.IsReadOnly(), tool.IsDestructive())
+			}
+		})
+	}
+}
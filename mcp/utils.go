@@ -5,172 +5,186 @@ import (
 	"fmt"
 )
 
-// ParseCallToolResult parses a raw JSON message into a CallToolResult.
+// ParseCallToolResult parses a raw JSON message into a CallToolResult,
+// decoding the content items into their types in a single pass.
 func ParseCallToolResult(rawMessage *json.RawMessage) (*CallToolResult, error) {
 	if rawMessage == nil {
 		return nil, fmt.Errorf("response is nil")
 	}
 
-	var jsonContent map[string]any
+	var jsonContent struct {
+		Result
+		Content           *[]contentJSON  `json:"content"`
+		StructuredContent json.RawMessage `json:"structuredContent"`
+		IsError           bool            `json:"isError"`
+	}
 	if err := json.Unmarshal(*rawMessage, &jsonContent); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
-
-	var result CallToolResult
-
-	meta, ok := jsonContent["_meta"]
-	if ok {
-		if metaMap, ok := meta.(map[string]any); ok {
-			result.Meta = metaMap
-		}
+	if jsonContent.Content == nil {
+		return nil, fmt.Errorf("content is missing")
 	}
 
-	isError, ok := jsonContent["isError"]
-	if ok {
-		if isErrorBool, ok := isError.(bool); ok {
-			result.IsError = isErrorBool
-		}
+	content, err := parseContents(*jsonContent.Content)
+	if err != nil {
+		return nil, err
 	}
+	return &CallToolResult{
+		Result:            jsonContent.Result,
+		Content:           content,
+		StructuredContent: nullToNil(jsonContent.StructuredContent),
+		IsError:           jsonContent.IsError,
+	}, nil
+}
 
-	contents, ok := jsonContent["content"]
-	if !ok {
-		return nil, fmt.Errorf("content is missing")
+// ParseReadResourceResult parses a raw JSON message into a ReadResourceResult,
+// decoding the contents into their types in a single pass.
+func ParseReadResourceResult(rawMessage *json.RawMessage) (*ReadResourceResult, error) {
+	if rawMessage == nil {
+		return nil, fmt.Errorf("response is nil")
 	}
 
-	contentArr, ok := contents.([]any)
-	if !ok {
-		return nil, fmt.Errorf("content is not an array")
+	var jsonContent struct {
+		Result
+		Contents *[]resourceContentsJSON `json:"contents"`
+	}
+	if err := json.Unmarshal(*rawMessage, &jsonContent); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if jsonContent.Contents == nil {
+		return nil, fmt.Errorf("contents is missing")
 	}
 
-	for _, content := range contentArr {
-		// Extract content
-		contentMap, ok := content.(map[string]any)
-		if !ok {
-			return nil, fmt.Errorf("content is not an object")
-		}
-
-		// Process content
-		content, err := ParseContent(contentMap)
+	result := ReadResourceResult{
+		Result:   jsonContent.Result,
+		Contents: make([]ResourceContents, 0, len(*jsonContent.Contents)),
+	}
+	for i := range *jsonContent.Contents {
+		contents, err := parseResourceContents(&(*jsonContent.Contents)[i])
 		if err != nil {
 			return nil, err
 		}
-
-		result.Content = append(result.Content, content)
+		result.Contents = append(result.Contents, contents)
 	}
-
 	return &result, nil
 }
 
-// ParseReadResourceResult parses a raw JSON message into a ReadResourceResult.
-func ParseReadResourceResult(rawMessage *json.RawMessage) (*ReadResourceResult, error) {
+// ParseGetPromptResult parses a raw JSON message into a GetPromptResult,
+// decoding the message contents into their types in a single pass.
+func ParseGetPromptResult(rawMessage *json.RawMessage) (*GetPromptResult, error) {
 	if rawMessage == nil {
 		return nil, fmt.Errorf("response is nil")
 	}
 
-	var jsonContent map[string]any
+	var jsonContent struct {
+		Result
+		Description string `json:"description"`
+		Messages    []struct {
+			Role    Role         `json:"role"`
+			Content *contentJSON `json:"content"`
+		} `json:"messages"`
+	}
 	if err := json.Unmarshal(*rawMessage, &jsonContent); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	var result ReadResourceResult
-
-	meta, ok := jsonContent["_meta"]
-	if ok {
-		if metaMap, ok := meta.(map[string]any); ok {
-			result.Meta = metaMap
-		}
-	}
-
-	contents, ok := jsonContent["contents"]
-	if !ok {
-		return nil, fmt.Errorf("contents is missing")
-	}
-
-	contentArr, ok := contents.([]any)
-	if !ok {
-		return nil, fmt.Errorf("contents is not an array")
+	result := GetPromptResult{
+		Result:      jsonContent.Result,
+		Description: jsonContent.Description,
+		Messages:    make([]PromptMessage, 0, len(jsonContent.Messages)),
 	}
-
-	for _, content := range contentArr {
-		// Extract content
-		contentMap, ok := content.(map[string]any)
-		if !ok {
+	for _, message := range jsonContent.Messages {
+		if message.Role != RoleAssistant && message.Role != RoleUser {
+			return nil, fmt.Errorf("unsupported role: %s", message.Role)
+		}
+		if message.Content == nil {
 			return nil, fmt.Errorf("content is not an object")
 		}
+		content, err := parseContent(message.Content)
+		if err != nil {
+			return nil, err
+		}
+		result.Messages = append(result.Messages, NewPromptMessage(message.Role, content))
+	}
+	return &result, nil
+}
 
-		// Process content
-		content, err := ParseResourceContents(contentMap)
+// parseContents converts decoded content items, checking them as ParseContent does.
+func parseContents(items []contentJSON) ([]Content, error) {
+	contents := make([]Content, 0, len(items))
+	for i := range items {
+		content, err := parseContent(&items[i])
 		if err != nil {
 			return nil, err
 		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
 
-		result.Contents = append(result.Contents, content)
+// parseContent converts a decoded content item, checking it as ParseContent does.
+func parseContent(item *contentJSON) (Content, error) {
+	switch item.Type {
+	case "image", "audio":
+		if item.Data == "" || item.MimeType == "" {
+			return nil, fmt.Errorf("%s data or mimeType is missing", item.Type)
+		}
+	case "resource":
+		if item.Resource != nil && item.Resource.URI == "" {
+			return nil, fmt.Errorf("resource uri is missing")
+		}
 	}
+	return item.content()
+}
 
-	return &result, nil
+// parseResourceContents converts decoded resource contents, checking them as
+// ParseResourceContents does.
+func parseResourceContents(item *resourceContentsJSON) (ResourceContents, error) {
+	if item.URI == "" {
+		return nil, fmt.Errorf("resource uri is missing")
+	}
+	return item.contents()
 }
 
-// ParseGetPromptResult parses a raw JSON message into a GetPromptResult.
-func ParseGetPromptResult(rawMessage *json.RawMessage) (*GetPromptResult, error) {
+// ParseListResourceTemplatesResult parses a raw JSON message into a ListResourceTemplatesResult.
+// In lenient mode, a template whose uriTemplate fails to parse is kept with its raw string,
+// its URITemplate reports the failure through Err, and the rest of the list is still parsed.
+// Otherwise, the first invalid template fails the whole result.
+func ParseListResourceTemplatesResult(rawMessage *json.RawMessage, lenient bool) (*ListResourceTemplatesResult, error) {
 	if rawMessage == nil {
 		return nil, fmt.Errorf("response is nil")
 	}
 
-	var jsonContent map[string]any
+	var jsonContent struct {
+		PaginatedResult
+		ResourceTemplates []json.RawMessage `json:"resourceTemplates"`
+	}
 	if err := json.Unmarshal(*rawMessage, &jsonContent); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	result := GetPromptResult{}
-
-	meta, ok := jsonContent["_meta"]
-	if ok {
-		if metaMap, ok := meta.(map[string]any); ok {
-			result.Meta = metaMap
+	result := ListResourceTemplatesResult{PaginatedResult: jsonContent.PaginatedResult}
+
+	for i, item := range jsonContent.ResourceTemplates {
+		// Decode uriTemplate as a plain string so one bad template doesn't abort decoding
+		type alias ResourceTemplate
+		var template ResourceTemplate
+		aux := struct {
+			*alias
+			URITemplate string `json:"uriTemplate"`
+		}{
+			alias: (*alias)(&template),
 		}
-	}
-
-	description, ok := jsonContent["description"]
-	if ok {
-		if descriptionStr, ok := description.(string); ok {
-			result.Description = descriptionStr
+		if err := json.Unmarshal(item, &aux); err != nil {
+			return nil, fmt.Errorf("resource template %d: %w", i, err)
 		}
-	}
 
-	messages, ok := jsonContent["messages"]
-	if ok {
-		messagesArr, ok := messages.([]any)
-		if !ok {
-			return nil, fmt.Errorf("messages is not an array")
+		template.URITemplate = &URITemplate{}
+		if err := template.URITemplate.parse(aux.URITemplate); err != nil && !lenient {
+			return nil, fmt.Errorf("resource template %d: %w", i, err)
 		}
 
-		for _, message := range messagesArr {
-			messageMap, ok := message.(map[string]any)
-			if !ok {
-				return nil, fmt.Errorf("message is not an object")
-			}
-
-			// Extract role
-			roleStr := ExtractString(messageMap, "role")
-			if roleStr == "" || (roleStr != string(RoleAssistant) && roleStr != string(RoleUser)) {
-				return nil, fmt.Errorf("unsupported role: %s", roleStr)
-			}
-
-			// Extract content
-			contentMap, ok := messageMap["content"].(map[string]any)
-			if !ok {
-				return nil, fmt.Errorf("content is not an object")
-			}
-
-			// Process content
-			content, err := ParseContent(contentMap)
-			if err != nil {
-				return nil, err
-			}
-
-			// Append processed message
-			result.Messages = append(result.Messages, NewPromptMessage(Role(roleStr), content))
-		}
+		result.ResourceTemplates = append(result.ResourceTemplates, template)
 	}
 
 	return &result, nil
@@ -227,7 +241,8 @@ func ParseResourceContents(contentMap map[string]any) (ResourceContents, error)
 
 	mimeType := ExtractString(contentMap, "mimeType")
 
-	if text := ExtractString(contentMap, "text"); text != "" {
+	// An empty text is valid content, so check for the key rather than the value
+	if text, ok := contentMap["text"].(string); ok {
 		return TextResourceContents{
 			URI:      uri,
 			MimeType: mimeType,
@@ -235,7 +250,7 @@ func ParseResourceContents(contentMap map[string]any) (ResourceContents, error)
 		}, nil
 	}
 
-	if blob := ExtractString(contentMap, "blob"); blob != "" {
+	if blob, ok := contentMap["blob"].(string); ok {
 		return BlobResourceContents{
 			URI:      uri,
 			MimeType: mimeType,
@@ -266,6 +281,23 @@ func ExtractMap(data map[string]any, key string) map[string]any {
 	return nil
 }
 
+// NewPromptReference creates a reference to a prompt for completion/complete.
+func NewPromptReference(name string) PromptReference {
+	return PromptReference{
+		Type: RefTypePrompt,
+		Name: name,
+	}
+}
+
+// NewResourceReference creates a reference to a resource or resource template
+// for completion/complete.
+func NewResourceReference(uri string) ResourceReference {
+	return ResourceReference{
+		Type: RefTypeResource,
+		URI:  uri,
+	}
+}
+
 // NewTextContent creates a new TextContent with the given text.
 func NewTextContent(text string) TextContent {
 	return TextContent{
@@ -323,4 +355,4 @@ func NewToolResultText(text string) *CallToolResult {
 // ToBoolPtr returns a pointer to the given boolean value.
 func ToBoolPtr(b bool) *bool {
 	return &b
-}
\ No newline at end of file
+}
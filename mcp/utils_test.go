@@ -0,0 +1,238 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseListResourceTemplatesResult(t *testing.T) {
+	raw := json.RawMessage(`{
+		"resourceTemplates": [
+			{"uriTemplate": "file:///{path}", "name": "files"},
+			{"uriTemplate": "db://{table", "name": "broken"},
+			{"uriTemplate": "users://{id}/profile", "name": "profiles", "mimeType": "application/json"}
+		],
+		"nextCursor": "abc"
+	}`)
+
+	t.Run("Strict", func(t *testing.T) {
+		_, err := ParseListResourceTemplatesResult(&raw, false)
+		var templateErr *URITemplateError
+		if !errors.As(err, &templateErr) {
+			t.Fatalf("Expected URITemplateError, got %v", err)
+		}
+		if templateErr.Raw != "db://{table" {
+			t.Errorf("Expected raw template in error, got %q", templateErr.Raw)
+		}
+	})
+
+	t.Run("Lenient", func(t *testing.T) {
+		result, err := ParseListResourceTemplatesResult(&raw, true)
+		if err != nil {
+			t.Fatalf("Lenient parse failed: %v", err)
+		}
+		if len(result.ResourceTemplates) != 3 || result.NextCursor != "abc" {
+			t.Fatalf("Unexpected result: %+v", result)
+		}
+
+		broken := result.ResourceTemplates[1]
+		if broken.Name != "broken" || broken.URITemplate.Valid() || broken.URITemplate.Err() == nil {
+			t.Errorf("Expected invalid template to be marked, got %+v", broken)
+		}
+		if broken.URITemplate.Raw() != "db://{table" {
+			t.Errorf("Expected raw string to be kept, got %q", broken.URITemplate.Raw())
+		}
+
+		profiles := result.ResourceTemplates[2]
+		if !profiles.URITemplate.Valid() || profiles.MimeType != "application/json" {
+			t.Errorf("Expected valid template after the broken one, got %+v", profiles)
+		}
+		if got := profiles.URITemplate.Raw(); got != "users://{id}/profile" {
+			t.Errorf("Unexpected raw template %q", got)
+		}
+
+		// Invalid templates still marshal back to their raw string
+		data, err := json.Marshal(broken)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		var roundTrip map[string]any
+		_ = json.Unmarshal(data, &roundTrip)
+		if roundTrip["uriTemplate"] != "db://{table" {
+			t.Errorf("Unexpected marshaled template: %s", data)
+		}
+	})
+}
+
+func TestParseCallToolResult(t *testing.T) {
+	t.Run("Typed", func(t *testing.T) {
+		raw := json.RawMessage(`{"content":[{"type":"text","text":"hi","annotations":{"priority":0.5}},{"type":"resource","resource":{"uri":"file:///a","blob":"aGk="}}],"structuredContent":null,"_meta":{"k":"v"}}`)
+		result, err := ParseCallToolResult(&raw)
+		if err != nil {
+			t.Fatalf("ParseCallToolResult failed: %v", err)
+		}
+		text, ok := result.Content[0].(TextContent)
+		if !ok || text.Annotations == nil || text.Annotations.Priority != 0.5 {
+			t.Errorf("Expected annotated text content, got %+v", result.Content[0])
+		}
+		if resource, ok := result.Content[1].(EmbeddedResource); !ok || resource.Resource.(BlobResourceContents).Blob != "aGk=" {
+			t.Errorf("Expected an embedded blob, got %+v", result.Content[1])
+		}
+		if result.StructuredContent != nil || result.Meta.Get("k") != "v" {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for name, data := range map[string]string{
+			"MissingContent":  `{}`,
+			"ContentNotArray": `{"content":{}}`,
+			"ImageNoMimeType": `{"content":[{"type":"image","data":"aGk="}]}`,
+			"UnknownType":     `{"content":[{"type":"video"}]}`,
+		} {
+			raw := json.RawMessage(data)
+			if _, err := ParseCallToolResult(&raw); err == nil {
+				t.Errorf("%s: Expected an error", name)
+			}
+		}
+	})
+}
+
+func FuzzParseCallToolResult(f *testing.F) {
+	f.Add([]byte(`{"content":[{"type":"text","text":"hello"}],"isError":true}`))
+	f.Add([]byte(`{"content":[{"type":"image","data":"aGk=","mimeType":"image/png"},{"type":"audio","data":"aGk=","mimeType":"audio/wav"}]}`))
+	f.Add([]byte(`{"content":[{"type":"resource","resource":{"uri":"file:///a","text":"x"}}],"_meta":{"k":1}}`))
+	f.Add([]byte(`{"content":[]}`))
+	f.Add([]byte(`{"content":[{"type":"text","text":"{\"n\":1}"}],"structuredContent":{"n":1}}`))
+	f.Add([]byte(`null`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		raw := json.RawMessage(data)
+		result, err := ParseCallToolResult(&raw)
+		if err != nil {
+			return
+		}
+
+		// Anything we parse must survive a marshal/parse round trip
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("Failed to marshal parsed result: %v", err)
+		}
+		raw = encoded
+		again, err := ParseCallToolResult(&raw)
+		if err != nil {
+			t.Fatalf("Failed to re-parse %s: %v", encoded, err)
+		}
+		if len(again.Content) != len(result.Content) || again.IsError != result.IsError || !bytes.Equal(again.StructuredContent, result.StructuredContent) {
+			t.Fatalf("Round trip mismatch: %+v != %+v", again, result)
+		}
+	})
+}
+
+func FuzzParseReadResourceResult(f *testing.F) {
+	f.Add([]byte(`{"contents":[{"uri":"file:///a","mimeType":"text/plain","text":"hello"}]}`))
+	f.Add([]byte(`{"contents":[{"uri":"file:///b","blob":"aGk="}],"_meta":{}}`))
+	f.Add([]byte(`{"contents":[{"uri":"file:///c","text":""}]}`))
+	f.Add([]byte(`{"contents":[]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		raw := json.RawMessage(data)
+		result, err := ParseReadResourceResult(&raw)
+		if err != nil {
+			return
+		}
+
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("Failed to marshal parsed result: %v", err)
+		}
+		raw = encoded
+		again, err := ParseReadResourceResult(&raw)
+		if err != nil {
+			t.Fatalf("Failed to re-parse %s: %v", encoded, err)
+		}
+		if len(again.Contents) != len(result.Contents) {
+			t.Fatalf("Round trip mismatch: %+v != %+v", again, result)
+		}
+	})
+}
+
+func FuzzParseGetPromptResult(f *testing.F) {
+	f.Add([]byte(`{"description":"d","messages":[{"role":"user","content":{"type":"text","text":"hi"}}]}`))
+	f.Add([]byte(`{"messages":[{"role":"assistant","content":{"type":"resource","resource":{"uri":"a","blob":"aGk="}}}]}`))
+	f.Add([]byte(`{"messages":[]}`))
+	f.Add([]byte(`{}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		raw := json.RawMessage(data)
+		result, err := ParseGetPromptResult(&raw)
+		if err != nil {
+			return
+		}
+
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("Failed to marshal parsed result: %v", err)
+		}
+		raw = encoded
+		again, err := ParseGetPromptResult(&raw)
+		if err != nil {
+			t.Fatalf("Failed to re-parse %s: %v", encoded, err)
+		}
+		if len(again.Messages) != len(result.Messages) || again.Description != result.Description {
+			t.Fatalf("Round trip mismatch: %+v != %+v", again, result)
+		}
+	})
+}
+
+// largeResult returns a result whose key field lists n copies of item.
+func largeResult(b *testing.B, key, item string, n int) json.RawMessage {
+	b.Helper()
+	items := make([]json.RawMessage, n)
+	for i := range items {
+		items[i] = json.RawMessage(item)
+	}
+	data, err := json.Marshal(map[string]any{key: items, "_meta": map[string]any{"k": 1}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func BenchmarkParseCallToolResult(b *testing.B) {
+	text, _ := json.Marshal(strings.Repeat("lorem ipsum dolor sit amet ", 40000))
+	raw := largeResult(b, "content", `{"type":"text","text":`+string(text)+`}`, 4)
+	b.SetBytes(int64(len(raw)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := ParseCallToolResult(&raw); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseCallToolResultManyItems(b *testing.B) {
+	raw := largeResult(b, "content", `{"type":"text","text":"a line of output","annotations":{"priority":0.5}}`, 50000)
+	b.SetBytes(int64(len(raw)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := ParseCallToolResult(&raw); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseReadResourceResult(b *testing.B) {
+	blob := strings.Repeat("QUJD", 500000)
+	raw := largeResult(b, "contents", `{"uri":"file:///a.bin","mimeType":"application/octet-stream","blob":"`+blob+`"}`, 2)
+	b.SetBytes(int64(len(raw)))
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := ParseReadResourceResult(&raw); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
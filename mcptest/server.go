@@ -0,0 +1,600 @@
+// Package mcptest provides an in-process MCP server for testing MCP clients.
+//
+// Tests register canned tools, resources and prompts, optionally attach
+// behaviors (delays, errors, notifications) to methods, and get back an
+// httptest.Server speaking the Streamable HTTP transport:
+//
+//	srv := mcptest.NewServer()
+//	srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+//		return mcp.NewToolResultText(fmt.Sprint(args["text"])), nil
+//	})
+//	srv.Start()
+//	defer srv.Close()
+package mcptest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+const headerKeySessionID = "Mcp-Session-Id"
+
+// Request is a JSON-RPC request or notification received by the server,
+// or a response the client sent to a server-initiated request.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      mcp.RequestId   `json:"id,omitzero"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *mcp.Error      `json:"error,omitempty"`
+
+	// SessionID is the session header sent with the request
+	SessionID string `json:"-"`
+	// Header holds the HTTP headers sent with the request
+	Header http.Header `json:"-"`
+}
+
+// IsNotification reports whether the request carries no ID.
+func (r *Request) IsNotification() bool {
+	return r.ID.IsNil()
+}
+
+// IsResponse reports whether the message answers a server-initiated request.
+func (r *Request) IsResponse() bool {
+	return r.Method == "" && !r.ID.IsNil()
+}
+
+// HandlerFunc answers a request with a result to be marshaled, or an error.
+// Errors of type *mcp.Error are sent as-is; other errors become internal errors.
+type HandlerFunc func(ctx context.Context, request *Request) (any, error)
+
+// ToolHandlerFunc answers a tools/call request.
+type ToolHandlerFunc func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error)
+
+// PromptHandlerFunc answers a prompts/get request.
+type PromptHandlerFunc func(ctx context.Context, arguments map[string]string) (*mcp.GetPromptResult, error)
+
+// Behavior alters how the server answers a method.
+type Behavior struct {
+	// Delay postpones the response
+	Delay time.Duration
+	// Error replaces the result with a JSON-RPC error
+	Error *mcp.Error
+	// StatusCode, if set, answers with a bare HTTP error status instead of JSON-RPC
+	StatusCode int
+	// Notifications are streamed before the response, which switches it to SSE
+	Notifications []mcp.JSONRPCNotification
+	// SSE forces an SSE response even without notifications
+	SSE bool
+}
+
+// Server is an in-process MCP server for tests.
+type Server struct {
+	*httptest.Server
+
+	name         string
+	version      string
+	instructions string
+	capabilities *mcp.ServerCapabilities
+
+	mu        sync.Mutex
+	sessionID string
+	tools     []mcp.Tool
+	toolFuncs map[string]ToolHandlerFunc
+	resources map[string]mcp.ResourceContents
+	resList   []mcp.Resource
+	prompts   []mcp.Prompt
+	promptFns map[string]PromptHandlerFunc
+	handlers  map[string]HandlerFunc
+	behaviors map[string]Behavior
+	requests  []Request
+	// streams are the GET streams held open by clients
+	streams []chan any
+	closed  chan struct{}
+
+	sessionCounter atomic.Int64
+}
+
+// NewServer creates an unstarted test server. Register tools, resources,
+// prompts and behaviors, then call Start.
+func NewServer() *Server {
+	s := &Server{
+		name:      "mcptest",
+		version:   "0.0.1",
+		toolFuncs: make(map[string]ToolHandlerFunc),
+		resources: make(map[string]mcp.ResourceContents),
+		promptFns: make(map[string]PromptHandlerFunc),
+		handlers:  make(map[string]HandlerFunc),
+		behaviors: make(map[string]Behavior),
+		closed:    make(chan struct{}),
+	}
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// AddTool registers a tool served by tools/list and tools/call.
+func (s *Server) AddTool(tool mcp.Tool, handler ToolHandlerFunc) {
+	if len(tool.InputSchema) == 0 {
+		tool.InputSchema = json.RawMessage(`{"type":"object"}`)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools = append(s.tools, tool)
+	s.toolFuncs[tool.Name] = handler
+}
+
+// AddResource registers a resource served by resources/list and resources/read.
+func (s *Server) AddResource(resource mcp.Resource, contents mcp.ResourceContents) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resList = append(s.resList, resource)
+	s.resources[resource.URI] = contents
+}
+
+// AddPrompt registers a prompt served by prompts/list and prompts/get.
+func (s *Server) AddPrompt(prompt mcp.Prompt, handler PromptHandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prompts = append(s.prompts, prompt)
+	s.promptFns[prompt.Name] = handler
+}
+
+// SetCapabilities replaces the capabilities the server declares, which are
+// otherwise derived from the registered tools, resources, prompts and
+// completion/complete handler.
+func (s *Server) SetCapabilities(capabilities mcp.ServerCapabilities) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capabilities = &capabilities
+}
+
+// SetInstructions sets the instructions returned by initialize.
+func (s *Server) SetInstructions(instructions string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instructions = instructions
+}
+
+// HandleMethod registers a handler for an arbitrary method, replacing the built-in one if any.
+func (s *Server) HandleMethod(method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// SetBehavior attaches a behavior to a method.
+func (s *Server) SetBehavior(method string, behavior Behavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.behaviors[method] = behavior
+}
+
+// Requests returns a copy of all requests and notifications received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// SessionID returns the ID of the current session, if any.
+func (s *Server) SessionID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessionID
+}
+
+// ExpireSession forgets the current session, as a server that restarted or
+// timed it out does: requests carrying its ID are answered 404 Not Found
+// until a client initializes a new one.
+func (s *Server) ExpireSession() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessionID = ""
+}
+
+// Notify sends a notification on the GET streams clients hold open, as a
+// server does outside of any request. It returns the number of streams it
+// was sent on, which is zero until a client opens one. Streams that are
+// not keeping up miss it.
+func (s *Server) Notify(method string, params any) int {
+	notification := mcp.JSONRPCNotification{JSONRPC: mcp.JSONRPC_VERSION, Method: method, Params: params}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sent := 0
+	for _, stream := range s.streams {
+		select {
+		case stream <- notification:
+			sent++
+		default:
+		}
+	}
+	return sent
+}
+
+// Close ends the open GET streams and shuts the server down.
+func (s *Server) Close() {
+	s.mu.Lock()
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+	}
+	s.mu.Unlock()
+	s.Server.Close()
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.serveStream(w, r)
+		return
+	case http.MethodPost:
+	case http.MethodDelete:
+		s.mu.Lock()
+		if r.Header.Get(headerKeySessionID) == s.sessionID {
+			s.sessionID = ""
+		}
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request Request
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	request.SessionID = r.Header.Get(headerKeySessionID)
+	request.Header = r.Header.Clone()
+
+	s.mu.Lock()
+	s.requests = append(s.requests, request)
+	behavior := s.behaviors[request.Method]
+	sessionID := s.sessionID
+	s.mu.Unlock()
+
+	if request.Method != string(mcp.MethodInitialize) && request.SessionID != sessionID {
+		http.Error(w, "Invalid session ID", http.StatusNotFound)
+		return
+	}
+
+	if behavior.Delay > 0 {
+		select {
+		case <-time.After(behavior.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if behavior.StatusCode != 0 {
+		http.Error(w, http.StatusText(behavior.StatusCode), behavior.StatusCode)
+		return
+	}
+
+	if request.IsNotification() || request.IsResponse() {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if request.Method == string(mcp.MethodInitialize) {
+		sessionID = fmt.Sprintf("mcptest-session-%d", s.sessionCounter.Add(1))
+		s.mu.Lock()
+		s.sessionID = sessionID
+		s.mu.Unlock()
+		w.Header().Set(headerKeySessionID, sessionID)
+	}
+
+	// In SSE mode the notifications are flushed before the method runs,
+	// so clients observe them while the request is still in flight
+	ctx := r.Context()
+	sse := len(behavior.Notifications) > 0 || behavior.SSE
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for _, notification := range behavior.Notifications {
+			writeEvent(w, notification)
+		}
+		var mu sync.Mutex
+		ctx = context.WithValue(ctx, notifierKey{}, func(message any) {
+			mu.Lock()
+			defer mu.Unlock()
+			writeEvent(w, message)
+		})
+	}
+
+	response := s.respond(ctx, &request, behavior)
+
+	if sse {
+		writeEvent(w, response)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// serveStream holds a GET stream open until the client or the server closes
+// it, writing the messages given to Notify.
+func (s *Server) serveStream(w http.ResponseWriter, r *http.Request) {
+	stream := make(chan any, 16)
+	s.mu.Lock()
+	if r.Header.Get(headerKeySessionID) != s.sessionID {
+		s.mu.Unlock()
+		http.Error(w, "Invalid session ID", http.StatusNotFound)
+		return
+	}
+	s.streams = append(s.streams, stream)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, st := range s.streams {
+			if st == stream {
+				s.streams = append(s.streams[:i], s.streams[i+1:]...)
+				break
+			}
+		}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	for {
+		select {
+		case message := <-stream:
+			writeEvent(w, message)
+		case <-r.Context().Done():
+			return
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+// ServeStdio serves newline-delimited JSON-RPC messages read from r, as a
+// stdio MCP server would, writing to w until r ends. Requests
+// are handled concurrently. Behaviors apply as over HTTP except StatusCode,
+// and SendNotification and SendRequest always work.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	defer wg.Wait()
+	send := func(message any) {
+		data, _ := json.Marshal(message)
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = w.Write(append(data, '\n'))
+	}
+	ctx = context.WithValue(ctx, notifierKey{}, send)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var request Request
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			send(map[string]any{
+				"jsonrpc": mcp.JSONRPC_VERSION,
+				"id":      nil,
+				"error":   mcp.NewError(mcp.ErrorParseError, err.Error()),
+			})
+			continue
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, request)
+		behavior := s.behaviors[request.Method]
+		s.mu.Unlock()
+		if request.IsNotification() || request.IsResponse() {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if behavior.Delay > 0 {
+				select {
+				case <-time.After(behavior.Delay):
+				case <-ctx.Done():
+					return
+				}
+			}
+			for _, notification := range behavior.Notifications {
+				send(notification)
+			}
+			send(s.respond(ctx, &request, behavior))
+		}()
+	}
+	return scanner.Err()
+}
+
+// respond builds the JSON-RPC response envelope for a request.
+func (s *Server) respond(ctx context.Context, request *Request, behavior Behavior) map[string]any {
+	response := map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      request.ID,
+	}
+	if behavior.Error != nil {
+		response["error"] = behavior.Error
+	} else if result, err := s.dispatch(ctx, request); err != nil {
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = mcp.NewError(mcp.ErrorInternalError, err.Error())
+		}
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	return response
+}
+
+type notifierKey struct{}
+
+// ErrNotStreaming is returned by SendNotification and SendRequest when the
+// response to the current request is not an SSE stream.
+var ErrNotStreaming = errors.New("mcptest: response is not streaming; set Behavior.SSE")
+
+// SendNotification streams a notification to the client from within a
+// handler, before the response. It requires the method's Behavior to select
+// an SSE response.
+func SendNotification(ctx context.Context, method string, params any) error {
+	return stream(ctx, mcp.JSONRPCNotification{JSONRPC: mcp.JSONRPC_VERSION, Method: method, Params: params})
+}
+
+// SendRequest streams a server-initiated request to the client from within a
+// handler, like SendNotification. The client's answer is recorded by Requests
+// as a message for which IsResponse is true.
+func SendRequest(ctx context.Context, id mcp.RequestId, method string, params map[string]any) error {
+	return stream(ctx, mcp.JSONRPCRequest{JSONRPC: mcp.JSONRPC_VERSION, ID: id, Method: method, Params: params})
+}
+
+func stream(ctx context.Context, message any) error {
+	send, ok := ctx.Value(notifierKey{}).(func(any))
+	if !ok {
+		return ErrNotStreaming
+	}
+	send(message)
+	return nil
+}
+
+// writeEvent writes a single SSE message event and flushes it.
+func writeEvent(w http.ResponseWriter, message any) {
+	data, _ := json.Marshal(message)
+	fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *Server) dispatch(ctx context.Context, request *Request) (any, error) {
+	s.mu.Lock()
+	handler, ok := s.handlers[request.Method]
+	s.mu.Unlock()
+	if ok {
+		return handler(ctx, request)
+	}
+
+	switch mcp.MCPMethod(request.Method) {
+	case mcp.MethodInitialize:
+		return s.initialize(), nil
+	case mcp.MethodPing:
+		return map[string]any{}, nil
+	case mcp.MethodToolsList:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return mcp.ListToolsResult{Tools: append([]mcp.Tool{}, s.tools...)}, nil
+	case mcp.MethodToolsCall:
+		return s.callTool(ctx, request)
+	case mcp.MethodResourcesList:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return mcp.ListResourcesResult{Resources: append([]mcp.Resource{}, s.resList...)}, nil
+	case mcp.MethodResourcesRead:
+		return s.readResource(request)
+	case mcp.MethodPromptsList:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return mcp.ListPromptsResult{Prompts: append([]mcp.Prompt{}, s.prompts...)}, nil
+	case mcp.MethodPromptsGet:
+		return s.getPrompt(ctx, request)
+	}
+	return nil, mcp.NewError(mcp.ErrorMethodNotFound, fmt.Sprintf("method not found: %s", request.Method))
+}
+
+func (s *Server) initialize() mcp.InitializeResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := mcp.InitializeResult{
+		ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		ServerInfo:      mcp.Implementation{Name: s.name, Version: s.version},
+		Instructions:    s.instructions,
+	}
+	if len(s.tools) > 0 {
+		result.Capabilities.Tools = &mcp.ToolsCapabilities{}
+	}
+	if len(s.resList) > 0 {
+		result.Capabilities.Resources = &mcp.ResourcesCapabilities{}
+	}
+	if len(s.prompts) > 0 {
+		result.Capabilities.Prompts = &mcp.PromptsCapabilities{}
+	}
+	if _, ok := s.handlers[string(mcp.MethodCompleteList)]; ok {
+		result.Capabilities.Completions = &mcp.CompletionsCapabilities{}
+	}
+	if s.capabilities != nil {
+		result.Capabilities = *s.capabilities
+	}
+	return result
+}
+
+func (s *Server) callTool(ctx context.Context, request *Request) (any, error) {
+	var params struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments"`
+	}
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		return nil, mcp.NewError(mcp.ErrorInvalidParams, err.Error())
+	}
+
+	s.mu.Lock()
+	handler, ok := s.toolFuncs[params.Name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, mcp.NewError(mcp.ErrorToolNotFound, fmt.Sprintf("tool not found: %s", params.Name))
+	}
+	return handler(ctx, params.Arguments)
+}
+
+func (s *Server) readResource(request *Request) (any, error) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		return nil, mcp.NewError(mcp.ErrorInvalidParams, err.Error())
+	}
+
+	s.mu.Lock()
+	contents, ok := s.resources[params.URI]
+	s.mu.Unlock()
+	if !ok {
+		return nil, mcp.NewError(mcp.ErrorResourceNotFound, fmt.Sprintf("resource not found: %s", params.URI))
+	}
+	return mcp.ReadResourceResult{Contents: []mcp.ResourceContents{contents}}, nil
+}
+
+func (s *Server) getPrompt(ctx context.Context, request *Request) (any, error) {
+	var params struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		return nil, mcp.NewError(mcp.ErrorInvalidParams, err.Error())
+	}
+
+	s.mu.Lock()
+	handler, ok := s.promptFns[params.Name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, mcp.NewError(mcp.ErrorInvalidParams, fmt.Sprintf("prompt not found: %s", params.Name))
+	}
+	return handler(ctx, params.Arguments)
+}
@@ -0,0 +1,122 @@
+package mcptest_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+func TestServer(t *testing.T) {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, args map[string]any) (*mcp.CallToolResult, error) {
+		text, _ := args["text"].(string)
+		return mcp.NewToolResultText(text), nil
+	})
+	srv.SetBehavior("tools/call", mcptest.Behavior{
+		Notifications: []mcp.JSONRPCNotification{{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			Method:  string(mcp.MethodNotificationProgress),
+			Params:  map[string]any{"progressToken": "t", "progress": 1},
+		}},
+	})
+	srv.SetBehavior("slow", mcptest.Behavior{Delay: time.Second})
+	srv.SetBehavior("broken", mcptest.Behavior{Error: mcp.NewError(mcp.ErrorInternalError, "boom")})
+	srv.Start()
+	defer srv.Close()
+
+	trans, err := transport.NewStreamableHTTP(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	notifications := make(chan transport.JSONRPCNotification, 1)
+	trans.SetNotificationHandler(func(n transport.JSONRPCNotification) {
+		notifications <- n
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	initResult, err := trans.Initialize(ctx, mcp.LATEST_PROTOCOL_VERSION, map[string]any{"name": "test"}, map[string]any{})
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if initResult.ServerInfo.Name != "mcptest" || initResult.Capabilities.Tools == nil {
+		t.Errorf("Unexpected initialize result: %+v", initResult)
+	}
+	if srv.SessionID() == "" || trans.GetSessionId() != srv.SessionID() {
+		t.Errorf("Expected session %q, got %q", srv.SessionID(), trans.GetSessionId())
+	}
+
+	t.Run("CallToolWithNotifications", func(t *testing.T) {
+		resp, err := trans.Request(ctx, "tools/call", map[string]any{
+			"name":      "echo",
+			"arguments": map[string]any{"text": "hello"},
+		})
+		if err != nil {
+			t.Fatalf("tools/call failed: %v", err)
+		}
+		result, err := mcp.ParseCallToolResult(&resp.Result)
+		if err != nil {
+			t.Fatalf("Failed to parse result: %v", err)
+		}
+		if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "hello" {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+
+		select {
+		case n := <-notifications:
+			if n.Method != string(mcp.MethodNotificationProgress) {
+				t.Errorf("Unexpected notification %s", n.Method)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Expected a progress notification")
+		}
+	})
+
+	t.Run("UnknownTool", func(t *testing.T) {
+		resp, err := trans.Request(ctx, "tools/call", map[string]any{"name": "missing"})
+		if err != nil {
+			t.Fatalf("tools/call failed: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Code != mcp.ErrorToolNotFound {
+			t.Errorf("Expected ToolNotFound error, got %+v", resp.Error)
+		}
+	})
+
+	t.Run("Behaviors", func(t *testing.T) {
+		resp, err := trans.Request(ctx, "broken", nil)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if resp.Error == nil || resp.Error.Message != "boom" {
+			t.Errorf("Expected configured error, got %+v", resp.Error)
+		}
+
+		shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		if _, err := trans.Request(shortCtx, "slow", nil); err == nil {
+			t.Errorf("Expected delayed request to time out")
+		}
+	})
+
+	t.Run("Requests", func(t *testing.T) {
+		requests := srv.Requests()
+		if len(requests) == 0 || requests[0].Method != "initialize" {
+			t.Fatalf("Expected initialize to be recorded first, got %+v", requests)
+		}
+		var params map[string]any
+		if err := json.Unmarshal(requests[0].Params, &params); err != nil {
+			t.Fatal(err)
+		}
+		if params["protocolVersion"] != mcp.LATEST_PROTOCOL_VERSION {
+			t.Errorf("Unexpected initialize params: %v", params)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package proxy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+const (
+	headerKeySessionID = "Mcp-Session-Id"
+	// streamBuffer is the number of server messages queued per response stream
+	streamBuffer = 64
+)
+
+// errNoStream is returned to the server when it sends a request while no
+// client request is in flight to carry it.
+var errNoStream = errors.New("no client stream to forward the request on")
+
+// NewHTTPHandler returns a Streamable HTTP endpoint forwarding the messages of
+// its client to the server behind upstream, typically a started *transport.Stdio.
+//
+// The endpoint holds a single session, created by initialize. Every request is
+// answered with an SSE stream. Since stdio does not tie notifications and
+// server requests to a client request, they are sent on the streams of the
+// requests in flight: notifications on all of them, server requests on the oldest.
+// Messages of the server while no request is in flight are dropped.
+//
+// The handler replaces the notification and request handlers of upstream.
+func NewHTTPHandler(upstream transport.Interface) http.Handler {
+	h := &httpBridge{upstream: upstream}
+	upstream.SetNotificationHandler(func(notification transport.JSONRPCNotification) {
+		h.broadcast(notification)
+	})
+	upstream.SetRequestHandler(func(ctx context.Context, request transport.JSONRPCRequest) (any, error) {
+		return h.serverRequests.forward(ctx, request, h.sendToOldest)
+	})
+	return h
+}
+
+// httpBridge is the handler returned by NewHTTPHandler.
+type httpBridge struct {
+	upstream       transport.Interface
+	serverRequests serverRequests
+
+	mu        sync.Mutex
+	sessionID string
+	// streams are the response streams of the requests in flight, oldest first
+	streams []chan any
+}
+
+func (h *httpBridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		h.mu.Lock()
+		if r.Header.Get(headerKeySessionID) == h.sessionID {
+			h.sessionID = ""
+		}
+		h.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	sessionID := h.sessionID
+	h.mu.Unlock()
+	if msg.Method != string(mcp.MethodInitialize) && r.Header.Get(headerKeySessionID) != sessionID {
+		http.Error(w, "Invalid session ID", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case msg.isResponse():
+		h.serverRequests.resolve(&msg)
+		w.WriteHeader(http.StatusAccepted)
+	case msg.isNotification():
+		notification, err := msg.notification()
+		if err == nil {
+			err = h.upstream.SendNotification(r.Context(), notification)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	case msg.isRequest():
+		h.serveRequest(w, r, &msg)
+	default:
+		http.Error(w, "Invalid JSON-RPC message", http.StatusBadRequest)
+	}
+}
+
+// serveRequest forwards a request upstream and streams the server's messages
+// until the response arrives.
+func (h *httpBridge) serveRequest(w http.ResponseWriter, r *http.Request, msg *message) {
+	ctx := r.Context()
+	stream := make(chan any, streamBuffer)
+	h.mu.Lock()
+	h.streams = append(h.streams, stream)
+	h.mu.Unlock()
+	defer h.removeStream(stream)
+
+	responseChan := make(chan transport.JSONRPCResponse, 1)
+	go func() {
+		response, err := h.upstream.SendRequest(ctx, msg.request())
+		if err != nil {
+			responseChan <- errorResponse(msg.ID, fmt.Errorf("upstream request failed: %w", err))
+			return
+		}
+		responseChan <- *response
+	}()
+
+	if msg.Method == string(mcp.MethodInitialize) {
+		sessionID := newSessionID()
+		h.mu.Lock()
+		h.sessionID = sessionID
+		h.mu.Unlock()
+		w.Header().Set(headerKeySessionID, sessionID)
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flush(w)
+
+	for {
+		select {
+		case message := <-stream:
+			writeEvent(w, message)
+		case response := <-responseChan:
+			h.removeStream(stream)
+			for len(stream) > 0 {
+				writeEvent(w, <-stream)
+			}
+			writeEvent(w, response)
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// broadcast queues a message on every stream, dropping it for full ones.
+func (h *httpBridge) broadcast(message any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, stream := range h.streams {
+		select {
+		case stream <- message:
+		default:
+		}
+	}
+}
+
+// sendToOldest queues a message on the oldest stream.
+func (h *httpBridge) sendToOldest(request transport.JSONRPCRequest) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.streams) == 0 {
+		return errNoStream
+	}
+	select {
+	case h.streams[0] <- request:
+		return nil
+	default:
+		return fmt.Errorf("client stream is full")
+	}
+}
+
+func (h *httpBridge) removeStream(stream chan any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, s := range h.streams {
+		if s == stream {
+			h.streams = append(h.streams[:i:i], h.streams[i+1:]...)
+			return
+		}
+	}
+}
+
+// writeEvent writes a single SSE message event and flushes it.
+func writeEvent(w http.ResponseWriter, message any) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+	flush(w)
+}
+
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// newSessionID returns a random session ID.
+func newSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
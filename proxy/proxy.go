@@ -0,0 +1,131 @@
+// Package proxy bridges MCP transports, so that a client speaking one
+// transport can reach a server speaking another.
+//
+// ServeStdio lets a stdio-only client, such as a desktop host, use a remote
+// Streamable HTTP server:
+//
+//	upstream, _ := transport.NewStreamableHTTP("https://example.com/mcp")
+//	err := proxy.ServeStdio(ctx, os.Stdin, os.Stdout, upstream)
+//
+// NewHTTPHandler does the reverse, exposing a stdio server over Streamable HTTP:
+//
+//	upstream := transport.NewStdio("my-server", nil)
+//	_ = upstream.Start(ctx)
+//	http.ListenAndServe(":8080", proxy.NewHTTPHandler(upstream))
+//
+// Messages are forwarded unchanged, including their IDs, so the proxy is
+// transparent to both sides. Pings are answered by the proxy itself.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// message is a JSON-RPC request, notification or response read by the proxy.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      mcp.RequestId   `json:"id,omitzero"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *mcp.Error      `json:"error,omitempty"`
+}
+
+func (m *message) isRequest() bool {
+	return m.Method != "" && !m.ID.IsNil()
+}
+
+func (m *message) isNotification() bool {
+	return m.Method != "" && m.ID.IsNil()
+}
+
+func (m *message) isResponse() bool {
+	return m.Method == "" && !m.ID.IsNil()
+}
+
+// request converts the message to a transport request, keeping its params as-is.
+func (m *message) request() transport.JSONRPCRequest {
+	request := transport.JSONRPCRequest{JSONRPC: mcp.JSONRPC_VERSION, ID: m.ID, Method: m.Method}
+	if len(m.Params) > 0 {
+		request.Params = m.Params
+	}
+	return request
+}
+
+// notification converts the message to a transport notification.
+func (m *message) notification() (transport.JSONRPCNotification, error) {
+	notification := transport.JSONRPCNotification{JSONRPC: mcp.JSONRPC_VERSION, Method: m.Method}
+	if len(m.Params) > 0 {
+		if err := json.Unmarshal(m.Params, &notification.Params.AdditionalFields); err != nil {
+			return notification, fmt.Errorf("invalid notification params: %w", err)
+		}
+	}
+	return notification, nil
+}
+
+// errorResponse builds the response sent when a request cannot be forwarded.
+func errorResponse(id mcp.RequestId, err error) transport.JSONRPCResponse {
+	return transport.JSONRPCResponse{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      id,
+		Error:   mcp.NewError(mcp.ErrorInternalError, err.Error()),
+	}
+}
+
+// serverRequests tracks the requests of the server forwarded to the client
+// until the client answers them.
+type serverRequests struct {
+	mu      sync.Mutex
+	pending map[mcp.RequestId]chan *message
+}
+
+// forward hands request to send, which passes it on to the client, and waits
+// for the client's answer, which is returned in the form a transport.RequestHandler returns.
+func (s *serverRequests) forward(ctx context.Context, request transport.JSONRPCRequest, send func(transport.JSONRPCRequest) error) (any, error) {
+	answer := make(chan *message, 1)
+	s.mu.Lock()
+	if s.pending == nil {
+		s.pending = make(map[mcp.RequestId]chan *message)
+	}
+	s.pending[request.ID] = answer
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, request.ID)
+		s.mu.Unlock()
+	}()
+
+	if err := send(request); err != nil {
+		return nil, err
+	}
+	select {
+	case response := <-answer:
+		if response.Error != nil {
+			return nil, response.Error
+		}
+		return response.Result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// resolve delivers the client's answer to the request waiting for it.
+// It reports whether one was.
+func (s *serverRequests) resolve(response *message) bool {
+	s.mu.Lock()
+	answer, ok := s.pending[response.ID]
+	s.mu.Unlock()
+	if ok {
+		select {
+		case answer <- response:
+		default:
+		}
+	}
+	return ok
+}
@@ -0,0 +1,170 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/client/transport"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcptest"
+)
+
+// newTestServer returns a server with an echo tool and a "work" method that
+// notifies and asks the client for its roots, waiting for the answer.
+func newTestServer() *mcptest.Server {
+	srv := mcptest.NewServer()
+	srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("hello"), nil
+	})
+	srv.HandleMethod("work", func(ctx context.Context, request *mcptest.Request) (any, error) {
+		if err := mcptest.SendNotification(ctx, "notifications/message", map[string]any{"data": "working"}); err != nil {
+			return nil, err
+		}
+		if err := mcptest.SendRequest(ctx, mcp.NewStringRequestId("srv-1"), "roots/list", nil); err != nil {
+			return nil, err
+		}
+		if _, ok := rootsAnswer(srv); !ok {
+			return nil, fmt.Errorf("no answer to roots/list")
+		}
+		return map[string]any{}, nil
+	})
+	srv.SetBehavior("work", mcptest.Behavior{SSE: true})
+	return srv
+}
+
+// rootsAnswer waits for the client's answer to the server's roots/list request.
+func rootsAnswer(srv *mcptest.Server) (mcptest.Request, bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, request := range srv.Requests() {
+			if request.IsResponse() && request.ID == mcp.NewStringRequestId("srv-1") {
+				return request, true
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return mcptest.Request{}, false
+}
+
+func TestServeStdio(t *testing.T) {
+	srv := newTestServer()
+	srv.Start()
+	defer srv.Close()
+
+	upstream, err := transport.NewStreamableHTTP(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer upstream.Close()
+
+	clientReader, proxyWriter := io.Pipe()
+	proxyReader, clientWriter := io.Pipe()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	served := make(chan error, 1)
+	go func() { served <- ServeStdio(ctx, proxyReader, proxyWriter, upstream) }()
+
+	stdio := transport.NewStdioPipe(clientReader, clientWriter)
+	if err := stdio.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	notifications := make(chan transport.JSONRPCNotification, 1)
+	stdio.SetNotificationHandler(func(notification transport.JSONRPCNotification) { notifications <- notification })
+	stdio.SetRequestHandler(func(ctx context.Context, request transport.JSONRPCRequest) (any, error) {
+		return mcp.ListRootsResult{Roots: []mcp.Root{{URI: "file:///project"}}}, nil
+	})
+
+	if _, err := stdio.Initialize(ctx, mcp.LATEST_PROTOCOL_VERSION, map[string]any{"name": "test"}, map[string]any{}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if upstream.GetSessionId() == "" {
+		t.Errorf("Expected the upstream session to be established")
+	}
+
+	response, err := stdio.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      mcp.NewStringRequestId("call"),
+		Method:  "tools/call",
+		Params:  map[string]any{"name": "echo"},
+	})
+	if err != nil {
+		t.Fatalf("SendRequest failed: %v", err)
+	}
+	if response.ID != mcp.NewStringRequestId("call") || !json.Valid(response.Result) {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+
+	if _, err := stdio.SendRequest(ctx, transport.JSONRPCRequest{JSONRPC: "2.0", ID: mcp.NewIntRequestId(9), Method: "work"}); err != nil {
+		t.Fatalf("SendRequest failed: %v", err)
+	}
+	select {
+	case notification := <-notifications:
+		if notification.Method != "notifications/message" {
+			t.Errorf("Unexpected notification %q", notification.Method)
+		}
+	case <-ctx.Done():
+		t.Fatalf("Expected the server notification to be forwarded")
+	}
+	if answer, _ := rootsAnswer(srv); answer.Error != nil || len(answer.Result) == 0 {
+		t.Errorf("Expected the client's roots, got %+v", answer)
+	}
+
+	clientWriter.Close()
+	if err := <-served; err != nil {
+		t.Errorf("ServeStdio failed: %v", err)
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	srv := newTestServer()
+	serverReader, upstreamWriter := io.Pipe()
+	upstreamReader, serverWriter := io.Pipe()
+	go srv.ServeStdio(context.Background(), serverReader, serverWriter)
+
+	upstream := transport.NewStdioPipe(upstreamReader, upstreamWriter)
+	if err := upstream.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer upstream.Close()
+
+	proxy := httptest.NewServer(NewHTTPHandler(upstream))
+	defer proxy.Close()
+
+	c, err := client.NewHTTPClient(&client.Options{BaseURL: proxy.URL}, client.WithRoots(false, mcp.Root{URI: "file:///project"}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+	if c.GetSessionID() == "" {
+		t.Errorf("Expected the proxy to assign a session")
+	}
+
+	ctx := context.Background()
+	result, err := c.CallTool(ctx, "echo", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "hello" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	var methods []string
+	c.Subscribe("*", func(notification mcp.Notification) { methods = append(methods, notification.Method) })
+	if _, err := c.Request(ctx, "work", nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if len(methods) != 1 || methods[0] != "notifications/message" {
+		t.Errorf("Expected the server notification on the request stream, got %v", methods)
+	}
+	var roots mcp.ListRootsResult
+	answer, _ := rootsAnswer(srv)
+	if err := json.Unmarshal(answer.Result, &roots); err != nil || len(roots.Roots) != 1 {
+		t.Errorf("Expected the client's roots, got %+v (%v)", roots, err)
+	}
+}
@@ -0,0 +1,128 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/client/transport"
+)
+
+// maxMessageSize bounds a single message read from a stdio client.
+const maxMessageSize = 16 << 20
+
+// ServeStdio serves a stdio MCP client that writes its messages to r and
+// reads from w, forwarding them to the server behind upstream, typically a
+// *transport.StreamableHTTP. Notifications and requests of the server are
+// forwarded to the client. It returns when r ends or ctx is done, after the
+// requests in flight are answered.
+//
+// upstream must be started; its notification and request handlers are replaced.
+func ServeStdio(ctx context.Context, r io.Reader, w io.Writer, upstream transport.Interface) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s := &stdioBridge{w: w, upstream: upstream}
+	upstream.SetNotificationHandler(func(notification transport.JSONRPCNotification) {
+		s.write(notification)
+	})
+	upstream.SetRequestHandler(func(ctx context.Context, request transport.JSONRPCRequest) (any, error) {
+		return s.serverRequests.forward(ctx, request, func(request transport.JSONRPCRequest) error {
+			return s.write(request)
+		})
+	})
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+		for scanner.Scan() {
+			select {
+			case lines <- append([]byte(nil), scanner.Bytes()...):
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			if err != nil {
+				return fmt.Errorf("failed to read from client: %w", err)
+			}
+			return nil
+		case line := <-lines:
+			var msg message
+			if err := json.Unmarshal(line, &msg); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to unmarshal message: %v\n", err)
+				continue
+			}
+			switch {
+			case msg.isRequest():
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					s.forwardRequest(ctx, &msg)
+				}()
+			case msg.isNotification():
+				s.forwardNotification(ctx, &msg)
+			case msg.isResponse():
+				s.serverRequests.resolve(&msg)
+			}
+		}
+	}
+}
+
+// stdioBridge is the state of ServeStdio.
+type stdioBridge struct {
+	upstream       transport.Interface
+	serverRequests serverRequests
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// forwardRequest sends a request of the client upstream and writes back the answer.
+func (s *stdioBridge) forwardRequest(ctx context.Context, msg *message) {
+	response, err := s.upstream.SendRequest(ctx, msg.request())
+	if err != nil {
+		s.write(errorResponse(msg.ID, fmt.Errorf("upstream request failed: %w", err)))
+		return
+	}
+	s.write(response)
+}
+
+// forwardNotification sends a notification of the client upstream.
+func (s *stdioBridge) forwardNotification(ctx context.Context, msg *message) {
+	notification, err := msg.notification()
+	if err == nil {
+		err = s.upstream.SendNotification(ctx, notification)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to forward %s: %v\n", msg.Method, err)
+	}
+}
+
+// write sends a message to the client as a single line.
+func (s *stdioBridge) write(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned by a TokenVerifier rejecting a token. The
+// client is then asked to authenticate again; other verification errors are
+// answered with a server error.
+var ErrInvalidToken = errors.New("invalid token")
+
+// ProtectedResourceMetadataPath is where RFC 9728 places the metadata of a
+// protected resource, relative to its origin.
+const ProtectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// TokenInfo describes the principal authenticated by a bearer token.
+type TokenInfo struct {
+	// Subject identifies the principal, as the "sub" claim of a JWT
+	Subject string
+	// ClientID identifies the OAuth client the token was issued to
+	ClientID string
+	// Scopes are the scopes granted to the token
+	Scopes []string
+	// ExpiresAt is when the token expires, zero if unknown
+	ExpiresAt time.Time
+	// Claims are the claims of a JWT, or the fields of an introspection
+	// response
+	Claims map[string]any
+}
+
+// TokenVerifier validates bearer tokens.
+type TokenVerifier interface {
+	// VerifyToken returns the principal authenticated by token, or an error
+	// wrapping ErrInvalidToken if the token is not valid.
+	VerifyToken(ctx context.Context, token string) (*TokenInfo, error)
+}
+
+// TokenVerifierFunc adapts a function to TokenVerifier, typically one
+// calling the token introspection endpoint (RFC 7662) of an authorization
+// server.
+type TokenVerifierFunc func(ctx context.Context, token string) (*TokenInfo, error)
+
+// VerifyToken implements TokenVerifier.
+func (f TokenVerifierFunc) VerifyToken(ctx context.Context, token string) (*TokenInfo, error) {
+	return f(ctx, token)
+}
+
+// StaticTokens returns a TokenVerifier accepting a fixed set of keys, such as
+// API keys, each authenticating the principal it maps to.
+func StaticTokens(tokens map[string]*TokenInfo) TokenVerifier {
+	return TokenVerifierFunc(func(ctx context.Context, token string) (*TokenInfo, error) {
+		for key, info := range tokens {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+				return info, nil
+			}
+		}
+		return nil, ErrInvalidToken
+	})
+}
+
+type tokenInfoKey struct{}
+
+// TokenInfoFromContext returns the principal authenticated by the bearer
+// token of the HTTP request handled in ctx, or nil.
+func TokenInfoFromContext(ctx context.Context) *TokenInfo {
+	info, _ := ctx.Value(tokenInfoKey{}).(*TokenInfo)
+	return info
+}
+
+// AuthOption configures RequireBearerToken.
+type AuthOption func(*bearerAuth)
+
+// WithResourceMetadataURL sets the URL of the protected resource metadata
+// document, advertised to unauthenticated clients in the WWW-Authenticate
+// header so they can discover the authorization server.
+func WithResourceMetadataURL(url string) AuthOption {
+	return func(a *bearerAuth) {
+		a.resourceMetadataURL = url
+	}
+}
+
+// WithRequiredScopes rejects the tokens missing any of scopes.
+func WithRequiredScopes(scopes ...string) AuthOption {
+	return func(a *bearerAuth) {
+		a.scopes = scopes
+	}
+}
+
+type bearerAuth struct {
+	verifier            TokenVerifier
+	resourceMetadataURL string
+	scopes              []string
+}
+
+// RequireBearerToken returns middleware authenticating requests with the
+// bearer token of their Authorization header (RFC 6750). Requests without a
+// valid token are rejected with 401 and a WWW-Authenticate challenge, and
+// those missing a required scope with 403. CORS preflight requests are let
+// through unauthenticated. The handler gets the
+// authenticated principal from TokenInfoFromContext.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/authorization
+func RequireBearerToken(verifier TokenVerifier, opts ...AuthOption) func(http.Handler) http.Handler {
+	a := &bearerAuth{verifier: verifier}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				// CORS preflight requests carry no credentials
+				next.ServeHTTP(w, r)
+				return
+			}
+			scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !strings.EqualFold(scheme, "Bearer") || token == "" {
+				a.challenge(w, http.StatusUnauthorized, "", "")
+				return
+			}
+			info, err := a.verifier.VerifyToken(r.Context(), token)
+			if errors.Is(err, ErrInvalidToken) {
+				a.challenge(w, http.StatusUnauthorized, "invalid_token", err.Error())
+				return
+			}
+			if err != nil {
+				http.Error(w, fmt.Sprintf("failed to verify token: %v", err), http.StatusInternalServerError)
+				return
+			}
+			for _, scope := range a.scopes {
+				if !slices.Contains(info.Scopes, scope) {
+					a.challenge(w, http.StatusForbidden, "insufficient_scope", "missing scope "+scope)
+					return
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tokenInfoKey{}, info)))
+		})
+	}
+}
+
+// challenge rejects a request with a WWW-Authenticate header describing the
+// expected token, and the OAuth error, if any, as JSON.
+func (a *bearerAuth) challenge(w http.ResponseWriter, status int, code, description string) {
+	var params []string
+	if code != "" {
+		params = append(params, fmt.Sprintf("error=%q", code))
+		params = append(params, fmt.Sprintf("error_description=%q", description))
+	}
+	if len(a.scopes) > 0 {
+		params = append(params, fmt.Sprintf("scope=%q", strings.Join(a.scopes, " ")))
+	}
+	if a.resourceMetadataURL != "" {
+		params = append(params, fmt.Sprintf("resource_metadata=%q", a.resourceMetadataURL))
+	}
+	challenge := "Bearer"
+	if len(params) > 0 {
+		challenge += " " + strings.Join(params, ", ")
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	if code == "" {
+		w.WriteHeader(status)
+		return
+	}
+	writeJSON(w, status, map[string]string{"error": code, "error_description": description})
+}
+
+// ProtectedResourceMetadata is the OAuth protected resource metadata (RFC
+// 9728) of an MCP server, telling clients which authorization servers issue
+// its tokens.
+type ProtectedResourceMetadata struct {
+	// Resource is the URL identifying the MCP server
+	Resource string `json:"resource"`
+	// AuthorizationServers are the issuer URLs of the authorization servers
+	AuthorizationServers []string `json:"authorization_servers"`
+	// ScopesSupported are the scopes the MCP server understands
+	ScopesSupported []string `json:"scopes_supported,omitempty"`
+	// BearerMethodsSupported defaults to the Authorization header
+	BearerMethodsSupported []string `json:"bearer_methods_supported,omitempty"`
+	// ResourceDocumentation is a URL describing the MCP server for developers
+	ResourceDocumentation string `json:"resource_documentation,omitempty"`
+}
+
+// ServeHTTP serves the metadata document, to be mounted at
+// ProtectedResourceMetadataPath without authentication.
+func (m ProtectedResourceMetadata) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if m.BearerMethodsSupported == nil {
+		m.BearerMethodsSupported = []string{"header"}
+	}
+	writeJSON(w, http.StatusOK, m)
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireBearerToken(t *testing.T) {
+	verifier := StaticTokens(map[string]*TokenInfo{
+		"reader-key": {Subject: "alice", Scopes: []string{"mcp:read"}},
+		"writer-key": {Subject: "bob", Scopes: []string{"mcp:read", "mcp:write"}},
+	})
+	protected := func(opts ...AuthOption) http.Handler {
+		return RequireBearerToken(verifier, opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(TokenInfoFromContext(r.Context()).Subject))
+		}))
+	}
+	serve := func(handler http.Handler, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	metadataURL := "https://mcp.example.com" + ProtectedResourceMetadataPath
+
+	t.Run("ValidToken", func(t *testing.T) {
+		rec := serve(protected(), "Bearer reader-key")
+		if rec.Code != http.StatusOK || rec.Body.String() != "alice" {
+			t.Errorf("Expected alice to be authenticated, got %d %q", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		rec := serve(protected(WithResourceMetadataURL(metadataURL)), "")
+		expected := `Bearer resource_metadata="` + metadataURL + `"`
+		if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != expected {
+			t.Errorf("Expected 401 with %s, got %d %q", expected, rec.Code, rec.Header().Get("WWW-Authenticate"))
+		}
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		rec := serve(protected(), "Bearer unknown-key")
+		expected := `Bearer error="invalid_token", error_description="invalid token"`
+		if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != expected {
+			t.Errorf("Expected 401 with %s, got %d %q", expected, rec.Code, rec.Header().Get("WWW-Authenticate"))
+		}
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] != "invalid_token" {
+			t.Errorf("Expected an invalid_token error, got %q", rec.Body.String())
+		}
+	})
+
+	t.Run("OtherScheme", func(t *testing.T) {
+		if rec := serve(protected(), "Basic cmVhZGVyLWtleQ=="); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("InsufficientScope", func(t *testing.T) {
+		handler := protected(WithRequiredScopes("mcp:write"))
+		rec := serve(handler, "Bearer reader-key")
+		expected := `Bearer error="insufficient_scope", error_description="missing scope mcp:write", scope="mcp:write"`
+		if rec.Code != http.StatusForbidden || rec.Header().Get("WWW-Authenticate") != expected {
+			t.Errorf("Expected 403 with %s, got %d %q", expected, rec.Code, rec.Header().Get("WWW-Authenticate"))
+		}
+		if rec := serve(handler, "bearer writer-key"); rec.Code != http.StatusOK {
+			t.Errorf("Expected 200, got %d", rec.Code)
+		}
+	})
+
+	t.Run("VerifierError", func(t *testing.T) {
+		failing := TokenVerifierFunc(func(ctx context.Context, token string) (*TokenInfo, error) {
+			return nil, errors.New("introspection endpoint unavailable")
+		})
+		rec := serve(RequireBearerToken(failing)(http.NotFoundHandler()), "Bearer key")
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Expected 500, got %d", rec.Code)
+		}
+	})
+}
+
+func TestProtectedResourceMetadata(t *testing.T) {
+	metadata := ProtectedResourceMetadata{
+		Resource:             "https://mcp.example.com",
+		AuthorizationServers: []string{"https://auth.example.com"},
+	}
+	rec := httptest.NewRecorder()
+	metadata.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ProtectedResourceMetadataPath, nil))
+	expected := `{"resource":"https://mcp.example.com","authorization_servers":["https://auth.example.com"],"bearer_methods_supported":["header"]}` + "\n"
+	if rec.Code != http.StatusOK || rec.Body.String() != expected {
+		t.Errorf("Expected %s, got %d %s", expected, rec.Code, rec.Body.String())
+	}
+}
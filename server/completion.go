@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// maxCompletionValues is the number of values a completion/complete result
+// may hold.
+const maxCompletionValues = 100
+
+// CompletionFunc returns the values completing an argument, given what was
+// typed so far in request.Params.Argument.Value. request.Params.Ref is the
+// mcp.PromptReference or mcp.ResourceReference of the completed feature, and
+// request.Params.Context, if not nil, holds the arguments already filled in.
+type CompletionFunc func(ctx context.Context, request mcp.CompleteRequest) ([]string, error)
+
+// CompletionOption attaches completions to a prompt or resource template.
+type CompletionOption func(completions map[string]CompletionFunc)
+
+// WithCompletion completes the argument of a prompt, or the variable of a
+// resource template, with the given name.
+func WithCompletion(argument string, complete CompletionFunc) CompletionOption {
+	return func(completions map[string]CompletionFunc) {
+		completions[argument] = complete
+	}
+}
+
+// CompleteValues returns a CompletionFunc offering a fixed set of values.
+func CompleteValues(values ...string) CompletionFunc {
+	return func(ctx context.Context, request mcp.CompleteRequest) ([]string, error) {
+		return values, nil
+	}
+}
+
+// newCompletions collects the completions of a registration, nil if none.
+func newCompletions(opts []CompletionOption) map[string]CompletionFunc {
+	if len(opts) == 0 {
+		return nil
+	}
+	completions := map[string]CompletionFunc{}
+	for _, opt := range opts {
+		opt(completions)
+	}
+	return completions
+}
+
+// complete answers completion/complete. The values not starting with the
+// typed value, ignoring case, are dropped, and at most maxCompletionValues
+// are returned with the total number of matches.
+func (s *Server) complete(ctx context.Context, params json.RawMessage) (*mcp.CompleteResult, error) {
+	request := mcp.CompleteRequest{Method: string(mcp.MethodCompleteList)}
+	if len(params) == 0 {
+		return nil, mcp.NewError(mcp.ErrorInvalidParams, "invalid params: ref is missing")
+	}
+	if err := decodeParams(params, &request.Params); err != nil {
+		return nil, err
+	}
+
+	var completions map[string]CompletionFunc
+	var found bool
+	s.mu.RLock()
+	switch ref := request.Params.Ref.(type) {
+	case mcp.PromptReference:
+		for _, p := range s.prompts {
+			if p.prompt.Name == ref.Name {
+				completions, found = p.completions, true
+			}
+		}
+	case mcp.ResourceReference:
+		for _, t := range s.resourceTemplates {
+			if t.template.URITemplate.Raw() == ref.URI {
+				completions, found = t.completions, true
+			}
+		}
+	}
+	s.mu.RUnlock()
+	if !found {
+		if ref, ok := request.Params.Ref.(mcp.PromptReference); ok {
+			return nil, mcp.NewError(mcp.ErrorInvalidParams, fmt.Sprintf("prompt not found: %s", ref.Name))
+		}
+		ref := request.Params.Ref.(mcp.ResourceReference)
+		return nil, mcp.NewError(mcp.ErrorResourceNotFound, fmt.Sprintf("resource template not found: %s", ref.URI))
+	}
+
+	result := &mcp.CompleteResult{}
+	result.Completion.Values = []string{}
+	complete := completions[request.Params.Argument.Name]
+	if complete == nil {
+		return result, nil
+	}
+	values, err := complete(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.ToLower(request.Params.Argument.Value)
+	for _, value := range values {
+		if !strings.HasPrefix(strings.ToLower(value), prefix) {
+			continue
+		}
+		result.Completion.Total++
+		if len(result.Completion.Values) < maxCompletionValues {
+			result.Completion.Values = append(result.Completion.Values, value)
+		}
+	}
+	result.Completion.HasMore = result.Completion.Total > len(result.Completion.Values)
+	return result, nil
+}
+
+// hasCompletions reports whether a registered feature has completions.
+// s.mu must be held.
+func (s *Server) hasCompletions() bool {
+	for _, p := range s.prompts {
+		if len(p.completions) > 0 {
+			return true
+		}
+	}
+	for _, t := range s.resourceTemplates {
+		if len(t.completions) > 0 {
+			return true
+		}
+	}
+	return false
+}
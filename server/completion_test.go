@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestComplete(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddPrompt(mcp.Prompt{
+		Name:      "review",
+		Arguments: []mcp.PromptArgument{{Name: "language"}, {Name: "code"}},
+	}, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	}, WithCompletion("language", CompleteValues("go", "Gleam", "python")),
+		WithCompletion("code", func(ctx context.Context, request mcp.CompleteRequest) ([]string, error) {
+			if request.Params.Context == nil {
+				return nil, nil
+			}
+			return []string{"func main() in " + request.Params.Context.Arguments["language"]}, nil
+		}))
+
+	var numbers []string
+	for i := range 150 {
+		numbers = append(numbers, fmt.Sprint(i))
+	}
+	template, err := mcp.NewURITemplate("items://{id}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.AddResourceTemplate(mcp.ResourceTemplate{URITemplate: template, Name: "item"}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, nil
+	}, WithCompletion("id", func(ctx context.Context, request mcp.CompleteRequest) ([]string, error) {
+		if _, ok := request.Params.Ref.(mcp.ResourceReference); !ok {
+			return nil, errors.New("expected a resource reference")
+		}
+		return numbers, nil
+	}))
+
+	c := connect(t, srv)
+	ctx := context.Background()
+
+	if c.ServerCapabilities().Completions == nil {
+		t.Error("Expected the completions capability")
+	}
+
+	t.Run("Prompt", func(t *testing.T) {
+		result, err := c.Complete(ctx, mcp.NewPromptReference("review"), "language", "g")
+		if err != nil {
+			t.Fatalf("Complete failed: %v", err)
+		}
+		if !slices.Equal(result.Completion.Values, []string{"go", "Gleam"}) || result.Completion.Total != 2 || result.Completion.HasMore {
+			t.Errorf("Expected go and Gleam, got %+v", result.Completion)
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		result, err := c.Complete(ctx, mcp.NewPromptReference("review"), "code", "", client.WithCompletionContext(map[string]string{"language": "go"}))
+		if err != nil {
+			t.Fatalf("Complete failed: %v", err)
+		}
+		if !slices.Equal(result.Completion.Values, []string{"func main() in go"}) {
+			t.Errorf("Expected a value depending on the language, got %+v", result.Completion)
+		}
+	})
+
+	t.Run("Capped", func(t *testing.T) {
+		result, err := c.Complete(ctx, mcp.NewResourceReference("items://{id}"), "id", "")
+		if err != nil {
+			t.Fatalf("Complete failed: %v", err)
+		}
+		if len(result.Completion.Values) != 100 || result.Completion.Total != 150 || !result.Completion.HasMore {
+			t.Errorf("Expected 100 of 150 values, got %d of %d", len(result.Completion.Values), result.Completion.Total)
+		}
+	})
+
+	t.Run("NoCompletion", func(t *testing.T) {
+		result, err := c.Complete(ctx, mcp.NewPromptReference("review"), "code", "x")
+		if err != nil {
+			t.Fatalf("Complete failed: %v", err)
+		}
+		if len(result.Completion.Values) != 0 {
+			t.Errorf("Expected no values, got %v", result.Completion.Values)
+		}
+	})
+
+	t.Run("UnknownPrompt", func(t *testing.T) {
+		_, err := c.Complete(ctx, mcp.NewPromptReference("unknown"), "language", "")
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorInvalidParams {
+			t.Errorf("Expected ErrorInvalidParams, got %v", err)
+		}
+	})
+
+	t.Run("UnknownResourceTemplate", func(t *testing.T) {
+		_, err := c.Complete(ctx, mcp.NewResourceReference("unknown://{id}"), "id", "")
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorResourceNotFound {
+			t.Errorf("Expected ErrorResourceNotFound, got %v", err)
+		}
+	})
+}
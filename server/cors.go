@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// WithAllowedOrigins allows browsers to send requests from the given origins,
+// such as "https://app.example.com", answering them with CORS headers. "*"
+// allows every origin. Requests carrying any other Origin header are rejected
+// with 403, guarding servers against DNS rebinding attacks.
+func WithAllowedOrigins(origins ...string) HTTPHandlerOption {
+	return func(h *httpHandler) {
+		h.origins = append(h.origins, origins...)
+	}
+}
+
+// WithLocalhostOrigins allows browsers to send requests from pages served by
+// localhost, on any port, as local tools such as inspectors are.
+func WithLocalhostOrigins() HTTPHandlerOption {
+	return func(h *httpHandler) {
+		h.localhostOrigins = true
+	}
+}
+
+// allowsOrigin reports whether requests from origin are served.
+func (h *httpHandler) allowsOrigin(origin string) bool {
+	if slices.Contains(h.origins, "*") || slices.Contains(h.origins, origin) {
+		return true
+	}
+	if !h.localhostOrigins {
+		return false
+	}
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && isLoopbackHost(u.Hostname())
+}
+
+// serveCORS adds the CORS headers allowing a browser to read the response
+// of an allowed origin, and answers preflight requests. It reports whether
+// the request was answered.
+func (h *httpHandler) serveCORS(w http.ResponseWriter, r *http.Request, origin string) bool {
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Add("Vary", "Origin")
+	w.Header().Set("Access-Control-Expose-Headers", headerKeySessionID)
+	if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Methods", h.allowedMethods())
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, "+headerKeySessionID+", "+headerKeyLastEventID+", Mcp-Protocol-Version")
+	w.Header().Set("Access-Control-Max-Age", "86400")
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// isLoopbackHost reports whether host names the local machine.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ListenLocalhost listens for TCP connections on the loopback interface only,
+// at port, or a free port if zero. Serving locally-run HTTP servers on it
+// keeps them unreachable from the network.
+func ListenLocalhost(port int) (net.Listener, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on localhost: %w", err)
+	}
+	return listener, nil
+}
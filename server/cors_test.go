@@ -0,0 +1,89 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPHandlerOrigins(t *testing.T) {
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1"},"capabilities":{}}}`
+	post := func(handler http.Handler, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(initialize))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("NoOrigin", func(t *testing.T) {
+		rec := post(NewHTTPHandler(newTestServer()), "")
+		if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("Expected 200 without CORS headers, got %d %v", rec.Code, rec.Header())
+		}
+	})
+
+	t.Run("DisallowedOrigin", func(t *testing.T) {
+		rec := post(NewHTTPHandler(newTestServer(), WithAllowedOrigins("https://app.example.com")), "http://evil.example.com")
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("AllowedOrigin", func(t *testing.T) {
+		rec := post(NewHTTPHandler(newTestServer(), WithAllowedOrigins("https://app.example.com")), "https://app.example.com")
+		if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+			t.Errorf("Expected 200 allowing the origin, got %d %v", rec.Code, rec.Header())
+		}
+		if rec.Header().Get("Access-Control-Expose-Headers") != headerKeySessionID {
+			t.Errorf("Expected the session ID to be exposed, got %q", rec.Header().Get("Access-Control-Expose-Headers"))
+		}
+	})
+
+	t.Run("LocalhostOrigins", func(t *testing.T) {
+		handler := NewHTTPHandler(newTestServer(), WithLocalhostOrigins())
+		for origin, expected := range map[string]int{
+			"http://localhost:6274":      http.StatusOK,
+			"http://127.0.0.1:3000":      http.StatusOK,
+			"http://[::1]:3000":          http.StatusOK,
+			"http://localhost.evil.com":  http.StatusForbidden,
+			"http://192.168.1.10:3000":   http.StatusForbidden,
+			"file://localhost/index.htm": http.StatusForbidden,
+		} {
+			if rec := post(handler, origin); rec.Code != expected {
+				t.Errorf("Expected %d for %s, got %d", expected, origin, rec.Code)
+			}
+		}
+	})
+
+	t.Run("Preflight", func(t *testing.T) {
+		handler := RequireBearerToken(StaticTokens(nil))(NewHTTPHandler(newTestServer(), WithAllowedOrigins("*")))
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Methods") != "GET, POST, DELETE" {
+			t.Errorf("Expected 204 allowing the methods, got %d %v", rec.Code, rec.Header())
+		}
+		if !strings.Contains(rec.Header().Get("Access-Control-Allow-Headers"), headerKeySessionID) {
+			t.Errorf("Expected the session ID header to be allowed, got %q", rec.Header().Get("Access-Control-Allow-Headers"))
+		}
+	})
+}
+
+func TestListenLocalhost(t *testing.T) {
+	listener, err := ListenLocalhost(0)
+	if err != nil {
+		t.Fatalf("ListenLocalhost failed: %v", err)
+	}
+	defer listener.Close()
+	if addr := listener.Addr().String(); !strings.HasPrefix(addr, "127.0.0.1:") {
+		t.Errorf("Expected a loopback address, got %s", addr)
+	}
+}
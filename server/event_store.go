@@ -0,0 +1,255 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrEventNotFound is returned by EventStore.Replay when the given Last-Event-ID
+// is unknown or has already fallen out of the retained window.
+var ErrEventNotFound = errors.New("event not found")
+
+// EventStore assigns SSE event IDs and retains events so that a client
+// reconnecting with a Last-Event-ID header can receive the messages it missed.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/transports#resumability-and-redelivery
+type EventStore interface {
+	// Append stores a message sent on the given stream of a session
+	// and returns the event ID assigned to it.
+	Append(ctx context.Context, sessionID, streamID string, message []byte) (eventID string, err error)
+
+	// Replay calls send, in order, for every event stored after lastEventID on the
+	// same stream, and returns the ID of that stream.
+	Replay(ctx context.Context, lastEventID string, send func(eventID string, message []byte) error) (streamID string, err error)
+
+	// DeleteSession discards all events of a session.
+	DeleteSession(ctx context.Context, sessionID string) error
+}
+
+// DefaultEventWindow is the number of events retained per stream by NewMemoryEventStore
+// when no window is given.
+const DefaultEventWindow = 1000
+
+// MemoryEventStore is an EventStore keeping the most recent events of each stream in memory.
+type MemoryEventStore struct {
+	mu      sync.Mutex
+	window  int
+	streams map[string]*eventStream
+}
+
+type eventStream struct {
+	sessionID string
+	// sequence number of the first retained event
+	first  uint64
+	events [][]byte
+}
+
+type storedEvent struct {
+	id      string
+	message []byte
+}
+
+// NewMemoryEventStore creates an in-memory EventStore retaining up to window events per stream.
+// A window of zero or less uses DefaultEventWindow.
+func NewMemoryEventStore(window int) *MemoryEventStore {
+	if window <= 0 {
+		window = DefaultEventWindow
+	}
+	return &MemoryEventStore{
+		window:  window,
+		streams: make(map[string]*eventStream),
+	}
+}
+
+// Append implements EventStore.
+func (s *MemoryEventStore) Append(ctx context.Context, sessionID, streamID string, message []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream, ok := s.streams[streamID]
+	if !ok {
+		stream = &eventStream{sessionID: sessionID}
+		s.streams[streamID] = stream
+	}
+
+	seq := stream.first + uint64(len(stream.events))
+	stream.events = append(stream.events, message)
+	if len(stream.events) > s.window {
+		dropped := len(stream.events) - s.window
+		stream.events = append(stream.events[:0:0], stream.events[dropped:]...)
+		stream.first += uint64(dropped)
+	}
+
+	return formatEventID(streamID, seq), nil
+}
+
+// Replay implements EventStore.
+func (s *MemoryEventStore) Replay(ctx context.Context, lastEventID string, send func(eventID string, message []byte) error) (string, error) {
+	streamID, seq, err := parseEventID(lastEventID)
+	if err != nil {
+		return "", err
+	}
+
+	// Copy the pending events so send is called without holding the lock
+	s.mu.Lock()
+	stream, ok := s.streams[streamID]
+	if !ok || seq+1 < stream.first || seq >= stream.first+uint64(len(stream.events)) {
+		s.mu.Unlock()
+		return "", fmt.Errorf("%w: %s", ErrEventNotFound, lastEventID)
+	}
+	var pending []storedEvent
+	for i := seq + 1 - stream.first; i < uint64(len(stream.events)); i++ {
+		pending = append(pending, storedEvent{
+			id:      formatEventID(streamID, stream.first+i),
+			message: stream.events[i],
+		})
+	}
+	s.mu.Unlock()
+
+	for _, event := range pending {
+		if err := ctx.Err(); err != nil {
+			return streamID, err
+		}
+		if err := send(event.id, event.message); err != nil {
+			return streamID, err
+		}
+	}
+	return streamID, nil
+}
+
+// DeleteSession implements EventStore.
+func (s *MemoryEventStore) DeleteSession(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, stream := range s.streams {
+		if stream.sessionID == sessionID {
+			delete(s.streams, id)
+		}
+	}
+	return nil
+}
+
+// formatEventID builds an event ID that encodes the stream it belongs to,
+// so a Last-Event-ID alone is enough to resume the right stream.
+func formatEventID(streamID string, seq uint64) string {
+	return streamID + "_" + strconv.FormatUint(seq, 10)
+}
+
+func parseEventID(eventID string) (string, uint64, error) {
+	i := strings.LastIndex(eventID, "_")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("%w: malformed event ID %q", ErrEventNotFound, eventID)
+	}
+	seq, err := strconv.ParseUint(eventID[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: malformed event ID %q", ErrEventNotFound, eventID)
+	}
+	return eventID[:i], seq, nil
+}
+
+// RingEventStore is an EventStore keeping the most recent events of all
+// streams in a single ring of fixed capacity, bounding the memory used
+// however many sessions are open.
+type RingEventStore struct {
+	mu     sync.Mutex
+	events []ringEvent
+	// start is the index of the oldest event and n the number of events
+	start, n int
+	// next is the sequence number of the next event
+	next uint64
+}
+
+type ringEvent struct {
+	sessionID string
+	streamID  string
+	message   []byte
+	deleted   bool
+}
+
+// NewRingEventStore creates an in-memory EventStore retaining the last
+// capacity events across all streams. A capacity of zero or less uses
+// DefaultEventWindow.
+func NewRingEventStore(capacity int) *RingEventStore {
+	if capacity <= 0 {
+		capacity = DefaultEventWindow
+	}
+	return &RingEventStore{events: make([]ringEvent, capacity)}
+}
+
+// Append implements EventStore.
+func (s *RingEventStore) Append(ctx context.Context, sessionID, streamID string, message []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := ringEvent{sessionID: sessionID, streamID: streamID, message: message}
+	if s.n < len(s.events) {
+		s.events[(s.start+s.n)%len(s.events)] = event
+		s.n++
+	} else {
+		s.events[s.start] = event
+		s.start = (s.start + 1) % len(s.events)
+	}
+	seq := s.next
+	s.next++
+	return formatEventID(streamID, seq), nil
+}
+
+// Replay implements EventStore.
+func (s *RingEventStore) Replay(ctx context.Context, lastEventID string, send func(eventID string, message []byte) error) (string, error) {
+	streamID, seq, err := parseEventID(lastEventID)
+	if err != nil {
+		return "", err
+	}
+
+	// Copy the pending events so send is called without holding the lock
+	s.mu.Lock()
+	first := s.next - uint64(s.n)
+	if seq < first || seq >= s.next {
+		s.mu.Unlock()
+		return "", fmt.Errorf("%w: %s", ErrEventNotFound, lastEventID)
+	}
+	last := s.events[(s.start+int(seq-first))%len(s.events)]
+	if last.deleted || last.streamID != streamID {
+		s.mu.Unlock()
+		return "", fmt.Errorf("%w: %s", ErrEventNotFound, lastEventID)
+	}
+	var pending []storedEvent
+	for i := seq + 1 - first; i < uint64(s.n); i++ {
+		event := s.events[(s.start+int(i))%len(s.events)]
+		if event.deleted || event.streamID != streamID {
+			continue
+		}
+		pending = append(pending, storedEvent{
+			id:      formatEventID(streamID, first+i),
+			message: event.message,
+		})
+	}
+	s.mu.Unlock()
+
+	for _, event := range pending {
+		if err := ctx.Err(); err != nil {
+			return streamID, err
+		}
+		if err := send(event.id, event.message); err != nil {
+			return streamID, err
+		}
+	}
+	return streamID, nil
+}
+
+// DeleteSession implements EventStore. The events of the session are
+// discarded, but keep their place in the ring until overwritten.
+func (s *RingEventStore) DeleteSession(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < s.n; i++ {
+		event := &s.events[(s.start+i)%len(s.events)]
+		if event.sessionID == sessionID {
+			*event = ringEvent{deleted: true}
+		}
+	}
+	return nil
+}
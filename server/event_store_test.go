@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMemoryEventStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryEventStore(3)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		id, err := store.Append(ctx, "session-1", "stream-a", []byte(fmt.Sprintf("message-%d", i)))
+		if err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if _, err := store.Append(ctx, "session-2", "stream-b", []byte("other")); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	t.Run("ReplayAfterLastEvent", func(t *testing.T) {
+		var got []string
+		streamID, err := store.Replay(ctx, ids[2], func(eventID string, message []byte) error {
+			got = append(got, eventID+"="+string(message))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Replay failed: %v", err)
+		}
+		if streamID != "stream-a" {
+			t.Errorf("Expected stream-a, got %s", streamID)
+		}
+		expected := []string{ids[3] + "=message-3", ids[4] + "=message-4"}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("ReplayOutsideWindow", func(t *testing.T) {
+		_, err := store.Replay(ctx, ids[0], func(string, []byte) error { return nil })
+		if !errors.Is(err, ErrEventNotFound) {
+			t.Errorf("Expected ErrEventNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ReplayMalformedID", func(t *testing.T) {
+		_, err := store.Replay(ctx, "garbage", func(string, []byte) error { return nil })
+		if !errors.Is(err, ErrEventNotFound) {
+			t.Errorf("Expected ErrEventNotFound, got %v", err)
+		}
+	})
+
+	t.Run("DeleteSession", func(t *testing.T) {
+		if err := store.DeleteSession(ctx, "session-1"); err != nil {
+			t.Fatalf("DeleteSession failed: %v", err)
+		}
+		_, err := store.Replay(ctx, ids[3], func(string, []byte) error { return nil })
+		if !errors.Is(err, ErrEventNotFound) {
+			t.Errorf("Expected ErrEventNotFound after delete, got %v", err)
+		}
+	})
+}
+
+func TestRingEventStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewRingEventStore(4)
+
+	var a, b []string
+	for i := 0; i < 3; i++ {
+		id, err := store.Append(ctx, "session-1", "stream-a", []byte(fmt.Sprintf("a-%d", i)))
+		if err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		a = append(a, id)
+		id, err = store.Append(ctx, "session-2", "stream-b", []byte(fmt.Sprintf("b-%d", i)))
+		if err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		b = append(b, id)
+	}
+
+	t.Run("ReplaySkipsOtherStreams", func(t *testing.T) {
+		var got []string
+		streamID, err := store.Replay(ctx, a[1], func(eventID string, message []byte) error {
+			got = append(got, eventID+"="+string(message))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Replay failed: %v", err)
+		}
+		if streamID != "stream-a" {
+			t.Errorf("Expected stream-a, got %s", streamID)
+		}
+		expected := []string{a[2] + "=a-2"}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("ReplayOverwritten", func(t *testing.T) {
+		// The ring holds the last 4 of 6 events, so a-0 and b-0 are gone
+		_, err := store.Replay(ctx, a[0], func(string, []byte) error { return nil })
+		if !errors.Is(err, ErrEventNotFound) {
+			t.Errorf("Expected ErrEventNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ReplayWrongStream", func(t *testing.T) {
+		_, err := store.Replay(ctx, "stream-a_"+b[1][len("stream-b_"):], func(string, []byte) error { return nil })
+		if !errors.Is(err, ErrEventNotFound) {
+			t.Errorf("Expected ErrEventNotFound, got %v", err)
+		}
+	})
+
+	t.Run("DeleteSession", func(t *testing.T) {
+		if err := store.DeleteSession(ctx, "session-2"); err != nil {
+			t.Fatalf("DeleteSession failed: %v", err)
+		}
+		_, err := store.Replay(ctx, b[1], func(string, []byte) error { return nil })
+		if !errors.Is(err, ErrEventNotFound) {
+			t.Errorf("Expected ErrEventNotFound after delete, got %v", err)
+		}
+		if _, err := store.Replay(ctx, a[1], func(string, []byte) error { return nil }); err != nil {
+			t.Errorf("Expected other sessions to be kept, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// FileSystemOption configures a FileSystem.
+type FileSystemOption func(*FileSystem)
+
+// WithInclude only exposes the files matching one of patterns. Patterns use
+// the syntax of path.Match and are matched against the slash-separated path
+// relative to the root, or against the file name if they contain no slash.
+func WithInclude(patterns ...string) FileSystemOption {
+	return func(f *FileSystem) {
+		f.include = append(f.include, patterns...)
+	}
+}
+
+// WithExclude hides the files and directories matching one of patterns, such
+// as ".git" or "*.tmp", with the syntax of WithInclude.
+func WithExclude(patterns ...string) FileSystemOption {
+	return func(f *FileSystem) {
+		f.exclude = append(f.exclude, patterns...)
+	}
+}
+
+// WithPolling scans the directory tree every interval to pick up added,
+// removed and modified files.
+func WithPolling(interval time.Duration) FileSystemOption {
+	return func(f *FileSystem) {
+		f.interval = interval
+	}
+}
+
+// WithFileEvents picks up changes when a path is received from events, such
+// as the names of the events of an fsnotify watcher adding the directories
+// of the tree:
+//
+//	events := make(chan string)
+//	go func() {
+//		for event := range watcher.Events {
+//			events <- event.Name
+//		}
+//	}()
+//
+// The tree is scanned again until events is closed.
+func WithFileEvents(events <-chan string) FileSystemOption {
+	return func(f *FileSystem) {
+		f.events = events
+	}
+}
+
+// FileSystem exposes the regular files of a directory tree as file://
+// resources. Their MIME type is detected from their extension, or else
+// from their contents. Subscribed clients are told when a file is modified,
+// and all clients when files are added or removed, once changes are picked
+// up by Rescan, WithPolling or WithFileEvents.
+type FileSystem struct {
+	server   *Server
+	root     string
+	include  []string
+	exclude  []string
+	interval time.Duration
+	events   <-chan string
+
+	mu    sync.Mutex
+	files map[string]fileState
+	stop  chan struct{}
+	once  sync.Once
+}
+
+// fileState is what a scan knows of a file, to detect changes.
+type fileState struct {
+	uri     string
+	size    int64
+	modTime time.Time
+}
+
+// AddFileSystem registers the files under root as resources, keeping them in
+// sync as configured by opts until the FileSystem is closed or the server
+// shuts down.
+func (s *Server) AddFileSystem(root string, opts ...FileSystemOption) (*FileSystem, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve root %s: %w", root, err)
+	}
+	f := &FileSystem{
+		server: s,
+		root:   abs,
+		files:  map[string]fileState{},
+		stop:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if err := f.Rescan(); err != nil {
+		return nil, err
+	}
+	if f.interval > 0 || f.events != nil {
+		go f.watch()
+	}
+	s.onShutdown(func(ctx context.Context) { f.Close() })
+	return f, nil
+}
+
+// Close stops picking up changes. The registered resources are kept.
+func (f *FileSystem) Close() error {
+	f.once.Do(func() { close(f.stop) })
+	return nil
+}
+
+// watch rescans the tree on every tick or event until closed.
+func (f *FileSystem) watch() {
+	var tick <-chan time.Time
+	if f.interval > 0 {
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	events := f.events
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-tick:
+		case _, ok := <-events:
+			if !ok {
+				// Keep polling, if enabled
+				events = nil
+				continue
+			}
+		}
+		_ = f.Rescan()
+	}
+}
+
+// Rescan walks the tree, registering the added files, unregistering the
+// removed ones, and notifying the subscribers of the modified ones.
+func (f *FileSystem) Rescan() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	found := map[string]fileState{}
+	err := filepath.WalkDir(f.root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if name == f.root {
+				return err
+			}
+			// Files may be removed during the walk
+			return nil
+		}
+		if name == f.root {
+			return nil
+		}
+		rel := filepath.ToSlash(strings.TrimPrefix(name, f.root+string(filepath.Separator)))
+		if matchAny(f.exclude, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || (len(f.include) > 0 && !matchAny(f.include, rel)) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		found[rel] = fileState{uri: fileURI(name), size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %w", f.root, err)
+	}
+
+	var removed, updated []string
+	for rel, state := range f.files {
+		if _, ok := found[rel]; !ok {
+			removed = append(removed, state.uri)
+		}
+	}
+	f.server.RemoveResource(removed...)
+	for rel, state := range found {
+		old, ok := f.files[rel]
+		if ok && old.size == state.size && old.modTime.Equal(state.modTime) {
+			continue
+		}
+		if ok {
+			updated = append(updated, state.uri)
+		}
+		if !ok || old.size != state.size {
+			f.register(rel, state)
+		}
+	}
+	f.files = found
+	for _, uri := range updated {
+		f.server.NotifyResourceUpdated(uri)
+	}
+	return nil
+}
+
+// register adds the resource of the file at rel.
+func (f *FileSystem) register(rel string, state fileState) {
+	name := filepath.Join(f.root, filepath.FromSlash(rel))
+	size := state.size
+	resource := mcp.Resource{
+		URI:      state.uri,
+		Name:     rel,
+		MimeType: detectMimeType(name),
+		Size:     &size,
+	}
+	f.server.AddResource(resource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, mcp.NewError(mcp.ErrorResourceNotFound, fmt.Sprintf("failed to read %s: %v", rel, err))
+		}
+		if isText(resource.MimeType) && utf8.Valid(data) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{Text: string(data)}}, nil
+		}
+		return []mcp.ResourceContents{mcp.BlobResourceContents{Blob: base64.StdEncoding.EncodeToString(data)}}, nil
+	})
+}
+
+// matchAny reports whether rel, or its file name for patterns without a
+// slash, matches one of patterns.
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		target := rel
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(rel)
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fileURI returns the file:// URI of an absolute path.
+func fileURI(name string) string {
+	name = filepath.ToSlash(name)
+	if !strings.HasPrefix(name, "/") {
+		// Windows drive letters
+		name = "/" + name
+	}
+	return (&url.URL{Scheme: "file", Path: name}).String()
+}
+
+// detectMimeType returns the MIME type of a file from its extension, or
+// else from its first 512 bytes.
+func detectMimeType(name string) string {
+	if mimeType := mime.TypeByExtension(filepath.Ext(name)); mimeType != "" {
+		return mimeType
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, _ := file.Read(head)
+	return http.DetectContentType(head[:n])
+}
+
+// isText reports whether contents of mimeType are sent as text.
+func isText(mimeType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(mimeType)
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" || mediaType == "application/xml" ||
+		mediaType == "application/javascript" || mediaType == "application/yaml" ||
+		strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
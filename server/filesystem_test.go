@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestFileSystem(t *testing.T) {
+	root := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		name = filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("readme.md", "# Hello")
+	writeFile("docs/guide.txt", "Guide")
+	writeFile("data.bin", "\x00\x01\x02")
+	writeFile(".git/config", "[core]")
+	writeFile("notes.tmp", "scratch")
+
+	srv := NewServer("test-server", "1.0.0")
+	fsys, err := srv.AddFileSystem(root, WithExclude(".git", "*.tmp"))
+	if err != nil {
+		t.Fatalf("AddFileSystem failed: %v", err)
+	}
+	defer fsys.Close()
+	ts := httptest.NewServer(NewHTTPHandler(srv))
+	t.Cleanup(ts.Close)
+	c, err := client.NewHTTPClient(&client.Options{BaseURL: ts.URL, Listen: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	uri := func(rel string) string {
+		return fileURI(filepath.Join(root, rel))
+	}
+	listed := func() map[string]mcp.Resource {
+		t.Helper()
+		result, err := srv.listResources(nil)
+		if err != nil {
+			t.Fatalf("listResources failed: %v", err)
+		}
+		resources := map[string]mcp.Resource{}
+		for _, r := range result.Resources {
+			resources[r.Name] = r
+		}
+		return resources
+	}
+
+	t.Run("List", func(t *testing.T) {
+		resources := listed()
+		if len(resources) != 3 {
+			t.Fatalf("Expected 3 resources, got %+v", resources)
+		}
+		guide := resources["docs/guide.txt"]
+		if guide.URI != uri("docs/guide.txt") || !strings.HasPrefix(guide.MimeType, "text/plain") || guide.Size == nil || *guide.Size != 5 {
+			t.Errorf("Expected the guide resource, got %+v", guide)
+		}
+		if !strings.HasPrefix(guide.URI, "file:///") {
+			t.Errorf("Expected a file URI, got %s", guide.URI)
+		}
+	})
+
+	t.Run("ReadText", func(t *testing.T) {
+		result, err := c.ReadResource(ctx, uri("readme.md"))
+		if err != nil {
+			t.Fatalf("ReadResource failed: %v", err)
+		}
+		text, ok := result.Contents[0].(mcp.TextResourceContents)
+		if !ok || text.Text != "# Hello" {
+			t.Errorf("Expected the text of the file, got %+v", result.Contents)
+		}
+	})
+
+	t.Run("ReadBlob", func(t *testing.T) {
+		result, err := c.ReadResource(ctx, uri("data.bin"))
+		if err != nil {
+			t.Fatalf("ReadResource failed: %v", err)
+		}
+		blob, ok := result.Contents[0].(mcp.BlobResourceContents)
+		if !ok || blob.Blob != "AAEC" {
+			t.Errorf("Expected the blob of the file, got %+v", result.Contents)
+		}
+	})
+
+	t.Run("Rescan", func(t *testing.T) {
+		if err := c.SubscribeResource(ctx, uri("readme.md")); err != nil {
+			t.Fatalf("SubscribeResource failed: %v", err)
+		}
+		updates := c.Notifications(ctx, string(mcp.MethodNotificationResourceUpdated))
+		// Notify until the GET stream of the client is open
+		for received := false; !received; {
+			srv.NotifyResourceUpdated(uri("readme.md"))
+			select {
+			case <-updates:
+				received = true
+			case <-time.After(20 * time.Millisecond):
+			case <-ctx.Done():
+				t.Fatal("Expected a resources/updated notification")
+			}
+		}
+		for len(updates) > 0 {
+			<-updates
+		}
+
+		writeFile("readme.md", "# Hello, world")
+		writeFile("docs/new.txt", "New")
+		if err := os.Remove(filepath.Join(root, "data.bin")); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsys.Rescan(); err != nil {
+			t.Fatalf("Rescan failed: %v", err)
+		}
+
+		select {
+		case update := <-updates:
+			if params, _ := update.Params.(map[string]any); params["uri"] != uri("readme.md") {
+				t.Errorf("Expected an update of the readme, got %+v", update.Params)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected a resource updated notification")
+		}
+		resources := listed()
+		if _, ok := resources["data.bin"]; ok {
+			t.Error("Expected the removed file to be unregistered")
+		}
+		if _, ok := resources["docs/new.txt"]; !ok {
+			t.Error("Expected the added file to be registered")
+		}
+		if size := resources["readme.md"].Size; size == nil || *size != 14 {
+			t.Errorf("Expected the new size of the readme, got %+v", resources["readme.md"])
+		}
+	})
+
+	t.Run("Include", func(t *testing.T) {
+		srv := NewServer("test-server", "1.0.0")
+		if _, err := srv.AddFileSystem(root, WithInclude("docs/*.txt")); err != nil {
+			t.Fatalf("AddFileSystem failed: %v", err)
+		}
+		result, err := srv.listResources(nil)
+		if err != nil {
+			t.Fatalf("listResources failed: %v", err)
+		}
+		if len(result.Resources) != 2 {
+			t.Errorf("Expected the 2 text files of docs, got %+v", result.Resources)
+		}
+	})
+}
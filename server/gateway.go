@@ -0,0 +1,427 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// Gateway federates the tools, resources and prompts of the servers of a
+// client.Manager into a Server, so that clients reach them all through a
+// single endpoint. Tools and prompts are named "server.name", as by
+// Manager.ListTools. Resources and resource templates keep their URI, which
+// identifies them already; when two servers list the same URI, the first one
+// registered keeps it.
+//
+// Only the features a server declares in its capabilities are listed, and
+// the Server declares those registered from any of them. Upstream
+// list_changed notifications sync the features of their server again, and
+// resources/updated notifications are passed on to the subscribed clients.
+// The Gateway subscribes to a resource of a server supporting it while a
+// session connected to the Server is subscribed to it. The clients of the
+// Manager must listen for notifications, see client.Options.Listen, for
+// changes to be picked up.
+type Gateway struct {
+	server  *Server
+	manager *client.Manager
+	// ctx is canceled by Close, ending the syncs in flight
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu sync.Mutex
+	// upstreams are the features registered for each server of the manager
+	upstreams map[string]*gatewayUpstream
+	// owners are the servers of the registered resource URIs and templates
+	owners map[string]string
+	// templates are the registered resource templates, by URI template
+	templates   map[string]*mcp.URITemplate
+	unsubscribe []func()
+	once        sync.Once
+
+	subscribedMu sync.Mutex
+	// subscribed are the clients subscribed upstream, by resource URI
+	subscribed map[string]*client.HTTPClient
+}
+
+// gatewayUpstream is what a Gateway registered for one upstream server.
+type gatewayUpstream struct {
+	tools     []string
+	resources []string
+	templates []string
+	prompts   []string
+}
+
+// AddGateway registers the features of the servers of manager, keeping them
+// in sync until the Gateway is closed or the server shuts down. Servers
+// failing to list their features are reported in the returned error,
+// alongside the Gateway serving the others. The Gateway does not take
+// ownership of manager.
+func (s *Server) AddGateway(ctx context.Context, manager *client.Manager) (*Gateway, error) {
+	g := &Gateway{
+		server:     s,
+		manager:    manager,
+		upstreams:  map[string]*gatewayUpstream{},
+		owners:     map[string]string{},
+		templates:  map[string]*mcp.URITemplate{},
+		subscribed: map[string]*client.HTTPClient{},
+	}
+	g.ctx, g.cancel = context.WithCancel(context.Background())
+	g.unsubscribe = []func(){
+		manager.Subscribe(string(mcp.MethodNotificationToolsListChanged), g.listChanged),
+		manager.Subscribe(string(mcp.MethodNotificationResourcesListChanged), g.listChanged),
+		manager.Subscribe(string(mcp.MethodNotificationPromptsListChanged), g.listChanged),
+		manager.Subscribe(string(mcp.MethodNotificationResourceUpdated), g.resourceUpdated),
+	}
+	s.onSubscriptionChange(g.subscriptionChanged)
+	s.onShutdown(func(ctx context.Context) { g.Close() })
+	return g, g.Sync(ctx)
+}
+
+// Close stops picking up changes, waiting for the syncs in flight to end.
+// The registered features and upstream subscriptions are kept.
+func (g *Gateway) Close() error {
+	g.once.Do(func() {
+		for _, unsubscribe := range g.unsubscribe {
+			unsubscribe()
+		}
+		g.mu.Lock()
+		g.cancel()
+		g.mu.Unlock()
+		g.wg.Wait()
+	})
+	return nil
+}
+
+// spawn runs f on its own goroutine with a context canceled by Close, unless
+// the Gateway is closed.
+func (g *Gateway) spawn(f func(ctx context.Context)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.ctx.Err() != nil {
+		return
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		f(g.ctx)
+	}()
+}
+
+// Sync lists the features of every server of the manager again, registering
+// the added ones and unregistering the removed ones, as well as those of the
+// servers removed from the manager.
+func (g *Gateway) Sync(ctx context.Context) error {
+	servers := g.manager.Servers()
+	g.mu.Lock()
+	for name := range g.upstreams {
+		if !slices.Contains(servers, name) {
+			g.unregisterRemoved(name, &gatewayUpstream{})
+			delete(g.upstreams, name)
+		}
+	}
+	g.mu.Unlock()
+
+	var errs []error
+	for _, name := range servers {
+		if err := g.syncServer(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("server %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// syncServer lists the features of the server with name and registers them.
+func (g *Gateway) syncServer(ctx context.Context, name string) error {
+	c, ok := g.manager.Client(name)
+	if !ok {
+		return fmt.Errorf("unknown server %q", name)
+	}
+	capabilities := c.ServerCapabilities()
+	var (
+		tools     []mcp.Tool
+		resources []mcp.Resource
+		templates []mcp.ResourceTemplate
+		prompts   []mcp.Prompt
+		err       error
+	)
+	if capabilities.Tools != nil {
+		tools, err = listAll(ctx, c.ListTools, func(r *mcp.ListToolsResult) ([]mcp.Tool, mcp.Cursor) {
+			return r.Tools, r.NextCursor
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if capabilities.Resources != nil {
+		resources, err = listAll(ctx, c.ListResources, func(r *mcp.ListResourcesResult) ([]mcp.Resource, mcp.Cursor) {
+			return r.Resources, r.NextCursor
+		})
+		if err != nil {
+			return err
+		}
+		templates, err = listAll(ctx, c.ListResourceTemplates, func(r *mcp.ListResourceTemplatesResult) ([]mcp.ResourceTemplate, mcp.Cursor) {
+			return r.ResourceTemplates, r.NextCursor
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if capabilities.Prompts != nil {
+		prompts, err = listAll(ctx, c.ListPrompts, func(r *mcp.ListPromptsResult) ([]mcp.Prompt, mcp.Cursor) {
+			return r.Prompts, r.NextCursor
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	g.mu.Lock()
+	g.update(name, c, tools, resources, templates, prompts)
+	g.mu.Unlock()
+	// Resources may have changed server
+	for _, uri := range g.subscribedURIs() {
+		g.resubscribe(ctx, uri)
+	}
+	return nil
+}
+
+// update registers the listed features of the server with name and
+// unregisters those it no longer lists. g.mu must be held.
+func (g *Gateway) update(name string, c *client.HTTPClient, tools []mcp.Tool, resources []mcp.Resource, templates []mcp.ResourceTemplate, prompts []mcp.Prompt) {
+	if current, ok := g.manager.Client(name); !ok || current != c {
+		// The server was removed or replaced while listing its features
+		return
+	}
+	upstream := &gatewayUpstream{}
+	for _, tool := range tools {
+		upstream.tools = append(upstream.tools, g.addTool(name, c, tool))
+	}
+	for _, resource := range resources {
+		if !g.claim(name, resource.URI) {
+			continue
+		}
+		g.addResource(c, resource)
+		upstream.resources = append(upstream.resources, resource.URI)
+	}
+	for _, template := range templates {
+		if template.URITemplate == nil || !template.URITemplate.Valid() {
+			continue
+		}
+		if raw := template.URITemplate.Raw(); g.claim(name, raw) {
+			g.addResourceTemplate(c, template)
+			g.templates[raw] = template.URITemplate
+			upstream.templates = append(upstream.templates, raw)
+		}
+	}
+	for _, prompt := range prompts {
+		upstream.prompts = append(upstream.prompts, g.addPrompt(name, c, prompt))
+	}
+	g.unregisterRemoved(name, upstream)
+	g.upstreams[name] = upstream
+}
+
+// unregisterRemoved unregisters the features of the server with name missing
+// from upstream. g.mu must be held.
+func (g *Gateway) unregisterRemoved(name string, upstream *gatewayUpstream) {
+	old := g.upstreams[name]
+	if old == nil {
+		return
+	}
+	removed := func(old, current []string) []string {
+		return slices.DeleteFunc(slices.Clone(old), func(key string) bool {
+			return slices.Contains(current, key)
+		})
+	}
+	g.server.RemoveTool(removed(old.tools, upstream.tools)...)
+	g.server.RemovePrompt(removed(old.prompts, upstream.prompts)...)
+	resources := removed(old.resources, upstream.resources)
+	templates := removed(old.templates, upstream.templates)
+	g.server.RemoveResource(resources...)
+	g.server.RemoveResourceTemplate(templates...)
+	for _, key := range append(resources, templates...) {
+		delete(g.owners, key)
+		delete(g.templates, key)
+	}
+}
+
+// claim reports whether the resource URI or URI template key belongs to the
+// server with name, recording it if no other server has it. g.mu must be held.
+func (g *Gateway) claim(name, key string) bool {
+	owner, ok := g.owners[key]
+	if ok && owner != name {
+		return false
+	}
+	g.owners[key] = name
+	return true
+}
+
+// addTool registers tool of the server with name, returning its namespaced name.
+func (g *Gateway) addTool(name string, c *client.HTTPClient, tool mcp.Tool) string {
+	upstreamName := tool.Name
+	tool.Name = name + client.ToolNameSeparator + tool.Name
+	g.server.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return c.CallTool(ctx, upstreamName, request.Params.Arguments)
+	})
+	return tool.Name
+}
+
+// addResource registers a resource read from c.
+func (g *Gateway) addResource(c *client.HTTPClient, resource mcp.Resource) {
+	g.server.AddResource(resource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return readUpstream(ctx, c, request.Params.URI)
+	})
+}
+
+// addResourceTemplate registers a resource template read from c.
+func (g *Gateway) addResourceTemplate(c *client.HTTPClient, template mcp.ResourceTemplate) {
+	g.server.AddResourceTemplate(template, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return readUpstream(ctx, c, request.Params.URI)
+	})
+}
+
+// addPrompt registers prompt of the server with name, returning its namespaced name.
+func (g *Gateway) addPrompt(name string, c *client.HTTPClient, prompt mcp.Prompt) string {
+	upstreamName := prompt.Name
+	prompt.Name = name + client.ToolNameSeparator + prompt.Name
+	g.server.AddPrompt(prompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		arguments := make(map[string]string, len(request.Params.Arguments))
+		for key, value := range request.Params.Arguments {
+			if s, ok := value.(string); ok {
+				arguments[key] = s
+			} else {
+				arguments[key] = fmt.Sprint(value)
+			}
+		}
+		return c.GetPrompt(ctx, upstreamName, arguments)
+	})
+	return prompt.Name
+}
+
+// listChanged syncs the features of server again. Requests cannot be sent
+// from a notification handler, which would block the client reading the
+// responses, so the sync runs on its own goroutine.
+func (g *Gateway) listChanged(server string, notification mcp.Notification) {
+	g.spawn(func(ctx context.Context) { _ = g.syncServer(ctx, server) })
+}
+
+// subscriptionChanged subscribes upstream to the resource with uri once a
+// session subscribes to it, and unsubscribes once none is. Subscribing may
+// take a request, so it runs on its own goroutine.
+func (g *Gateway) subscriptionChanged(uri string) {
+	g.spawn(func(ctx context.Context) { g.resubscribe(ctx, uri) })
+}
+
+// subscribedURIs returns the URIs the Gateway is or should be subscribed to
+// upstream.
+func (g *Gateway) subscribedURIs() []string {
+	uris := g.server.subscribedURIs()
+	g.subscribedMu.Lock()
+	defer g.subscribedMu.Unlock()
+	for uri := range g.subscribed {
+		if !slices.Contains(uris, uri) {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// resubscribe brings the upstream subscription to the resource with uri in
+// line with the sessions subscribed to it and the server serving it. Being
+// serialized and looking at the current state, calls may come in any order.
+func (g *Gateway) resubscribe(ctx context.Context, uri string) {
+	g.subscribedMu.Lock()
+	defer g.subscribedMu.Unlock()
+	var want *client.HTTPClient
+	if g.server.hasSubscribers(uri) {
+		if c, ok := g.manager.Client(g.owner(uri)); ok {
+			if resources := c.ServerCapabilities().Resources; resources != nil && resources.Subscribe {
+				want = c
+			}
+		}
+	}
+	have := g.subscribed[uri]
+	if have == want {
+		return
+	}
+	if have != nil {
+		_ = have.UnsubscribeResource(ctx, uri)
+		delete(g.subscribed, uri)
+	}
+	if want != nil && want.SubscribeResource(ctx, uri) == nil {
+		g.subscribed[uri] = want
+	}
+}
+
+// owner returns the server serving the resource with uri, directly or
+// through a resource template, or "" if none is.
+func (g *Gateway) owner(uri string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if owner, ok := g.owners[uri]; ok {
+		return owner
+	}
+	for raw, template := range g.templates {
+		if template.Match(uri) != nil {
+			return g.owners[raw]
+		}
+	}
+	return ""
+}
+
+// resourceUpdated passes a resources/updated notification of server on to
+// the clients subscribed to the resource, which may also be one read
+// through a resource template.
+func (g *Gateway) resourceUpdated(server string, notification mcp.Notification) {
+	typed, err := client.DecodeNotification(notification)
+	if err != nil {
+		return
+	}
+	updated, ok := typed.(*mcp.ResourceUpdatedNotification)
+	if !ok {
+		return
+	}
+	g.mu.Lock()
+	owner, ok := g.owners[updated.Params.URI]
+	g.mu.Unlock()
+	if ok && owner != server {
+		// The URI is served by another server
+		return
+	}
+	g.server.NotifyResourceUpdated(updated.Params.URI)
+}
+
+// readUpstream reads the resource at uri from c.
+func readUpstream(ctx context.Context, c *client.HTTPClient, uri string) ([]mcp.ResourceContents, error) {
+	result, err := c.ReadResource(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// listAll lists every page of a paginated list, extracting the items and
+// the next cursor of each page with page.
+func listAll[R, T any](ctx context.Context, list func(context.Context, mcp.Cursor) (*R, error), page func(*R) ([]T, mcp.Cursor)) ([]T, error) {
+	var (
+		items  []T
+		cursor mcp.Cursor
+	)
+	for {
+		result, err := list(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		pageItems, next := page(result)
+		items = append(items, pageItems...)
+		if next == "" {
+			return items, nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestGateway(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	text := func(s string) ResourceHandlerFunc {
+		return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{Text: s}}, nil
+		}
+	}
+	upstreams := map[string]*Server{}
+	manager := client.NewManager()
+	defer manager.Close()
+	for _, name := range []string{"alpha", "beta"} {
+		srv := NewServer(name, "1.0.0", WithListChangedDelay(0))
+		srv.AddTool(mcp.Tool{Name: "whoami"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(name), nil
+		})
+		srv.AddResource(mcp.Resource{URI: "file:///shared", Name: "shared"}, text(name))
+		upstreams[name] = srv
+	}
+	upstreams["alpha"].AddPrompt(mcp.Prompt{Name: "greet", Arguments: []mcp.PromptArgument{{Name: "name", Required: true}}}, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{Messages: []mcp.PromptMessage{
+			{Role: mcp.RoleUser, Content: mcp.NewTextContent("Hello, " + request.Params.Arguments["name"].(string))},
+		}}, nil
+	})
+	upstreams["beta"].AddResource(mcp.Resource{URI: "file:///beta", Name: "beta"}, text("beta only"))
+	template, err := mcp.NewURITemplate("notes://{id}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstreams["beta"].AddResourceTemplate(mcp.ResourceTemplate{URITemplate: template, Name: "note"}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{Text: "note " + request.Params.Arguments["id"].(string)}}, nil
+	})
+	for _, name := range []string{"alpha", "beta"} {
+		ts := httptest.NewServer(NewHTTPHandler(upstreams[name]))
+		t.Cleanup(ts.Close)
+		c, err := client.NewHTTPClient(&client.Options{BaseURL: ts.URL, Listen: true})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if err := manager.Add(name, c); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	gateway := NewServer("gateway", "1.0.0", WithListChangedDelay(0))
+	g, err := gateway.AddGateway(ctx, manager)
+	if err != nil {
+		t.Fatalf("AddGateway failed: %v", err)
+	}
+	defer g.Close()
+	ts := httptest.NewServer(NewHTTPHandler(gateway))
+	t.Cleanup(ts.Close)
+	c, err := client.NewHTTPClient(&client.Options{BaseURL: ts.URL, Listen: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	toolNames := func() []string {
+		t.Helper()
+		result, err := c.ListTools(ctx, "")
+		if err != nil {
+			t.Fatalf("ListTools failed: %v", err)
+		}
+		var names []string
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+	readText := func(uri string) string {
+		t.Helper()
+		result, err := c.ReadResource(ctx, uri)
+		if err != nil {
+			t.Fatalf("ReadResource failed: %v", err)
+		}
+		text, _ := result.Contents[0].(mcp.TextResourceContents)
+		return text.Text
+	}
+
+	t.Run("Capabilities", func(t *testing.T) {
+		capabilities := c.ServerCapabilities()
+		if capabilities.Tools == nil || capabilities.Resources == nil || capabilities.Prompts == nil {
+			t.Errorf("Expected the merged capabilities of the upstream servers, got %+v", capabilities)
+		}
+	})
+
+	t.Run("Tools", func(t *testing.T) {
+		if names := toolNames(); !slices.Equal(names, []string{"alpha.whoami", "beta.whoami"}) {
+			t.Errorf("Expected namespaced tools, got %v", names)
+		}
+		result, err := c.CallTool(ctx, "beta.whoami", nil)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if text, ok := result.Content[0].(mcp.TextContent); !ok || text.Text != "beta" {
+			t.Errorf("Expected the call to be routed to beta, got %+v", result.Content)
+		}
+	})
+
+	t.Run("Resources", func(t *testing.T) {
+		if got := readText("file:///shared"); got != "alpha" {
+			t.Errorf("Expected the first server to keep a shared URI, got %q", got)
+		}
+		if got := readText("file:///beta"); got != "beta only" {
+			t.Errorf("Expected the resource of beta, got %q", got)
+		}
+		if got := readText("notes://42"); got != "note 42" {
+			t.Errorf("Expected the resource template of beta, got %q", got)
+		}
+	})
+
+	t.Run("Prompts", func(t *testing.T) {
+		result, err := c.GetPrompt(ctx, "alpha.greet", map[string]string{"name": "Ada"})
+		if err != nil {
+			t.Fatalf("GetPrompt failed: %v", err)
+		}
+		if text, ok := result.Messages[0].Content.(mcp.TextContent); !ok || text.Text != "Hello, Ada" {
+			t.Errorf("Expected the prompt of alpha, got %+v", result.Messages)
+		}
+	})
+
+	t.Run("ListChanged", func(t *testing.T) {
+		handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, nil
+		}
+		// Change the tools until the GET stream of the upstream client is open
+		for !slices.Contains(toolNames(), "beta.added") {
+			upstreams["beta"].AddTool(mcp.Tool{Name: "added"}, handler)
+			select {
+			case <-time.After(20 * time.Millisecond):
+			case <-ctx.Done():
+				t.Fatal("Expected the added tool to be synced")
+			}
+		}
+		upstreams["beta"].RemoveTool("added")
+		for slices.Contains(toolNames(), "beta.added") {
+			select {
+			case <-time.After(20 * time.Millisecond):
+			case <-ctx.Done():
+				t.Fatal("Expected the removed tool to be synced")
+			}
+		}
+	})
+
+	// waitSubscribed waits for the gateway to be subscribed to uri of server,
+	// or not
+	waitSubscribed := func(server, uri string, subscribed bool) {
+		t.Helper()
+		for upstreams[server].hasSubscribers(uri) != subscribed {
+			select {
+			case <-time.After(20 * time.Millisecond):
+			case <-ctx.Done():
+				t.Fatalf("Expected the upstream subscription to %s to be %v", uri, subscribed)
+			}
+		}
+	}
+
+	t.Run("Subscriptions", func(t *testing.T) {
+		if upstreams["beta"].hasSubscribers("file:///beta") {
+			t.Fatal("Expected no upstream subscription before a session subscribes")
+		}
+		other := connect(t, gateway)
+		for _, subscriber := range []*client.HTTPClient{c, other} {
+			if err := subscriber.SubscribeResource(ctx, "file:///beta"); err != nil {
+				t.Fatalf("SubscribeResource failed: %v", err)
+			}
+		}
+		if err := c.SubscribeResource(ctx, "notes://7"); err != nil {
+			t.Fatalf("SubscribeResource failed: %v", err)
+		}
+		waitSubscribed("beta", "file:///beta", true)
+		waitSubscribed("beta", "notes://7", true)
+
+		if err := c.UnsubscribeResource(ctx, "notes://7"); err != nil {
+			t.Fatalf("UnsubscribeResource failed: %v", err)
+		}
+		waitSubscribed("beta", "notes://7", false)
+		if err := other.UnsubscribeResource(ctx, "file:///beta"); err != nil {
+			t.Fatalf("UnsubscribeResource failed: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+		if !upstreams["beta"].hasSubscribers("file:///beta") {
+			t.Error("Expected the upstream subscription to be kept while a session is subscribed")
+		}
+	})
+
+	t.Run("ResourceUpdated", func(t *testing.T) {
+		updates := c.Notifications(ctx, string(mcp.MethodNotificationResourceUpdated))
+		// Notify until the GET streams of both clients are open
+		var update mcp.JSONRPCNotification
+		for received := false; !received; {
+			upstreams["beta"].NotifyResourceUpdated("file:///beta")
+			select {
+			case update = <-updates:
+				received = true
+			case <-time.After(20 * time.Millisecond):
+			case <-ctx.Done():
+				t.Fatal("Expected a resources/updated notification")
+			}
+		}
+		if params, _ := update.Params.(map[string]any); params["uri"] != "file:///beta" {
+			t.Errorf("Expected the updated URI, got %+v", update.Params)
+		}
+	})
+
+	t.Run("RemovedServer", func(t *testing.T) {
+		if err := manager.Remove("beta"); err != nil {
+			t.Fatalf("Remove failed: %v", err)
+		}
+		if err := g.Sync(ctx); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+		// The client lists the tools again once told of the change
+		for names := toolNames(); !slices.Equal(names, []string{"alpha.whoami"}); names = toolNames() {
+			select {
+			case <-time.After(20 * time.Millisecond):
+			case <-ctx.Done():
+				t.Fatalf("Expected the tools of beta to be unregistered, got %v", names)
+			}
+		}
+		if got := readText("file:///shared"); got != "alpha" {
+			t.Errorf("Expected the resources of alpha to be kept, got %q", got)
+		}
+		if _, err := c.ReadResource(ctx, "file:///beta"); err == nil {
+			t.Error("Expected the resources of beta to be unregistered")
+		}
+	})
+
+	t.Run("LastSubscriberLeaves", func(t *testing.T) {
+		if err := c.SubscribeResource(ctx, "file:///shared"); err != nil {
+			t.Fatalf("SubscribeResource failed: %v", err)
+		}
+		waitSubscribed("alpha", "file:///shared", true)
+		if err := c.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		waitSubscribed("alpha", "file:///shared", false)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		g.Close()
+		ran := false
+		g.spawn(func(ctx context.Context) { ran = true })
+		g.wg.Wait()
+		if ran {
+			t.Error("Expected no sync to start once the gateway is closed")
+		}
+	})
+}
@@ -0,0 +1,604 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+const (
+	headerKeySessionID   = "Mcp-Session-Id"
+	headerKeyLastEventID = "Last-Event-ID"
+)
+
+// maxHTTPMessageSize bounds the body of a POST request.
+const maxHTTPMessageSize = 16 * 1024 * 1024
+
+// sessionSweepInterval is how often a handler looks for the sessions that
+// expired from its session store.
+const sessionSweepInterval = time.Minute
+
+// errStreamAttached is returned when a client opens a stream that another
+// connection is already receiving.
+var errStreamAttached = errors.New("stream already open on another connection")
+
+// HTTPHandlerOption configures the handler returned by NewHTTPHandler.
+type HTTPHandlerOption func(*httpHandler)
+
+// WithSessionStore persists sessions in store, so that replicas sharing it
+// can serve each other's sessions. Sessions are kept in memory by default.
+func WithSessionStore(store SessionStore) HTTPHandlerOption {
+	return func(h *httpHandler) {
+		h.store = store
+	}
+}
+
+// WithEventStore makes SSE streams resumable: their events are given IDs and
+// stored in events, and a client reconnecting with a GET request carrying the
+// Last-Event-ID header receives the events it missed, then the rest of the
+// stream. Requests keep running when the client disconnects, so their results
+// can be resumed.
+func WithEventStore(events EventStore) HTTPHandlerOption {
+	return func(h *httpHandler) {
+		h.events = events
+	}
+}
+
+// WithStateless serves every request independently: no session ID is issued
+// and requests need none, so any replica can answer any request without
+// sticky sessions, as in serverless deployments. Requests cannot be cancelled
+// and the client's capabilities are unknown outside of initialize.
+func WithStateless() HTTPHandlerOption {
+	return func(h *httpHandler) {
+		h.stateless = true
+	}
+}
+
+// httpHandler serves a Server over the Streamable HTTP transport.
+type httpHandler struct {
+	server    *Server
+	store     SessionStore
+	events    EventStore
+	stateless bool
+	// origins are the origins allowed to send requests from browsers
+	origins          []string
+	localhostOrigins bool
+
+	mu sync.Mutex
+	// sessions holds the live state of the sessions served by this replica
+	sessions map[string]*httpSession
+}
+
+// httpSession is a session served over HTTP, with its SSE streams.
+type httpSession struct {
+	*session
+	// standalone is the stream opened by GET requests, carrying the messages
+	// unrelated to a request
+	standalone *sseStream
+
+	streamsMu sync.Mutex
+	// streams are the POST streams in progress, by ID, for resumption
+	streams map[string]*sseStream
+}
+
+func newHTTPSession(id string, events EventStore) *httpSession {
+	hs := &httpSession{
+		session:    newSession(id),
+		standalone: newSSEStream(id, events),
+		streams:    map[string]*sseStream{},
+	}
+	hs.out = hs.standalone
+	return hs
+}
+
+func (hs *httpSession) addStream(stream *sseStream) {
+	hs.streamsMu.Lock()
+	defer hs.streamsMu.Unlock()
+	hs.streams[stream.id] = stream
+}
+
+func (hs *httpSession) removeStream(stream *sseStream) {
+	hs.streamsMu.Lock()
+	defer hs.streamsMu.Unlock()
+	delete(hs.streams, stream.id)
+}
+
+// stream returns the stream of the session with id, or nil once it has ended.
+func (hs *httpSession) stream(id string) *sseStream {
+	if id == hs.standalone.id {
+		return hs.standalone
+	}
+	hs.streamsMu.Lock()
+	defer hs.streamsMu.Unlock()
+	return hs.streams[id]
+}
+
+// close ends the streams and requests of the session.
+func (hs *httpSession) close() {
+	hs.standalone.close()
+	hs.streamsMu.Lock()
+	for _, stream := range hs.streams {
+		stream.close()
+	}
+	hs.streamsMu.Unlock()
+	hs.cancelAll()
+}
+
+// NewHTTPHandler returns an http.Handler serving s over the Streamable HTTP
+// transport at the path it is mounted on. Each initialize request starts a
+// session, identified by the Mcp-Session-Id header of the requests that follow.
+// Responses are sent as JSON, or as an SSE stream when the server sends other
+// messages while handling the request; a GET request opens a stream carrying
+// the messages unrelated to a request. Requests from browsers are rejected
+// unless their origin is allowed with WithAllowedOrigins or
+// WithLocalhostOrigins.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/transports#streamable-http
+func NewHTTPHandler(s *Server, opts ...HTTPHandlerOption) http.Handler {
+	h := &httpHandler{server: s, sessions: map[string]*httpSession{}}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.store == nil {
+		h.store = NewMemorySessionStore(0)
+	}
+	if h.stateless {
+		h.events = nil
+	}
+	s.onShutdown(h.shutdown)
+	if !h.stateless {
+		go h.sweep(sessionSweepInterval)
+	}
+	return h
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if !h.allowsOrigin(origin) {
+			http.Error(w, "Origin not allowed", http.StatusForbidden)
+			return
+		}
+		if h.serveCORS(w, r, origin) {
+			return
+		}
+	}
+	switch {
+	case r.Method == http.MethodPost:
+		h.servePost(w, r)
+	case r.Method == http.MethodGet && !h.stateless:
+		h.serveGet(w, r)
+	case r.Method == http.MethodDelete && !h.stateless:
+		h.serveDelete(w, r)
+	default:
+		w.Header().Set("Allow", h.allowedMethods())
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// allowedMethods lists the HTTP methods served.
+func (h *httpHandler) allowedMethods() string {
+	if h.stateless {
+		return "POST"
+	}
+	return "GET, POST, DELETE"
+}
+
+func (h *httpHandler) servePost(w http.ResponseWriter, r *http.Request) {
+	var reader io.Reader = r.Body
+	switch encoding := r.Header.Get("Content-Encoding"); encoding {
+	case "", "identity":
+	case "gzip":
+		// Sent by clients compressing large requests
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			httpError(w, mcp.ErrorInvalidRequest, fmt.Sprintf("Failed to read body: %v", err))
+			return
+		}
+		reader = zr
+	default:
+		http.Error(w, "Unsupported content encoding: "+encoding, http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(reader, maxHTTPMessageSize))
+	if err != nil {
+		httpError(w, mcp.ErrorInvalidRequest, fmt.Sprintf("Failed to read body: %v", err))
+		return
+	}
+
+	// The body holds a single message or a batch of them
+	var messages []*message
+	batch := len(bytes.TrimSpace(body)) > 0 && bytes.TrimSpace(body)[0] == '['
+	if batch {
+		err = json.Unmarshal(body, &messages)
+	} else {
+		var msg message
+		err = json.Unmarshal(body, &msg)
+		messages = []*message{&msg}
+	}
+	if err != nil || len(messages) == 0 {
+		writeJSON(w, mcp.HTTPStatusFromErrorCode(mcp.ErrorParseError), &message{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			Error:   mcp.NewError(mcp.ErrorParseError, fmt.Sprintf("invalid JSON-RPC message: %v", err)),
+		})
+		return
+	}
+
+	if h.stateless {
+		h.serveMessages(w, r, nil, newStatelessSession(), messages, batch)
+		return
+	}
+	if slices.ContainsFunc(messages, isInitialize) {
+		if batch {
+			httpError(w, mcp.ErrorInvalidRequest, "initialize must not be part of a batch")
+			return
+		}
+		h.initialize(w, r, messages[0])
+		return
+	}
+
+	hs, ok := h.requestSession(w, r)
+	if !ok {
+		return
+	}
+	h.serveMessages(w, r, hs, hs.session, messages, batch)
+}
+
+// serveMessages handles the messages of a POST request and writes the
+// responses to the requests among them. hs is nil for stateless requests.
+func (h *httpHandler) serveMessages(w http.ResponseWriter, r *http.Request, hs *httpSession, sess *session, messages []*message, batch bool) {
+	if !slices.ContainsFunc(messages, (*message).isRequest) {
+		for _, msg := range messages {
+			h.server.handle(r.Context(), sess, msg)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	stream := newSSEStream(sess.id, h.events)
+	stream.attach(w, acceptsSSE(r))
+	stop := context.AfterFunc(r.Context(), func() { stream.detach(w) })
+	defer stop()
+	ctx := r.Context()
+	if stream.events != nil {
+		// The client may resume the stream after a disconnection, so the
+		// requests outlive the connection
+		ctx = context.WithoutCancel(ctx)
+		hs.addStream(stream)
+		defer hs.removeStream(stream)
+	}
+	ctx = withRequestStream(ctx, stream)
+
+	var responses []*message
+	for _, msg := range messages {
+		if response := h.server.handle(ctx, sess, msg); response != nil {
+			responses = append(responses, response)
+		}
+	}
+
+	if stream.finish(ctx, responses) {
+		return
+	}
+	if batch {
+		writeJSON(w, http.StatusOK, responses)
+		return
+	}
+	writeJSON(w, http.StatusOK, responses[0])
+}
+
+// initialize starts a session with an initialize request.
+func (h *httpHandler) initialize(w http.ResponseWriter, r *http.Request, msg *message) {
+	if h.server.isClosing() {
+		w.Header().Set("Retry-After", strconv.Itoa(int(shutdownRetry.Seconds())))
+		httpError(w, mcp.ErrorServiceUnavailable, "Server shutting down")
+		return
+	}
+	if !msg.isRequest() {
+		// Without an ID, there is no response to carry the session ID
+		httpError(w, mcp.ErrorInvalidRequest, "initialize must be a request with an ID")
+		return
+	}
+	hs := newHTTPSession(newSessionID(), h.events)
+	response := h.server.handle(r.Context(), hs.session, msg)
+	if response == nil {
+		httpError(w, mcp.ErrorInvalidRequest, "initialize got no response")
+		return
+	}
+	if response.Error == nil {
+		if err := h.store.Create(r.Context(), hs.info()); err != nil {
+			httpError(w, mcp.ErrorInternalError, err.Error())
+			return
+		}
+		h.mu.Lock()
+		h.sessions[hs.id] = hs
+		h.mu.Unlock()
+		h.server.addSession(hs.session)
+		w.Header().Set(headerKeySessionID, hs.id)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// requestSession returns the session named by the request, or answers with
+// an error and reports false.
+func (h *httpHandler) requestSession(w http.ResponseWriter, r *http.Request) (*httpSession, bool) {
+	id := r.Header.Get(headerKeySessionID)
+	if id == "" {
+		httpError(w, mcp.ErrorInvalidRequest, "Missing session ID")
+		return nil, false
+	}
+	hs, err := h.session(r.Context(), id)
+	if errors.Is(err, ErrSessionNotFound) {
+		httpError(w, mcp.ErrorSessionTerminated, "Invalid session ID")
+		return nil, false
+	}
+	if err != nil {
+		httpError(w, mcp.ErrorInternalError, err.Error())
+		return nil, false
+	}
+	return hs, true
+}
+
+// session returns the session with id after recording activity on it. A
+// session started on another replica is restored from the store.
+func (h *httpHandler) session(ctx context.Context, id string) (*httpSession, error) {
+	if err := h.store.Touch(ctx, id); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			h.forget(id)
+		}
+		return nil, err
+	}
+
+	h.mu.Lock()
+	hs := h.sessions[id]
+	h.mu.Unlock()
+	if hs != nil {
+		return hs, nil
+	}
+
+	info, err := h.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	hs = newHTTPSession(id, h.events)
+	hs.setClient(info.ProtocolVersion, info.ClientInfo, info.ClientCapabilities)
+	hs.setInitialized()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if existing := h.sessions[id]; existing != nil {
+		return existing, nil
+	}
+	h.sessions[id] = hs
+	h.server.addSession(hs.session)
+	return hs, nil
+}
+
+// shutdown ends and deletes the sessions served by this replica when the
+// server shuts down.
+func (h *httpHandler) shutdown(ctx context.Context) {
+	h.mu.Lock()
+	sessions := make([]*httpSession, 0, len(h.sessions))
+	for _, hs := range h.sessions {
+		sessions = append(sessions, hs)
+	}
+	h.mu.Unlock()
+	for _, hs := range sessions {
+		hs.standalone.end(shutdownRetry)
+		_ = h.store.Delete(ctx, hs.id)
+		if h.events != nil {
+			_ = h.events.DeleteSession(ctx, hs.id)
+		}
+		h.forget(hs.id)
+	}
+}
+
+// sweep forgets the sessions that expired from the store without their
+// client coming back, every interval until the server shuts down.
+func (h *httpHandler) sweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.server.done:
+			return
+		case <-ticker.C:
+			h.forgetExpired(context.Background())
+		}
+	}
+}
+
+// forgetExpired forgets the sessions served by this replica that the store
+// no longer has, with their events.
+func (h *httpHandler) forgetExpired(ctx context.Context) {
+	h.mu.Lock()
+	ids := slices.Collect(maps.Keys(h.sessions))
+	h.mu.Unlock()
+	for _, id := range ids {
+		if _, err := h.store.Get(ctx, id); !errors.Is(err, ErrSessionNotFound) {
+			continue
+		}
+		if h.events != nil {
+			_ = h.events.DeleteSession(ctx, id)
+		}
+		h.forget(id)
+	}
+}
+
+// forget drops the live state of a session, ending its streams and requests.
+func (h *httpHandler) forget(id string) {
+	h.mu.Lock()
+	hs := h.sessions[id]
+	delete(h.sessions, id)
+	h.mu.Unlock()
+	if hs != nil {
+		h.server.removeSession(hs.session)
+		hs.close()
+	}
+}
+
+// serveGet opens the stream carrying the messages unrelated to a request, or
+// resumes a stream from the Last-Event-ID header.
+func (h *httpHandler) serveGet(w http.ResponseWriter, r *http.Request) {
+	if !acceptsSSE(r) {
+		http.Error(w, "Accept must include text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	hs, ok := h.requestSession(w, r)
+	if !ok {
+		return
+	}
+
+	stream := hs.standalone
+	var err error
+	if lastEventID := r.Header.Get(headerKeyLastEventID); lastEventID != "" && h.events != nil {
+		stream, err = h.resume(r.Context(), w, hs, lastEventID)
+	} else if !stream.attach(w, true) {
+		err = errStreamAttached
+	} else {
+		stream.begin()
+	}
+	if errors.Is(err, errStreamAttached) {
+		http.Error(w, "Stream already open", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		httpError(w, mcp.ErrorInvalidRequest, err.Error())
+		return
+	}
+	if stream == nil {
+		// The resumed stream has ended
+		return
+	}
+	defer stream.detach(w)
+
+	select {
+	case <-stream.done:
+	case <-r.Context().Done():
+	}
+}
+
+// resume replays the events of a stream of hs after lastEventID to w and
+// attaches w to the stream if it is still in progress. It returns the stream,
+// or nil if it has ended.
+func (h *httpHandler) resume(ctx context.Context, w http.ResponseWriter, hs *httpSession, lastEventID string) (*sseStream, error) {
+	type event struct {
+		id   string
+		data []byte
+	}
+	var missed []event
+	collect := func(eventID string, data []byte) error {
+		missed = append(missed, event{eventID, data})
+		return nil
+	}
+
+	streamID, err := h.events.Replay(ctx, lastEventID, collect)
+	if err == nil && !strings.HasPrefix(streamID, hs.id+"-") {
+		err = fmt.Errorf("%w: %s", ErrEventNotFound, lastEventID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume stream: %w", err)
+	}
+
+	stream := hs.stream(streamID)
+	if stream == nil {
+		startSSE(w)
+		for _, e := range missed {
+			_ = writeEvent(w, e.id, e.data)
+		}
+		return nil, nil
+	}
+
+	// Replay the events stored since, and attach, without letting new events
+	// through in between
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if stream.w != nil {
+		return nil, errStreamAttached
+	}
+	if len(missed) > 0 {
+		lastEventID = missed[len(missed)-1].id
+	}
+	if _, err := h.events.Replay(ctx, lastEventID, collect); err != nil {
+		return nil, fmt.Errorf("failed to resume stream: %w", err)
+	}
+	startSSE(w)
+	for _, e := range missed {
+		_ = writeEvent(w, e.id, e.data)
+	}
+	if stream.closed {
+		// The stream ended after being looked up; its last events were stored
+		return nil, nil
+	}
+	stream.w, stream.acceptsSSE, stream.started = w, true, true
+	return stream, nil
+}
+
+// serveDelete ends the session named by the request.
+func (h *httpHandler) serveDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(headerKeySessionID)
+	if id == "" {
+		httpError(w, mcp.ErrorInvalidRequest, "Missing session ID")
+		return
+	}
+	_, err := h.store.Get(r.Context(), id)
+	if errors.Is(err, ErrSessionNotFound) {
+		h.forget(id)
+		httpError(w, mcp.ErrorSessionTerminated, "Invalid session ID")
+		return
+	}
+	if err == nil {
+		err = h.store.Delete(r.Context(), id)
+	}
+	if err == nil && h.events != nil {
+		err = h.events.DeleteSession(r.Context(), id)
+	}
+	if err != nil {
+		httpError(w, mcp.ErrorInternalError, err.Error())
+		return
+	}
+	h.forget(id)
+	w.WriteHeader(http.StatusOK)
+}
+
+func isInitialize(msg *message) bool {
+	return msg.Method == string(mcp.MethodInitialize)
+}
+
+// acceptsSSE reports whether the client accepts an SSE stream in response.
+func acceptsSSE(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err == nil && (mediaType == "text/event-stream" || mediaType == "*/*" || mediaType == "text/*") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// httpError answers a request that gets no JSON-RPC response with the HTTP
+// status of code, which clients map back with mcp.ErrorCodeFromHTTPStatus.
+// Failures of the HTTP exchange itself, such as a wrong method or media
+// type, keep their own status.
+func httpError(w http.ResponseWriter, code mcp.ErrorCode, message string) {
+	http.Error(w, message, mcp.HTTPStatusFromErrorCode(code))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
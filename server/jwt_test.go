@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// signJWT signs claims with key as a JWT with the given header.
+func signJWT(t *testing.T, header, claims map[string]any, key crypto.Signer) string {
+	t.Helper()
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+	var signature []byte
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := func(n *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(n.Bytes())
+	}
+	keys := []map[string]any{
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(rsaKey.N), "e": b64(big.NewInt(int64(rsaKey.E)))},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecKey.X), "y": b64(ecKey.Y)},
+	}
+	var fetches atomic.Int32
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		writeJSON(w, http.StatusOK, map[string]any{"keys": keys})
+	}))
+	defer jwks.Close()
+
+	verifier := NewJWTVerifier(jwks.URL, WithIssuer("https://auth.example.com"), WithAudience("https://mcp.example.com"))
+	ctx := context.Background()
+	claims := func(overrides map[string]any) map[string]any {
+		claims := map[string]any{
+			"iss":   "https://auth.example.com",
+			"aud":   []string{"https://mcp.example.com"},
+			"sub":   "alice",
+			"azp":   "inspector",
+			"scope": "mcp:read mcp:write",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			claims[k] = v
+		}
+		return claims
+	}
+
+	t.Run("RS256", func(t *testing.T) {
+		token := signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(nil), rsaKey)
+		info, err := verifier.VerifyToken(ctx, token)
+		if err != nil {
+			t.Fatalf("VerifyToken failed: %v", err)
+		}
+		if info.Subject != "alice" || info.ClientID != "inspector" || !slices.Equal(info.Scopes, []string{"mcp:read", "mcp:write"}) {
+			t.Errorf("Expected alice's token info, got %+v", info)
+		}
+	})
+
+	t.Run("ES256", func(t *testing.T) {
+		token := signJWT(t, map[string]any{"alg": "ES256", "kid": "ec"}, claims(map[string]any{"scope": nil, "scp": []string{"mcp:read"}}), ecKey)
+		info, err := verifier.VerifyToken(ctx, token)
+		if err != nil {
+			t.Fatalf("VerifyToken failed: %v", err)
+		}
+		if !slices.Equal(info.Scopes, []string{"mcp:read"}) {
+			t.Errorf("Expected the scp scopes, got %v", info.Scopes)
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		tests := map[string]string{
+			"Expired":        signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}), rsaKey),
+			"NotYetValid":    signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(map[string]any{"nbf": time.Now().Add(time.Hour).Unix()}), rsaKey),
+			"NoExpiration":   signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(map[string]any{"exp": nil}), rsaKey),
+			"WrongIssuer":    signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(map[string]any{"iss": "https://evil.example.com"}), rsaKey),
+			"WrongAudience":  signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(map[string]any{"aud": "https://other.example.com"}), rsaKey),
+			"WrongKey":       signJWT(t, map[string]any{"alg": "RS256", "kid": "ec"}, claims(nil), rsaKey),
+			"UnknownKey":     signJWT(t, map[string]any{"alg": "RS256", "kid": "unknown"}, claims(nil), rsaKey),
+			"AlgorithmNone":  signJWT(t, map[string]any{"alg": "none", "kid": "rsa"}, claims(nil), rsaKey),
+			"Malformed":      "not-a-jwt",
+			"BadSignature":   signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(nil), rsaKey) + "AA",
+			"SignedByOthers": signJWT(t, map[string]any{"alg": "ES256", "kid": "ec"}, claims(nil), mustECKey(t)),
+		}
+		for name, token := range tests {
+			t.Run(name, func(t *testing.T) {
+				if _, err := verifier.VerifyToken(ctx, token); !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("Expected ErrInvalidToken, got %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("KeyCache", func(t *testing.T) {
+		fetches.Store(0)
+		token := signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(nil), rsaKey)
+		for range 3 {
+			if _, err := verifier.VerifyToken(ctx, token); err != nil {
+				t.Fatalf("VerifyToken failed: %v", err)
+			}
+		}
+		if fetches.Load() != 0 {
+			t.Errorf("Expected the cached key set to be used, got %d fetches", fetches.Load())
+		}
+	})
+
+	t.Run("JWKSUnavailable", func(t *testing.T) {
+		unavailable := NewJWTVerifier("http://127.0.0.1:0/jwks")
+		token := signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(nil), rsaKey)
+		if _, err := unavailable.VerifyToken(ctx, token); err == nil || errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Expected a fetch error, got %v", err)
+		}
+	})
+}
+
+func mustECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
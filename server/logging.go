@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// logLevels are the log levels by increasing severity.
+var logLevels = []mcp.LoggingLevel{
+	mcp.LoggingLevelDebug,
+	mcp.LoggingLevelInfo,
+	mcp.LoggingLevelNotice,
+	mcp.LoggingLevelWarning,
+	mcp.LoggingLevelError,
+	mcp.LoggingLevelCritical,
+	mcp.LoggingLevelAlert,
+	mcp.LoggingLevelEmergency,
+}
+
+// defaultLogLevel is the least severe level sent until the client sets one.
+const defaultLogLevel = mcp.LoggingLevelInfo
+
+// Logger sends log messages to the client of a session, as
+// notifications/logging/message. Messages less severe than the level set by
+// the client with logging/setLevel, info by default, are dropped.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/server/utilities/logging
+type Logger struct {
+	ctx  context.Context
+	sess *session
+	name string
+}
+
+// LoggerFromContext returns the Logger of the session whose request is
+// handled in ctx. Outside of a request, the Logger drops every message.
+func LoggerFromContext(ctx context.Context) *Logger {
+	return &Logger{ctx: ctx, sess: sessionFromContext(ctx)}
+}
+
+// Named returns a Logger sending messages with the given logger name.
+func (l *Logger) Named(name string) *Logger {
+	named := *l
+	named.name = name
+	return &named
+}
+
+// Log sends data, a message or any JSON-serializable value, at level.
+func (l *Logger) Log(level mcp.LoggingLevel, data any) error {
+	if l.sess == nil || !l.sess.logs(level) {
+		return nil
+	}
+	params := map[string]any{"level": level, "data": data}
+	if l.name != "" {
+		params["logger"] = l.name
+	}
+	return l.sess.notify(l.ctx, mcp.MethodNotificationLoggingMessage, params)
+}
+
+// Debug logs data at the debug level.
+func (l *Logger) Debug(data any) {
+	_ = l.Log(mcp.LoggingLevelDebug, data)
+}
+
+// Info logs data at the info level.
+func (l *Logger) Info(data any) {
+	_ = l.Log(mcp.LoggingLevelInfo, data)
+}
+
+// Warning logs data at the warning level.
+func (l *Logger) Warning(data any) {
+	_ = l.Log(mcp.LoggingLevelWarning, data)
+}
+
+// Error logs data at the error level.
+func (l *Logger) Error(data any) {
+	_ = l.Log(mcp.LoggingLevelError, data)
+}
+
+// setLogLevel answers logging/setLevel.
+func setLogLevel(sess *session, params json.RawMessage) (*mcp.EmptyResult, error) {
+	var request mcp.SetLevelRequest
+	if err := decodeParams(params, &request.Params); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(logLevels, request.Params.Level) {
+		return nil, mcp.NewError(mcp.ErrorInvalidParams, fmt.Sprintf("invalid log level: %q", request.Params.Level))
+	}
+	sess.setLogLevel(request.Params.Level)
+	return &mcp.EmptyResult{}, nil
+}
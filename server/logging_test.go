@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestLogger(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(mcp.Tool{Name: "work"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		logger := LoggerFromContext(ctx)
+		logger.Debug("starting")
+		logger.Info("working")
+		logger.Named("db").Error(map[string]any{"query": "select 1"})
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	c := connect(t, srv)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if c.ServerCapabilities().Logging == nil {
+		t.Error("Expected the logging capability")
+	}
+
+	messages := c.Notifications(ctx, string(mcp.MethodNotificationLoggingMessage))
+	receive := func(t *testing.T, n int) []map[string]any {
+		t.Helper()
+		if _, err := c.CallTool(ctx, "work", nil); err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		var received []map[string]any
+		for len(received) < n {
+			select {
+			case msg := <-messages:
+				params, _ := msg.Params.(map[string]any)
+				received = append(received, params)
+			case <-ctx.Done():
+				t.Fatalf("Expected %d log messages, got %v", n, received)
+			}
+		}
+		select {
+		case msg := <-messages:
+			t.Errorf("Expected %d log messages, got another: %+v", n, msg)
+		case <-time.After(50 * time.Millisecond):
+		}
+		return received
+	}
+
+	t.Run("DefaultLevel", func(t *testing.T) {
+		received := receive(t, 2)
+		if received[0]["level"] != "info" || received[0]["data"] != "working" {
+			t.Errorf("Expected the info message, got %v", received[0])
+		}
+		data, _ := received[1]["data"].(map[string]any)
+		if received[1]["level"] != "error" || received[1]["logger"] != "db" || data["query"] != "select 1" {
+			t.Errorf("Expected the db error, got %v", received[1])
+		}
+	})
+
+	t.Run("SetLevel", func(t *testing.T) {
+		if _, err := c.Request(ctx, string(mcp.MethodLoggingSetLevel), map[string]any{"level": "debug"}); err != nil {
+			t.Fatalf("logging/setLevel failed: %v", err)
+		}
+		if received := receive(t, 3); received[0]["data"] != "starting" {
+			t.Errorf("Expected the debug message, got %v", received[0])
+		}
+
+		if _, err := c.Request(ctx, string(mcp.MethodLoggingSetLevel), map[string]any{"level": "critical"}); err != nil {
+			t.Fatalf("logging/setLevel failed: %v", err)
+		}
+		receive(t, 0)
+	})
+
+	t.Run("InvalidLevel", func(t *testing.T) {
+		_, err := c.Request(ctx, string(mcp.MethodLoggingSetLevel), map[string]any{"level": "verbose"})
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorInvalidParams {
+			t.Errorf("Expected ErrorInvalidParams, got %v", err)
+		}
+	})
+
+	t.Run("OutsideRequest", func(t *testing.T) {
+		if err := LoggerFromContext(context.Background()).Log(mcp.LoggingLevelEmergency, "dropped"); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,394 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// openAPIMethods are the operations of an OpenAPI path item, in the order
+// their tools are registered.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// invalidToolNameChars are replaced in the names derived from paths.
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// OpenAPIOption configures AddOpenAPI.
+type OpenAPIOption func(*openAPI)
+
+// WithBaseURL sets the URL the operation paths are relative to, instead of
+// the first server of the document.
+func WithBaseURL(baseURL string) OpenAPIOption {
+	return func(a *openAPI) {
+		a.baseURL = baseURL
+	}
+}
+
+// WithOpenAPIClient sets the HTTP client calling the API.
+func WithOpenAPIClient(client *http.Client) OpenAPIOption {
+	return func(a *openAPI) {
+		a.client = client
+	}
+}
+
+// WithRequestEditor modifies the requests to the API before they are sent,
+// typically to add credentials.
+func WithRequestEditor(edit func(ctx context.Context, req *http.Request) error) OpenAPIOption {
+	return func(a *openAPI) {
+		a.editors = append(a.editors, edit)
+	}
+}
+
+// DefaultOpenAPIMaxResponseSize is the size of the largest API response
+// passed on whole as tool output unless WithOpenAPIMaxResponseSize is given.
+const DefaultOpenAPIMaxResponseSize = 1 << 20
+
+// WithOpenAPIMaxResponseSize limits the API responses passed on as tool
+// output to size bytes, DefaultOpenAPIMaxResponseSize by default. Longer
+// responses are cut, saying so at their end. A size of zero or less removes
+// the limit.
+func WithOpenAPIMaxResponseSize(size int64) OpenAPIOption {
+	return func(a *openAPI) {
+		a.maxResponseSize = size
+	}
+}
+
+// WithOperations only registers the operations with the given IDs, or tool
+// names for operations without an ID.
+func WithOperations(names ...string) OpenAPIOption {
+	return func(a *openAPI) {
+		a.operations = names
+	}
+}
+
+type openAPI struct {
+	baseURL         string
+	client          *http.Client
+	editors         []func(ctx context.Context, req *http.Request) error
+	operations      []string
+	maxResponseSize int64
+}
+
+type openAPIParameter struct {
+	Name        string          `json:"name"`
+	In          string          `json:"in"`
+	Description string          `json:"description"`
+	Required    bool            `json:"required"`
+	Schema      json.RawMessage `json:"schema"`
+}
+
+type openAPIOperation struct {
+	OperationID string             `json:"operationId"`
+	Summary     string             `json:"summary"`
+	Description string             `json:"description"`
+	Parameters  []openAPIParameter `json:"parameters"`
+	RequestBody *struct {
+		Description string `json:"description"`
+		Required    bool   `json:"required"`
+		Content     map[string]struct {
+			Schema json.RawMessage `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+// AddOpenAPI registers a tool for each operation of an OpenAPI 3 document in
+// JSON, calling the API when the tool is called. Tools are named after the
+// operation IDs and take the parameters of the operation as arguments, and
+// its JSON request body as the "body" argument, checked by the validator of
+// the server. Responses are returned as text, as tool errors for error
+// statuses. Local $ref are resolved.
+func (s *Server) AddOpenAPI(document []byte, opts ...OpenAPIOption) error {
+	a := &openAPI{client: http.DefaultClient, maxResponseSize: DefaultOpenAPIMaxResponseSize}
+	for _, opt := range opts {
+		opt(a)
+	}
+	var root map[string]any
+	if err := json.Unmarshal(document, &root); err != nil {
+		return fmt.Errorf("failed to decode OpenAPI document: %w", err)
+	}
+	version, _ := root["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return fmt.Errorf("unsupported OpenAPI version %q", version)
+	}
+	resolved, err := json.Marshal(resolveRefs(root, root, nil))
+	if err != nil {
+		return fmt.Errorf("failed to resolve OpenAPI document: %w", err)
+	}
+	var doc struct {
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(resolved, &doc); err != nil {
+		return fmt.Errorf("failed to decode OpenAPI document: %w", err)
+	}
+	if a.baseURL == "" && len(doc.Servers) > 0 {
+		a.baseURL = doc.Servers[0].URL
+	}
+	if a.baseURL == "" {
+		return fmt.Errorf("missing base URL of the API")
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	for _, p := range paths {
+		item := doc.Paths[p]
+		var common []openAPIParameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &common); err != nil {
+				return fmt.Errorf("failed to decode parameters of %s: %w", p, err)
+			}
+		}
+		for _, method := range openAPIMethods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var op openAPIOperation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return fmt.Errorf("failed to decode operation %s %s: %w", method, p, err)
+			}
+			tool, call := a.tool(strings.ToUpper(method), p, op, common)
+			if len(a.operations) > 0 && !slices.Contains(a.operations, tool.Name) {
+				continue
+			}
+			s.AddTool(tool, call)
+		}
+	}
+	return nil
+}
+
+// tool returns the tool calling an operation.
+func (a *openAPI) tool(method, path string, op openAPIOperation, common []openAPIParameter) (mcp.Tool, ToolHandlerFunc) {
+	name := op.OperationID
+	if name == "" {
+		name = strings.Trim(invalidToolNameChars.ReplaceAllString(strings.ToLower(method)+path, "_"), "_")
+	}
+	description := strings.TrimSpace(op.Summary + "\n\n" + op.Description)
+
+	// Parameters of the operation override those of the path
+	var params []openAPIParameter
+	for _, p := range slices.Concat(op.Parameters, common) {
+		if !slices.ContainsFunc(params, func(q openAPIParameter) bool { return q.Name == p.Name && q.In == p.In }) {
+			params = append(params, p)
+		}
+	}
+	properties := map[string]any{}
+	required := []string{}
+	for _, p := range params {
+		schema := map[string]any{}
+		_ = json.Unmarshal(p.Schema, &schema)
+		if p.Description != "" {
+			schema["description"] = p.Description
+		}
+		properties[p.Name] = schema
+		if p.Required || p.In == "path" {
+			required = append(required, p.Name)
+		}
+	}
+	var contentType string
+	if body := op.RequestBody; body != nil && len(body.Content) > 0 {
+		contentType = "application/json"
+		content, ok := body.Content[contentType]
+		if !ok {
+			// Other media types are sent as text
+			types := make([]string, 0, len(body.Content))
+			for t := range body.Content {
+				types = append(types, t)
+			}
+			slices.Sort(types)
+			contentType = types[0]
+			content.Schema = json.RawMessage(`{"type":"string"}`)
+		}
+		schema := map[string]any{}
+		_ = json.Unmarshal(content.Schema, &schema)
+		if body.Description != "" {
+			schema["description"] = body.Description
+		}
+		properties["body"] = schema
+		if body.Required {
+			required = append(required, "body")
+		}
+	}
+	inputSchema, _ := json.Marshal(map[string]any{"type": "object", "properties": properties, "required": required})
+
+	tool := mcp.Tool{Name: name, Description: description, InputSchema: inputSchema}
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		readOnly := true
+		tool.Annotations = &mcp.ToolAnnotations{ReadOnlyHint: &readOnly}
+	case http.MethodDelete:
+		destructive := true
+		tool.Annotations = &mcp.ToolAnnotations{DestructiveHint: &destructive}
+	}
+
+	return tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		req, err := a.request(ctx, method, path, params, contentType, request.Params.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := a.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call %s %s: %w", method, path, err)
+		}
+		defer resp.Body.Close()
+		text, err := a.readResponse(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response of %s %s: %w", method, path, err)
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			result := mcp.NewToolResultText(fmt.Sprintf("%s: %s", resp.Status, text))
+			result.IsError = true
+			return result, nil
+		}
+		return mcp.NewToolResultText(text), nil
+	}
+}
+
+// readResponse reads an API response, cut to the maximum response size.
+func (a *openAPI) readResponse(body io.Reader) (string, error) {
+	if a.maxResponseSize <= 0 {
+		data, err := io.ReadAll(body)
+		return string(data), err
+	}
+	data, err := io.ReadAll(io.LimitReader(body, a.maxResponseSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > a.maxResponseSize {
+		return fmt.Sprintf("%s\n[response truncated to %d bytes]", data[:a.maxResponseSize], a.maxResponseSize), nil
+	}
+	return string(data), nil
+}
+
+// request builds the HTTP request of an operation from the tool arguments.
+func (a *openAPI) request(ctx context.Context, method, path string, params []openAPIParameter, contentType string, arguments map[string]any) (*http.Request, error) {
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+	for _, p := range params {
+		value, ok := arguments[p.Name]
+		if !ok {
+			continue
+		}
+		values := parameterValues(value)
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(strings.Join(values, ",")))
+		case "query":
+			query[p.Name] = values
+		case "header":
+			header.Set(p.Name, strings.Join(values, ","))
+		case "cookie":
+			cookies = append(cookies, &http.Cookie{Name: p.Name, Value: strings.Join(values, ",")})
+		}
+	}
+
+	var body io.Reader
+	if value, ok := arguments["body"]; ok && contentType != "" {
+		if contentType == "application/json" {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode request body: %w", err)
+			}
+			body = bytes.NewReader(data)
+		} else {
+			body = strings.NewReader(formatParameter(value))
+		}
+		header.Set("Content-Type", contentType)
+	}
+
+	target := strings.TrimSuffix(a.baseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	for _, edit := range a.editors {
+		if err := edit(ctx, req); err != nil {
+			return nil, fmt.Errorf("failed to edit request: %w", err)
+		}
+	}
+	return req, nil
+}
+
+// parameterValues formats the value of a parameter, one value per item of
+// arrays.
+func parameterValues(value any) []string {
+	if items, ok := value.([]any); ok {
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			values = append(values, formatParameter(item))
+		}
+		return values
+	}
+	return []string{formatParameter(value)}
+}
+
+// formatParameter formats a value sent to the API. Numbers, decoded from
+// JSON as float64, are written without exponent, as 1234567 rather than
+// 1.234567e+06.
+func formatParameter(value any) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// resolveRefs replaces the local $ref of v by the values they point to in
+// root. A reference to one of its enclosing references, as by a recursive
+// schema, is replaced by an empty schema.
+func resolveRefs(v any, root map[string]any, seen []string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#/") {
+			if slices.Contains(seen, ref) {
+				return map[string]any{}
+			}
+			var target any = root
+			for _, token := range strings.Split(ref[2:], "/") {
+				token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+				m, _ := target.(map[string]any)
+				target = m[token]
+			}
+			if target == nil {
+				return map[string]any{}
+			}
+			return resolveRefs(target, root, append(seen, ref))
+		}
+		resolved := make(map[string]any, len(v))
+		for key, value := range v {
+			resolved[key] = resolveRefs(value, root, seen)
+		}
+		return resolved
+	case []any:
+		resolved := make([]any, len(v))
+		for i, value := range v {
+			resolved[i] = resolveRefs(value, root, seen)
+		}
+		return resolved
+	}
+	return v
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+const petstore = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Petstore", "version": "1.0.0"},
+  "servers": [{"url": "https://petstore.example.com/v1"}],
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "summary": "List pets",
+        "parameters": [
+          {"name": "limit", "in": "query", "schema": {"type": "integer"}},
+          {"name": "tag", "in": "query", "schema": {"type": "array", "items": {"type": "string"}}}
+        ]
+      },
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+        }
+      }
+    },
+    "/pets/{petId}": {
+      "parameters": [{"name": "petId", "in": "path", "required": true, "description": "ID of the pet", "schema": {"type": "string"}}],
+      "delete": {
+        "parameters": [{"name": "X-Reason", "in": "header", "schema": {"type": "string"}}]
+      }
+    },
+    "/vets/{vetId}": {
+      "get": {
+        "operationId": "getVet",
+        "parameters": [{"name": "vetId", "in": "path", "required": true, "schema": {"type": "integer"}}]
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string"},
+          "parent": {"$ref": "#/components/schemas/Pet"}
+        }
+      }
+    }
+  }
+}`
+
+func TestAddOpenAPI(t *testing.T) {
+	var received *http.Request
+	var body string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		if r.URL.Path == "/v1/pets/missing" {
+			http.Error(w, "pet not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(api.Close)
+
+	srv := NewServer("test-server", "1.0.0")
+	err := srv.AddOpenAPI([]byte(petstore), WithBaseURL(api.URL+"/v1"), WithRequestEditor(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer key")
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("AddOpenAPI failed: %v", err)
+	}
+	c := connect(t, srv)
+	ctx := context.Background()
+
+	t.Run("Tools", func(t *testing.T) {
+		result, err := c.ListTools(ctx, "")
+		if err != nil {
+			t.Fatalf("ListTools failed: %v", err)
+		}
+		var names []string
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		if strings.Join(names, ",") != "listPets,createPet,delete_pets_petId,getVet" {
+			t.Fatalf("Expected a tool per operation, got %v", names)
+		}
+		if result.Tools[0].Description != "List pets" || !*result.Tools[0].Annotations.ReadOnlyHint {
+			t.Errorf("Expected a read-only tool described by its summary, got %+v", result.Tools[0])
+		}
+
+		var schema struct {
+			Properties map[string]struct {
+				Description string         `json:"description"`
+				Properties  map[string]any `json:"properties"`
+			} `json:"properties"`
+			Required []string `json:"required"`
+		}
+		if err := json.Unmarshal(result.Tools[1].InputSchema, &schema); err != nil {
+			t.Fatalf("Failed to decode schema: %v", err)
+		}
+		if _, ok := schema.Properties["body"].Properties["parent"]; !ok || len(schema.Required) != 1 {
+			t.Errorf("Expected the resolved body schema, got %s", result.Tools[1].InputSchema)
+		}
+		if err := json.Unmarshal(result.Tools[2].InputSchema, &schema); err != nil {
+			t.Fatalf("Failed to decode schema: %v", err)
+		}
+		if schema.Properties["petId"].Description != "ID of the pet" {
+			t.Errorf("Expected the path parameter, got %s", result.Tools[2].InputSchema)
+		}
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		_, err := c.CallTool(ctx, "listPets", map[string]any{"limit": 10, "tag": []any{"cat", "dog"}})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if received.Method != http.MethodGet || received.URL.RawQuery != "limit=10&tag=cat&tag=dog" {
+			t.Errorf("Expected the query parameters, got %s %s", received.Method, received.URL)
+		}
+		if received.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("Expected the edited request, got %v", received.Header)
+		}
+	})
+
+	t.Run("Body", func(t *testing.T) {
+		result, err := c.CallTool(ctx, "createPet", map[string]any{"body": map[string]any{"name": "Rex"}})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if body != `{"name":"Rex"}` || received.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected the JSON body, got %q", body)
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; text != `{"ok":true}` {
+			t.Errorf("Expected the response, got %q", text)
+		}
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		_, err := c.CallTool(ctx, "delete_pets_petId", map[string]any{"petId": "a/b", "X-Reason": "sold"})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if received.Method != http.MethodDelete || received.URL.EscapedPath() != "/v1/pets/a%2Fb" || received.Header.Get("X-Reason") != "sold" {
+			t.Errorf("Expected the path and header parameters, got %s %s", received.Method, received.URL.EscapedPath())
+		}
+	})
+
+	t.Run("NumericPath", func(t *testing.T) {
+		_, err := c.CallTool(ctx, "getVet", map[string]any{"vetId": 1234567})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if received.URL.Path != "/v1/vets/1234567" {
+			t.Errorf("Expected the number without exponent, got %s", received.URL.Path)
+		}
+	})
+
+	t.Run("LargeResponse", func(t *testing.T) {
+		srv := NewServer("test-server", "1.0.0")
+		if err := srv.AddOpenAPI([]byte(petstore), WithBaseURL(api.URL+"/v1"), WithOpenAPIMaxResponseSize(4)); err != nil {
+			t.Fatalf("AddOpenAPI failed: %v", err)
+		}
+		result, err := connect(t, srv).CallTool(ctx, "listPets", nil)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; text != "{\"ok\n[response truncated to 4 bytes]" {
+			t.Errorf("Expected the cut response, got %q", text)
+		}
+	})
+
+	t.Run("ErrorStatus", func(t *testing.T) {
+		result, err := c.CallTool(ctx, "delete_pets_petId", map[string]any{"petId": "missing"})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; !result.IsError || !strings.Contains(text, "404") {
+			t.Errorf("Expected a tool error, got %q", text)
+		}
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		result, err := c.CallTool(ctx, "createPet", map[string]any{})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if !result.IsError {
+			t.Error("Expected a tool error for the missing body")
+		}
+	})
+
+	t.Run("Operations", func(t *testing.T) {
+		srv := NewServer("test-server", "1.0.0")
+		if err := srv.AddOpenAPI([]byte(petstore), WithOperations("listPets")); err != nil {
+			t.Fatalf("AddOpenAPI failed: %v", err)
+		}
+		result, err := srv.listTools(nil)
+		if err != nil {
+			t.Fatalf("listTools failed: %v", err)
+		}
+		if len(result.Tools) != 1 || result.Tools[0].Name != "listPets" {
+			t.Errorf("Expected only listPets, got %+v", result.Tools)
+		}
+	})
+
+	t.Run("InvalidDocument", func(t *testing.T) {
+		srv := NewServer("test-server", "1.0.0")
+		if err := srv.AddOpenAPI([]byte(`{"swagger": "2.0"}`)); err == nil {
+			t.Error("Expected an error for Swagger 2.0")
+		}
+	})
+}
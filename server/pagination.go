@@ -0,0 +1,49 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// DefaultPageSize is the number of items per page of the tools, prompts,
+// resources and resource templates lists.
+const DefaultPageSize = 100
+
+// WithPageSize sets the number of items per page of the tools, prompts,
+// resources and resource templates lists, DefaultPageSize by default. A size
+// of zero or less lists all the items at once.
+func WithPageSize(size int) ServerOption {
+	return func(s *Server) {
+		s.pageSize = size
+	}
+}
+
+// paginate returns the bounds of the page of n items starting at the cursor
+// of a list request, and the cursor of the next page, empty on the last one.
+// Cursors are item offsets, so a list changing between requests may repeat
+// or skip items.
+func (s *Server) paginate(params json.RawMessage, n int) (start, end int, next mcp.Cursor, err error) {
+	var request mcp.PaginatedRequest
+	if err := decodeParams(params, &request); err != nil {
+		return 0, 0, "", err
+	}
+	if request.Cursor != "" {
+		offset, err := base64.RawURLEncoding.DecodeString(string(request.Cursor))
+		if err == nil {
+			start, err = strconv.Atoi(string(offset))
+		}
+		if err != nil || start < 0 {
+			return 0, 0, "", mcp.NewError(mcp.ErrorInvalidParams, "invalid cursor: "+string(request.Cursor))
+		}
+	}
+	start = min(start, n)
+	end = n
+	if s.pageSize > 0 && n-start > s.pageSize {
+		end = start + s.pageSize
+		next = mcp.Cursor(base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(end))))
+	}
+	return start, end, next, nil
+}
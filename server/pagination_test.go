@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestPagination(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0", WithPageSize(2))
+	for i := range 5 {
+		srv.AddTool(mcp.Tool{Name: fmt.Sprintf("tool%d", i)}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, nil
+		})
+		srv.AddPrompt(mcp.Prompt{Name: fmt.Sprintf("prompt%d", i)}, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return nil, nil
+		})
+	}
+	c := connect(t, srv)
+	ctx := context.Background()
+
+	t.Run("Tools", func(t *testing.T) {
+		var names []string
+		var cursor mcp.Cursor
+		for pages := 1; ; pages++ {
+			result, err := c.ListTools(ctx, cursor)
+			if err != nil {
+				t.Fatalf("ListTools failed: %v", err)
+			}
+			if len(result.Tools) > 2 {
+				t.Errorf("Expected at most 2 tools per page, got %d", len(result.Tools))
+			}
+			for _, tool := range result.Tools {
+				names = append(names, tool.Name)
+			}
+			if cursor = result.NextCursor; cursor == "" {
+				if pages != 3 {
+					t.Errorf("Expected 3 pages, got %d", pages)
+				}
+				break
+			}
+		}
+		if fmt.Sprint(names) != "[tool0 tool1 tool2 tool3 tool4]" {
+			t.Errorf("Expected every tool once, got %v", names)
+		}
+	})
+
+	t.Run("Prompts", func(t *testing.T) {
+		first, err := c.ListPrompts(ctx, "")
+		if err != nil {
+			t.Fatalf("ListPrompts failed: %v", err)
+		}
+		second, err := c.ListPrompts(ctx, first.NextCursor)
+		if err != nil {
+			t.Fatalf("ListPrompts failed: %v", err)
+		}
+		if len(second.Prompts) != 2 || second.Prompts[0].Name != "prompt2" {
+			t.Errorf("Expected the second page, got %+v", second.Prompts)
+		}
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		_, err := c.ListTools(ctx, "not a cursor")
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorInvalidParams {
+			t.Errorf("Expected ErrorInvalidParams, got %v", err)
+		}
+	})
+
+	t.Run("Unpaginated", func(t *testing.T) {
+		srv := NewServer("test-server", "1.0.0", WithPageSize(0))
+		for i := range 150 {
+			srv.AddTool(mcp.Tool{Name: fmt.Sprintf("tool%d", i)}, nil)
+		}
+		result, err := connect(t, srv).ListTools(ctx, "")
+		if err != nil {
+			t.Fatalf("ListTools failed: %v", err)
+		}
+		if len(result.Tools) != 150 || result.NextCursor != "" {
+			t.Errorf("Expected all 150 tools at once, got %d and cursor %q", len(result.Tools), result.NextCursor)
+		}
+	})
+}
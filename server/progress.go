@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+type progressKey struct{}
+
+// withProgressToken returns a context carrying the progress token of a
+// request, if its params have one.
+func withProgressToken(ctx context.Context, params json.RawMessage) context.Context {
+	meta := mcp.MetaFromParams(params)
+	if meta == nil || meta.ProgressToken == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, meta.ProgressToken)
+}
+
+// ProgressReporterFromContext returns the mcp.ProgressReporter of the request
+// handled in ctx. When the client asked for no progress, by leaving out the
+// progressToken of the request, reports are dropped.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/utilities/progress
+func ProgressReporterFromContext(ctx context.Context) mcp.ProgressReporter {
+	return &progressReporter{
+		ctx:   ctx,
+		sess:  sessionFromContext(ctx),
+		token: ctx.Value(progressKey{}),
+	}
+}
+
+// progressReporter sends the progress notifications of a request.
+type progressReporter struct {
+	ctx   context.Context
+	sess  *session
+	token mcp.ProgressToken
+}
+
+// Report implements mcp.ProgressReporter.
+func (r *progressReporter) Report(progress, total float64, message string) error {
+	if r.sess == nil || r.token == nil {
+		return nil
+	}
+	params := map[string]any{"progressToken": r.token, "progress": progress}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	return r.sess.notify(r.ctx, mcp.MethodNotificationProgress, params)
+}
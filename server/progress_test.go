@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestProgressReporter(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(mcp.Tool{Name: "count"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		progress := ProgressReporterFromContext(ctx)
+		for i := 1; i <= 3; i++ {
+			if err := progress.Report(float64(i), 3, "counting"); err != nil {
+				return nil, err
+			}
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	c := connect(t, srv)
+	ctx := context.Background()
+
+	t.Run("WithToken", func(t *testing.T) {
+		var mu sync.Mutex
+		var reports []float64
+		_, err := c.CallToolWithProgress(ctx, "count", nil, func(progress, total float64, message string) {
+			mu.Lock()
+			defer mu.Unlock()
+			if total != 3 || message != "counting" {
+				t.Errorf("Expected 3 in total while counting, got %v %q", total, message)
+			}
+			reports = append(reports, progress)
+		})
+		if err != nil {
+			t.Fatalf("CallToolWithProgress failed: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(reports) != 3 || reports[2] != 3 {
+			t.Errorf("Expected 3 reports, got %v", reports)
+		}
+	})
+
+	t.Run("WithoutToken", func(t *testing.T) {
+		result, err := c.CallTool(ctx, "count", nil)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if result.IsError {
+			t.Errorf("Expected a result, got %+v", result)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// PromptHandlerFunc answers a prompts/get request with the messages of the
+// prompt, filled in with request.Params.Arguments.
+type PromptHandlerFunc func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+
+// serverPrompt is a registered prompt.
+type serverPrompt struct {
+	prompt      mcp.Prompt
+	handler     PromptHandlerFunc
+	completions map[string]CompletionFunc
+}
+
+// AddPrompt registers a prompt served by prompts/list and prompts/get,
+// replacing any prompt of the same name. Requests missing an argument the
+// prompt declares as required are rejected before reaching handler. Its
+// arguments are completed by the completions given with WithCompletion.
+func (s *Server) AddPrompt(prompt mcp.Prompt, handler PromptHandlerFunc, opts ...CompletionOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.listChanged(mcp.MethodNotificationPromptsListChanged)
+	registered := serverPrompt{prompt: prompt, handler: handler, completions: newCompletions(opts)}
+	for i, p := range s.prompts {
+		if p.prompt.Name == prompt.Name {
+			s.prompts[i] = registered
+			return
+		}
+	}
+	s.prompts = append(s.prompts, registered)
+}
+
+// RemovePrompt unregisters the prompts with the given names.
+func (s *Server) RemovePrompt(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.prompts)
+	s.prompts = slices.DeleteFunc(s.prompts, func(p serverPrompt) bool {
+		return slices.Contains(names, p.prompt.Name)
+	})
+	if len(s.prompts) != n {
+		s.listChanged(mcp.MethodNotificationPromptsListChanged)
+	}
+}
+
+func (s *Server) listPrompts(params json.RawMessage) (*mcp.ListPromptsResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	start, end, next, err := s.paginate(params, len(s.prompts))
+	if err != nil {
+		return nil, err
+	}
+	prompts := make([]mcp.Prompt, 0, end-start)
+	for _, p := range s.prompts[start:end] {
+		prompts = append(prompts, p.prompt)
+	}
+	result := &mcp.ListPromptsResult{Prompts: prompts}
+	result.NextCursor = next
+	return result, nil
+}
+
+func (s *Server) getPrompt(ctx context.Context, params json.RawMessage) (*mcp.GetPromptResult, error) {
+	request := mcp.GetPromptRequest{Method: string(mcp.MethodPromptsGet)}
+	if err := decodeParams(params, &request.Params); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var registered *serverPrompt
+	for i := range s.prompts {
+		if s.prompts[i].prompt.Name == request.Params.Name {
+			registered = &s.prompts[i]
+			break
+		}
+	}
+	s.mu.RUnlock()
+	if registered == nil {
+		return nil, mcp.NewError(mcp.ErrorInvalidParams, fmt.Sprintf("prompt not found: %s", request.Params.Name))
+	}
+
+	var missing []string
+	for _, argument := range registered.prompt.Arguments {
+		if _, ok := request.Params.Arguments[argument.Name]; argument.Required && !ok {
+			missing = append(missing, argument.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, mcp.NewError(mcp.ErrorInvalidParams, fmt.Sprintf("missing required arguments of prompt %s: %s", request.Params.Name, strings.Join(missing, ", ")))
+	}
+
+	result, err := registered.handler(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &mcp.GetPromptResult{}
+	}
+	if result.Messages == nil {
+		result.Messages = []mcp.PromptMessage{}
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestPrompts(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddPrompt(mcp.Prompt{
+		Name:        "review",
+		Description: "Reviews code",
+		Arguments: []mcp.PromptArgument{
+			{Name: "code", Required: true},
+			{Name: "language"},
+		},
+	}, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		code, _ := request.Params.Arguments["code"].(string)
+		return &mcp.GetPromptResult{
+			Description: "Code review",
+			Messages:    []mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("Review: "+code))},
+		}, nil
+	})
+
+	c := connect(t, srv)
+	ctx := context.Background()
+
+	if c.ServerCapabilities().Prompts == nil {
+		t.Error("Expected the prompts capability")
+	}
+
+	t.Run("ListPrompts", func(t *testing.T) {
+		result, err := c.ListPrompts(ctx, "")
+		if err != nil {
+			t.Fatalf("ListPrompts failed: %v", err)
+		}
+		if len(result.Prompts) != 1 || result.Prompts[0].Name != "review" || len(result.Prompts[0].Arguments) != 2 {
+			t.Errorf("Expected the review prompt, got %+v", result.Prompts)
+		}
+	})
+
+	t.Run("GetPrompt", func(t *testing.T) {
+		result, err := c.GetPrompt(ctx, "review", map[string]string{"code": "x := 1"})
+		if err != nil {
+			t.Fatalf("GetPrompt failed: %v", err)
+		}
+		if len(result.Messages) != 1 || result.Messages[0].Content.(mcp.TextContent).Text != "Review: x := 1" {
+			t.Errorf("Expected the review message, got %+v", result.Messages)
+		}
+	})
+
+	t.Run("MissingArgument", func(t *testing.T) {
+		_, err := c.GetPrompt(ctx, "review", map[string]string{"language": "go"})
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorInvalidParams {
+			t.Fatalf("Expected ErrorInvalidParams, got %v", err)
+		}
+		if rpcErr.Message != "missing required arguments of prompt review: code" {
+			t.Errorf("Unexpected message %q", rpcErr.Message)
+		}
+	})
+
+	t.Run("UnknownPrompt", func(t *testing.T) {
+		_, err := c.GetPrompt(ctx, "missing", nil)
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorInvalidParams {
+			t.Errorf("Expected ErrorInvalidParams, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// QuotaLimit bounds the number of tool calls. Zero values mean unlimited.
+type QuotaLimit struct {
+	// CallsPerMinute limits calls within a fixed one-minute window
+	CallsPerMinute int
+	// TotalCalls limits calls over the whole lifetime of the session or principal
+	TotalCalls int
+}
+
+func (l QuotaLimit) isZero() bool {
+	return l.CallsPerMinute <= 0 && l.TotalCalls <= 0
+}
+
+// Quotas configures tool usage limits for multi-tenant servers.
+type Quotas struct {
+	// PerSession applies to all tool calls of a session
+	PerSession QuotaLimit
+	// PerPrincipal applies to all tool calls of an authenticated principal, across sessions
+	PerPrincipal QuotaLimit
+	// PerTool applies to calls of a single tool within a session, keyed by tool name
+	PerTool map[string]QuotaLimit
+}
+
+// QuotaExceededError is returned when a tool call would exceed a quota.
+type QuotaExceededError struct {
+	// Scope is "session", "principal" or "tool"
+	Scope string `json:"scope"`
+	// Tool is the tool name for tool-scoped quotas
+	Tool string `json:"tool,omitempty"`
+	// Limit is "callsPerMinute" or "totalCalls"
+	Limit string `json:"limit"`
+	// ResetAt is when the exhausted limit resets; zero for lifetime limits
+	ResetAt time.Time `json:"resetAt,omitzero"`
+	// Remaining is the number of calls left under the total limit of the
+	// quota, or -1 when it has none
+	Remaining int `json:"remaining"`
+}
+
+func (e *QuotaExceededError) Error() string {
+	msg := fmt.Sprintf("%s quota exceeded (%s)", e.Scope, e.Limit)
+	if e.Tool != "" {
+		msg = fmt.Sprintf("%s quota for tool %q exceeded (%s)", e.Scope, e.Tool, e.Limit)
+	}
+	if !e.ResetAt.IsZero() {
+		msg += fmt.Sprintf(", resets at %s", e.ResetAt.Format(time.RFC3339))
+	}
+	return msg
+}
+
+// RPCError converts the error into a JSON-RPC error carrying the quota details as data.
+func (e *QuotaExceededError) RPCError() *mcp.Error {
+	data, _ := json.Marshal(e)
+	return &mcp.Error{
+		Code:    mcp.ErrorRateLimited,
+		Message: e.Error(),
+		Data:    data,
+	}
+}
+
+// WithQuotas enforces quotas on the tool calls of the server. A call
+// exceeding one is answered with the JSON-RPC error of its
+// *QuotaExceededError. The principal is the subject, or else the client ID,
+// of TokenInfoFromContext. Each HTTP and stdio session has its own session
+// quotas, dropped when the session ends; the calls of a stateless HTTP
+// handler, which has no sessions, all share the same session quotas, so
+// that PerPrincipal is the way to tell its clients apart.
+func WithQuotas(quotas Quotas) ServerOption {
+	return func(s *Server) {
+		s.quotas = NewQuotaEnforcer(quotas)
+	}
+}
+
+// allowCall records a call of tool against the quotas of the server, if
+// any, or returns the JSON-RPC error of the quota it exceeds.
+func (s *Server) allowCall(ctx context.Context, tool string) error {
+	if s.quotas == nil {
+		return nil
+	}
+	var sessionID, principal string
+	if sess := sessionFromContext(ctx); sess != nil {
+		sessionID = sess.quotaID
+	}
+	if info := TokenInfoFromContext(ctx); info != nil {
+		principal = info.Subject
+		if principal == "" {
+			principal = info.ClientID
+		}
+	}
+	err := s.quotas.Allow(sessionID, principal, tool, time.Now())
+	var quotaErr *QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		return quotaErr.RPCError()
+	}
+	return err
+}
+
+// QuotaEnforcer tracks tool usage and enforces Quotas. It is safe for concurrent use.
+type QuotaEnforcer struct {
+	quotas Quotas
+
+	mu       sync.Mutex
+	counters map[quotaKey]*quotaCounter
+}
+
+type quotaKey struct {
+	scope string
+	id    string
+	tool  string
+}
+
+type quotaCounter struct {
+	total       int
+	windowStart time.Time
+	windowCalls int
+}
+
+// NewQuotaEnforcer creates a QuotaEnforcer for the given quotas.
+func NewQuotaEnforcer(quotas Quotas) *QuotaEnforcer {
+	return &QuotaEnforcer{
+		quotas:   quotas,
+		counters: make(map[quotaKey]*quotaCounter),
+	}
+}
+
+// Allow records a call of tool by the given session and principal, or returns a
+// *QuotaExceededError without recording anything if any applicable quota is exhausted.
+// An empty principal skips the per-principal quota.
+func (e *QuotaEnforcer) Allow(sessionID, principal, tool string, now time.Time) error {
+	type check struct {
+		key   quotaKey
+		limit QuotaLimit
+	}
+	checks := []check{{quotaKey{"session", sessionID, ""}, e.quotas.PerSession}}
+	if principal != "" {
+		checks = append(checks, check{quotaKey{"principal", principal, ""}, e.quotas.PerPrincipal})
+	}
+	if limit, ok := e.quotas.PerTool[tool]; ok {
+		checks = append(checks, check{quotaKey{"tool", sessionID, tool}, limit})
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	counters := make([]*quotaCounter, 0, len(checks))
+	for _, c := range checks {
+		if c.limit.isZero() {
+			continue
+		}
+		counter, ok := e.counters[c.key]
+		if !ok {
+			counter = &quotaCounter{}
+			e.counters[c.key] = counter
+		}
+		if now.Sub(counter.windowStart) >= time.Minute {
+			counter.windowStart = now
+			counter.windowCalls = 0
+		}
+
+		remaining := -1
+		if c.limit.TotalCalls > 0 {
+			remaining = max(c.limit.TotalCalls-counter.total, 0)
+		}
+		if c.limit.TotalCalls > 0 && counter.total >= c.limit.TotalCalls {
+			return &QuotaExceededError{Scope: c.key.scope, Tool: c.key.tool, Limit: "totalCalls", Remaining: remaining}
+		}
+		if c.limit.CallsPerMinute > 0 && counter.windowCalls >= c.limit.CallsPerMinute {
+			return &QuotaExceededError{
+				Scope:     c.key.scope,
+				Tool:      c.key.tool,
+				Limit:     "callsPerMinute",
+				ResetAt:   counter.windowStart.Add(time.Minute),
+				Remaining: remaining,
+			}
+		}
+		counters = append(counters, counter)
+	}
+
+	for _, counter := range counters {
+		counter.total++
+		counter.windowCalls++
+	}
+	return nil
+}
+
+// Remaining returns the number of calls the session may still make in the current
+// minute and in total, or -1 for unlimited.
+func (e *QuotaEnforcer) Remaining(sessionID string, now time.Time) (perMinute, total int) {
+	limit := e.quotas.PerSession
+	perMinute, total = -1, -1
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	counter := e.counters[quotaKey{"session", sessionID, ""}]
+	if counter == nil {
+		counter = &quotaCounter{}
+	}
+	if limit.CallsPerMinute > 0 {
+		perMinute = limit.CallsPerMinute
+		if now.Sub(counter.windowStart) < time.Minute {
+			perMinute -= counter.windowCalls
+		}
+	}
+	if limit.TotalCalls > 0 {
+		total = limit.TotalCalls - counter.total
+	}
+	return perMinute, total
+}
+
+// ForgetSession drops the counters of a session, typically when it is deleted.
+// Per-principal counters are kept since they span sessions.
+func (e *QuotaEnforcer) ForgetSession(sessionID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for key := range e.counters {
+		if key.scope != "principal" && key.id == sessionID {
+			delete(e.counters, key)
+		}
+	}
+}
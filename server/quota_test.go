@@ -0,0 +1,242 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestQuotaEnforcer(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("CallsPerMinute", func(t *testing.T) {
+		enforcer := NewQuotaEnforcer(Quotas{PerSession: QuotaLimit{CallsPerMinute: 2}})
+		for i := 0; i < 2; i++ {
+			if err := enforcer.Allow("s1", "", "echo", now); err != nil {
+				t.Fatalf("Call %d rejected: %v", i, err)
+			}
+		}
+
+		err := enforcer.Allow("s1", "", "echo", now.Add(10*time.Second))
+		var quotaErr *QuotaExceededError
+		if !errors.As(err, &quotaErr) {
+			t.Fatalf("Expected QuotaExceededError, got %v", err)
+		}
+		if quotaErr.Scope != "session" || quotaErr.Limit != "callsPerMinute" || !quotaErr.ResetAt.Equal(now.Add(time.Minute)) || quotaErr.Remaining != -1 {
+			t.Errorf("Unexpected quota error: %+v", quotaErr)
+		}
+
+		// Other sessions are unaffected, and the window resets after a minute
+		if err := enforcer.Allow("s2", "", "echo", now); err != nil {
+			t.Errorf("Other session rejected: %v", err)
+		}
+		if err := enforcer.Allow("s1", "", "echo", now.Add(time.Minute)); err != nil {
+			t.Errorf("Call after window reset rejected: %v", err)
+		}
+	})
+
+	t.Run("PrincipalSpansSessions", func(t *testing.T) {
+		enforcer := NewQuotaEnforcer(Quotas{PerPrincipal: QuotaLimit{TotalCalls: 1}})
+		if err := enforcer.Allow("s1", "alice", "echo", now); err != nil {
+			t.Fatalf("First call rejected: %v", err)
+		}
+		if err := enforcer.Allow("s2", "alice", "echo", now); err == nil {
+			t.Errorf("Expected principal quota to be exceeded")
+		}
+		if err := enforcer.Allow("s2", "bob", "echo", now); err != nil {
+			t.Errorf("Other principal rejected: %v", err)
+		}
+	})
+
+	t.Run("PerToolDoesNotConsumeOnRejection", func(t *testing.T) {
+		enforcer := NewQuotaEnforcer(Quotas{
+			PerSession: QuotaLimit{TotalCalls: 3},
+			PerTool:    map[string]QuotaLimit{"expensive": {TotalCalls: 1}},
+		})
+		if err := enforcer.Allow("s1", "", "expensive", now); err != nil {
+			t.Fatalf("First call rejected: %v", err)
+		}
+		var quotaErr *QuotaExceededError
+		if err := enforcer.Allow("s1", "", "expensive", now); !errors.As(err, &quotaErr) {
+			t.Fatalf("Expected tool quota to be exceeded, got %v", err)
+		}
+		if quotaErr.Remaining != 0 {
+			t.Errorf("Expected no remaining tool calls, got %d", quotaErr.Remaining)
+		}
+		if _, total := enforcer.Remaining("s1", now); total != 2 {
+			t.Errorf("Expected 2 remaining session calls, got %d", total)
+		}
+
+		enforcer.ForgetSession("s1")
+		if err := enforcer.Allow("s1", "", "expensive", now); err != nil {
+			t.Errorf("Call after ForgetSession rejected: %v", err)
+		}
+	})
+}
+
+func TestQuotaExceededErrorRPCError(t *testing.T) {
+	reset := time.Date(2025, 5, 1, 12, 1, 0, 0, time.UTC)
+	rpcErr := (&QuotaExceededError{Scope: "tool", Tool: "search", Limit: "callsPerMinute", ResetAt: reset, Remaining: 7}).RPCError()
+	if rpcErr.Code != mcp.ErrorRateLimited {
+		t.Errorf("Expected RateLimited code, got %s", rpcErr.Code)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(rpcErr.Data, &data); err != nil {
+		t.Fatalf("Failed to decode error data: %v", err)
+	}
+	if data["tool"] != "search" || data["resetAt"] != "2025-05-01T12:01:00Z" || data["limit"] != "callsPerMinute" || data["remaining"] != float64(7) {
+		t.Errorf("Unexpected error data: %v", data)
+	}
+}
+
+func TestWithQuotas(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0", WithQuotas(Quotas{
+		PerPrincipal: QuotaLimit{TotalCalls: 3},
+		PerTool:      map[string]QuotaLimit{"echo": {TotalCalls: 2}},
+	}))
+	srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv.AddTool(mcp.Tool{Name: "other"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	auth := RequireBearerToken(StaticTokens(map[string]*TokenInfo{"a1": {Subject: "alice"}, "a2": {Subject: "alice"}}))
+	ts := httptest.NewServer(auth(NewHTTPHandler(srv)))
+	t.Cleanup(ts.Close)
+	connectAs := func(token string) *client.HTTPClient {
+		c, err := client.NewHTTPClient(&client.Options{
+			BaseURL: ts.URL,
+			Headers: map[string]string{"Authorization": "Bearer " + token},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		t.Cleanup(func() { c.Close() })
+		return c
+	}
+	ctx := context.Background()
+	quotaError := func(err error) map[string]any {
+		t.Helper()
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorRateLimited {
+			t.Fatalf("Expected a rate limited error, got %v", err)
+		}
+		var data map[string]any
+		if err := json.Unmarshal(rpcErr.Data, &data); err != nil {
+			t.Fatalf("Failed to decode error data: %v", err)
+		}
+		return data
+	}
+
+	first := connectAs("a1")
+	for i := 0; i < 2; i++ {
+		if _, err := first.CallTool(ctx, "echo", nil); err != nil {
+			t.Fatalf("Call %d rejected: %v", i, err)
+		}
+	}
+	_, err := first.CallTool(ctx, "echo", nil)
+	if data := quotaError(err); data["scope"] != "tool" || data["tool"] != "echo" {
+		t.Errorf("Expected the tool quota to be exceeded, got %v", data)
+	}
+
+	// The principal quota spans the sessions of the same subject
+	second := connectAs("a2")
+	if _, err := second.CallTool(ctx, "other", nil); err != nil {
+		t.Fatalf("Call rejected: %v", err)
+	}
+	_, err = second.CallTool(ctx, "echo", nil)
+	if data := quotaError(err); data["scope"] != "principal" {
+		t.Errorf("Expected the principal quota to be exceeded, got %v", data)
+	}
+
+	// Unknown tools are not counted against the exhausted quotas
+	_, err = second.CallTool(ctx, "missing", nil)
+	var rpcErr *mcp.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorToolNotFound {
+		t.Errorf("Expected a tool not found error, got %v", err)
+	}
+}
+
+func TestQuotaSessions(t *testing.T) {
+	newServer := func() *Server {
+		srv := NewServer("test-server", "1.0.0", WithQuotas(Quotas{PerSession: QuotaLimit{TotalCalls: 1}}))
+		srv.AddTool(mcp.Tool{Name: "echo"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+		return srv
+	}
+	counters := func(srv *Server) int {
+		srv.quotas.mu.Lock()
+		defer srv.quotas.mu.Unlock()
+		return len(srv.quotas.counters)
+	}
+	ctx := context.Background()
+
+	t.Run("Expired", func(t *testing.T) {
+		srv := newServer()
+		h := NewHTTPHandler(srv, WithSessionStore(NewMemorySessionStore(50*time.Millisecond))).(*httpHandler)
+		ts := httptest.NewServer(h)
+		t.Cleanup(ts.Close)
+		c, err := client.NewHTTPClient(&client.Options{BaseURL: ts.URL})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer c.Close()
+		if _, err := c.CallTool(ctx, "echo", nil); err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if n := counters(srv); n != 1 {
+			t.Fatalf("Expected the session to be counted, got %d counters", n)
+		}
+		time.Sleep(100 * time.Millisecond)
+		h.forgetExpired(ctx)
+		if n := counters(srv); n != 0 {
+			t.Errorf("Expected the counters of the expired session to be dropped, got %d", n)
+		}
+	})
+
+	t.Run("Shutdown", func(t *testing.T) {
+		srv := newServer()
+		c := connect(t, srv)
+		if _, err := c.CallTool(ctx, "echo", nil); err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if err := srv.Shutdown(ctx); err != nil {
+			t.Fatalf("Shutdown failed: %v", err)
+		}
+		if n := counters(srv); n != 0 {
+			t.Errorf("Expected the counters to be dropped at shutdown, got %d", n)
+		}
+	})
+
+	t.Run("Stdio", func(t *testing.T) {
+		srv := newServer()
+		input := strings.Join([]string{
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1"},"capabilities":{}}}`,
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo"}}`,
+		}, "\n") + "\n"
+		// Each stdio session has its own quota, dropped when it ends
+		for i := range 2 {
+			var out bytes.Buffer
+			if err := srv.ServeStdio(ctx, strings.NewReader(input), &out); err != nil {
+				t.Fatalf("ServeStdio failed: %v", err)
+			}
+			if strings.Contains(out.String(), `"error"`) {
+				t.Errorf("Session %d: expected the call to be allowed, got %s", i, out.String())
+			}
+		}
+		if n := counters(srv); n != 0 {
+			t.Errorf("Expected the counters of the ended sessions to be dropped, got %d", n)
+		}
+	})
+}
@@ -0,0 +1,303 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/yosida95/uritemplate/v3"
+)
+
+// ResourceHandlerFunc answers a resources/read request with the contents of
+// the resource, as mcp.TextResourceContents or mcp.BlobResourceContents.
+// Contents without a URI or MIME type get those of the resource. For resource
+// templates, request.Params.Arguments holds the variables matched in the URI.
+type ResourceHandlerFunc func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)
+
+// serverResource is a registered resource.
+type serverResource struct {
+	resource mcp.Resource
+	handler  ResourceHandlerFunc
+}
+
+// serverResourceTemplate is a registered resource template.
+type serverResourceTemplate struct {
+	template    mcp.ResourceTemplate
+	handler     ResourceHandlerFunc
+	completions map[string]CompletionFunc
+}
+
+// AddResource registers a resource served by resources/list and
+// resources/read, replacing any resource with the same URI.
+func (s *Server) AddResource(resource mcp.Resource, handler ResourceHandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.listChanged(mcp.MethodNotificationResourcesListChanged)
+	registered := serverResource{resource: resource, handler: handler}
+	for i, r := range s.resources {
+		if r.resource.URI == resource.URI {
+			s.resources[i] = registered
+			return
+		}
+	}
+	s.resources = append(s.resources, registered)
+}
+
+// RemoveResource unregisters the resources with the given URIs.
+func (s *Server) RemoveResource(uris ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.resources)
+	s.resources = slices.DeleteFunc(s.resources, func(r serverResource) bool {
+		return slices.Contains(uris, r.resource.URI)
+	})
+	if len(s.resources) != n {
+		s.listChanged(mcp.MethodNotificationResourcesListChanged)
+	}
+}
+
+// AddResourceTemplate registers a resource template served by
+// resources/templates/list. A resources/read request for a URI matching no
+// resource is answered by the first template matching it, with the template
+// variables in request.Params.Arguments: a string, or a []string for variables
+// matching several values such as exploded lists. It replaces any template
+// with the same URI template and panics if the URI template is invalid. Its
+// variables are completed by the completions given with WithCompletion.
+func (s *Server) AddResourceTemplate(template mcp.ResourceTemplate, handler ResourceHandlerFunc, opts ...CompletionOption) {
+	if template.URITemplate == nil || !template.URITemplate.Valid() {
+		panic("server: invalid URI template for resource template " + template.Name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.listChanged(mcp.MethodNotificationResourcesListChanged)
+	registered := serverResourceTemplate{template: template, handler: handler, completions: newCompletions(opts)}
+	for i, t := range s.resourceTemplates {
+		if t.template.URITemplate.Raw() == template.URITemplate.Raw() {
+			s.resourceTemplates[i] = registered
+			return
+		}
+	}
+	s.resourceTemplates = append(s.resourceTemplates, registered)
+}
+
+// RemoveResourceTemplate unregisters the resource templates with the given
+// URI templates.
+func (s *Server) RemoveResourceTemplate(uriTemplates ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.resourceTemplates)
+	s.resourceTemplates = slices.DeleteFunc(s.resourceTemplates, func(t serverResourceTemplate) bool {
+		return slices.Contains(uriTemplates, t.template.URITemplate.Raw())
+	})
+	if len(s.resourceTemplates) != n {
+		s.listChanged(mcp.MethodNotificationResourcesListChanged)
+	}
+}
+
+func (s *Server) listResources(params json.RawMessage) (*mcp.ListResourcesResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	start, end, next, err := s.paginate(params, len(s.resources))
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]mcp.Resource, 0, end-start)
+	for _, r := range s.resources[start:end] {
+		resources = append(resources, r.resource)
+	}
+	result := &mcp.ListResourcesResult{Resources: resources}
+	result.NextCursor = next
+	return result, nil
+}
+
+func (s *Server) listResourceTemplates(params json.RawMessage) (*mcp.ListResourceTemplatesResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	start, end, next, err := s.paginate(params, len(s.resourceTemplates))
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]mcp.ResourceTemplate, 0, end-start)
+	for _, t := range s.resourceTemplates[start:end] {
+		templates = append(templates, t.template)
+	}
+	result := &mcp.ListResourceTemplatesResult{ResourceTemplates: templates}
+	result.NextCursor = next
+	return result, nil
+}
+
+func (s *Server) readResource(ctx context.Context, params json.RawMessage) (*mcp.ReadResourceResult, error) {
+	request := mcp.ReadResourceRequest{Method: string(mcp.MethodResourcesRead)}
+	if err := decodeParams(params, &request.Params); err != nil {
+		return nil, err
+	}
+
+	handler, mimeType, arguments := s.resolveResource(request.Params.URI)
+	if handler == nil {
+		return nil, mcp.NewError(mcp.ErrorResourceNotFound, fmt.Sprintf("resource not found: %s", request.Params.URI))
+	}
+	if arguments != nil {
+		request.Params.Arguments = arguments
+	}
+
+	contents, err := handler(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{Contents: completeContents(contents, request.Params.URI, mimeType)}, nil
+}
+
+// resolveResource finds the handler of uri: that of the resource with this
+// URI, else that of the first template matching it, along with the variables
+// matched.
+func (s *Server) resolveResource(uri string) (ResourceHandlerFunc, string, map[string]any) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, r := range s.resources {
+		if r.resource.URI == uri {
+			return r.handler, r.resource.MimeType, nil
+		}
+	}
+	for _, t := range s.resourceTemplates {
+		values := t.template.URITemplate.Match(uri)
+		if values == nil {
+			continue
+		}
+		arguments := make(map[string]any, len(values))
+		for name, value := range values {
+			if value.T == uritemplate.ValueTypeString {
+				arguments[name] = value.String()
+			} else {
+				arguments[name] = value.List()
+			}
+		}
+		return t.handler, t.template.MimeType, arguments
+	}
+	return nil, "", nil
+}
+
+// completeContents fills in the URI and MIME type left empty by a handler.
+func completeContents(contents []mcp.ResourceContents, uri, mimeType string) []mcp.ResourceContents {
+	completed := make([]mcp.ResourceContents, 0, len(contents))
+	for _, content := range contents {
+		switch c := content.(type) {
+		case mcp.TextResourceContents:
+			if c.URI == "" {
+				c.URI = uri
+			}
+			if c.MimeType == "" {
+				c.MimeType = mimeType
+			}
+			content = c
+		case mcp.BlobResourceContents:
+			if c.URI == "" {
+				c.URI = uri
+			}
+			if c.MimeType == "" {
+				c.MimeType = mimeType
+			}
+			content = c
+		}
+		completed = append(completed, content)
+	}
+	return completed
+}
+
+func (s *Server) subscribe(sess *session, params json.RawMessage) (*mcp.EmptyResult, error) {
+	var request mcp.SubscribeRequest
+	if err := decodeParams(params, &request.Params); err != nil {
+		return nil, err
+	}
+	if request.Params.URI == "" {
+		return nil, mcp.NewError(mcp.ErrorInvalidParams, "missing resource URI")
+	}
+	s.setSubscribed(sess, request.Params.URI, true)
+	return &mcp.EmptyResult{}, nil
+}
+
+func (s *Server) unsubscribe(sess *session, params json.RawMessage) (*mcp.EmptyResult, error) {
+	var request mcp.UnsubscribeRequest
+	if err := decodeParams(params, &request.Params); err != nil {
+		return nil, err
+	}
+	s.setSubscribed(sess, request.Params.URI, false)
+	return &mcp.EmptyResult{}, nil
+}
+
+// setSubscribed records the subscription of sess to the resource with uri,
+// or its end. Only connected sessions, which can be notified, are counted
+// as subscribers.
+func (s *Server) setSubscribed(sess *session, uri string, subscribed bool) {
+	s.sessionsMu.Lock()
+	_, connected := s.sessions[sess]
+	changed := sess.subscribe(uri, subscribed) && connected
+	if changed {
+		delta := 1
+		if !subscribed {
+			delta = -1
+		}
+		changed = s.countSubscriber(uri, delta)
+	}
+	hooks := s.subscriptionHooks
+	s.sessionsMu.Unlock()
+	if changed {
+		for _, hook := range hooks {
+			hook(uri)
+		}
+	}
+}
+
+// countSubscriber adds delta to the subscribers of uri, reporting whether it
+// gained its first one or lost its last. s.sessionsMu must be held.
+func (s *Server) countSubscriber(uri string, delta int) bool {
+	if delta < 0 && s.subscribers[uri] == 0 {
+		return false
+	}
+	n := s.subscribers[uri] + delta
+	if n > 0 {
+		s.subscribers[uri] = n
+	} else {
+		delete(s.subscribers, uri)
+	}
+	return (n == 1 && delta > 0) || n == 0
+}
+
+// subscribedURIs returns the URIs of the resources connected sessions are
+// subscribed to.
+func (s *Server) subscribedURIs() []string {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	return slices.Collect(maps.Keys(s.subscribers))
+}
+
+// hasSubscribers reports whether a connected session is subscribed to the
+// resource with uri.
+func (s *Server) hasSubscribers(uri string) bool {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	return s.subscribers[uri] > 0
+}
+
+// onSubscriptionChange registers a function told of the resource URIs
+// gaining their first subscribed session or losing their last.
+func (s *Server) onSubscriptionChange(hook func(uri string)) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	s.subscriptionHooks = append(s.subscriptionHooks, hook)
+}
+
+// NotifyResourceUpdated tells the clients subscribed to the resource with uri
+// that it changed, so they can read it again. Subscriptions are kept by the
+// process serving the session, so with replicated HTTP servers every replica
+// has to be notified.
+func (s *Server) NotifyResourceUpdated(uri string) {
+	ctx := context.Background()
+	for _, sess := range s.connectedSessions() {
+		if sess.subscribed(uri) {
+			_ = sess.notify(ctx, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestResources(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddResource(mcp.Resource{URI: "file:///readme.md", Name: "readme", MimeType: "text/markdown"}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{Text: "# Hello"}}, nil
+	})
+	srv.AddResource(mcp.Resource{URI: "file:///logo.png", Name: "logo"}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.BlobResourceContents{URI: request.Params.URI, MimeType: "image/png", Blob: "iVBORw0KGgo="}}, nil
+	})
+	srv.AddResource(mcp.Resource{URI: "file:///broken", Name: "broken"}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, errors.New("disk failure")
+	})
+
+	c := connect(t, srv)
+	ctx := context.Background()
+
+	if c.ServerCapabilities().Resources == nil {
+		t.Error("Expected the resources capability")
+	}
+
+	t.Run("ListResources", func(t *testing.T) {
+		result, err := c.ListResources(ctx, "")
+		if err != nil {
+			t.Fatalf("ListResources failed: %v", err)
+		}
+		if len(result.Resources) != 3 || result.Resources[0].URI != "file:///readme.md" {
+			t.Errorf("Expected 3 resources, got %+v", result.Resources)
+		}
+	})
+
+	t.Run("ReadText", func(t *testing.T) {
+		result, err := c.ReadResource(ctx, "file:///readme.md")
+		if err != nil {
+			t.Fatalf("ReadResource failed: %v", err)
+		}
+		expected := mcp.TextResourceContents{URI: "file:///readme.md", MimeType: "text/markdown", Text: "# Hello"}
+		if len(result.Contents) != 1 || result.Contents[0] != expected {
+			t.Errorf("Expected %+v, got %+v", expected, result.Contents)
+		}
+	})
+
+	t.Run("ReadBlob", func(t *testing.T) {
+		result, err := c.ReadResource(ctx, "file:///logo.png")
+		if err != nil {
+			t.Fatalf("ReadResource failed: %v", err)
+		}
+		blob, ok := result.Contents[0].(mcp.BlobResourceContents)
+		if !ok || blob.Blob != "iVBORw0KGgo=" || blob.MimeType != "image/png" {
+			t.Errorf("Expected blob contents, got %+v", result.Contents)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := c.ReadResource(ctx, "file:///missing")
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorResourceNotFound {
+			t.Errorf("Expected ErrorResourceNotFound, got %v", err)
+		}
+	})
+
+	t.Run("HandlerError", func(t *testing.T) {
+		_, err := c.ReadResource(ctx, "file:///broken")
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorInternalError {
+			t.Errorf("Expected ErrorInternalError, got %v", err)
+		}
+	})
+}
+
+func TestResourceTemplates(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddResource(mcp.Resource{URI: "users://admin/profile", Name: "admin"}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{Text: "the admin"}}, nil
+	})
+	template, err := mcp.NewURITemplate("users://{id}/profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.AddResourceTemplate(mcp.ResourceTemplate{URITemplate: template, Name: "profile", MimeType: "application/json"}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{Text: `{"id":"` + request.Params.Arguments["id"].(string) + `"}`}}, nil
+	})
+	tags, err := mcp.NewURITemplate("tags://{/tags*}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.AddResourceTemplate(mcp.ResourceTemplate{URITemplate: tags, Name: "tags"}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		list, _ := request.Params.Arguments["tags"].([]string)
+		return []mcp.ResourceContents{mcp.TextResourceContents{Text: strings.Join(list, ",")}}, nil
+	})
+
+	c := connect(t, srv)
+	ctx := context.Background()
+
+	t.Run("ListResourceTemplates", func(t *testing.T) {
+		result, err := c.ListResourceTemplates(ctx, "")
+		if err != nil {
+			t.Fatalf("ListResourceTemplates failed: %v", err)
+		}
+		if len(result.ResourceTemplates) != 2 || result.ResourceTemplates[0].URITemplate.Raw() != "users://{id}/profile" {
+			t.Errorf("Expected 2 templates, got %+v", result.ResourceTemplates)
+		}
+	})
+
+	t.Run("Match", func(t *testing.T) {
+		result, err := c.ReadResource(ctx, "users://42/profile")
+		if err != nil {
+			t.Fatalf("ReadResource failed: %v", err)
+		}
+		expected := mcp.TextResourceContents{URI: "users://42/profile", MimeType: "application/json", Text: `{"id":"42"}`}
+		if len(result.Contents) != 1 || result.Contents[0] != expected {
+			t.Errorf("Expected %+v, got %+v", expected, result.Contents)
+		}
+	})
+
+	t.Run("ListVariable", func(t *testing.T) {
+		result, err := c.ReadResource(ctx, "tags:///go/mcp")
+		if err != nil {
+			t.Fatalf("ReadResource failed: %v", err)
+		}
+		if text := result.Contents[0].(mcp.TextResourceContents).Text; text != "go,mcp" {
+			t.Errorf("Expected go,mcp, got %q", text)
+		}
+	})
+
+	t.Run("ResourceBeforeTemplate", func(t *testing.T) {
+		result, err := c.ReadResource(ctx, "users://admin/profile")
+		if err != nil {
+			t.Fatalf("ReadResource failed: %v", err)
+		}
+		if text := result.Contents[0].(mcp.TextResourceContents).Text; text != "the admin" {
+			t.Errorf("Expected the admin, got %q", text)
+		}
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		_, err := c.ReadResource(ctx, "users://42/settings")
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorResourceNotFound {
+			t.Errorf("Expected ErrorResourceNotFound, got %v", err)
+		}
+	})
+}
+
+func TestResourceSubscriptions(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddResource(mcp.Resource{URI: "file:///a", Name: "a"}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{Text: "a"}}, nil
+	})
+	ts := httptest.NewServer(NewHTTPHandler(srv))
+	t.Cleanup(ts.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	listen := func() (*client.HTTPClient, <-chan mcp.JSONRPCNotification) {
+		c, err := client.NewHTTPClient(&client.Options{BaseURL: ts.URL, Listen: true})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		t.Cleanup(func() { c.Close() })
+		return c, c.Notifications(ctx, string(mcp.MethodNotificationResourceUpdated))
+	}
+	subscriber, updates := listen()
+	other, otherUpdates := listen()
+
+	if !subscriber.ServerCapabilities().Resources.Subscribe {
+		t.Error("Expected the resources.subscribe capability")
+	}
+	if err := subscriber.SubscribeResource(ctx, "file:///a"); err != nil {
+		t.Fatalf("SubscribeResource failed: %v", err)
+	}
+	if err := other.SubscribeResource(ctx, "file:///b"); err != nil {
+		t.Fatalf("SubscribeResource failed: %v", err)
+	}
+
+	// Notify until the GET stream of the subscriber is open
+	var update mcp.JSONRPCNotification
+	for received := false; !received; {
+		srv.NotifyResourceUpdated("file:///a")
+		select {
+		case update = <-updates:
+			received = true
+		case <-time.After(20 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("Expected a resources/updated notification")
+		}
+	}
+	if params, _ := update.Params.(map[string]any); params["uri"] != "file:///a" {
+		t.Errorf("Expected the updated URI, got %+v", update.Params)
+	}
+	select {
+	case n := <-otherUpdates:
+		t.Errorf("Expected no notification for other resources, got %+v", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := subscriber.UnsubscribeResource(ctx, "file:///a"); err != nil {
+		t.Fatalf("UnsubscribeResource failed: %v", err)
+	}
+	for len(updates) > 0 {
+		<-updates
+	}
+	srv.NotifyResourceUpdated("file:///a")
+	select {
+	case n := <-updates:
+		t.Errorf("Expected no notification after unsubscribing, got %+v", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// ErrSamplingNotSupported is returned by CreateMessage when the client did not
+// declare the sampling capability.
+var ErrSamplingNotSupported = errors.New("client does not support sampling")
+
+// CreateMessage asks the client of session to sample its LLM and waits for
+// the result, or for ctx to be done. Called from the handler of a request,
+// with its context, the request is sent on the stream answering it.
+// Sessions of a stateless HTTP handler cannot receive responses, so they
+// don't support sampling.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/client/sampling
+func (s *Server) CreateMessage(ctx context.Context, session *ClientSession, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	if session == nil {
+		return nil, errNoStream
+	}
+	if session.ClientCapabilities().Sampling == nil {
+		return nil, ErrSamplingNotSupported
+	}
+
+	var result mcp.CreateMessageResult
+	if err := session.sess.request(ctx, mcp.MethodSamplingCreateMessage, request.Params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestCreateMessage(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(mcp.Tool{Name: "ask"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var sampling mcp.CreateMessageRequest
+		sampling.Params.Messages = []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("What is 6 * 7?")}}
+		sampling.Params.MaxTokens = 10
+		result, err := srv.CreateMessage(ctx, SessionFromContext(ctx), sampling)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(result.Model + ": " + result.Content.(mcp.TextContent).Text), nil
+	})
+	ts := httptest.NewServer(NewHTTPHandler(srv))
+	t.Cleanup(ts.Close)
+	ctx := context.Background()
+
+	t.Run("Sampling", func(t *testing.T) {
+		c, err := client.NewHTTPClient(&client.Options{BaseURL: ts.URL}, client.WithSampling(func(ctx context.Context, request *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			question := request.Params.Messages[0].Content.(mcp.TextContent).Text
+			if question != "What is 6 * 7?" || request.Params.MaxTokens != 10 {
+				t.Errorf("Expected the question, got %q", question)
+			}
+			return &mcp.CreateMessageResult{
+				SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("42")},
+				Model:           "test-model",
+			}, nil
+		}))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer c.Close()
+
+		result, err := c.CallTool(ctx, "ask", nil)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; text != "test-model: 42" {
+			t.Errorf("Expected the sampled answer, got %q", text)
+		}
+	})
+
+	t.Run("NotSupported", func(t *testing.T) {
+		result, err := connect(t, srv).CallTool(ctx, "ask", nil)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; !result.IsError || text != ErrSamplingNotSupported.Error() {
+			t.Errorf("Expected ErrSamplingNotSupported, got %q", text)
+		}
+	})
+}
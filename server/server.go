@@ -0,0 +1,479 @@
+// Package server implements the server side of the Model Context Protocol (MCP).
+//
+// A Server holds the registered tools, resources and prompts and answers the
+// protocol's requests; ServeStdio and NewHTTPHandler expose it over the stdio
+// and Streamable HTTP transports:
+//
+//	srv := server.NewServer("weather", "1.0.0")
+//	srv.AddTool(mcp.Tool{Name: "forecast"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+//		return mcp.NewToolResultText("sunny"), nil
+//	})
+//	http.ListenAndServe(":8080", server.NewHTTPHandler(srv))
+//
+// The package also provides building blocks for multi-tenant deployments,
+// such as SessionStore, EventStore and the quotas set with WithQuotas.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcp/jsonschema"
+)
+
+// supportedProtocolVersions are the protocol revisions the server speaks,
+// latest first.
+var supportedProtocolVersions = []string{mcp.LATEST_PROTOCOL_VERSION, "2024-11-05"}
+
+// ToolHandlerFunc answers a tools/call request. Errors of type *mcp.Error are
+// sent as JSON-RPC errors; other errors are reported to the model as a tool
+// result with isError set, as the spec asks for tool execution failures.
+type ToolHandlerFunc func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+
+// DefaultListChangedDelay is how long a server waits after a change to its
+// tools, prompts or resources before notifying the connected sessions, so
+// that a burst of changes sends a single notification.
+const DefaultListChangedDelay = 50 * time.Millisecond
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithInstructions sets the instructions returned by initialize, describing
+// to the model how to use the server.
+func WithInstructions(instructions string) ServerOption {
+	return func(s *Server) {
+		s.instructions = instructions
+	}
+}
+
+// WithListChangedDelay sets how long the server waits after a change to its
+// tools, prompts or resources before sending list_changed notifications.
+// It defaults to DefaultListChangedDelay.
+func WithListChangedDelay(delay time.Duration) ServerOption {
+	return func(s *Server) {
+		s.listChangedDelay = delay
+	}
+}
+
+// WithValidator sets the validator checking the arguments of tool calls
+// against the input schema of the tool, before the handler runs. It defaults
+// to jsonschema.Default; jsonschema.Nop disables validation.
+func WithValidator(validator jsonschema.Validator) ServerOption {
+	return func(s *Server) {
+		s.validator = validator
+	}
+}
+
+// Server is an MCP server. Register its features, then serve it with
+// ServeStdio or NewHTTPHandler. Features may also be added and removed while
+// serving; the connected sessions are then sent list_changed notifications.
+type Server struct {
+	name         string
+	version      string
+	instructions string
+	pageSize     int
+	validator    jsonschema.Validator
+	// quotas limit the tool calls, if set
+	quotas *QuotaEnforcer
+
+	mu                sync.RWMutex
+	tools             []serverTool
+	resources         []serverResource
+	resourceTemplates []serverResourceTemplate
+	prompts           []serverPrompt
+
+	sessionsMu sync.Mutex
+	// sessions are the sessions connected to this process
+	sessions map[*session]struct{}
+	// subscribers count the sessions subscribed to each resource URI
+	subscribers map[string]int
+	// subscriptionHooks are told of the URIs gaining their first subscriber
+	// or losing their last
+	subscriptionHooks []func(uri string)
+
+	listChangedDelay time.Duration
+	changedMu        sync.Mutex
+	// changed are the list_changed notifications waiting for changedTimer
+	changed      map[mcp.MCPMethod]bool
+	changedTimer *time.Timer
+
+	shutdownMu sync.Mutex
+	closing    bool
+	// active counts the requests in flight
+	active int
+	// idle is closed once no request is in flight while closing
+	idle          chan struct{}
+	shutdownHooks []func(ctx context.Context)
+	// done is closed once the server is shut down
+	done chan struct{}
+}
+
+// serverTool is a registered tool.
+type serverTool struct {
+	tool    mcp.Tool
+	handler ToolHandlerFunc
+}
+
+// NewServer creates a server identifying itself with name and version.
+func NewServer(name, version string, opts ...ServerOption) *Server {
+	s := &Server{
+		name:             name,
+		version:          version,
+		sessions:         map[*session]struct{}{},
+		subscribers:      map[string]int{},
+		listChangedDelay: DefaultListChangedDelay,
+		pageSize:         DefaultPageSize,
+		validator:        jsonschema.Default,
+		changed:          map[mcp.MCPMethod]bool{},
+		idle:             make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// AddTool registers a tool served by tools/list and tools/call, replacing any
+// tool of the same name. A tool without an input schema accepts an object.
+func (s *Server) AddTool(tool mcp.Tool, handler ToolHandlerFunc) {
+	if len(tool.InputSchema) == 0 {
+		tool.InputSchema = json.RawMessage(`{"type":"object"}`)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.listChanged(mcp.MethodNotificationToolsListChanged)
+	registered := serverTool{tool: tool, handler: handler}
+	for i, t := range s.tools {
+		if t.tool.Name == tool.Name {
+			s.tools[i] = registered
+			return
+		}
+	}
+	s.tools = append(s.tools, registered)
+}
+
+// RemoveTool unregisters the tools with the given names.
+func (s *Server) RemoveTool(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.tools)
+	s.tools = slices.DeleteFunc(s.tools, func(t serverTool) bool {
+		return slices.Contains(names, t.tool.Name)
+	})
+	if len(s.tools) != n {
+		s.listChanged(mcp.MethodNotificationToolsListChanged)
+	}
+}
+
+// addSession registers a connected session, to receive notifications.
+func (s *Server) addSession(sess *session) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	s.sessions[sess] = struct{}{}
+}
+
+// removeSession unregisters a session that ended, dropping its quotas and
+// subscriptions.
+func (s *Server) removeSession(sess *session) {
+	s.sessionsMu.Lock()
+	var changed []string
+	if _, ok := s.sessions[sess]; ok {
+		delete(s.sessions, sess)
+		for _, uri := range sess.unsubscribeAll() {
+			if s.countSubscriber(uri, -1) {
+				changed = append(changed, uri)
+			}
+		}
+	}
+	hooks := s.subscriptionHooks
+	s.sessionsMu.Unlock()
+	for _, uri := range changed {
+		for _, hook := range hooks {
+			hook(uri)
+		}
+	}
+	if s.quotas != nil && sess.quotaID != "" {
+		s.quotas.ForgetSession(sess.quotaID)
+	}
+}
+
+// connectedSessions returns the sessions connected to this process.
+func (s *Server) connectedSessions() []*session {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	sessions := make([]*session, 0, len(s.sessions))
+	for sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	return sessions
+}
+
+// listChanged schedules a list_changed notification to the connected
+// sessions. Changes made within the list changed delay are notified once.
+func (s *Server) listChanged(method mcp.MCPMethod) {
+	if len(s.connectedSessions()) == 0 {
+		// Sessions list the features when they connect
+		return
+	}
+	s.changedMu.Lock()
+	defer s.changedMu.Unlock()
+	s.changed[method] = true
+	if s.changedTimer == nil {
+		s.changedTimer = time.AfterFunc(s.listChangedDelay, s.sendListChanged)
+	}
+}
+
+// sendListChanged sends the scheduled list_changed notifications.
+func (s *Server) sendListChanged() {
+	s.changedMu.Lock()
+	changed := s.changed
+	s.changed = map[mcp.MCPMethod]bool{}
+	s.changedTimer = nil
+	s.changedMu.Unlock()
+
+	ctx := context.Background()
+	for _, sess := range s.connectedSessions() {
+		for _, method := range []mcp.MCPMethod{
+			mcp.MethodNotificationToolsListChanged,
+			mcp.MethodNotificationPromptsListChanged,
+			mcp.MethodNotificationResourcesListChanged,
+		} {
+			if changed[method] {
+				_ = sess.notify(ctx, method, nil)
+			}
+		}
+	}
+}
+
+// message is a JSON-RPC request, notification or response.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      mcp.RequestId   `json:"id,omitzero"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *mcp.Error      `json:"error,omitempty"`
+}
+
+func (m *message) isRequest() bool {
+	return m.Method != "" && !m.ID.IsNil()
+}
+
+func (m *message) isNotification() bool {
+	return m.Method != "" && m.ID.IsNil()
+}
+
+// handle processes a message received on a session and returns the response
+// to send, or nil when the message calls for none.
+func (s *Server) handle(ctx context.Context, sess *session, msg *message) *message {
+	if msg.isNotification() {
+		s.handleNotification(sess, msg)
+		return nil
+	}
+	if !msg.isRequest() {
+		sess.resolve(msg)
+		return nil
+	}
+
+	if !s.startRequest() {
+		return &message{JSONRPC: mcp.JSONRPC_VERSION, ID: msg.ID, Error: mcp.NewError(mcp.ErrorInternalError, ErrServerClosed.Error())}
+	}
+	defer s.endRequest()
+
+	ctx, done := sess.track(withProgressToken(withSession(ctx, sess), msg.Params), msg.ID)
+	defer done()
+
+	response := &message{JSONRPC: mcp.JSONRPC_VERSION, ID: msg.ID}
+	result, err := s.dispatch(ctx, sess, msg)
+	if err != nil {
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = mcp.NewError(mcp.ErrorInternalError, err.Error())
+		}
+		response.Error = rpcErr
+		return response
+	}
+	response.Result = result
+	return response
+}
+
+func (s *Server) handleNotification(sess *session, msg *message) {
+	switch mcp.MCPMethod(msg.Method) {
+	case mcp.MethodNotificationInitialized:
+		sess.setInitialized()
+	case mcp.MethodNotificationCancelled:
+		var params struct {
+			RequestID mcp.RequestId `json:"requestId"`
+		}
+		if json.Unmarshal(msg.Params, &params) == nil {
+			sess.cancel(params.RequestID)
+		}
+	}
+}
+
+// dispatch answers a request with a result to be marshaled, or an error.
+// Until the client sends notifications/initialized, only initialize and ping
+// are answered.
+func (s *Server) dispatch(ctx context.Context, sess *session, msg *message) (any, error) {
+	method := mcp.MCPMethod(msg.Method)
+	if method != mcp.MethodInitialize && method != mcp.MethodPing && !sess.isInitialized() {
+		return nil, mcp.NewError(mcp.ErrorInvalidRequest, fmt.Sprintf("session not initialized: %s sent before notifications/initialized", msg.Method))
+	}
+	switch method {
+	case mcp.MethodInitialize:
+		return s.initialize(sess, msg.Params)
+	case mcp.MethodPing:
+		return struct{}{}, nil
+	case mcp.MethodToolsList:
+		return s.listTools(msg.Params)
+	case mcp.MethodToolsCall:
+		return s.callTool(ctx, msg.Params)
+	case mcp.MethodResourcesList:
+		return s.listResources(msg.Params)
+	case mcp.MethodResourcesTemplatesList:
+		return s.listResourceTemplates(msg.Params)
+	case mcp.MethodResourcesRead:
+		return s.readResource(ctx, msg.Params)
+	case mcp.MethodResourcesSubscribe:
+		return s.subscribe(sess, msg.Params)
+	case mcp.MethodResourcesUnsubscribe:
+		return s.unsubscribe(sess, msg.Params)
+	case mcp.MethodPromptsList:
+		return s.listPrompts(msg.Params)
+	case mcp.MethodPromptsGet:
+		return s.getPrompt(ctx, msg.Params)
+	case mcp.MethodCompleteList:
+		return s.complete(ctx, msg.Params)
+	case mcp.MethodLoggingSetLevel:
+		return setLogLevel(sess, msg.Params)
+	}
+	return nil, mcp.NewError(mcp.ErrorMethodNotFound, fmt.Sprintf("method not found: %s", msg.Method))
+}
+
+func (s *Server) initialize(sess *session, params json.RawMessage) (*mcp.InitializeResult, error) {
+	var request mcp.InitializeRequest
+	if err := decodeParams(params, &request.Params); err != nil {
+		return nil, err
+	}
+
+	// Answer with the requested version when supported, else the latest
+	version := supportedProtocolVersions[0]
+	if slices.Contains(supportedProtocolVersions, request.Params.ProtocolVersion) {
+		version = request.Params.ProtocolVersion
+	}
+	sess.setClient(version, request.Params.ClientInfo, request.Params.Capabilities)
+
+	return &mcp.InitializeResult{
+		ProtocolVersion: version,
+		Capabilities:    s.capabilities(),
+		ServerInfo:      mcp.Implementation{Name: s.name, Version: s.version},
+		Instructions:    s.instructions,
+	}, nil
+}
+
+// capabilities declares the features that have been registered.
+func (s *Server) capabilities() mcp.ServerCapabilities {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	capabilities := mcp.ServerCapabilities{Logging: &mcp.LoggingCapabilities{}}
+	if len(s.tools) > 0 {
+		capabilities.Tools = &mcp.ToolsCapabilities{ListChanged: true}
+	}
+	if len(s.resources) > 0 || len(s.resourceTemplates) > 0 {
+		capabilities.Resources = &mcp.ResourcesCapabilities{Subscribe: true, ListChanged: true}
+	}
+	if len(s.prompts) > 0 {
+		capabilities.Prompts = &mcp.PromptsCapabilities{ListChanged: true}
+	}
+	if s.hasCompletions() {
+		capabilities.Completions = &mcp.CompletionsCapabilities{}
+	}
+	return capabilities
+}
+
+func (s *Server) listTools(params json.RawMessage) (*mcp.ListToolsResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	start, end, next, err := s.paginate(params, len(s.tools))
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]mcp.Tool, 0, end-start)
+	for _, t := range s.tools[start:end] {
+		tools = append(tools, t.tool)
+	}
+	result := &mcp.ListToolsResult{Tools: tools}
+	result.NextCursor = next
+	return result, nil
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (*mcp.CallToolResult, error) {
+	request := mcp.CallToolRequest{Method: string(mcp.MethodToolsCall)}
+	if err := decodeParams(params, &request.Params); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var registered *serverTool
+	for i, t := range s.tools {
+		if t.tool.Name == request.Params.Name {
+			registered = &s.tools[i]
+		}
+	}
+	var tool mcp.Tool
+	var handler ToolHandlerFunc
+	if registered != nil {
+		tool, handler = registered.tool, registered.handler
+	}
+	s.mu.RUnlock()
+	if handler == nil {
+		return nil, mcp.NewError(mcp.ErrorToolNotFound, fmt.Sprintf("tool not found: %s", request.Params.Name))
+	}
+	if request.Params.Arguments == nil {
+		request.Params.Arguments = map[string]any{}
+	}
+	if err := s.validator.Validate(tool.InputSchema, request.Params.Arguments); err != nil {
+		result := mcp.NewToolResultText(fmt.Sprintf("invalid arguments: %v", err))
+		result.IsError = true
+		return result, nil
+	}
+	if err := s.allowCall(ctx, request.Params.Name); err != nil {
+		return nil, err
+	}
+
+	result, err := handler(ctx, request)
+	if err != nil {
+		var rpcErr *mcp.Error
+		if errors.As(err, &rpcErr) {
+			return nil, rpcErr
+		}
+		result = mcp.NewToolResultText(err.Error())
+		result.IsError = true
+	}
+	if result == nil {
+		result = &mcp.CallToolResult{}
+	}
+	if result.Content == nil {
+		result.Content = []mcp.Content{}
+	}
+	return result, nil
+}
+
+// decodeParams decodes the params of a request into v, which is left as is
+// when there are none.
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return mcp.NewError(mcp.ErrorInvalidParams, fmt.Sprintf("invalid params: %v", err))
+	}
+	return nil
+}
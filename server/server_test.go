@@ -0,0 +1,500 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcp/jsonschema"
+)
+
+func newTestServer() *Server {
+	srv := NewServer("test-server", "1.0.0", WithInstructions("Use echo to repeat text."))
+	srv.AddTool(mcp.Tool{Name: "echo", Description: "Repeats text"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		text, _ := request.Params.Arguments["text"].(string)
+		return mcp.NewToolResultText(text), nil
+	})
+	srv.AddTool(mcp.Tool{Name: "fail"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("something went wrong")
+	})
+	return srv
+}
+
+// connect serves srv over HTTP and returns a client connected to it.
+func connect(t *testing.T, srv *Server) *client.HTTPClient {
+	t.Helper()
+	ts := httptest.NewServer(NewHTTPHandler(srv))
+	t.Cleanup(ts.Close)
+
+	c, err := client.NewHTTPClient(&client.Options{BaseURL: ts.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestHTTPHandler(t *testing.T) {
+	ts := httptest.NewServer(NewHTTPHandler(newTestServer()))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := client.NewHTTPClient(&client.Options{BaseURL: ts.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	t.Run("Initialize", func(t *testing.T) {
+		if info := c.ServerInfo(); info.Name != "test-server" || info.Version != "1.0.0" {
+			t.Errorf("Expected test-server 1.0.0, got %+v", info)
+		}
+		if c.ServerCapabilities().Tools == nil {
+			t.Error("Expected the tools capability")
+		}
+		if c.Instructions() != "Use echo to repeat text." {
+			t.Errorf("Expected instructions, got %q", c.Instructions())
+		}
+		if c.GetSessionID() == "" {
+			t.Error("Expected a session ID")
+		}
+	})
+
+	t.Run("ListTools", func(t *testing.T) {
+		result, err := c.ListTools(ctx, "")
+		if err != nil {
+			t.Fatalf("ListTools failed: %v", err)
+		}
+		if len(result.Tools) != 2 || result.Tools[0].Name != "echo" || result.Tools[1].Name != "fail" {
+			t.Fatalf("Expected tools echo and fail, got %+v", result.Tools)
+		}
+		if string(result.Tools[0].InputSchema) != `{"type":"object"}` {
+			t.Errorf("Expected default input schema, got %s", result.Tools[0].InputSchema)
+		}
+	})
+
+	t.Run("CallTool", func(t *testing.T) {
+		result, err := c.CallTool(ctx, "echo", map[string]any{"text": "hello"})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if len(result.Content) != 1 || result.Content[0].(mcp.TextContent).Text != "hello" {
+			t.Errorf("Expected hello, got %+v", result.Content)
+		}
+	})
+
+	t.Run("ToolError", func(t *testing.T) {
+		result, err := c.CallTool(ctx, "fail", nil)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if !result.IsError || result.Content[0].(mcp.TextContent).Text != "something went wrong" {
+			t.Errorf("Expected an error result, got %+v", result)
+		}
+	})
+
+	t.Run("UnknownTool", func(t *testing.T) {
+		_, err := c.CallTool(ctx, "missing", nil)
+		var rpcErr *mcp.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != mcp.ErrorToolNotFound {
+			t.Errorf("Expected ErrorToolNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Ping", func(t *testing.T) {
+		if _, err := c.Ping(ctx); err != nil {
+			t.Errorf("Ping failed: %v", err)
+		}
+	})
+
+	t.Run("MissingSession", func(t *testing.T) {
+		resp, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("InitializeWithoutID", func(t *testing.T) {
+		resp, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","method":"initialize"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", resp.StatusCode)
+		}
+		if id := resp.Header.Get(headerKeySessionID); id != "" {
+			t.Errorf("Expected no session to be created, got %q", id)
+		}
+	})
+
+	t.Run("NotInitialized", func(t *testing.T) {
+		post := func(sessionID, body string) (*http.Response, message) {
+			t.Helper()
+			req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+			if sessionID != "" {
+				req.Header.Set(headerKeySessionID, sessionID)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var response message
+			if resp.StatusCode == http.StatusOK {
+				if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+			}
+			return resp, response
+		}
+		resp, _ := post("", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1"},"capabilities":{}}}`)
+		sessionID := resp.Header.Get(headerKeySessionID)
+		if sessionID == "" {
+			t.Fatal("Expected a session ID")
+		}
+		if _, response := post(sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`); response.Error == nil || response.Error.Code != mcp.ErrorInvalidRequest {
+			t.Errorf("Expected a request before notifications/initialized to be rejected, got %+v", response)
+		}
+		if _, response := post(sessionID, `{"jsonrpc":"2.0","id":3,"method":"ping"}`); response.Error != nil {
+			t.Errorf("Expected ping to be answered before notifications/initialized, got %+v", response.Error)
+		}
+		post(sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+		if _, response := post(sessionID, `{"jsonrpc":"2.0","id":4,"method":"tools/list"}`); response.Error != nil {
+			t.Errorf("Expected the request after notifications/initialized to succeed, got %+v", response.Error)
+		}
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(
+			`[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"unknown"}]`))
+		req.Header.Set(headerKeySessionID, c.GetSessionID())
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var responses []message
+		if err := json.NewDecoder(resp.Body).Decode(&responses); err != nil {
+			t.Fatalf("Failed to decode batch response: %v", err)
+		}
+		if len(responses) != 2 || responses[0].Error != nil || responses[1].Error == nil || responses[1].Error.Code != mcp.ErrorMethodNotFound {
+			t.Errorf("Expected a result and a method not found error, got %+v", responses)
+		}
+	})
+
+	t.Run("Gzip", func(t *testing.T) {
+		post := func(encoding string) *http.Response {
+			var body bytes.Buffer
+			zw := gzip.NewWriter(&body)
+			zw.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+			zw.Close()
+			req, _ := http.NewRequest(http.MethodPost, ts.URL, &body)
+			req.Header.Set("Content-Encoding", encoding)
+			req.Header.Set(headerKeySessionID, c.GetSessionID())
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			return resp
+		}
+		if resp := post("gzip"); resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected 200 for a gzipped request, got %d", resp.StatusCode)
+		}
+		if resp := post("br"); resp.StatusCode != http.StatusUnsupportedMediaType {
+			t.Errorf("Expected 415 for an unknown encoding, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		get := func() *http.Response {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+			req.Header.Set("Accept", "text/event-stream")
+			req.Header.Set(headerKeySessionID, c.GetSessionID())
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return resp
+		}
+		stream := get()
+		defer stream.Body.Close()
+		if stream.StatusCode != http.StatusOK || stream.Header.Get("Content-Type") != "text/event-stream" {
+			t.Errorf("Expected an event stream, got %d %q", stream.StatusCode, stream.Header.Get("Content-Type"))
+		}
+		second := get()
+		second.Body.Close()
+		if second.StatusCode != http.StatusConflict {
+			t.Errorf("Expected 409 for a second stream, got %d", second.StatusCode)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, ts.URL, nil)
+		req.Header.Set(headerKeySessionID, c.GetSessionID())
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected 200, got %d", resp.StatusCode)
+		}
+		if _, err := c.Ping(ctx); err == nil {
+			t.Error("Expected an error after the session was deleted")
+		}
+	})
+}
+
+func TestServeStdio(t *testing.T) {
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1"},"capabilities":{}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":"call","method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+	}, "\n") + "\n"
+
+	r, w := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- newTestServer().ServeStdio(context.Background(), strings.NewReader(input), w)
+		w.Close()
+	}()
+
+	responses := map[string]map[string]any{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var response map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			t.Fatalf("Invalid response %q: %v", scanner.Text(), err)
+		}
+		id, _ := json.Marshal(response["id"])
+		responses[string(id)] = response
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("ServeStdio failed: %v", err)
+	}
+
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %v", responses)
+	}
+	initResult, _ := responses["1"]["result"].(map[string]any)
+	if initResult["protocolVersion"] != "2024-11-05" {
+		t.Errorf("Expected protocol version 2024-11-05, got %v", initResult["protocolVersion"])
+	}
+	parseError, _ := responses["null"]["error"].(map[string]any)
+	if parseError["code"] != float64(mcp.ErrorParseError) {
+		t.Errorf("Expected parse error, got %v", responses["null"])
+	}
+	callResult, _ := json.Marshal(responses[`"call"`]["result"])
+	if string(callResult) != `{"content":[{"text":"hi","type":"text"}]}` {
+		t.Errorf("Expected echoed text, got %s", callResult)
+	}
+}
+
+func TestHTTPHandlerStateless(t *testing.T) {
+	ts := httptest.NewServer(NewHTTPHandler(newTestServer(), WithStateless()))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := client.NewHTTPClient(&client.Options{BaseURL: ts.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	if c.GetSessionID() != "" {
+		t.Errorf("Expected no session ID, got %q", c.GetSessionID())
+	}
+
+	t.Run("CallTool", func(t *testing.T) {
+		result, err := c.CallTool(ctx, "echo", map[string]any{"text": "hello"})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if result.Content[0].(mcp.TextContent).Text != "hello" {
+			t.Errorf("Expected hello, got %+v", result.Content)
+		}
+	})
+
+	t.Run("WithoutInitialize", func(t *testing.T) {
+		resp, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var response message
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK || response.Error != nil || resp.Header.Get(headerKeySessionID) != "" {
+			t.Errorf("Expected a result without session, got %d %+v", resp.StatusCode, response)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, ts.URL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "POST" {
+			t.Errorf("Expected 405 allowing POST, got %d %q", resp.StatusCode, resp.Header.Get("Allow"))
+		}
+	})
+}
+
+func TestListChanged(t *testing.T) {
+	srv := newTestServer()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.ServeStdio(context.Background(), inR, outW)
+		outW.Close()
+	}()
+	defer func() {
+		inW.Close()
+		if err := <-done; err != nil {
+			t.Errorf("ServeStdio failed: %v", err)
+		}
+	}()
+
+	messages := bufio.NewScanner(outR)
+	next := func() map[string]any {
+		t.Helper()
+		if !messages.Scan() {
+			t.Fatalf("Expected a message: %v", messages.Err())
+		}
+		var msg map[string]any
+		if err := json.Unmarshal(messages.Bytes(), &msg); err != nil {
+			t.Fatalf("Invalid message %q: %v", messages.Text(), err)
+		}
+		return msg
+	}
+
+	fmt.Fprintln(inW, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1"},"capabilities":{}}}`)
+	initResult, _ := next()["result"].(map[string]any)
+	capabilities, _ := json.Marshal(initResult["capabilities"])
+	if string(capabilities) != `{"logging":{},"tools":{"listChanged":true}}` {
+		t.Errorf("Expected tools.listChanged, got %s", capabilities)
+	}
+
+	t.Run("Tools", func(t *testing.T) {
+		handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, nil
+		}
+		srv.AddTool(mcp.Tool{Name: "a"}, handler)
+		srv.AddTool(mcp.Tool{Name: "b"}, handler)
+		srv.RemoveTool("a")
+		if msg := next(); msg["method"] != string(mcp.MethodNotificationToolsListChanged) {
+			t.Errorf("Expected a tools/list_changed notification, got %v", msg)
+		}
+	})
+
+	t.Run("Prompts", func(t *testing.T) {
+		// Removing unknown features changes nothing
+		srv.RemoveTool("unknown")
+		srv.RemoveResource("file:///unknown")
+		srv.AddPrompt(mcp.Prompt{Name: "p"}, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return nil, nil
+		})
+		if msg := next(); msg["method"] != string(mcp.MethodNotificationPromptsListChanged) {
+			t.Errorf("Expected a prompts/list_changed notification, got %v", msg)
+		}
+	})
+
+	t.Run("Resources", func(t *testing.T) {
+		srv.AddResource(mcp.Resource{URI: "file:///a", Name: "a"}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, nil
+		})
+		srv.RemovePrompt("p")
+		notified := map[any]bool{next()["method"]: true, next()["method"]: true}
+		if !notified[string(mcp.MethodNotificationResourcesListChanged)] || !notified[string(mcp.MethodNotificationPromptsListChanged)] {
+			t.Errorf("Expected resources and prompts list_changed notifications, got %v", notified)
+		}
+	})
+}
+
+func TestWithValidator(t *testing.T) {
+	ctx := context.Background()
+	schema := json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"}},"required":["text"]}`)
+	echo := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		text, _ := request.Params.Arguments["text"].(string)
+		return mcp.NewToolResultText("echo:" + text), nil
+	}
+	call := func(srv *Server, arguments map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		c := connect(t, srv)
+		result, err := c.CallTool(ctx, "echo", arguments)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		return result
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		srv := NewServer("test-server", "1.0.0")
+		srv.AddTool(mcp.Tool{Name: "echo", InputSchema: schema}, echo)
+		result := call(srv, map[string]any{"text": 1})
+		if text := result.Content[0].(mcp.TextContent).Text; !result.IsError || !strings.Contains(text, "invalid arguments") {
+			t.Errorf("Expected the arguments to be rejected, got %q", text)
+		}
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		var validated []any
+		validator := jsonschema.ValidatorFunc(func(schema json.RawMessage, instance any) error {
+			validated = append(validated, instance)
+			return errors.New("rejected by policy")
+		})
+		srv := NewServer("test-server", "1.0.0", WithValidator(validator))
+		srv.AddTool(mcp.Tool{Name: "echo", InputSchema: schema}, echo)
+		result := call(srv, map[string]any{"text": "hi"})
+		if text := result.Content[0].(mcp.TextContent).Text; !result.IsError || !strings.Contains(text, "rejected by policy") {
+			t.Errorf("Expected the custom validator to reject the call, got %q", text)
+		}
+		if len(validated) != 1 {
+			t.Errorf("Expected the custom validator to be called once, got %d", len(validated))
+		}
+	})
+
+	t.Run("Nop", func(t *testing.T) {
+		srv := NewServer("test-server", "1.0.0", WithValidator(jsonschema.Nop))
+		srv.AddTool(mcp.Tool{Name: "echo", InputSchema: schema}, echo)
+		AddTypedTool(srv, "typed", "", func(ctx context.Context, args struct {
+			Count int `json:"count"`
+		}) (int, error) {
+			return args.Count, nil
+		})
+		c := connect(t, srv)
+		if result, err := c.CallTool(ctx, "echo", nil); err != nil || result.IsError {
+			t.Errorf("Expected the missing argument to be accepted, got %+v: %v", result, err)
+		}
+		if result, err := c.CallTool(ctx, "typed", nil); err != nil || result.IsError || result.Content[0].(mcp.TextContent).Text != "0" {
+			t.Errorf("Expected the typed tool to get zero arguments, got %+v: %v", result, err)
+		}
+	})
+}
@@ -0,0 +1,323 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// session is the state of a connection with a client: a stdio stream, or
+// the requests sharing an Mcp-Session-Id over HTTP.
+type session struct {
+	id string
+	// quotaID keys the session quotas: the ID, or a random one for stdio
+	// sessions, which have none
+	quotaID string
+
+	mu                 sync.Mutex
+	initialized        bool
+	protocolVersion    string
+	clientInfo         mcp.Implementation
+	clientCapabilities mcp.ClientCapabilities
+	// inFlight cancels the requests being handled, by ID
+	inFlight map[mcp.RequestId]context.CancelFunc
+	// out delivers the messages unrelated to a request
+	out sender
+	// subscriptions are the URIs of the resources the client subscribed to
+	subscriptions map[string]bool
+	// logLevel is the least severe level of the log messages to send
+	logLevel mcp.LoggingLevel
+	// lastRequestID numbers the requests sent to the client
+	lastRequestID int64
+	// pending receive the responses to the requests sent to the client, by ID
+	pending map[string]chan *message
+}
+
+func newSession(id string) *session {
+	return &session{
+		id:            id,
+		quotaID:       id,
+		inFlight:      map[mcp.RequestId]context.CancelFunc{},
+		subscriptions: map[string]bool{},
+		logLevel:      defaultLogLevel,
+		pending:       map[string]chan *message{},
+	}
+}
+
+type sessionKey struct{}
+
+// withSession returns a context carrying the session a request belongs to.
+func withSession(ctx context.Context, sess *session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+// sessionFromContext returns the session of the request handled in ctx, if any.
+func sessionFromContext(ctx context.Context) *session {
+	sess, _ := ctx.Value(sessionKey{}).(*session)
+	return sess
+}
+
+// newStatelessSession returns a session serving a single HTTP request of a
+// stateless handler.
+func newStatelessSession() *session {
+	s := newSession("")
+	s.initialized = true
+	return s
+}
+
+// newSessionID returns a random, globally unique session ID.
+func newSessionID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func (s *session) setClient(protocolVersion string, info mcp.Implementation, capabilities mcp.ClientCapabilities) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.protocolVersion = protocolVersion
+	s.clientInfo = info
+	s.clientCapabilities = capabilities
+}
+
+func (s *session) setInitialized() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initialized = true
+}
+
+// isInitialized reports whether the client has sent notifications/initialized.
+func (s *session) isInitialized() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.initialized
+}
+
+// track makes the request with id cancellable by notifications/cancelled
+// until done is called.
+func (s *session) track(ctx context.Context, id mcp.RequestId) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.inFlight[id] = cancel
+	s.mu.Unlock()
+	return ctx, func() {
+		s.mu.Lock()
+		delete(s.inFlight, id)
+		s.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel cancels the in-flight request with id, if any.
+func (s *session) cancel(id mcp.RequestId) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.inFlight[id]; ok {
+		cancel()
+	}
+}
+
+// cancelAll cancels every in-flight request, when the session ends.
+func (s *session) cancelAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cancel := range s.inFlight {
+		cancel()
+	}
+}
+
+// info returns the state of the session to persist in a SessionStore.
+func (s *session) info() SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SessionInfo{
+		ID:                 s.id,
+		ProtocolVersion:    s.protocolVersion,
+		ClientInfo:         s.clientInfo,
+		ClientCapabilities: s.clientCapabilities,
+	}
+}
+
+// notify sends a notification to the client, see deliver.
+func (s *session) notify(ctx context.Context, method mcp.MCPMethod, params any) error {
+	msg, err := newMessage(method, params)
+	if err != nil {
+		return err
+	}
+	return s.deliver(ctx, msg)
+}
+
+// request sends a request to the client, like notify, and decodes the result
+// of its response into result.
+func (s *session) request(ctx context.Context, method mcp.MCPMethod, params any, result any) error {
+	msg, err := newMessage(method, params)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.lastRequestID++
+	msg.ID = mcp.NewIntRequestId(s.lastRequestID)
+	responses := make(chan *message, 1)
+	s.pending[msg.ID.String()] = responses
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, msg.ID.String())
+		s.mu.Unlock()
+	}()
+
+	if err := s.deliver(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+	select {
+	case response := <-responses:
+		if response.Error != nil {
+			return response.Error
+		}
+		data, err := json.Marshal(response.Result)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s result: %w", method, err)
+		}
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("failed to decode %s result: %w", method, err)
+		}
+		return nil
+	case <-ctx.Done():
+		_ = s.notify(context.WithoutCancel(ctx), mcp.MethodNotificationCancelled, map[string]any{
+			"requestId": msg.ID,
+			"reason":    ctx.Err().Error(),
+		})
+		return ctx.Err()
+	}
+}
+
+// resolve passes a response of the client to the request awaiting it.
+func (s *session) resolve(response *message) {
+	s.mu.Lock()
+	responses := s.pending[response.ID.String()]
+	s.mu.Unlock()
+	if responses != nil {
+		select {
+		case responses <- response:
+		default:
+		}
+	}
+}
+
+// deliver sends a message to the client: on the stream of the request
+// handled in ctx if any, else on the session's stream.
+func (s *session) deliver(ctx context.Context, msg *message) error {
+	if out := requestStream(ctx); out != nil {
+		return out.send(ctx, msg)
+	}
+	return s.send(ctx, msg)
+}
+
+// send sends a message on the session's stream.
+func (s *session) send(ctx context.Context, msg *message) error {
+	s.mu.Lock()
+	out := s.out
+	s.mu.Unlock()
+	if out == nil {
+		return errNoStream
+	}
+	return out.send(ctx, msg)
+}
+
+// subscribe records the client's subscription to the resource with uri, or
+// its end, reporting whether it changed.
+func (s *session) subscribe(uri string, subscribed bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscriptions[uri] == subscribed {
+		return false
+	}
+	if subscribed {
+		s.subscriptions[uri] = true
+	} else {
+		delete(s.subscriptions, uri)
+	}
+	return true
+}
+
+// unsubscribeAll ends the subscriptions of the client, returning their URIs.
+func (s *session) unsubscribeAll() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uris := slices.Collect(maps.Keys(s.subscriptions))
+	clear(s.subscriptions)
+	return uris
+}
+
+// subscribed reports whether the client subscribed to the resource with uri.
+func (s *session) subscribed(uri string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscriptions[uri]
+}
+
+// setLogLevel sets the least severe level of the log messages to send.
+func (s *session) setLogLevel(level mcp.LoggingLevel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logLevel = level
+}
+
+// logs reports whether log messages at level are sent to the client.
+func (s *session) logs(level mcp.LoggingLevel) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Index(logLevels, level) >= slices.Index(logLevels, s.logLevel)
+}
+
+// ClientSession is the session of a connected client, as seen by the
+// handlers of its requests to make per-client decisions. The principal
+// authenticated by the HTTP request is given by TokenInfoFromContext.
+type ClientSession struct {
+	sess *session
+}
+
+// SessionFromContext returns the session whose request is handled in ctx,
+// or nil outside of a request.
+func SessionFromContext(ctx context.Context) *ClientSession {
+	sess := sessionFromContext(ctx)
+	if sess == nil {
+		return nil
+	}
+	return &ClientSession{sess: sess}
+}
+
+// ID returns the Mcp-Session-Id of the session, empty over stdio and in
+// stateless mode.
+func (c *ClientSession) ID() string {
+	return c.sess.id
+}
+
+// ProtocolVersion returns the protocol version negotiated by initialize.
+func (c *ClientSession) ProtocolVersion() string {
+	c.sess.mu.Lock()
+	defer c.sess.mu.Unlock()
+	return c.sess.protocolVersion
+}
+
+// ClientInfo returns the name and version the client gave in initialize.
+func (c *ClientSession) ClientInfo() mcp.Implementation {
+	c.sess.mu.Lock()
+	defer c.sess.mu.Unlock()
+	return c.sess.clientInfo
+}
+
+// ClientCapabilities returns the capabilities the client declared in
+// initialize.
+func (c *ClientSession) ClientCapabilities() mcp.ClientCapabilities {
+	c.sess.mu.Lock()
+	defer c.sess.mu.Unlock()
+	return c.sess.clientCapabilities
+}
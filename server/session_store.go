@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// ErrSessionNotFound is returned by SessionStore when a session is unknown or
+// has expired.
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionInfo is the state of a session shared between server replicas.
+type SessionInfo struct {
+	ID                 string                 `json:"id"`
+	ProtocolVersion    string                 `json:"protocolVersion"`
+	ClientInfo         mcp.Implementation     `json:"clientInfo"`
+	ClientCapabilities mcp.ClientCapabilities `json:"clientCapabilities"`
+	CreatedAt          time.Time              `json:"createdAt"`
+	LastSeen           time.Time              `json:"lastSeen"`
+}
+
+// SessionStore persists the sessions of a Streamable HTTP server, so that
+// replicas behind a load balancer can serve each other's sessions.
+// Implementations must be safe for concurrent use.
+type SessionStore interface {
+	// Create stores a new session.
+	Create(ctx context.Context, info SessionInfo) error
+
+	// Touch records activity on a session, extending its lifetime, or returns
+	// ErrSessionNotFound.
+	Touch(ctx context.Context, sessionID string) error
+
+	// Get returns a session, or ErrSessionNotFound.
+	Get(ctx context.Context, sessionID string) (*SessionInfo, error)
+
+	// Delete discards a session. Deleting an unknown session is not an error.
+	Delete(ctx context.Context, sessionID string) error
+
+	// Sessions returns the live sessions.
+	Sessions(ctx context.Context) ([]SessionInfo, error)
+}
+
+// MemorySessionStore is a SessionStore keeping sessions in memory, for servers
+// running as a single replica.
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]*SessionInfo
+	// now is overridden by tests
+	now func() time.Time
+}
+
+// NewMemorySessionStore creates an in-memory SessionStore expiring sessions
+// idle for longer than ttl. A ttl of zero or less keeps sessions until deleted.
+func NewMemorySessionStore(ttl time.Duration) *MemorySessionStore {
+	return &MemorySessionStore{
+		ttl:      ttl,
+		sessions: make(map[string]*SessionInfo),
+		now:      time.Now,
+	}
+}
+
+// Create implements SessionStore.
+func (s *MemorySessionStore) Create(ctx context.Context, info SessionInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = now
+	}
+	info.LastSeen = now
+	s.sessions[info.ID] = &info
+	return nil
+}
+
+// Touch implements SessionStore.
+func (s *MemorySessionStore) Touch(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := s.lookup(sessionID)
+	if err != nil {
+		return err
+	}
+	info.LastSeen = s.now()
+	return nil
+}
+
+// Get implements SessionStore.
+func (s *MemorySessionStore) Get(ctx context.Context, sessionID string) (*SessionInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, err := s.lookup(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	copied := *info
+	return &copied, nil
+}
+
+// Delete implements SessionStore.
+func (s *MemorySessionStore) Delete(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+// Sessions implements SessionStore. Sessions are sorted by creation time.
+func (s *MemorySessionStore) Sessions(ctx context.Context) ([]SessionInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessions := make([]SessionInfo, 0, len(s.sessions))
+	for id := range s.sessions {
+		if info, err := s.lookup(id); err == nil {
+			sessions = append(sessions, *info)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].CreatedAt.Before(sessions[j].CreatedAt) })
+	return sessions, nil
+}
+
+// lookup returns a live session, dropping it if it has expired. s.mu must be held.
+func (s *MemorySessionStore) lookup(sessionID string) (*SessionInfo, error) {
+	info, ok := s.sessions[sessionID]
+	if ok && s.ttl > 0 && s.now().Sub(info.LastSeen) > s.ttl {
+		delete(s.sessions, sessionID)
+		ok = false
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
+	}
+	return info, nil
+}
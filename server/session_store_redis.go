@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RedisClient runs a Redis command and returns its reply: nil, an int64, a
+// string or []byte, or a []any for arrays. It matches the Do method of common
+// Redis clients; go-redis, for instance, is adapted with:
+//
+//	server.RedisFunc(func(ctx context.Context, args ...any) (any, error) {
+//		return rdb.Do(ctx, args...).Result()
+//	})
+type RedisClient interface {
+	Do(ctx context.Context, args ...any) (any, error)
+}
+
+// RedisFunc adapts a function to RedisClient.
+type RedisFunc func(ctx context.Context, args ...any) (any, error)
+
+// Do implements RedisClient.
+func (f RedisFunc) Do(ctx context.Context, args ...any) (any, error) {
+	return f(ctx, args...)
+}
+
+// DefaultRedisKeyPrefix prefixes the keys of the sessions stored by
+// RedisSessionStore unless WithRedisKeyPrefix is given.
+const DefaultRedisKeyPrefix = "mcp:session:"
+
+// touchScript updates the last activity of a session and extends its TTL,
+// atomically so that a deleted session is not recreated.
+const touchScript = `if redis.call('EXISTS', KEYS[1]) == 0 then return 0 end
+redis.call('HSET', KEYS[1], 'lastSeen', ARGV[1])
+if tonumber(ARGV[2]) > 0 then redis.call('PEXPIRE', KEYS[1], ARGV[2]) end
+return 1`
+
+// RedisSessionStore is a SessionStore keeping sessions in Redis, shared by
+// every replica of a server. Each session is a hash whose TTL is extended on
+// activity.
+type RedisSessionStore struct {
+	client RedisClient
+	ttl    time.Duration
+	prefix string
+}
+
+// RedisSessionStoreOption configures a RedisSessionStore.
+type RedisSessionStoreOption func(*RedisSessionStore)
+
+// WithRedisKeyPrefix sets the prefix of the session keys, to share a Redis
+// database between servers.
+func WithRedisKeyPrefix(prefix string) RedisSessionStoreOption {
+	return func(s *RedisSessionStore) {
+		s.prefix = prefix
+	}
+}
+
+// NewRedisSessionStore creates a SessionStore keeping sessions in Redis and
+// expiring those idle for longer than ttl. A ttl of zero or less keeps
+// sessions until deleted.
+func NewRedisSessionStore(client RedisClient, ttl time.Duration, opts ...RedisSessionStoreOption) *RedisSessionStore {
+	s := &RedisSessionStore{client: client, ttl: ttl, prefix: DefaultRedisKeyPrefix}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create implements SessionStore.
+func (s *RedisSessionStore) Create(ctx context.Context, info SessionInfo) error {
+	now := time.Now()
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = now
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	key := s.prefix + info.ID
+	if _, err := s.client.Do(ctx, "HSET", key, "info", string(data), "lastSeen", now.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	if s.ttl > 0 {
+		if _, err := s.client.Do(ctx, "PEXPIRE", key, s.ttl.Milliseconds()); err != nil {
+			return fmt.Errorf("failed to set session TTL: %w", err)
+		}
+	}
+	return nil
+}
+
+// Touch implements SessionStore.
+func (s *RedisSessionStore) Touch(ctx context.Context, sessionID string) error {
+	reply, err := s.client.Do(ctx, "EVAL", touchScript, 1, s.prefix+sessionID, time.Now().UnixMilli(), s.ttl.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	if n, _ := reply.(int64); n == 0 {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
+	}
+	return nil
+}
+
+// Get implements SessionStore.
+func (s *RedisSessionStore) Get(ctx context.Context, sessionID string) (*SessionInfo, error) {
+	return s.get(ctx, s.prefix+sessionID)
+}
+
+func (s *RedisSessionStore) get(ctx context.Context, key string) (*SessionInfo, error) {
+	reply, err := s.client.Do(ctx, "HMGET", key, "info", "lastSeen")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	fields, _ := reply.([]any)
+	if len(fields) != 2 || fields[0] == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, key[len(s.prefix):])
+	}
+
+	var info SessionInfo
+	if err := json.Unmarshal([]byte(redisString(fields[0])), &info); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	if ms, err := strconv.ParseInt(redisString(fields[1]), 10, 64); err == nil {
+		info.LastSeen = time.UnixMilli(ms)
+	}
+	return &info, nil
+}
+
+// Delete implements SessionStore.
+func (s *RedisSessionStore) Delete(ctx context.Context, sessionID string) error {
+	if _, err := s.client.Do(ctx, "DEL", s.prefix+sessionID); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// Sessions implements SessionStore. It scans the keys with the store's
+// prefix, so its cost grows with the size of the database.
+func (s *RedisSessionStore) Sessions(ctx context.Context) ([]SessionInfo, error) {
+	var sessions []SessionInfo
+	cursor := "0"
+	for {
+		reply, err := s.client.Do(ctx, "SCAN", cursor, "MATCH", s.prefix+"*", "COUNT", 100)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sessions: %w", err)
+		}
+		page, _ := reply.([]any)
+		if len(page) != 2 {
+			return nil, fmt.Errorf("failed to scan sessions: unexpected reply %v", reply)
+		}
+		keys, _ := page[1].([]any)
+		for _, key := range keys {
+			info, err := s.get(ctx, redisString(key))
+			if errors.Is(err, ErrSessionNotFound) {
+				// The session expired or was deleted during the scan
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			sessions = append(sessions, *info)
+		}
+		if cursor = redisString(page[0]); cursor == "0" {
+			return sessions, nil
+		}
+	}
+}
+
+// redisString returns a bulk string reply as a string.
+func redisString(reply any) string {
+	switch v := reply.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprint(reply)
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestMemorySessionStore(t *testing.T) {
+	testSessionStore(t, NewMemorySessionStore(0))
+
+	t.Run("Expiry", func(t *testing.T) {
+		ctx := context.Background()
+		now := time.Unix(1000, 0)
+		store := NewMemorySessionStore(time.Minute)
+		store.now = func() time.Time { return now }
+
+		if err := store.Create(ctx, SessionInfo{ID: "s1"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		now = now.Add(50 * time.Second)
+		if err := store.Touch(ctx, "s1"); err != nil {
+			t.Fatalf("Touch failed: %v", err)
+		}
+		now = now.Add(50 * time.Second)
+		if _, err := store.Get(ctx, "s1"); err != nil {
+			t.Errorf("Expected the touched session to be alive, got %v", err)
+		}
+		now = now.Add(2 * time.Minute)
+		if _, err := store.Get(ctx, "s1"); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Expected ErrSessionNotFound, got %v", err)
+		}
+	})
+}
+
+func TestRedisSessionStore(t *testing.T) {
+	redis := newFakeRedis()
+	testSessionStore(t, NewRedisSessionStore(redis, time.Hour, WithRedisKeyPrefix("test:")))
+
+	if ttl := redis.ttls["test:s1"]; ttl != time.Hour.Milliseconds() {
+		t.Errorf("Expected TTL of 1h, got %dms", ttl)
+	}
+}
+
+// testSessionStore checks the behavior common to SessionStore implementations.
+func testSessionStore(t *testing.T, store SessionStore) {
+	ctx := context.Background()
+	info := SessionInfo{
+		ID:              "s1",
+		ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		ClientInfo:      mcp.Implementation{Name: "test-client", Version: "1.0.0"},
+	}
+	if err := store.Create(ctx, info); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := store.Create(ctx, SessionInfo{ID: "s2"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		got, err := store.Get(ctx, "s1")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if got.ProtocolVersion != info.ProtocolVersion || got.ClientInfo != info.ClientInfo {
+			t.Errorf("Expected %+v, got %+v", info, got)
+		}
+		if got.CreatedAt.IsZero() || got.LastSeen.IsZero() {
+			t.Errorf("Expected timestamps, got %+v", got)
+		}
+	})
+
+	t.Run("Touch", func(t *testing.T) {
+		if err := store.Touch(ctx, "s1"); err != nil {
+			t.Errorf("Touch failed: %v", err)
+		}
+		if err := store.Touch(ctx, "missing"); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Expected ErrSessionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Sessions", func(t *testing.T) {
+		sessions, err := store.Sessions(ctx)
+		if err != nil {
+			t.Fatalf("Sessions failed: %v", err)
+		}
+		var ids []string
+		for _, s := range sessions {
+			ids = append(ids, s.ID)
+		}
+		if len(ids) != 2 || !strings.Contains(strings.Join(ids, ","), "s1") || !strings.Contains(strings.Join(ids, ","), "s2") {
+			t.Errorf("Expected sessions s1 and s2, got %v", ids)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := store.Delete(ctx, "s2"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, err := store.Get(ctx, "s2"); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Expected ErrSessionNotFound, got %v", err)
+		}
+		if err := store.Delete(ctx, "s2"); err != nil {
+			t.Errorf("Expected deleting twice to succeed, got %v", err)
+		}
+	})
+}
+
+func TestHTTPHandlerSharedSessionStore(t *testing.T) {
+	store := NewMemorySessionStore(0)
+	first := httptest.NewServer(NewHTTPHandler(newTestServer(), WithSessionStore(store)))
+	defer first.Close()
+	second := httptest.NewServer(NewHTTPHandler(newTestServer(), WithSessionStore(store)))
+	defer second.Close()
+
+	ctx := context.Background()
+	c, err := client.NewHTTPClient(&client.Options{BaseURL: first.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer c.Close()
+
+	sessions, err := store.Sessions(ctx)
+	if err != nil || len(sessions) != 1 || sessions[0].ID != c.GetSessionID() {
+		t.Fatalf("Expected the session in the store, got %+v, %v", sessions, err)
+	}
+	if sessions[0].ClientInfo.Name == "" {
+		t.Errorf("Expected the client info in the store, got %+v", sessions[0])
+	}
+
+	// A replica that did not see initialize serves the session from the store
+	req, _ := http.NewRequest(http.MethodPost, second.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	req.Header.Set(headerKeySessionID, c.GetSessionID())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 from the second replica, got %d", resp.StatusCode)
+	}
+
+	if err := store.Delete(ctx, c.GetSessionID()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Ping(ctx); err == nil {
+		t.Error("Expected an error once the session is deleted from the store")
+	}
+}
+
+// fakeRedis implements the Redis commands used by RedisSessionStore.
+type fakeRedis struct {
+	mu     sync.Mutex
+	hashes map[string]map[string]string
+	ttls   map[string]int64
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{hashes: map[string]map[string]string{}, ttls: map[string]int64{}}
+}
+
+func (r *fakeRedis) Do(ctx context.Context, args ...any) (any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := make([]string, len(args))
+	for i, arg := range args {
+		s[i] = fmt.Sprint(arg)
+	}
+	switch s[0] {
+	case "HSET":
+		if r.hashes[s[1]] == nil {
+			r.hashes[s[1]] = map[string]string{}
+		}
+		for i := 2; i+1 < len(s); i += 2 {
+			r.hashes[s[1]][s[i]] = s[i+1]
+		}
+		return int64(1), nil
+	case "PEXPIRE":
+		var ttl int64
+		fmt.Sscan(s[2], &ttl)
+		r.ttls[s[1]] = ttl
+		return int64(1), nil
+	case "EVAL":
+		// The touch script: KEYS[1], lastSeen, TTL
+		hash, ok := r.hashes[s[3]]
+		if !ok {
+			return int64(0), nil
+		}
+		hash["lastSeen"] = s[4]
+		return int64(1), nil
+	case "HMGET":
+		hash := r.hashes[s[1]]
+		reply := make([]any, 0, len(s)-2)
+		for _, field := range s[2:] {
+			if value, ok := hash[field]; ok {
+				reply = append(reply, value)
+			} else {
+				reply = append(reply, nil)
+			}
+		}
+		return reply, nil
+	case "DEL":
+		delete(r.hashes, s[1])
+		return int64(1), nil
+	case "SCAN":
+		prefix := strings.TrimSuffix(s[3], "*")
+		var keys []any
+		for key := range r.hashes {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		return []any{"0", keys}, nil
+	}
+	return nil, fmt.Errorf("unsupported command %s", s[0])
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestSessionFromContext(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(mcp.Tool{Name: "whoami"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		session := SessionFromContext(ctx)
+		var subject string
+		if info := TokenInfoFromContext(ctx); info != nil {
+			subject = info.Subject
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("%s %s %s %t %s",
+			session.ID(), session.ProtocolVersion(), session.ClientInfo().Name,
+			session.ClientCapabilities().Sampling != nil, subject)), nil
+	})
+	auth := RequireBearerToken(StaticTokens(map[string]*TokenInfo{"secret": {Subject: "alice"}}))
+	ts := httptest.NewServer(auth(NewHTTPHandler(srv)))
+	t.Cleanup(ts.Close)
+
+	c, err := client.NewHTTPClient(&client.Options{
+		BaseURL: ts.URL,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	result, err := c.CallTool(context.Background(), "whoami", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	expected := c.GetSessionID() + " 2025-03-26 mcpgopher false alice"
+	if text := result.Content[0].(mcp.TextContent).Text; text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	if session := SessionFromContext(context.Background()); session != nil {
+		t.Errorf("Expected no session outside of a request, got %v", session.ID())
+	}
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrServerClosed is returned by ServeStdio once the server is shut down, and
+// answers the requests received while it shuts down.
+var ErrServerClosed = errors.New("server closed")
+
+// shutdownRetry is the delay after which SSE clients are told to reconnect
+// when the server shuts down, by the final event of their streams.
+const shutdownRetry = time.Second
+
+// Shutdown gracefully stops the server: new sessions and requests are
+// refused, the requests in flight are given until ctx is done to finish,
+// then the sessions are ended and deleted from their stores. The SSE streams
+// of HTTP sessions end with a final event telling clients to reconnect after
+// a delay, to another replica during a rolling update. It returns ctx.Err()
+// if requests had to be cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownMu.Lock()
+	if s.closing {
+		s.shutdownMu.Unlock()
+		select {
+		case <-s.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	s.closing = true
+	if s.active == 0 {
+		close(s.idle)
+	}
+	hooks := s.shutdownHooks
+	s.shutdownMu.Unlock()
+
+	var err error
+	select {
+	case <-s.idle:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	for _, sess := range s.connectedSessions() {
+		sess.cancelAll()
+	}
+	// Cleaning up must not fail because the deadline has passed
+	cleanup := context.WithoutCancel(ctx)
+	for _, hook := range hooks {
+		hook(cleanup)
+	}
+	close(s.done)
+	return err
+}
+
+// onShutdown registers a function ending the sessions of a transport when
+// the server shuts down.
+func (s *Server) onShutdown(hook func(ctx context.Context)) {
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	s.shutdownHooks = append(s.shutdownHooks, hook)
+}
+
+// isClosing reports whether the server is shutting down.
+func (s *Server) isClosing() bool {
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	return s.closing
+}
+
+// startRequest counts a request in flight, or reports false if the server is
+// shutting down.
+func (s *Server) startRequest() bool {
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	if s.closing {
+		return false
+	}
+	s.active++
+	return true
+}
+
+// endRequest counts the end of a request started with startRequest.
+func (s *Server) endRequest() {
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	s.active--
+	if s.closing && s.active == 0 {
+		close(s.idle)
+	}
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+func TestShutdown(t *testing.T) {
+	// newSlowServer serves a tool answering once release is closed, or its
+	// request is cancelled
+	newSlowServer := func(release chan struct{}) (*Server, chan struct{}) {
+		started := make(chan struct{}, 1)
+		srv := NewServer("test-server", "1.0.0")
+		srv.AddTool(mcp.Tool{Name: "slow"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			started <- struct{}{}
+			select {
+			case <-release:
+				return mcp.NewToolResultText("done"), nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		})
+		return srv, started
+	}
+
+	t.Run("Drain", func(t *testing.T) {
+		release := make(chan struct{})
+		srv, started := newSlowServer(release)
+		store := NewMemorySessionStore(0)
+		ts := httptest.NewServer(NewHTTPHandler(srv, WithSessionStore(store)))
+		t.Cleanup(ts.Close)
+		c, err := client.NewHTTPClient(&client.Options{BaseURL: ts.URL})
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer c.Close()
+		ctx := context.Background()
+
+		// Open the GET stream of the session
+		req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set(headerKeySessionID, c.GetSessionID())
+		stream, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stream.Body.Close()
+
+		call := make(chan *mcp.CallToolResult, 1)
+		go func() {
+			result, _ := c.CallTool(ctx, "slow", nil)
+			call <- result
+		}()
+		<-started
+
+		shutdown := make(chan error, 1)
+		go func() {
+			shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			shutdown <- srv.Shutdown(shutdownCtx)
+		}()
+		for !srv.isClosing() {
+			time.Sleep(time.Millisecond)
+		}
+
+		resp, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected new sessions to be refused with 503, got %d", resp.StatusCode)
+		}
+		var rpcErr *mcp.Error
+		if _, err := c.Ping(ctx); !errors.As(err, &rpcErr) || rpcErr.Message != ErrServerClosed.Error() {
+			t.Errorf("Expected new requests to be refused, got %v", err)
+		}
+
+		close(release)
+		if result := <-call; result == nil || result.Content[0].(mcp.TextContent).Text != "done" {
+			t.Errorf("Expected the call in flight to finish, got %+v", result)
+		}
+		if err := <-shutdown; err != nil {
+			t.Errorf("Expected Shutdown to succeed, got %v", err)
+		}
+		if _, err := store.Get(ctx, c.GetSessionID()); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Expected the session to be deleted, got %v", err)
+		}
+		events, _ := io.ReadAll(stream.Body)
+		if !strings.Contains(string(events), "retry: 1000\n\n") {
+			t.Errorf("Expected the stream to end with a retry event, got %q", events)
+		}
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		srv, started := newSlowServer(nil)
+		c := connect(t, srv)
+		call := make(chan error, 1)
+		go func() {
+			_, err := c.CallTool(context.Background(), "slow", nil)
+			call <- err
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := srv.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		select {
+		case <-call:
+		case <-time.After(5 * time.Second):
+			t.Error("Expected the call in flight to be cancelled")
+		}
+	})
+
+	t.Run("Stdio", func(t *testing.T) {
+		srv := newTestServer()
+		r, w := io.Pipe()
+		defer w.Close()
+		done := make(chan error, 1)
+		go func() {
+			done <- srv.ServeStdio(context.Background(), r, io.Discard)
+		}()
+		// Wait for ServeStdio to read its input
+		_, _ = w.Write([]byte("\n"))
+		if err := srv.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown failed: %v", err)
+		}
+		if err := <-done; !errors.Is(err, ErrServerClosed) {
+			t.Errorf("Expected ErrServerClosed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// maxStdioMessageSize bounds a single message read from stdin.
+const maxStdioMessageSize = 16 * 1024 * 1024
+
+// ServeStdio serves s over the stdio transport: newline-delimited JSON-RPC
+// messages are read from r and answered on w, as a server subprocess does
+// with its stdin and stdout. Requests are handled concurrently, notifications
+// in order. It returns when r ends, ctx is done or the server is shut down,
+// after the requests in flight have been answered.
+// See: http://spec.modelcontextprotocol.io/2025-03-26/basic/transports#stdio
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		writeMu sync.Mutex
+	)
+	send := func(ctx context.Context, msg *message) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to encode message: %w", err)
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+
+	sess := newSession("")
+	sess.quotaID = newSessionID()
+	sess.out = senderFunc(send)
+	s.addSession(sess)
+	defer s.removeSession(sess)
+	defer wg.Wait()
+	defer sess.cancelAll()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxStdioMessageSize)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	for {
+		var line []byte
+		select {
+		case line = <-lines:
+		case err := <-readErr:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.done:
+			return ErrServerClosed
+		}
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			_ = send(ctx, &message{JSONRPC: mcp.JSONRPC_VERSION, Error: mcp.NewError(mcp.ErrorParseError, err.Error())})
+			continue
+		}
+		if msg.isNotification() {
+			// Handled in order, so that the requests that follow see their effect
+			s.handle(ctx, sess, &msg)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if response := s.handle(ctx, sess, &msg); response != nil {
+				_ = send(ctx, response)
+			}
+		}()
+	}
+}
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// errNoStream is returned when a message cannot be delivered because no
+// stream to the client is open.
+var errNoStream = errors.New("no stream open to the client")
+
+// sender delivers messages from the server to the client.
+type sender interface {
+	send(ctx context.Context, msg *message) error
+}
+
+// senderFunc adapts a function to sender.
+type senderFunc func(ctx context.Context, msg *message) error
+
+func (f senderFunc) send(ctx context.Context, msg *message) error {
+	return f(ctx, msg)
+}
+
+type requestStreamKey struct{}
+
+// withRequestStream returns a context whose messages to the client go to out,
+// the stream answering the request being handled.
+func withRequestStream(ctx context.Context, out sender) context.Context {
+	return context.WithValue(ctx, requestStreamKey{}, out)
+}
+
+// requestStream returns the stream answering the request handled in ctx, if any.
+func requestStream(ctx context.Context) sender {
+	out, _ := ctx.Value(requestStreamKey{}).(sender)
+	return out
+}
+
+// newMessage builds a JSON-RPC notification, or a request once given an ID.
+func newMessage(method mcp.MCPMethod, params any) (*message, error) {
+	msg := &message{JSONRPC: mcp.JSONRPC_VERSION, Method: string(method)}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s params: %w", method, err)
+		}
+		msg.Params = data
+	}
+	return msg, nil
+}
+
+// sseStream is an SSE stream of a session over HTTP: the response to a POST
+// request, or the GET stream carrying the messages unrelated to a request.
+// With an EventStore, its events are stored so that a client can resume it on
+// another connection after a disconnection.
+type sseStream struct {
+	id        string
+	sessionID string
+	events    EventStore
+
+	mu sync.Mutex
+	// w is the connection events are written to, nil when detached
+	w http.ResponseWriter
+	// started is set once the SSE response headers are written to w
+	started bool
+	// acceptsSSE is whether a stream may be started on w
+	acceptsSSE bool
+	closed     bool
+	done       chan struct{}
+}
+
+// newSSEStream creates a stream of the session, identified within the
+// event store by an ID prefixed with the session ID.
+func newSSEStream(sessionID string, events EventStore) *sseStream {
+	return &sseStream{
+		id:        sessionID + "-" + newSessionID(),
+		sessionID: sessionID,
+		events:    events,
+		done:      make(chan struct{}),
+	}
+}
+
+// send implements sender. The SSE response is started by the first event.
+func (s *sseStream) send(ctx context.Context, msg *message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sendLocked(ctx, msg)
+}
+
+// sendLocked sends msg as an event. s.mu must be held.
+func (s *sseStream) sendLocked(ctx context.Context, msg *message) error {
+	if s.closed || (s.w == nil && s.events == nil) || (s.w != nil && !s.started && !s.acceptsSSE) {
+		return errNoStream
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	var eventID string
+	if s.events != nil {
+		if eventID, err = s.events.Append(ctx, s.sessionID, s.id, data); err != nil {
+			return fmt.Errorf("failed to store event: %w", err)
+		}
+	}
+	if s.w == nil {
+		// The event is replayed when the client resumes the stream
+		return nil
+	}
+	s.start()
+	return writeEvent(s.w, eventID, data)
+}
+
+// start writes the SSE response headers, if not done yet. s.mu must be held.
+func (s *sseStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	startSSE(s.w)
+}
+
+// startSSE writes the headers of an SSE response and flushes them.
+func startSSE(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	_ = http.NewResponseController(w).Flush()
+}
+
+// attach writes the events of the stream to w from now on. It reports false
+// if another connection is attached.
+func (s *sseStream) attach(w http.ResponseWriter, acceptsSSE bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w != nil {
+		return false
+	}
+	s.w, s.started, s.acceptsSSE = w, false, acceptsSSE
+	return true
+}
+
+// begin starts the SSE response on the attached connection without waiting
+// for an event, so that the client knows the stream is open.
+func (s *sseStream) begin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w != nil {
+		s.start()
+	}
+}
+
+// detach stops writing events to w, which is about to be closed.
+func (s *sseStream) detach(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w == w {
+		s.w = nil
+	}
+}
+
+// finish sends the last messages of the stream and closes it. It reports
+// whether they were sent as events; if not, the stream was never started and
+// the caller answers with JSON instead.
+func (s *sseStream) finish(ctx context.Context, last []*message) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	started := s.started || (s.w == nil && s.events != nil)
+	if started {
+		for _, msg := range last {
+			_ = s.sendLocked(ctx, msg)
+		}
+	}
+	s.closeLocked()
+	return started
+}
+
+// close ends the stream, releasing the connection it is attached to.
+func (s *sseStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+}
+
+func (s *sseStream) closeLocked() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.w = nil
+	close(s.done)
+}
+
+// end closes the stream after writing a final event, telling the client to
+// reconnect after the retry delay.
+func (s *sseStream) end(retry time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w != nil && s.started {
+		if _, err := fmt.Fprintf(s.w, "retry: %d\n\n", retry.Milliseconds()); err == nil {
+			_ = http.NewResponseController(s.w).Flush()
+		}
+	}
+	s.closeLocked()
+}
+
+// writeEvent writes a message event to an SSE stream and flushes it.
+func writeEvent(w http.ResponseWriter, eventID string, data []byte) error {
+	var err error
+	if eventID != "" {
+		_, err = fmt.Fprintf(w, "id: %s\n", eventID)
+	}
+	if err == nil {
+		_, err = fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	_ = http.NewResponseController(w).Flush()
+	return nil
+}
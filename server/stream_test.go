@@ -0,0 +1,193 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+// sseEvent is an event read from an SSE stream.
+type sseEvent struct {
+	id   string
+	data string
+}
+
+// readEvent reads the next event of an SSE stream.
+func readEvent(r *bufio.Reader) (sseEvent, error) {
+	var event sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return event, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "" && event.data != "":
+			return event, nil
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// readEvents reads an SSE stream until it ends.
+func readEvents(r io.Reader) []sseEvent {
+	var events []sseEvent
+	br := bufio.NewReader(r)
+	for {
+		event, err := readEvent(br)
+		if err != nil {
+			return events
+		}
+		events = append(events, event)
+	}
+}
+
+func TestHTTPHandlerStreams(t *testing.T) {
+	release := make(chan struct{})
+	srv := NewServer("test-server", "1.0.0")
+	srv.AddTool(mcp.Tool{Name: "steps"}, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sess := sessionFromContext(ctx)
+		_ = sess.notify(ctx, mcp.MethodNotificationProgress, map[string]any{"progressToken": "t", "progress": 1})
+		if request.Params.Arguments["wait"] == true {
+			<-release
+		}
+		_ = sess.notify(ctx, mcp.MethodNotificationProgress, map[string]any{"progressToken": "t", "progress": 2})
+		return mcp.NewToolResultText("done"), nil
+	})
+	ts := httptest.NewServer(NewHTTPHandler(srv, WithEventStore(NewMemoryEventStore(0))))
+	defer ts.Close()
+
+	post := func(ctx context.Context, sessionID, body string) *http.Response {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL, strings.NewReader(body))
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			req.Header.Set(headerKeySessionID, sessionID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	resp := post(context.Background(), "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(headerKeySessionID)
+	post(context.Background(), sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`).Body.Close()
+
+	t.Run("SSEResponse", func(t *testing.T) {
+		resp := post(context.Background(), sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"steps"}}`)
+		defer resp.Body.Close()
+		if resp.Header.Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("Expected an event stream, got %q", resp.Header.Get("Content-Type"))
+		}
+		events := readEvents(resp.Body)
+		if len(events) != 3 || !strings.Contains(events[2].data, `"done"`) {
+			t.Fatalf("Expected 2 notifications and the result, got %+v", events)
+		}
+		for _, event := range events {
+			if event.id == "" {
+				t.Errorf("Expected event IDs, got %+v", event)
+			}
+		}
+	})
+
+	t.Run("JSONResponse", func(t *testing.T) {
+		resp := post(context.Background(), sessionID, `{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+		defer resp.Body.Close()
+		if resp.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON without messages to stream, got %q", resp.Header.Get("Content-Type"))
+		}
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		resp := post(ctx, sessionID, `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"steps","arguments":{"wait":true}}}`)
+		first, err := readEvent(bufio.NewReader(resp.Body))
+		if err != nil {
+			t.Fatalf("Failed to read the first event: %v", err)
+		}
+		// Disconnect while the tool is running
+		cancel()
+		resp.Body.Close()
+
+		var stream *http.Response
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+			req.Header.Set("Accept", "text/event-stream")
+			req.Header.Set(headerKeySessionID, sessionID)
+			req.Header.Set(headerKeyLastEventID, first.id)
+			if stream, err = http.DefaultClient.Do(req); err != nil {
+				t.Fatal(err)
+			}
+			if stream.StatusCode != http.StatusConflict || time.Now().After(deadline) {
+				break
+			}
+			// The server has not noticed the disconnection yet
+			stream.Body.Close()
+			time.Sleep(10 * time.Millisecond)
+		}
+		defer stream.Body.Close()
+		if stream.StatusCode != http.StatusOK {
+			t.Fatalf("Expected the stream to resume, got %d", stream.StatusCode)
+		}
+
+		close(release)
+		events := readEvents(stream.Body)
+		if len(events) != 2 {
+			t.Fatalf("Expected the second notification and the result, got %+v", events)
+		}
+		var result struct {
+			ID     int                `json:"id"`
+			Result mcp.CallToolResult `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(events[1].data), &result); err != nil || result.ID != 4 {
+			t.Errorf("Expected the result of request 4, got %s", events[1].data)
+		}
+	})
+
+	t.Run("ResumeEnded", func(t *testing.T) {
+		resp := post(context.Background(), sessionID, `{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"steps"}}`)
+		events := readEvents(resp.Body)
+		resp.Body.Close()
+
+		req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set(headerKeySessionID, sessionID)
+		req.Header.Set(headerKeyLastEventID, events[0].id)
+		stream, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stream.Body.Close()
+		if replayed := readEvents(stream.Body); len(replayed) != 2 || replayed[1] != events[2] {
+			t.Errorf("Expected the last 2 events, got %+v", replayed)
+		}
+	})
+
+	t.Run("ResumeUnknown", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set(headerKeySessionID, sessionID)
+		req.Header.Set(headerKeyLastEventID, "other-stream_0")
+		stream, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream.Body.Close()
+		if stream.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", stream.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/contriboss/mcpgopher/mcp"
+	"github.com/contriboss/mcpgopher/mcp/jsonschema"
+)
+
+// AddTypedTool registers a tool backed by a plain Go function. Its input
+// schema is derived from TArgs, typically a struct with json and jsonschema
+// tags (see jsonschema.SchemaFor); arguments are validated against it by the
+// validator of the server, see WithValidator, and decoded into a TArgs. The returned TResult is sent as JSON text, and as
+// structured content described by an output schema when it encodes to an
+// object. An error is reported to the model as a tool error, unless it is an
+// *mcp.Error.
+func AddTypedTool[TArgs any, TResult any](s *Server, name, description string, handler func(ctx context.Context, args TArgs) (TResult, error)) {
+	tool := mcp.Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: jsonschema.SchemaFor[TArgs]().Build(),
+	}
+	outputSchema := jsonschema.SchemaFor[TResult]().Build()
+	var output struct {
+		Type any `json:"type"`
+	}
+	structured := json.Unmarshal(outputSchema, &output) == nil && output.Type == "object"
+	if structured {
+		tool.OutputSchema = outputSchema
+	}
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		data, err := json.Marshal(request.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode arguments: %w", err)
+		}
+		var args TArgs
+		if err := json.Unmarshal(data, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+
+		value, err := handler(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		data, err = json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode result: %w", err)
+		}
+		result := mcp.NewToolResultText(string(data))
+		if structured && string(data) != "null" {
+			result.StructuredContent = data
+		}
+		return result, nil
+	})
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/contriboss/mcpgopher/client"
+	"github.com/contriboss/mcpgopher/mcp"
+)
+
+type forecastArgs struct {
+	City string `json:"city" jsonschema:"description=City to forecast"`
+	Days int    `json:"days,omitempty"`
+}
+
+type forecast struct {
+	City    string   `json:"city"`
+	Weather []string `json:"weather"`
+}
+
+func TestAddTypedTool(t *testing.T) {
+	srv := NewServer("test-server", "1.0.0")
+	AddTypedTool(srv, "forecast", "Forecasts the weather", func(ctx context.Context, args forecastArgs) (forecast, error) {
+		if args.City == "Atlantis" {
+			return forecast{}, errors.New("unknown city")
+		}
+		return forecast{City: args.City, Weather: slices.Repeat([]string{"sunny"}, max(args.Days, 1))}, nil
+	})
+	AddTypedTool(srv, "shout", "Upper-cases text", func(ctx context.Context, args struct {
+		Text string `json:"text"`
+	}) (string, error) {
+		return strings.ToUpper(args.Text), nil
+	})
+	c := connect(t, srv)
+	ctx := context.Background()
+
+	t.Run("Schemas", func(t *testing.T) {
+		result, err := c.ListTools(ctx, "")
+		if err != nil {
+			t.Fatalf("ListTools failed: %v", err)
+		}
+		tool := result.Tools[0]
+		var input map[string]any
+		if err := json.Unmarshal(tool.InputSchema, &input); err != nil {
+			t.Fatal(err)
+		}
+		if tool.Description != "Forecasts the weather" || input["type"] != "object" || len(input["required"].([]any)) != 1 {
+			t.Errorf("Expected the schema of forecastArgs, got %s", tool.InputSchema)
+		}
+		if len(tool.OutputSchema) == 0 {
+			t.Error("Expected an output schema")
+		}
+		if len(result.Tools[1].OutputSchema) != 0 {
+			t.Errorf("Expected no output schema for a string result, got %s", result.Tools[1].OutputSchema)
+		}
+	})
+
+	t.Run("Call", func(t *testing.T) {
+		result, err := client.CallToolAs[forecastArgs, forecast](ctx, c, "forecast", forecastArgs{City: "Paris", Days: 2})
+		if err != nil {
+			t.Fatalf("CallToolAs failed: %v", err)
+		}
+		if result.City != "Paris" || len(result.Weather) != 2 {
+			t.Errorf("Expected a 2 days forecast of Paris, got %+v", result)
+		}
+		shouted, err := client.CallToolAs[map[string]string, string](ctx, c, "shout", map[string]string{"text": "hi"})
+		if err != nil || shouted != "HI" {
+			t.Errorf("Expected HI, got %q %v", shouted, err)
+		}
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		result, err := c.CallTool(ctx, "forecast", map[string]any{"days": 2})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "invalid arguments") {
+			t.Errorf("Expected an invalid arguments error, got %+v", result)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := client.CallToolAs[forecastArgs, forecast](ctx, c, "forecast", forecastArgs{City: "Atlantis"})
+		var toolErr *client.ToolError
+		if !errors.As(err, &toolErr) || !strings.Contains(err.Error(), "unknown city") {
+			t.Errorf("Expected a tool error, got %v", err)
+		}
+	})
+}